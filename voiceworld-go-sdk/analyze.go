@@ -0,0 +1,218 @@
+package voiceworld
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"time"
+)
+
+// silenceFloor is the per-sample amplitude below which a frame counts as
+// silent for the leading/trailing measurements: roughly -60 dBFS.
+const silenceFloor = 33
+
+// AnalysisReport describes the loudness characteristics of one 16-bit PCM
+// WAV file, computed client-side in a single streaming pass; see
+// AnalyzeAudio. Levels are dBFS (0 is full scale, more negative is
+// quieter); an all-zero file reports -Inf for both levels.
+type AnalysisReport struct {
+	// PeakDBFS is the level of the loudest single sample.
+	PeakDBFS float64 `json:"peak_dbfs"`
+	// RMSDBFS is the root-mean-square level over the whole file, the
+	// better proxy for perceived loudness.
+	RMSDBFS float64 `json:"rms_dbfs"`
+	// ClippedPercent is the percentage of samples sitting at full scale,
+	// the usual sign of an overdriven input.
+	ClippedPercent float64 `json:"clipped_percent"`
+	// DCOffset is the mean sample value normalized to [-1, 1]; values far
+	// from zero indicate a miswired or faulty capture chain.
+	DCOffset float64 `json:"dc_offset"`
+	// LeadingSilence and TrailingSilence measure the quiet stretches at
+	// the edges of the recording (every channel below ~-60 dBFS).
+	LeadingSilence  time.Duration `json:"leading_silence"`
+	TrailingSilence time.Duration `json:"trailing_silence"`
+	// Duration is the analyzed audio length.
+	Duration time.Duration `json:"duration"`
+}
+
+// AnalyzeAudio measures peak and RMS level, clipping, DC offset and edge
+// silence for a 16-bit PCM WAV file, reading it once front to back. Run it
+// before spending transcription quota on a recording of unknown provenance,
+// or let UploadOptions.RejectIf do so automatically.
+func AnalyzeAudio(filePath string) (*AnalysisReport, error) {
+	info, err := ParseWAVFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if info.BitsPerSample != 16 {
+		return nil, fmt.Errorf("voiceworld: unsupported bit depth %d (only 16-bit PCM is supported)", info.BitsPerSample)
+	}
+	f, err := os.Open(longPath(filePath))
+	if err != nil {
+		return nil, fmt.Errorf("voiceworld: open audio: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Seek(info.DataOffset, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	frameBytes := int64(info.NumChannels) * 2
+	totalFrames := info.DataSize / frameBytes
+	var (
+		peak          int64
+		sumSquares    float64
+		sum           int64
+		clipped       int64
+		samples       int64
+		leading       int64
+		trailing      int64
+		leadingEnded  bool
+		buf           = make([]byte, copyBlockSize-copyBlockSize%int(frameBytes))
+		remaining     = totalFrames * frameBytes
+		frameChannels = int(info.NumChannels)
+	)
+	for remaining > 0 {
+		n := int64(len(buf))
+		if remaining < n {
+			n = remaining
+		}
+		if _, err := io.ReadFull(f, buf[:n]); err != nil {
+			return nil, fmt.Errorf("voiceworld: read pcm data: %w", err)
+		}
+		remaining -= n
+		for off := int64(0); off < n; off += frameBytes {
+			silent := true
+			for ch := 0; ch < frameChannels; ch++ {
+				s := int64(int16(binary.LittleEndian.Uint16(buf[off+int64(ch)*2:])))
+				abs := s
+				if abs < 0 {
+					abs = -abs
+				}
+				if abs > peak {
+					peak = abs
+				}
+				if abs >= 32767 {
+					clipped++
+				}
+				if abs >= silenceFloor {
+					silent = false
+				}
+				sum += s
+				sumSquares += float64(s) * float64(s)
+				samples++
+			}
+			if silent {
+				if !leadingEnded {
+					leading++
+				}
+				trailing++
+			} else {
+				leadingEnded = true
+				trailing = 0
+			}
+		}
+	}
+
+	report := &AnalysisReport{
+		PeakDBFS: dbfs(float64(peak)),
+		Duration: frameDuration(totalFrames, info.SampleRate),
+	}
+	if samples > 0 {
+		report.RMSDBFS = dbfs(math.Sqrt(sumSquares / float64(samples)))
+		report.ClippedPercent = 100 * float64(clipped) / float64(samples)
+		report.DCOffset = float64(sum) / float64(samples) / 32768
+	} else {
+		report.RMSDBFS = math.Inf(-1)
+	}
+	report.LeadingSilence = frameDuration(leading, info.SampleRate)
+	report.TrailingSilence = frameDuration(trailing, info.SampleRate)
+	return report, nil
+}
+
+// dbfs converts an amplitude on the 16-bit scale to dBFS; zero maps to -Inf.
+func dbfs(amplitude float64) float64 {
+	if amplitude <= 0 {
+		return math.Inf(-1)
+	}
+	return 20 * math.Log10(amplitude/32768)
+}
+
+// frameDuration converts a frame count at rate to a duration.
+func frameDuration(frames int64, rate int) time.Duration {
+	if rate <= 0 {
+		return 0
+	}
+	return time.Duration(float64(frames) / float64(rate) * float64(time.Second))
+}
+
+// ErrAudioQuality is the sentinel every RejectIf rejection unwraps to; the
+// concrete *AudioQualityError names the violated threshold.
+var ErrAudioQuality = errors.New("voiceworld: audio fails quality policy")
+
+// AudioQualityError reports audio rejected by UploadOptions.RejectIf before
+// any bytes were uploaded.
+type AudioQualityError struct {
+	// Check names the violated threshold, e.g. "clipped_percent".
+	Check string
+	// Value is the measured figure, Threshold the policy's limit, in the
+	// unit of the named check.
+	Value     float64
+	Threshold float64
+	// Report is the full analysis behind the decision.
+	Report *AnalysisReport
+}
+
+// Error implements the error interface.
+func (e *AudioQualityError) Error() string {
+	return fmt.Sprintf("voiceworld: audio fails quality policy: %s %.4g violates threshold %.4g", e.Check, e.Value, e.Threshold)
+}
+
+// Unwrap exposes the sentinel to errors.Is.
+func (e *AudioQualityError) Unwrap() error { return ErrAudioQuality }
+
+// AudioQualityPolicy is the threshold set behind UploadOptions.RejectIf.
+// Zero-valued fields disable their check.
+type AudioQualityPolicy struct {
+	// ClippingAbovePercent rejects audio whose share of full-scale
+	// samples exceeds this percentage.
+	ClippingAbovePercent float64
+	// RMSBelowDBFS rejects near-silent audio whose RMS level sits below
+	// this (e.g. -45). Meaningful thresholds are negative.
+	RMSBelowDBFS float64
+	// LeadingSilenceAbove and TrailingSilenceAbove reject recordings with
+	// longer quiet stretches at the respective edge.
+	LeadingSilenceAbove  time.Duration
+	TrailingSilenceAbove time.Duration
+}
+
+// analyzeForPolicy runs the analysis pass behind UploadOptions.RejectIf and
+// applies the policy, returning the report either way a policy decision was
+// reached.
+func (c *Client) analyzeForPolicy(filePath string, policy *AudioQualityPolicy) (*AnalysisReport, error) {
+	report, err := AnalyzeAudio(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return report, policy.check(report)
+}
+
+// check evaluates report against the policy, returning the first violation
+// as an *AudioQualityError.
+func (p *AudioQualityPolicy) check(report *AnalysisReport) error {
+	if p.ClippingAbovePercent > 0 && report.ClippedPercent > p.ClippingAbovePercent {
+		return &AudioQualityError{Check: "clipped_percent", Value: report.ClippedPercent, Threshold: p.ClippingAbovePercent, Report: report}
+	}
+	if p.RMSBelowDBFS != 0 && report.RMSDBFS < p.RMSBelowDBFS {
+		return &AudioQualityError{Check: "rms_dbfs", Value: report.RMSDBFS, Threshold: p.RMSBelowDBFS, Report: report}
+	}
+	if p.LeadingSilenceAbove > 0 && report.LeadingSilence > p.LeadingSilenceAbove {
+		return &AudioQualityError{Check: "leading_silence", Value: report.LeadingSilence.Seconds(), Threshold: p.LeadingSilenceAbove.Seconds(), Report: report}
+	}
+	if p.TrailingSilenceAbove > 0 && report.TrailingSilence > p.TrailingSilenceAbove {
+		return &AudioQualityError{Check: "trailing_silence", Value: report.TrailingSilence.Seconds(), Threshold: p.TrailingSilenceAbove.Seconds(), Report: report}
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package voiceworld
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// makeSampleWAVFile writes mono 16 kHz audio with exactly the given samples.
+func makeSampleWAVFile(t *testing.T, samples []int16) string {
+	t.Helper()
+	info := &WAVInfo{NumChannels: 1, SampleRate: 16000, BitsPerSample: 16}
+	var buf bytes.Buffer
+	if err := writeWAVHeader(&buf, info, int64(len(samples)*2)); err != nil {
+		t.Fatal(err)
+	}
+	b := make([]byte, 2)
+	for _, s := range samples {
+		binary.LittleEndian.PutUint16(b, uint16(s))
+		buf.Write(b)
+	}
+	path := filepath.Join(t.TempDir(), "audio.wav")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestAnalyzeAudioMath(t *testing.T) {
+	// 1 s: 0.25 s silence, 0.5 s half-scale square wave with every 10th
+	// sample clipped, 0.25 s silence.
+	samples := make([]int16, 16000)
+	clipped := 0
+	for i := 4000; i < 12000; i++ {
+		if (i-4000)%10 == 0 {
+			samples[i] = 32767
+			clipped++
+		} else if i%2 == 0 {
+			samples[i] = 16384
+		} else {
+			samples[i] = -16384
+		}
+	}
+	report, err := AnalyzeAudio(makeSampleWAVFile(t, samples))
+	if err != nil {
+		t.Fatalf("AnalyzeAudio: %v", err)
+	}
+	if report.PeakDBFS > 0.01 || report.PeakDBFS < -0.01 {
+		t.Errorf("PeakDBFS = %.3f, want ~0 (clipped samples hit full scale)", report.PeakDBFS)
+	}
+	wantClipped := 100 * float64(clipped) / float64(len(samples))
+	if math.Abs(report.ClippedPercent-wantClipped) > 0.001 {
+		t.Errorf("ClippedPercent = %.4f, want %.4f", report.ClippedPercent, wantClipped)
+	}
+	// Half the file at half scale plus the clipped spikes: mean square
+	// (7200*0.25 + 800*1.0)/16000 ≈ 0.1625, i.e. about -7.9 dBFS.
+	if report.RMSDBFS > -7.5 || report.RMSDBFS < -8.3 {
+		t.Errorf("RMSDBFS = %.2f, want about -7.9", report.RMSDBFS)
+	}
+	if got := report.LeadingSilence; got != 250*time.Millisecond {
+		t.Errorf("LeadingSilence = %v, want 250ms", got)
+	}
+	if got := report.TrailingSilence; got != 250*time.Millisecond {
+		t.Errorf("TrailingSilence = %v, want 250ms", got)
+	}
+	if report.Duration != time.Second {
+		t.Errorf("Duration = %v, want 1s", report.Duration)
+	}
+}
+
+func TestAnalyzeAudioDCOffsetAndSilence(t *testing.T) {
+	// A constant positive level: pure DC, no clipping, no silence.
+	samples := make([]int16, 8000)
+	for i := range samples {
+		samples[i] = 8192
+	}
+	report, err := AnalyzeAudio(makeSampleWAVFile(t, samples))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(report.DCOffset-0.25) > 0.001 {
+		t.Errorf("DCOffset = %.4f, want 0.25", report.DCOffset)
+	}
+	if report.LeadingSilence != 0 || report.TrailingSilence != 0 {
+		t.Errorf("silence = (%v, %v), want none", report.LeadingSilence, report.TrailingSilence)
+	}
+
+	// All zeros: -Inf levels, full leading silence.
+	quiet, err := AnalyzeAudio(makeSampleWAVFile(t, make([]int16, 8000)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !math.IsInf(quiet.PeakDBFS, -1) || !math.IsInf(quiet.RMSDBFS, -1) {
+		t.Errorf("silent file levels = (%v, %v), want -Inf", quiet.PeakDBFS, quiet.RMSDBFS)
+	}
+	if quiet.LeadingSilence != 500*time.Millisecond {
+		t.Errorf("LeadingSilence = %v, want 500ms", quiet.LeadingSilence)
+	}
+}
+
+func TestUploadRejectsOnQualityPolicy(t *testing.T) {
+	client, store := newTestClient(t, nil)
+
+	// Near-silent audio fails the RMS floor before any bytes move.
+	quiet := makeSampleWAVFile(t, make([]int16, 8000))
+	_, err := client.UploadFile(quiet, "req-1/a.wav", &UploadOptions{
+		RejectIf: &AudioQualityPolicy{RMSBelowDBFS: -45},
+	})
+	var qerr *AudioQualityError
+	if !errors.As(err, &qerr) || !errors.Is(err, ErrAudioQuality) {
+		t.Fatalf("err = %v, want *AudioQualityError", err)
+	}
+	if qerr.Check != "rms_dbfs" || qerr.Report == nil {
+		t.Errorf("violation = %+v, want rms_dbfs with report attached", qerr)
+	}
+	if len(store.Keys()) != 0 {
+		t.Errorf("rejected upload staged objects: %v", store.Keys())
+	}
+
+	// Healthy audio under the same policy goes through.
+	loud := make([]int16, 8000)
+	for i := range loud {
+		if i%2 == 0 {
+			loud[i] = 16384
+		} else {
+			loud[i] = -16384
+		}
+	}
+	if _, err := client.UploadFile(makeSampleWAVFile(t, loud), "req-1/b.wav", &UploadOptions{
+		RejectIf: &AudioQualityPolicy{RMSBelowDBFS: -45, ClippingAbovePercent: 1},
+	}); err != nil {
+		t.Fatalf("healthy upload rejected: %v", err)
+	}
+}
+
+func TestProcessAndUploadAttachesAnalysis(t *testing.T) {
+	client, _ := newTestClient(t, nil)
+	loud := make([]int16, 16000)
+	for i := range loud {
+		if i%2 == 0 {
+			loud[i] = 16384
+		} else {
+			loud[i] = -16384
+		}
+	}
+	result, err := client.ProcessAndUploadFile(makeSampleWAVFile(t, loud), "req-9", &UploadOptions{
+		RejectIf: &AudioQualityPolicy{RMSBelowDBFS: -45},
+	})
+	if err != nil {
+		t.Fatalf("ProcessAndUploadFile: %v", err)
+	}
+	if result.Analysis == nil {
+		t.Fatal("result carries no analysis report")
+	}
+	// A full-length half-scale square wave sits at -6 dBFS.
+	if result.Analysis.RMSDBFS > -5 || result.Analysis.RMSDBFS < -7 {
+		t.Errorf("attached RMSDBFS = %.2f, want about -6", result.Analysis.RMSDBFS)
+	}
+}
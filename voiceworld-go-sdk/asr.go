@@ -0,0 +1,608 @@
+package voiceworld
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Recognizer is an alternative transport for recognition requests, e.g. the
+// gRPC client in the voiceworldgrpc subpackage. When one is configured the
+// high-level recognition calls route through it instead of the REST /asr
+// endpoint.
+type Recognizer interface {
+	Recognize(ctx context.Context, audio []byte, config *RecognitionConfig) (*RecognitionResult, error)
+}
+
+// RecognitionConfig holds the per-request recognition parameters. The zero
+// value asks for 16 kHz mono WAV, the platform default.
+type RecognitionConfig struct {
+	// Format is the audio container/codec, e.g. "wav", "pcm", "amr" or
+	// "speex". Empty lets ASR sniff telephony containers from their
+	// magic bytes and otherwise means the platform default, wav.
+	Format string
+	// SampleRate is in Hz; the platform accepts 8000 and 16000.
+	SampleRate int
+	// RequestID correlates staged objects and results. Empty means the
+	// server assigns one.
+	RequestID string
+	// EnableLanguageDetection asks the server to identify the spoken
+	// language instead of assuming the account default. The result then
+	// carries DetectedLanguage and per-segment Language fields.
+	EnableLanguageDetection bool
+	// EnableProfanityFilter masks profanity in the transcript with "***".
+	// Servers that return both texts put the unfiltered one in RawResult.
+	EnableProfanityFilter bool
+	// RemoveDisfluencies drops filler words ("嗯", "那个", "uh") from the
+	// transcript.
+	RemoveDisfluencies bool
+	// ModelID selects a specific recognition model, e.g. a domain-adapted
+	// one; see ListModels. Empty means the account default. An unknown ID
+	// surfaces as ErrModelNotFound.
+	ModelID string
+	// EnableEmotionDetection asks the server for per-segment emotion
+	// labels and speech-rate metrics. Older servers ignore the flag and
+	// the fields simply stay at their zero values.
+	EnableEmotionDetection bool
+	// EnablePunctuation is shorthand for TextProcessing.Punctuation "on".
+	EnablePunctuation bool
+	// EnableNormalization is shorthand for normalizing numbers, dates and
+	// units all at once (TextProcessing "digits"/"digits"/"symbols").
+	EnableNormalization bool
+	// TextProcessing controls inverse text normalization and punctuation
+	// per aspect. When set it takes precedence over the two shorthand
+	// booleans above. Nil means the server defaults.
+	TextProcessing *TextProcessingOptions
+}
+
+// TextProcessingOptions selects how the server post-processes transcript
+// text, one aspect at a time. Every field accepts the empty string for the
+// server default; other values are validated before the request is sent.
+type TextProcessingOptions struct {
+	// Punctuation is "on" or "off".
+	Punctuation string
+	// ParagraphBreaks is "on" or "off".
+	ParagraphBreaks string
+	// NumberNormalization is "digits" (三百 -> 300) or "verbatim".
+	NumberNormalization string
+	// DateNormalization is "digits" or "verbatim".
+	DateNormalization string
+	// UnitNormalization is "symbols" (五千克 -> 5kg) or "verbatim".
+	UnitNormalization string
+}
+
+// textProcessingAllowed maps each query parameter to its permitted values.
+var textProcessingAllowed = map[string][]string{
+	"punctuation":          {"on", "off"},
+	"paragraph_breaks":     {"on", "off"},
+	"number_normalization": {"digits", "verbatim"},
+	"date_normalization":   {"digits", "verbatim"},
+	"unit_normalization":   {"symbols", "verbatim"},
+}
+
+// queryParams renders the options as their discrete query parameters.
+func (tp *TextProcessingOptions) queryParams() map[string]string {
+	return map[string]string{
+		"punctuation":          tp.Punctuation,
+		"paragraph_breaks":     tp.ParagraphBreaks,
+		"number_normalization": tp.NumberNormalization,
+		"date_normalization":   tp.DateNormalization,
+		"unit_normalization":   tp.UnitNormalization,
+	}
+}
+
+// validate rejects values outside textProcessingAllowed.
+func (tp *TextProcessingOptions) validate() error {
+	for param, value := range tp.queryParams() {
+		if value == "" {
+			continue
+		}
+		ok := false
+		for _, allowed := range textProcessingAllowed[param] {
+			if value == allowed {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return fmt.Errorf("voiceworld: invalid %s %q (allowed: %v)",
+				param, value, textProcessingAllowed[param])
+		}
+	}
+	return nil
+}
+
+// params renders the config as query parameters for the /asr endpoint.
+func (rc *RecognitionConfig) params() url.Values {
+	v := url.Values{}
+	if rc.Format != "" {
+		v.Set("format", rc.Format)
+	}
+	if rc.SampleRate > 0 {
+		v.Set("sample_rate", strconv.Itoa(rc.SampleRate))
+	}
+	if rc.RequestID != "" {
+		v.Set("request_id", rc.RequestID)
+	}
+	if rc.EnableLanguageDetection {
+		v.Set("enable_language_detection", "true")
+	}
+	if rc.EnableProfanityFilter {
+		v.Set("enable_profanity_filter", "true")
+	}
+	if rc.RemoveDisfluencies {
+		v.Set("remove_disfluencies", "true")
+	}
+	if rc.ModelID != "" {
+		v.Set("model_id", rc.ModelID)
+	}
+	if rc.EnableEmotionDetection {
+		v.Set("enable_emotion_detection", "true")
+	}
+	if tp := rc.resolvedTextProcessing(); tp != nil {
+		for param, value := range tp.queryParams() {
+			if value != "" {
+				v.Set(param, value)
+			}
+		}
+	}
+	return v
+}
+
+// resolvedTextProcessing returns the effective text processing options:
+// TextProcessing as-is when set, otherwise the expansion of the shorthand
+// booleans, otherwise nil.
+func (rc *RecognitionConfig) resolvedTextProcessing() *TextProcessingOptions {
+	if rc.TextProcessing != nil {
+		return rc.TextProcessing
+	}
+	if !rc.EnablePunctuation && !rc.EnableNormalization {
+		return nil
+	}
+	tp := &TextProcessingOptions{}
+	if rc.EnablePunctuation {
+		tp.Punctuation = "on"
+	}
+	if rc.EnableNormalization {
+		tp.NumberNormalization = "digits"
+		tp.DateNormalization = "digits"
+		tp.UnitNormalization = "symbols"
+	}
+	return tp
+}
+
+// validate rejects configurations the server would refuse, before any audio
+// is transmitted.
+func (rc *RecognitionConfig) validate() error {
+	if rc.TextProcessing != nil {
+		return rc.TextProcessing.validate()
+	}
+	return nil
+}
+
+// Word is one recognized token with its time span inside the audio.
+type Word struct {
+	Text       string    `json:"text"`
+	BeginTime  Timestamp `json:"begin_time"` // from audio start
+	EndTime    Timestamp `json:"end_time"`   // from audio start
+	Confidence float64   `json:"confidence"`
+	SpeakerID  string    `json:"speaker_id,omitempty"`
+}
+
+// Segment is one utterance-level span of the transcript. Servers return
+// segments for long-form audio; short utterances may carry none.
+type Segment struct {
+	Text      string    `json:"text"`
+	BeginTime Timestamp `json:"begin_time"` // from audio start
+	EndTime   Timestamp `json:"end_time"`   // from audio start
+	SpeakerID string    `json:"speaker_id,omitempty"`
+	// Confidence is the recognizer's certainty in (0, 1]; zero means the
+	// server did not report one.
+	Confidence float64 `json:"confidence,omitempty"`
+	// Language is the BCP 47 tag of this segment, set when language
+	// detection is enabled and the audio mixes languages.
+	Language string `json:"language,omitempty"`
+	// Emotion labels the dominant emotion of the segment ("neutral",
+	// "angry", "happy"), set when emotion detection is enabled and the
+	// server supports it.
+	Emotion           string  `json:"emotion,omitempty"`
+	EmotionConfidence float64 `json:"emotion_confidence,omitempty"`
+	// SpeechRateWPM is the speaking rate in words per minute.
+	SpeechRateWPM float64 `json:"speech_rate_wpm,omitempty"`
+}
+
+// RecognitionResult is the transcript for one piece of audio.
+type RecognitionResult struct {
+	TaskID string `json:"task_id"`
+	// Result is the full transcript text, after any server-side filtering.
+	Result string `json:"result"`
+	// RawResult is the unfiltered transcript, present only when a filter
+	// (profanity masking, disfluency removal) was applied and the server
+	// returns both texts.
+	RawResult string    `json:"raw_result,omitempty"`
+	Words     []Word    `json:"words,omitempty"`
+	Segments  []Segment `json:"segments,omitempty"`
+	// DetectedLanguage is the dominant language as a BCP 47 tag, set when
+	// language detection is enabled.
+	DetectedLanguage string `json:"detected_language,omitempty"`
+	// Duration is the recognized audio length.
+	Duration Timestamp `json:"duration"`
+
+	// Latency is how long the recognition request took end to end,
+	// measured by the client. It is populated client-side, never by the
+	// server, and is excluded from JSON so archived results stay pure
+	// server data.
+	Latency time.Duration `json:"-"`
+	// AudioDuration is the submitted audio's length as computed by the
+	// client - from the WAV header, or from the sample rate and byte
+	// count for raw PCM. Zero when the format does not let the client
+	// compute it.
+	AudioDuration time.Duration `json:"-"`
+	// RTF is the real-time factor Latency/AudioDuration, the number
+	// capacity planning cares about. Zero when AudioDuration is unknown.
+	RTF float64 `json:"-"`
+	// FromCache reports that this result was replayed from
+	// ClientConfig.DedupeStore instead of a fresh submission. Set
+	// client-side only, and excluded from JSON like the fields above.
+	FromCache bool `json:"-"`
+	// RawData is the undecoded envelope data, retained only when the
+	// response did not fully match the current schema, so fields an
+	// unknown schema generation moved or renamed stay reachable. Not
+	// re-serialized.
+	RawData json.RawMessage `json:"-"`
+}
+
+// RecognitionMetrics is the client-side timing of one recognition call,
+// delivered through ClientConfig.OnRecognition.
+type RecognitionMetrics struct {
+	// Format and SampleRate label the series. They echo the request
+	// configuration, with empty values resolved to the platform defaults
+	// ("wav", 16000).
+	Format     string
+	SampleRate int
+	// Latency, AudioDuration and RTF mirror the fields of the same name
+	// on RecognitionResult.
+	Latency       time.Duration
+	AudioDuration time.Duration
+	RTF           float64
+}
+
+// metricsFormat is the format label for metrics: the configured format, with
+// empty resolved to the platform default.
+func (rc *RecognitionConfig) metricsFormat() string {
+	if rc.Format == "" {
+		return "wav"
+	}
+	return rc.Format
+}
+
+// metricsSampleRate is the sample rate label for metrics: the configured
+// rate, with zero resolved to the platform default.
+func (rc *RecognitionConfig) metricsSampleRate() int {
+	if rc.SampleRate == 0 {
+		return targetSampleRate
+	}
+	return rc.SampleRate
+}
+
+// recognitionAudioDuration computes the length of the audio being submitted:
+// WAV from its header, raw PCM from the configured sample rate and the byte
+// count (16-bit mono, the platform layout). Other formats return zero and
+// the caller reports latency only. r is left at an unspecified offset.
+func recognitionAudioDuration(config *RecognitionConfig, r io.ReadSeeker, size int64) time.Duration {
+	switch config.metricsFormat() {
+	case "wav":
+		if r == nil {
+			return 0
+		}
+		info, err := ParseWAV(r)
+		if err != nil {
+			return 0
+		}
+		return time.Duration(info.Duration() * float64(time.Second))
+	case "pcm":
+		if size <= 0 {
+			return 0
+		}
+		bytesPerSecond := int64(config.metricsSampleRate()) * 2
+		return time.Duration(float64(size) / float64(bytesPerSecond) * float64(time.Second))
+	}
+	return 0
+}
+
+// finishRecognition stamps the client-side latency fields on result and
+// emits them through the OnRecognition hook.
+func (c *Client) finishRecognition(config *RecognitionConfig, result *RecognitionResult, begin time.Time, audio time.Duration) {
+	result.Latency = c.now().Sub(begin)
+	result.AudioDuration = audio
+	if audio > 0 {
+		result.RTF = float64(result.Latency) / float64(audio)
+	}
+	if c.config.OnRecognition != nil {
+		c.config.OnRecognition(RecognitionMetrics{
+			Format:        config.metricsFormat(),
+			SampleRate:    config.metricsSampleRate(),
+			Latency:       result.Latency,
+			AudioDuration: audio,
+			RTF:           result.RTF,
+		})
+	}
+}
+
+// ASR recognizes a local audio file by posting its bytes to the /asr
+// endpoint. The file is streamed directly from disk - it is never held in
+// memory - with Content-Length taken from the file size and Expect:
+// 100-continue set (unless disabled) so a rejected signature fails before
+// the audio is transmitted. config may be nil for platform defaults.
+func (c *Client) ASR(filePath string, config *RecognitionConfig) (*RecognitionResult, error) {
+	if config == nil {
+		config = &RecognitionConfig{}
+	}
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	// Telephony containers announce themselves in their magic bytes;
+	// servers that decode them natively need the format named, so sniff
+	// it when the caller left Format at the default.
+	if config.Format == "" {
+		if format, ferr := DetectAudioFormat(filePath); ferr == nil {
+			switch {
+			case isTelephonyFormat(format):
+				sniffed := *config
+				sniffed.Format = format
+				config = &sniffed
+			case format == "" && c.config.Strict:
+				return nil, fmt.Errorf("%w: %s matches no known container; set RecognitionConfig.Format", ErrAmbiguousFormat, filePath)
+			}
+		}
+	}
+	// Reject empty and header-only files before any network round trip: a
+	// sub-minimal body would otherwise be POSTed as-is and the failure
+	// would be whatever the server makes of an empty payload.
+	if fi, serr := os.Stat(longPath(filePath)); serr == nil {
+		format := config.Format
+		if format == "" {
+			// Format-less callers may legitimately send raw sample blobs,
+			// so only files that announce a known container are held to
+			// that container's minimum.
+			if sniffed, ferr := DetectAudioFormat(filePath); ferr == nil {
+				format = sniffed
+			}
+		}
+		if min := minAudioFileBytes(format); fi.Size() < min {
+			if format == "" {
+				return nil, invalidAudio("file is empty")
+			}
+			return nil, invalidAudio("file is %d bytes, smaller than the smallest valid %s audio (%d bytes)", fi.Size(), format, min)
+		}
+	}
+	var fingerprint string
+	if c.config.DedupeStore != nil {
+		if fp, ferr := recognitionFingerprint(filePath, config); ferr == nil {
+			fingerprint = fp
+			if cached := c.cachedRecognition(fingerprint); cached != nil {
+				return cached, nil
+			}
+		}
+	}
+	status, err := c.life.beginOp("recognize")
+	if err != nil {
+		return nil, err
+	}
+	defer c.life.endOp(status)
+	status.setRequestID(config.RequestID)
+	if fi, err := os.Stat(longPath(filePath)); err == nil {
+		status.setTotals(fi.Size(), 1)
+	}
+	if c.config.Recognizer != nil {
+		audio, err := os.ReadFile(longPath(filePath))
+		if err != nil {
+			return nil, fmt.Errorf("voiceworld: read audio: %w", err)
+		}
+		audioDur := recognitionAudioDuration(config, bytes.NewReader(audio), int64(len(audio)))
+		begin := c.now()
+		result, err := c.config.Recognizer.Recognize(context.Background(), audio, config)
+		if err != nil {
+			return nil, err
+		}
+		c.finishRecognition(config, result, begin, audioDur)
+		c.storeRecognition(fingerprint, result)
+		return result, nil
+	}
+	f, err := os.Open(longPath(filePath))
+	if err != nil {
+		return nil, fmt.Errorf("voiceworld: open audio: %w", err)
+	}
+	defer f.Close()
+	var audioDur time.Duration
+	if fi, err := f.Stat(); err == nil {
+		audioDur = recognitionAudioDuration(config, f, fi.Size())
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	var result RecognitionResult
+	begin := c.now()
+	if err := c.do(http.MethodPost, "/asr", config.params(), "application/octet-stream",
+		f, &result); err != nil {
+		return nil, modelSelectionError(err, config.ModelID)
+	}
+	c.finishRecognition(config, &result, begin, audioDur)
+	c.storeRecognition(fingerprint, &result)
+	return &result, nil
+}
+
+// ASRMultipart recognizes audio read from r by posting it to /asr as
+// multipart/form-data: a JSON "config" part carrying the recognition
+// parameters followed by an "audio" file part named filename. Deployments
+// fronted by gateways that reject raw octet-stream bodies use this instead
+// of ASR. The body is streamed through a pipe - the audio is never buffered
+// in memory - which also means a failed request is not retried, since a
+// pipe cannot be rewound. config may be nil for platform defaults.
+func (c *Client) ASRMultipart(r io.Reader, filename string, config *RecognitionConfig) (*RecognitionResult, error) {
+	if config == nil {
+		config = &RecognitionConfig{}
+	}
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	status, err := c.life.beginOp("recognize")
+	if err != nil {
+		return nil, err
+	}
+	defer c.life.endOp(status)
+	status.setRequestID(config.RequestID)
+
+	cfg := struct {
+		Format                  string `json:"format,omitempty"`
+		SampleRate              int    `json:"sample_rate,omitempty"`
+		RequestID               string `json:"request_id,omitempty"`
+		EnableLanguageDetection bool   `json:"enable_language_detection,omitempty"`
+		ModelID                 string `json:"model_id,omitempty"`
+		Punctuation             string `json:"punctuation,omitempty"`
+		ParagraphBreaks         string `json:"paragraph_breaks,omitempty"`
+		NumberNormalization     string `json:"number_normalization,omitempty"`
+		DateNormalization       string `json:"date_normalization,omitempty"`
+		UnitNormalization       string `json:"unit_normalization,omitempty"`
+	}{
+		Format:                  config.Format,
+		SampleRate:              config.SampleRate,
+		RequestID:               config.RequestID,
+		EnableLanguageDetection: config.EnableLanguageDetection,
+		ModelID:                 config.ModelID,
+	}
+	if tp := config.resolvedTextProcessing(); tp != nil {
+		cfg.Punctuation = tp.Punctuation
+		cfg.ParagraphBreaks = tp.ParagraphBreaks
+		cfg.NumberNormalization = tp.NumberNormalization
+		cfg.DateNormalization = tp.DateNormalization
+		cfg.UnitNormalization = tp.UnitNormalization
+	}
+	cfgJSON, err := json.Marshal(&cfg)
+	if err != nil {
+		return nil, fmt.Errorf("voiceworld: encode recognition config: %w", err)
+	}
+
+	// A seekable source lets the audio duration be measured up front;
+	// a plain stream reports latency only.
+	var audioDur time.Duration
+	if rs, ok := r.(io.ReadSeeker); ok {
+		if size, serr := rs.Seek(0, io.SeekEnd); serr == nil {
+			audioDur = recognitionAudioDuration(config, rs, size)
+			if _, serr := rs.Seek(0, io.SeekStart); serr != nil {
+				return nil, serr
+			}
+		}
+	}
+
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		werr := func() error {
+			header := textproto.MIMEHeader{}
+			header.Set("Content-Disposition", `form-data; name="config"`)
+			header.Set("Content-Type", "application/json")
+			cfgPart, err := mw.CreatePart(header)
+			if err != nil {
+				return err
+			}
+			if _, err := cfgPart.Write(cfgJSON); err != nil {
+				return err
+			}
+			audioPart, err := mw.CreateFormFile("audio", filename)
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(audioPart, r); err != nil {
+				return err
+			}
+			return mw.Close()
+		}()
+		pw.CloseWithError(werr)
+	}()
+
+	var result RecognitionResult
+	begin := c.now()
+	if err := c.do(http.MethodPost, "/asr", nil, mw.FormDataContentType(), pr, &result); err != nil {
+		return nil, modelSelectionError(err, config.ModelID)
+	}
+	c.finishRecognition(config, &result, begin, audioDur)
+	return &result, nil
+}
+
+// RecognizeURL recognizes audio that is already reachable by URL, typically
+// a signed URL returned by one of the upload helpers.
+func (c *Client) RecognizeURL(audioURL string, config *RecognitionConfig) (*RecognitionResult, error) {
+	if config == nil {
+		config = &RecognitionConfig{}
+	}
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	status, err := c.life.beginOp("recognize")
+	if err != nil {
+		return nil, err
+	}
+	defer c.life.endOp(status)
+	status.setRequestID(config.RequestID)
+	// URLs the SDK signed itself get a fresh signature just before the
+	// submission, so links minted long before the call do not arrive at
+	// the server already expired. Foreign URLs pass through untouched.
+	audioURL, resignKey := c.resignAudioURL(audioURL)
+	req := struct {
+		URL                     string `json:"url"`
+		Format                  string `json:"format,omitempty"`
+		SampleRate              int    `json:"sample_rate,omitempty"`
+		RequestID               string `json:"request_id,omitempty"`
+		EnableLanguageDetection bool   `json:"enable_language_detection,omitempty"`
+		ModelID                 string `json:"model_id,omitempty"`
+		Punctuation             string `json:"punctuation,omitempty"`
+		ParagraphBreaks         string `json:"paragraph_breaks,omitempty"`
+		NumberNormalization     string `json:"number_normalization,omitempty"`
+		DateNormalization       string `json:"date_normalization,omitempty"`
+		UnitNormalization       string `json:"unit_normalization,omitempty"`
+	}{
+		URL:                     audioURL,
+		Format:                  config.Format,
+		SampleRate:              config.SampleRate,
+		RequestID:               config.RequestID,
+		EnableLanguageDetection: config.EnableLanguageDetection,
+		ModelID:                 config.ModelID,
+	}
+	if tp := config.resolvedTextProcessing(); tp != nil {
+		req.Punctuation = tp.Punctuation
+		req.ParagraphBreaks = tp.ParagraphBreaks
+		req.NumberNormalization = tp.NumberNormalization
+		req.DateNormalization = tp.DateNormalization
+		req.UnitNormalization = tp.UnitNormalization
+	}
+	var result RecognitionResult
+	begin := c.now()
+	err = c.postJSON("/asr", &req, &result)
+	if err != nil && resignKey != "" && isAudioFetchDenied(err) {
+		// The signature may have expired while the job sat in the
+		// server's queue; sign once more and resubmit.
+		if fresh, key := c.resignAudioURL(req.URL); key != "" {
+			req.URL = fresh
+			err = c.postJSON("/asr", &req, &result)
+		}
+	}
+	if err != nil {
+		if isObjectArchived(err) {
+			return nil, fmt.Errorf("voiceworld: audio at %s: %w", audioURL, ErrObjectArchived)
+		}
+		return nil, modelSelectionError(err, config.ModelID)
+	}
+	// The audio is remote, so only latency is measurable client-side.
+	c.finishRecognition(config, &result, begin, 0)
+	return &result, nil
+}
@@ -0,0 +1,114 @@
+package voiceworld
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestRecognitionAudioDuration(t *testing.T) {
+	wav := makeWAVData(t, 16000, 1, 2)
+	tests := []struct {
+		name   string
+		config *RecognitionConfig
+		data   []byte
+		want   time.Duration
+	}{
+		{"wav header", &RecognitionConfig{Format: "wav"}, wav, 2 * time.Second},
+		{"default format is wav", &RecognitionConfig{}, wav, 2 * time.Second},
+		{"pcm arithmetic", &RecognitionConfig{Format: "pcm", SampleRate: 8000}, make([]byte, 8000*2), time.Second},
+		{"pcm default rate", &RecognitionConfig{Format: "pcm"}, make([]byte, 16000*2*3), 3 * time.Second},
+		{"unknown format", &RecognitionConfig{Format: "mp3"}, wav, 0},
+		{"garbage wav", &RecognitionConfig{Format: "wav"}, []byte("not audio"), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := recognitionAudioDuration(tt.config, bytes.NewReader(tt.data), int64(len(tt.data)))
+			if got != tt.want {
+				t.Errorf("recognitionAudioDuration = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestASRPopulatesLatencyMetrics(t *testing.T) {
+	server := newEnvelopeServer(t, `{"code":0,"data":{"result":"ok"}}`)
+	var metrics []RecognitionMetrics
+	client, err := NewClient("ak", "sk", &ClientConfig{
+		BaseURL:       server.URL,
+		OnRecognition: func(m RecognitionMetrics) { metrics = append(metrics, m) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := client.ASR(makeWAVFile(t, 16000, 1, 0.5), nil)
+	if err != nil {
+		t.Fatalf("ASR: %v", err)
+	}
+
+	if result.AudioDuration != 500*time.Millisecond {
+		t.Errorf("AudioDuration = %v, want 500ms", result.AudioDuration)
+	}
+	if result.Latency <= 0 {
+		t.Errorf("Latency = %v, want > 0", result.Latency)
+	}
+	if want := float64(result.Latency) / float64(result.AudioDuration); result.RTF != want {
+		t.Errorf("RTF = %v, want %v", result.RTF, want)
+	}
+	if len(metrics) != 1 {
+		t.Fatalf("got %d metrics callbacks, want 1", len(metrics))
+	}
+	m := metrics[0]
+	if m.Format != "wav" || m.SampleRate != 16000 {
+		t.Errorf("labels = %q/%d, want wav/16000", m.Format, m.SampleRate)
+	}
+	if m.Latency != result.Latency || m.AudioDuration != result.AudioDuration || m.RTF != result.RTF {
+		t.Errorf("hook metrics %+v disagree with result fields", m)
+	}
+}
+
+func TestASRMetricsUnknownFormatLatencyOnly(t *testing.T) {
+	server := newEnvelopeServer(t, `{"code":0,"data":{"result":"ok"}}`)
+	var metrics []RecognitionMetrics
+	client, err := NewClient("ak", "sk", &ClientConfig{
+		BaseURL:       server.URL,
+		OnRecognition: func(m RecognitionMetrics) { metrics = append(metrics, m) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := client.ASR(makeWAVFile(t, 16000, 1, 0.1),
+		&RecognitionConfig{Format: "opus", SampleRate: 8000})
+	if err != nil {
+		t.Fatalf("ASR: %v", err)
+	}
+	if result.AudioDuration != 0 || result.RTF != 0 {
+		t.Errorf("AudioDuration = %v, RTF = %v, want zero for an unmeasurable format",
+			result.AudioDuration, result.RTF)
+	}
+	if result.Latency <= 0 {
+		t.Errorf("Latency = %v, want > 0", result.Latency)
+	}
+	if len(metrics) != 1 || metrics[0].Format != "opus" || metrics[0].SampleRate != 8000 {
+		t.Errorf("metrics = %+v, want one entry labeled opus/8000", metrics)
+	}
+}
+
+func TestASRMultipartMeasuresSeekableSource(t *testing.T) {
+	server := newEnvelopeServer(t, `{"code":0,"data":{"result":"ok"}}`)
+	client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := makeWAVData(t, 16000, 1, 1)
+	result, err := client.ASRMultipart(bytes.NewReader(data), "audio.wav", nil)
+	if err != nil {
+		t.Fatalf("ASRMultipart: %v", err)
+	}
+	if result.AudioDuration != time.Second {
+		t.Errorf("AudioDuration = %v, want 1s from the seekable source", result.AudioDuration)
+	}
+	if result.Latency <= 0 {
+		t.Errorf("Latency = %v, want > 0", result.Latency)
+	}
+}
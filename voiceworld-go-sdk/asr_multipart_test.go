@@ -0,0 +1,147 @@
+package voiceworld
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// multipartRecording is what the fake server saw in one ASRMultipart request.
+type multipartRecording struct {
+	contentType string
+	partNames   []string
+	filename    string
+	configJSON  map[string]interface{}
+	audio       []byte
+}
+
+func newMultipartServer(t *testing.T, rec *multipartRecording) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec.contentType = r.Header.Get("Content-Type")
+		mediaType, params, err := mime.ParseMediaType(rec.contentType)
+		if err != nil || mediaType != "multipart/form-data" {
+			t.Errorf("Content-Type = %q: %v", rec.contentType, err)
+			http.Error(w, "bad content type", http.StatusBadRequest)
+			return
+		}
+		mr := multipart.NewReader(r.Body, params["boundary"])
+		for {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Errorf("read part: %v", err)
+				break
+			}
+			rec.partNames = append(rec.partNames, part.FormName())
+			switch part.FormName() {
+			case "config":
+				if ct := part.Header.Get("Content-Type"); ct != "application/json" {
+					t.Errorf("config part Content-Type = %q", ct)
+				}
+				if err := json.NewDecoder(part).Decode(&rec.configJSON); err != nil {
+					t.Errorf("decode config part: %v", err)
+				}
+			case "audio":
+				rec.filename = part.FileName()
+				rec.audio, _ = io.ReadAll(part)
+			}
+		}
+		w.Write([]byte(`{"code":0,"data":{"result":"你好"}}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestASRMultipartRequestShape(t *testing.T) {
+	var rec multipartRecording
+	server := newMultipartServer(t, &rec)
+	client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	audio := makeWAVData(t, 16000, 1, 0.5)
+	result, err := client.ASRMultipart(bytes.NewReader(audio), "meeting.wav", &RecognitionConfig{
+		Format:     "wav",
+		SampleRate: 16000,
+		RequestID:  "req-7",
+		ModelID:    "model-medical",
+	})
+	if err != nil {
+		t.Fatalf("ASRMultipart: %v", err)
+	}
+	if result.Result != "你好" {
+		t.Errorf("Result = %q", result.Result)
+	}
+
+	if !strings.HasPrefix(rec.contentType, "multipart/form-data; boundary=") {
+		t.Errorf("Content-Type = %q", rec.contentType)
+	}
+	if want := []string{"config", "audio"}; len(rec.partNames) != 2 ||
+		rec.partNames[0] != want[0] || rec.partNames[1] != want[1] {
+		t.Errorf("part names = %v, want %v", rec.partNames, want)
+	}
+	if rec.filename != "meeting.wav" {
+		t.Errorf("audio filename = %q", rec.filename)
+	}
+	if !bytes.Equal(rec.audio, audio) {
+		t.Errorf("audio part: got %d bytes, want %d intact", len(rec.audio), len(audio))
+	}
+	want := map[string]interface{}{
+		"format":      "wav",
+		"sample_rate": float64(16000),
+		"request_id":  "req-7",
+		"model_id":    "model-medical",
+	}
+	for key, value := range want {
+		if rec.configJSON[key] != value {
+			t.Errorf("config[%q] = %v, want %v", key, rec.configJSON[key], value)
+		}
+	}
+	for key := range rec.configJSON {
+		if _, ok := want[key]; !ok {
+			t.Errorf("config part carries unexpected field %q = %v", key, rec.configJSON[key])
+		}
+	}
+}
+
+func TestASRMultipartTextProcessing(t *testing.T) {
+	var rec multipartRecording
+	server := newMultipartServer(t, &rec)
+	client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.ASRMultipart(strings.NewReader("pcm"), "a.pcm", &RecognitionConfig{
+		EnablePunctuation:   true,
+		EnableNormalization: true,
+	})
+	if err != nil {
+		t.Fatalf("ASRMultipart: %v", err)
+	}
+	if rec.configJSON["punctuation"] != "on" {
+		t.Errorf("config[punctuation] = %v, want \"on\"", rec.configJSON["punctuation"])
+	}
+	if rec.configJSON["number_normalization"] != "digits" {
+		t.Errorf("config[number_normalization] = %v", rec.configJSON["number_normalization"])
+	}
+}
+
+func TestASRMultipartValidation(t *testing.T) {
+	client, _ := newTestClient(t, nil)
+	_, err := client.ASRMultipart(strings.NewReader(""), "a.wav", &RecognitionConfig{
+		TextProcessing: &TextProcessingOptions{Punctuation: "maybe"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "punctuation") {
+		t.Fatalf("err = %v, want punctuation validation failure", err)
+	}
+}
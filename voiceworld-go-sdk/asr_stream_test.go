@@ -0,0 +1,126 @@
+package voiceworld
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// headerSpy records request headers on their way to the wire, after net/http
+// and the SDK have finished with them.
+type headerSpy struct {
+	inner  http.RoundTripper
+	expect []string
+	length []int64
+}
+
+func (s *headerSpy) RoundTrip(req *http.Request) (*http.Response, error) {
+	s.expect = append(s.expect, req.Header.Get("Expect"))
+	s.length = append(s.length, req.ContentLength)
+	return s.inner.RoundTrip(req)
+}
+
+func discardingASRServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := io.Copy(io.Discard, r.Body); err != nil {
+			t.Errorf("server read body: %v", err)
+		}
+		fmt.Fprint(w, `{"code":0,"message":"ok","data":{"task_id":"t","result":"x"}}`)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestASRStreamsWithContentLengthAndExpectContinue(t *testing.T) {
+	srv := discardingASRServer(t)
+	spy := &headerSpy{inner: http.DefaultTransport}
+	client, err := NewClient("ak", "sk", &ClientConfig{
+		BaseURL:    srv.URL,
+		HTTPClient: &http.Client{Transport: spy},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "a.pcm")
+	payload := make([]byte, 100000)
+	if err := os.WriteFile(path, payload, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ASR(path, nil); err != nil {
+		t.Fatalf("ASR: %v", err)
+	}
+	if len(spy.length) != 1 || spy.length[0] != int64(len(payload)) {
+		t.Errorf("ContentLength = %v, want [%d]", spy.length, len(payload))
+	}
+	if spy.expect[0] != "100-continue" {
+		t.Errorf("Expect header = %q, want 100-continue", spy.expect[0])
+	}
+}
+
+func TestASRDisableExpectContinue(t *testing.T) {
+	srv := discardingASRServer(t)
+	spy := &headerSpy{inner: http.DefaultTransport}
+	client, err := NewClient("ak", "sk", &ClientConfig{
+		BaseURL:               srv.URL,
+		HTTPClient:            &http.Client{Transport: spy},
+		DisableExpectContinue: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "a.pcm")
+	if err := os.WriteFile(path, []byte("tiny"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ASR(path, nil); err != nil {
+		t.Fatal(err)
+	}
+	if spy.expect[0] != "" {
+		t.Errorf("Expect header = %q, want unset", spy.expect[0])
+	}
+}
+
+// TestASRConstantMemory streams a large sparse file and asserts the heap
+// never sees more than a sliver of it.
+func TestASRConstantMemory(t *testing.T) {
+	const fileSize = 256 << 20 // sparse, so cheap to create
+
+	srv := discardingASRServer(t)
+	client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: srv.URL, Timeout: 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "huge.pcm")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Truncate(fileSize); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	if _, err := client.ASR(path, &RecognitionConfig{Format: "pcm", SampleRate: 16000}); err != nil {
+		t.Fatalf("ASR: %v", err)
+	}
+	runtime.ReadMemStats(&after)
+
+	allocated := int64(after.TotalAlloc - before.TotalAlloc)
+	if allocated > 16<<20 {
+		t.Errorf("streaming a %d MB file allocated %d MB; body is being buffered",
+			fileSize>>20, allocated>>20)
+	}
+}
@@ -0,0 +1,322 @@
+package voiceworld
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecognitionConfigParams(t *testing.T) {
+	config := &RecognitionConfig{
+		Format:                  "wav",
+		SampleRate:              16000,
+		RequestID:               "req-1",
+		EnableLanguageDetection: true,
+		EnableProfanityFilter:   true,
+		RemoveDisfluencies:      true,
+	}
+	want := url.Values{
+		"format":                    {"wav"},
+		"sample_rate":               {"16000"},
+		"request_id":                {"req-1"},
+		"enable_language_detection": {"true"},
+		"enable_profanity_filter":   {"true"},
+		"remove_disfluencies":       {"true"},
+	}
+	if got := config.params(); got.Encode() != want.Encode() {
+		t.Errorf("params() = %v, want %v", got, want)
+	}
+	if got := (&RecognitionConfig{}).params(); len(got) != 0 {
+		t.Errorf("zero config params() = %v, want empty", got)
+	}
+}
+
+// TestRawResultDecoding covers servers that return both filtered and raw
+// transcripts and servers that return only one.
+func TestRawResultDecoding(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		body      string
+		result    string
+		rawResult string
+	}{
+		{
+			name:      "both texts",
+			body:      `{"code":0,"data":{"result":"that is *** rude","raw_result":"that is damn rude"}}`,
+			result:    "that is *** rude",
+			rawResult: "that is damn rude",
+		},
+		{
+			name:   "filtered only",
+			body:   `{"code":0,"data":{"result":"hello"}}`,
+			result: "hello",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newEnvelopeServer(t, tc.body)
+			client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: server.URL})
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := client.ASR(makeWAVFile(t, 16000, 1, 0.1),
+				&RecognitionConfig{EnableProfanityFilter: true})
+			if err != nil {
+				t.Fatalf("ASR: %v", err)
+			}
+			if result.Result != tc.result || result.RawResult != tc.rawResult {
+				t.Errorf("Result = %q, RawResult = %q", result.Result, result.RawResult)
+			}
+		})
+	}
+}
+
+// TestSegmentEmotionDecoding covers both server generations: one that tags
+// emotions and one that predates the fields.
+func TestSegmentEmotionDecoding(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		body    string
+		emotion string
+		wpm     float64
+	}{
+		{
+			name: "tagging server",
+			body: `{"code":0,"data":{"result":"ok","segments":[{"text":"ok","begin_time":0,` +
+				`"end_time":300,"emotion":"happy","emotion_confidence":0.83,"speech_rate_wpm":162.5}]}}`,
+			emotion: "happy",
+			wpm:     162.5,
+		},
+		{
+			name: "older server",
+			body: `{"code":0,"data":{"result":"ok","segments":[{"text":"ok","begin_time":0,"end_time":300}]}}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newEnvelopeServer(t, tc.body)
+			client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: server.URL})
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := client.ASR(makeWAVFile(t, 16000, 1, 0.1),
+				&RecognitionConfig{EnableEmotionDetection: true})
+			if err != nil {
+				t.Fatalf("ASR: %v", err)
+			}
+			if len(result.Segments) != 1 {
+				t.Fatalf("Segments = %+v", result.Segments)
+			}
+			seg := result.Segments[0]
+			if seg.Emotion != tc.emotion || seg.SpeechRateWPM != tc.wpm {
+				t.Errorf("segment = %+v", seg)
+			}
+		})
+	}
+}
+
+func TestMergeResults(t *testing.T) {
+	merged := MergeResults([]*RecognitionResult{
+		{
+			TaskID:   "t1",
+			Result:   "第一段",
+			Duration: ms(60000),
+			Segments: []Segment{{Text: "第一段", BeginTime: ms(0), EndTime: ms(2500),
+				Emotion: "neutral", EmotionConfidence: 0.7, SpeechRateWPM: 140}},
+			Words: []Word{{Text: "第一段", BeginTime: ms(0), EndTime: ms(2500)}},
+		},
+		nil,
+		{
+			Result:   "第二段",
+			Duration: ms(30000),
+			Segments: []Segment{{Text: "第二段", BeginTime: ms(100), EndTime: ms(2000), Emotion: "angry"}},
+		},
+	})
+	if merged.TaskID != "t1" || merged.Result != "第一段 第二段" {
+		t.Errorf("merged = %+v", merged)
+	}
+	if merged.Duration != ms(90000) {
+		t.Errorf("Duration = %v", merged.Duration)
+	}
+	if len(merged.Segments) != 2 {
+		t.Fatalf("Segments = %+v", merged.Segments)
+	}
+	if s := merged.Segments[1]; s.BeginTime != ms(60100) || s.EndTime != ms(62000) || s.Emotion != "angry" {
+		t.Errorf("second segment = %+v", s)
+	}
+	if s := merged.Segments[0]; s.EmotionConfidence != 0.7 || s.SpeechRateWPM != 140 {
+		t.Errorf("first segment lost annotations: %+v", s)
+	}
+	if len(merged.Words) != 1 || merged.Words[0].EndTime != ms(2500) {
+		t.Errorf("Words = %+v", merged.Words)
+	}
+}
+
+func TestTextProcessingParams(t *testing.T) {
+	config := &RecognitionConfig{TextProcessing: &TextProcessingOptions{
+		Punctuation:         "on",
+		ParagraphBreaks:     "off",
+		NumberNormalization: "digits",
+		DateNormalization:   "verbatim",
+		UnitNormalization:   "symbols",
+	}}
+	got := config.params()
+	for param, want := range map[string]string{
+		"punctuation":          "on",
+		"paragraph_breaks":     "off",
+		"number_normalization": "digits",
+		"date_normalization":   "verbatim",
+		"unit_normalization":   "symbols",
+	} {
+		if got.Get(param) != want {
+			t.Errorf("%s = %q, want %q", param, got.Get(param), want)
+		}
+	}
+}
+
+func TestTextProcessingShorthands(t *testing.T) {
+	got := (&RecognitionConfig{EnablePunctuation: true, EnableNormalization: true}).params()
+	for param, want := range map[string]string{
+		"punctuation":          "on",
+		"number_normalization": "digits",
+		"date_normalization":   "digits",
+		"unit_normalization":   "symbols",
+	} {
+		if got.Get(param) != want {
+			t.Errorf("%s = %q, want %q", param, got.Get(param), want)
+		}
+	}
+	if got.Get("paragraph_breaks") != "" {
+		t.Errorf("paragraph_breaks = %q, want unset", got.Get("paragraph_breaks"))
+	}
+
+	// An explicit struct takes precedence over the shorthands.
+	got = (&RecognitionConfig{
+		EnableNormalization: true,
+		TextProcessing:      &TextProcessingOptions{Punctuation: "off"},
+	}).params()
+	if got.Get("number_normalization") != "" || got.Get("punctuation") != "off" {
+		t.Errorf("params = %v, want only punctuation=off", got)
+	}
+}
+
+func TestTextProcessingValidation(t *testing.T) {
+	client, _ := newTestClient(t, nil)
+	_, err := client.ASR("ignored.wav", &RecognitionConfig{
+		TextProcessing: &TextProcessingOptions{NumberNormalization: "roman"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "number_normalization") {
+		t.Fatalf("err = %v, want number_normalization validation failure", err)
+	}
+}
+
+// TestMergeResultsSpeakerReconciliation feeds three independently diarized
+// chunks with known ground truth: chunk 2 swaps its local labels but shares
+// an overlap word with chunk 1, and chunk 3 has no overlap so speakers are
+// matched by speaking-time rank.
+func TestMergeResultsSpeakerReconciliation(t *testing.T) {
+	merged := MergeResults([]*RecognitionResult{
+		{
+			Duration: ms(10000),
+			Segments: []Segment{
+				{Text: "大家好", BeginTime: ms(0), EndTime: ms(4000), SpeakerID: "1"},
+				{Text: "你好", BeginTime: ms(4000), EndTime: ms(8500), SpeakerID: "2"},
+				// Runs past the chunk boundary: the overlap region.
+				{Text: "下面交给你", BeginTime: ms(9000), EndTime: ms(11000), SpeakerID: "1"},
+			},
+		},
+		{
+			Duration: ms(8000),
+			Segments: []Segment{
+				// Same handover words, attributed to local "2" here.
+				{Text: "下面交给你", BeginTime: ms(0), EndTime: ms(1000), SpeakerID: "2"},
+				{Text: "谢谢", BeginTime: ms(1000), EndTime: ms(5000), SpeakerID: "1"},
+				{Text: "继续", BeginTime: ms(5000), EndTime: ms(6000), SpeakerID: "2"},
+			},
+		},
+		{
+			Duration: ms(6000),
+			Segments: []Segment{
+				{Text: "总结", BeginTime: ms(0), EndTime: ms(5000), SpeakerID: "x"},
+				{Text: "补充", BeginTime: ms(5000), EndTime: ms(6000), SpeakerID: "y"},
+			},
+		},
+	})
+
+	want := []string{"S1", "S2", "S1", "S1", "S2", "S1", "S2", "S1"}
+	if len(merged.Segments) != len(want) {
+		t.Fatalf("got %d segments", len(merged.Segments))
+	}
+	for i, seg := range merged.Segments {
+		if seg.SpeakerID != want[i] {
+			t.Errorf("segment %d (%q): speaker %q, want %q", i, seg.Text, seg.SpeakerID, want[i])
+		}
+	}
+
+	if len(merged.SpeakerMap) != 3 {
+		t.Fatalf("SpeakerMap = %+v", merged.SpeakerMap)
+	}
+	if m := merged.SpeakerMap[1]; m["2"] != "S1" || m["1"] != "S2" {
+		t.Errorf("chunk 2 mapping = %v, want 2->S1 1->S2", m)
+	}
+	if m := merged.SpeakerMap[2]; m["x"] != "S2" || m["y"] != "S1" {
+		t.Errorf("chunk 3 mapping = %v, want x->S2 y->S1", m)
+	}
+}
+
+// TestASRRejectsTinyFiles covers the sizes around the minimal valid wav: the
+// 44-byte header alone holds no audio and must be rejected before any
+// network call, while one frame more is the server's problem.
+func TestASRRejectsTinyFiles(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"code":0,"data":{"result":"ok"}}`))
+	}))
+	t.Cleanup(server.Close)
+	client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A valid header for 8-bit mono audio followed by one 1-byte frame.
+	var full bytes.Buffer
+	if err := writeWAVHeader(&full, &WAVInfo{NumChannels: 1, SampleRate: 16000, BitsPerSample: 8}, 1); err != nil {
+		t.Fatal(err)
+	}
+	full.WriteByte(0x80)
+
+	for _, tc := range []struct {
+		bytes int
+		ok    bool
+	}{
+		{0, false},
+		{43, false},
+		{44, false},
+		{45, true},
+	} {
+		path := filepath.Join(t.TempDir(), fmt.Sprintf("tiny%d.wav", tc.bytes))
+		if err := os.WriteFile(path, full.Bytes()[:tc.bytes], 0o644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := client.ASR(path, nil)
+		if tc.ok {
+			if err != nil {
+				t.Errorf("%d bytes: ASR: %v", tc.bytes, err)
+			}
+			continue
+		}
+		var verr *AudioValidationError
+		if !errors.As(err, &verr) {
+			t.Errorf("%d bytes: err = %v, want *AudioValidationError", tc.bytes, err)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("server saw %d requests, want only the 45-byte file", requests)
+	}
+}
@@ -0,0 +1,120 @@
+package voiceworld
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Pinned signatures for fixed inputs, so a change to the canonical string
+// cannot slip through unnoticed: every deployed server verifies these.
+func TestSignaturePinnedOutputs(t *testing.T) {
+	client, err := NewClient("test-app-key", "test-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v1 := client.signature("POST", "/asr", "1700000000", "nonce123")
+	if want := "86fbf83f421b7d59181ca4458c19606338b467cd3be1c77715a709654b4b4ef0"; v1 != want {
+		t.Errorf("v1 signature = %s, want %s", v1, want)
+	}
+	sum := sha256.Sum256([]byte("hello audio"))
+	v2 := client.signatureV2("POST", "/asr", "1700000000", "nonce123", hex.EncodeToString(sum[:]))
+	if want := "87bcec53405a3c2d5657c8145c57b93ed8b6737ccbd07b96ba0b9fc19b842455"; v2 != want {
+		t.Errorf("v2 signature = %s, want %s", v2, want)
+	}
+}
+
+func TestHashBody(t *testing.T) {
+	hash, err := hashBody(nil)
+	if err != nil {
+		t.Fatalf("hashBody(nil): %v", err)
+	}
+	empty := sha256.Sum256(nil)
+	if hash != hex.EncodeToString(empty[:]) {
+		t.Errorf("nil body hash = %s, want the empty hash", hash)
+	}
+
+	r := bytes.NewReader([]byte("hello audio"))
+	hash, err = hashBody(r)
+	if err != nil {
+		t.Fatalf("hashBody: %v", err)
+	}
+	sum := sha256.Sum256([]byte("hello audio"))
+	if hash != hex.EncodeToString(sum[:]) {
+		t.Errorf("body hash = %s", hash)
+	}
+	// The body must be rewound for the actual send.
+	rest, _ := io.ReadAll(r)
+	if string(rest) != "hello audio" {
+		t.Errorf("body after hashing = %q, want it rewound", rest)
+	}
+
+	if _, err := hashBody(io.LimitReader(r, 1)); err == nil {
+		t.Error("expected error for an unrewindable body")
+	}
+}
+
+// newVerifyingServer recomputes the version 2 signature from the request it
+// received and fails the test on any mismatch.
+func newVerifyingServer(t *testing.T, respBody string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if v := r.Header.Get(headerSignatureVersion); v != signatureVersion2 {
+			t.Errorf("signature version = %q, want %q", v, signatureVersion2)
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("read body: %v", err)
+		}
+		sum := sha256.Sum256(body)
+		verifier := &Client{appKey: "test-app-key", secretKey: "test-secret"}
+		want := verifier.signatureV2(r.Method, r.URL.Path,
+			r.Header.Get(headerTimestamp), r.Header.Get(headerNonce), hex.EncodeToString(sum[:]))
+		if got := r.Header.Get(headerSignature); got != want {
+			t.Errorf("signature = %s, want %s (body %d bytes)", got, want, len(body))
+		}
+		w.Write([]byte(respBody))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSignRequestBodyJSON(t *testing.T) {
+	server := newVerifyingServer(t, `{"code":0,"data":{"url":"https://example.com/out.wav"}}`)
+	client, err := NewClient("test-app-key", "test-secret", &ClientConfig{
+		BaseURL: server.URL, SignRequestBody: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.PreprocessAudio("https://example.com/in.wav", "req-1"); err != nil {
+		t.Fatalf("PreprocessAudio: %v", err)
+	}
+}
+
+func TestSignRequestBodyStreamedFile(t *testing.T) {
+	server := newVerifyingServer(t, `{"code":0,"data":{"task_id":"t1","result":"ok"}}`)
+	client, err := NewClient("test-app-key", "test-secret", &ClientConfig{
+		BaseURL: server.URL, SignRequestBody: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "audio.pcm")
+	if err := os.WriteFile(path, bytes.Repeat([]byte{7}, 4096), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	result, err := client.ASR(path, nil)
+	if err != nil {
+		t.Fatalf("ASR: %v", err)
+	}
+	if result.Result != "ok" {
+		t.Errorf("result = %+v", result)
+	}
+}
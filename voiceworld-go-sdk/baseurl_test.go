@@ -0,0 +1,61 @@
+package voiceworld
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestBaseURLNormalization(t *testing.T) {
+	cases := []struct {
+		name string
+		base string
+		want string // exact URL for the /asr endpoint
+	}{
+		{"bare host", "http://api.example.com", "http://api.example.com/asr"},
+		{"trailing slash", "http://api.example.com/v1/", "http://api.example.com/v1/asr"},
+		{"many trailing slashes", "http://api.example.com/v1///", "http://api.example.com/v1/asr"},
+		{"path prefix", "https://gw.example.com/tenant/a", "https://gw.example.com/tenant/a/asr"},
+		{"https with port", "https://api.example.com:8443/v1", "https://api.example.com:8443/v1/asr"},
+		{"ipv6 host", "http://[::1]:8080", "http://[::1]:8080/asr"},
+		{"ipv6 with path", "http://[2001:db8::2]/v1/", "http://[2001:db8::2]/v1/asr"},
+	}
+	for _, tc := range cases {
+		client, err := NewClient("test-app-key", "test-secret", &ClientConfig{BaseURL: tc.base})
+		if err != nil {
+			t.Errorf("%s: NewClient(%q): %v", tc.name, tc.base, err)
+			continue
+		}
+		req, err := client.NewSignedRequest(context.Background(), http.MethodPost, "/asr", nil, "")
+		if err != nil {
+			t.Errorf("%s: NewSignedRequest: %v", tc.name, err)
+			continue
+		}
+		if got := req.URL.String(); got != tc.want {
+			t.Errorf("%s: endpoint URL = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestBaseURLValidation(t *testing.T) {
+	cases := []struct {
+		name string
+		base string
+	}{
+		{"missing scheme", "api.example.com/v1"},
+		{"unsupported scheme", "ftp://api.example.com"},
+		{"no host", "http:///v1"},
+		{"unparsable", "http://[::1"},
+	}
+	for _, tc := range cases {
+		_, err := NewClient("test-app-key", "test-secret", &ClientConfig{BaseURL: tc.base})
+		if err == nil {
+			t.Errorf("%s: NewClient(%q) accepted an invalid base", tc.name, tc.base)
+			continue
+		}
+		if !strings.Contains(err.Error(), "BaseURL") {
+			t.Errorf("%s: error %q does not mention BaseURL", tc.name, err)
+		}
+	}
+}
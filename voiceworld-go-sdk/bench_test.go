@@ -0,0 +1,197 @@
+package voiceworld
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+// Benchmarks for the hot paths: audio preprocessing, split planning plus
+// chunk streaming, and the resampler. All of them run against the in-memory
+// storage fake so the numbers measure SDK code, not the network.
+//
+// The allocation budget for splitting is asserted in
+// TestSplitAllocationBudget below so regressions fail CI rather than only
+// showing up in benchmark diffs.
+
+var (
+	benchWAVOnce sync.Once
+	benchWAVPath string
+)
+
+// benchWAV lazily builds a ~100 MB 16 kHz mono WAV shared by the benchmarks.
+func benchWAV(tb testing.TB) string {
+	benchWAVOnce.Do(func() {
+		dir, err := os.MkdirTemp("", "voiceworld-bench")
+		if err != nil {
+			tb.Fatalf("bench fixture dir: %v", err)
+		}
+		path := filepath.Join(dir, "bench.wav")
+		f, err := os.Create(path)
+		if err != nil {
+			tb.Fatalf("bench fixture: %v", err)
+		}
+		info := &WAVInfo{NumChannels: 1, SampleRate: 16000, BitsPerSample: 16}
+		const seconds = 3200 // ~100 MiB at 32 kB/s
+		dataSize := int64(seconds * info.BytesPerSecond())
+		if err := writeWAVHeader(f, info, dataSize); err != nil {
+			tb.Fatal(err)
+		}
+		buf := make([]byte, 1<<20)
+		for i := range buf {
+			buf[i] = byte(i * 31)
+		}
+		for written := int64(0); written < dataSize; written += int64(len(buf)) {
+			n := int64(len(buf))
+			if dataSize-written < n {
+				n = dataSize - written
+			}
+			if _, err := f.Write(buf[:n]); err != nil {
+				tb.Fatal(err)
+			}
+		}
+		if err := f.Close(); err != nil {
+			tb.Fatal(err)
+		}
+		benchWAVPath = path
+	})
+	return benchWAVPath
+}
+
+func BenchmarkProcessAudio(b *testing.B) {
+	path := benchWAV(b)
+	fi, err := os.Stat(path)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(fi.Size())
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		out, err := ProcessAudio(path)
+		if err != nil {
+			b.Fatal(err)
+		}
+		os.Remove(out)
+	}
+}
+
+func BenchmarkSplitAudioFile(b *testing.B) {
+	path := benchWAV(b)
+	fi, err := os.Stat(path)
+	if err != nil {
+		b.Fatal(err)
+	}
+	store := NewMemoryStorage()
+	client, err := NewClient("ak", "sk", &ClientConfig{Storage: store, Parallelism: 4})
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(fi.Size())
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		result, err := client.SplitAudioFile(path, 60)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.StopTimer()
+		var keys []string
+		for _, p := range result.Parts {
+			keys = append(keys, p.ObjectKey)
+		}
+		if err := store.DeleteObjects(keys); err != nil {
+			b.Fatal(err)
+		}
+		b.StartTimer()
+	}
+}
+
+// BenchmarkProcessAndUploadFile measures the convert→upload pipeline. With
+// conversion streaming into the multipart uploader the wall clock should sit
+// near max(convert, upload) rather than their sum; compare against
+// BenchmarkProcessAudio to see the conversion-only cost.
+func BenchmarkProcessAndUploadFile(b *testing.B) {
+	path := benchWAV(b)
+	fi, err := os.Stat(path)
+	if err != nil {
+		b.Fatal(err)
+	}
+	store := NewMemoryStorage()
+	client, err := NewClient("ak", "sk", &ClientConfig{Storage: store, Parallelism: 4})
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(fi.Size())
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		result, err := client.ProcessAndUploadFile(path, "bench")
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.StopTimer()
+		if err := store.DeleteObjects([]string{result.ProcessedKey}); err != nil {
+			b.Fatal(err)
+		}
+		b.StartTimer()
+	}
+}
+
+func BenchmarkPlanChunks(b *testing.B) {
+	info := &WAVInfo{NumChannels: 1, SampleRate: 16000, BitsPerSample: 16, DataOffset: 44, DataSize: 100 << 20}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := planChunks(info, 60); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkResampleLinear(b *testing.B) {
+	in := make([]int16, 44100*10) // 10 s at 44.1 kHz
+	for i := range in {
+		in[i] = int16(i % 2048)
+	}
+	b.SetBytes(int64(len(in) * 2))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		resampleLinear(in, 44100, 16000)
+	}
+}
+
+// TestSplitAllocationBudget pins the allocation behaviour of the split path.
+// Splitting streams each chunk once through a copy buffer and hands it to the
+// storage backend, whose in-memory fake retains one growth-doubled copy; that
+// puts the honest total under 5x the audio payload. The budget exists to
+// catch the expensive regressions - per-part full-file buffers or quadratic
+// reassembly - which blow well past it.
+func TestSplitAllocationBudget(t *testing.T) {
+	path := makeWAVFile(t, 16000, 1, 10) // 320 kB of PCM
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, _ := newTestClient(t, nil)
+
+	// Warm up caches and lazily-initialized state.
+	if _, err := client.SplitAudioFile(path, 2); err != nil {
+		t.Fatalf("SplitAudioFile: %v", err)
+	}
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	if _, err := client.SplitAudioFile(path, 2); err != nil {
+		t.Fatal(err)
+	}
+	runtime.ReadMemStats(&after)
+
+	allocated := int64(after.TotalAlloc - before.TotalAlloc)
+	budget := 5*fi.Size() + 256<<10 // see doc comment above
+	if allocated > budget {
+		t.Errorf("split of %d bytes allocated %d bytes, budget %d", fi.Size(), allocated, budget)
+	}
+}
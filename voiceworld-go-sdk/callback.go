@@ -0,0 +1,184 @@
+package voiceworld
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// OSSCallback has the object store itself notify a server when an upload
+// lands, so the backend does not have to trust the client to report. It is
+// translated into the base64-encoded x-oss-callback header on PutObject and
+// CompleteMultipartUpload.
+type OSSCallback struct {
+	// URL receives the callback POST from OSS. Required.
+	URL string
+
+	// Host overrides the Host header of the callback request. Optional.
+	Host string
+
+	// Body is the callback body template. Required. OSS expands
+	// ${object} and ${size} server-side; the SDK expands ${requestID}
+	// to the first path segment of the object key before the upload.
+	Body string
+
+	// BodyType is the Content-Type of the callback body:
+	// application/x-www-form-urlencoded (the OSS default when empty) or
+	// application/json.
+	BodyType string
+}
+
+// validate rejects callback configs OSS would refuse, before any bytes are
+// uploaded.
+func (cb *OSSCallback) validate() error {
+	u, err := url.Parse(cb.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("voiceworld: callback URL %q must be absolute http(s)", cb.URL)
+	}
+	if cb.Body == "" {
+		return fmt.Errorf("voiceworld: callback body template is required")
+	}
+	switch cb.BodyType {
+	case "", "application/x-www-form-urlencoded", "application/json":
+		return nil
+	default:
+		return fmt.Errorf("voiceworld: callback body type %q is not supported by OSS", cb.BodyType)
+	}
+}
+
+// encode renders the x-oss-callback header value for one object.
+func (cb *OSSCallback) encode(objectKey string) string {
+	body := strings.ReplaceAll(cb.Body, "${requestID}", requestIDFromKey(objectKey))
+	payload := struct {
+		URL      string `json:"callbackUrl"`
+		Host     string `json:"callbackHost,omitempty"`
+		Body     string `json:"callbackBody"`
+		BodyType string `json:"callbackBodyType,omitempty"`
+	}{cb.URL, cb.Host, body, cb.BodyType}
+	raw, _ := json.Marshal(payload)
+	return base64.StdEncoding.EncodeToString(raw)
+}
+
+// requestIDFromKey recovers the request prefix from an object key laid out
+// as "<requestID>/...". Keys without a prefix yield the empty string.
+func requestIDFromKey(key string) string {
+	if i := strings.IndexByte(key, '/'); i >= 0 {
+		return key[:i]
+	}
+	return ""
+}
+
+// UploadOptions carries the optional knobs for UploadFile and
+// MultipartUploadFile. Nil and the zero value keep the historical
+// behaviour.
+type UploadOptions struct {
+	// Callback, when set, asks the object store to notify the configured
+	// server once the object is stored. The store's response to that
+	// callback lands on the OperationReport as CallbackResponse. The
+	// storage backend must support callbacks (OSS does; MemoryStorage
+	// and the fs backend do not).
+	Callback *OSSCallback
+
+	// UploadOriginal also stages the untouched source file alongside the
+	// processed output, under ClientConfig.RawPrefix. Only
+	// ProcessAndUploadFile consults it.
+	UploadOriginal bool
+
+	// Source attaches source-file audit metadata (base name, size,
+	// modification time) to the uploaded object, when the backend supports
+	// object metadata; see SourceInfo. Capture it with SourceInfoFor, or
+	// construct it directly when the uploaded file is a converted copy of
+	// the real source. Nil records nothing.
+	Source *SourceInfo
+
+	// Checksum selects a digest to compute while the file streams to the
+	// store — the file is not read a second time. The algorithm and value
+	// land on the OperationReport and, when the backend supports object
+	// metadata, on the object as "checksum-<algorithm>" (surfacing as
+	// x-oss-meta-checksum-<algorithm> on OSS). Supported by UploadFile
+	// only. Nil records nothing.
+	Checksum Checksummer
+
+	// IfNoneMatch refuses to replace an existing object: the store is
+	// asked to forbid overwrites and the upload fails with
+	// ErrObjectAlreadyExists when the key is already taken. Without it an
+	// upload silently overwrites. Supported by UploadFile only; it cannot
+	// be combined with Callback or used with MultipartUploadFile.
+	IfNoneMatch bool
+
+	// RejectIf analyzes the audio before the upload starts and fails fast
+	// with an *AudioQualityError when a threshold is violated, so clipped
+	// or near-silent recordings never reach transcription spend. The file
+	// must be 16-bit PCM WAV when set. ProcessAndUploadFile additionally
+	// attaches the analysis to its result; see AnalyzeAudio. Nil skips
+	// the analysis pass.
+	RejectIf *AudioQualityPolicy
+
+	// MaxObjectBytes caps the size of any single staged object, for
+	// buckets whose policy enforces a maximum object size. When the
+	// processed output would exceed it, ProcessAndUploadFile falls back
+	// to SplitAudioFile under the same request id and returns the chunk
+	// keys on UploadResult.Parts instead of one processed object. Zero
+	// means no cap. Only ProcessAndUploadFile consults it.
+	MaxObjectBytes int64
+
+	// FailInsteadOfSplit turns the splitting fallback into a failure: an
+	// over-cap processed output returns an *ObjectTooLargeError instead,
+	// for callers that require exactly one staged object.
+	FailInsteadOfSplit bool
+
+	// ProbePermissions writes and deletes a zero-byte probe object under
+	// the target prefix before the upload starts, so a mis-scoped STS
+	// policy fails in two cheap requests instead of after minutes of
+	// preprocessing and transfer. A failed probe surfaces as a
+	// *PermissionProbeError naming the missing permission. Off by default
+	// because of those two extra requests.
+	ProbePermissions bool
+}
+
+// uploadOptions validates the variadic options of an upload entry point.
+func uploadOptions(options []*UploadOptions) (*UploadOptions, error) {
+	if len(options) > 1 {
+		return nil, fmt.Errorf("voiceworld: at most one UploadOptions may be given, got %d", len(options))
+	}
+	opts := &UploadOptions{}
+	if len(options) == 1 && options[0] != nil {
+		opts = options[0]
+	}
+	if opts.Callback != nil {
+		if err := opts.Callback.validate(); err != nil {
+			return nil, err
+		}
+		if opts.IfNoneMatch {
+			return nil, fmt.Errorf("voiceworld: IfNoneMatch cannot be combined with Callback")
+		}
+	}
+	return opts, nil
+}
+
+// callbackStorage is the optional ObjectStorage capability for attaching an
+// upload callback. The returned bytes are the store's response to the
+// callback it fired.
+type callbackStorage interface {
+	PutObjectWithCallback(key string, r io.Reader, callback string) ([]byte, error)
+	CompleteMultipartUploadWithCallback(key, uploadID string, parts []UploadedPart, callback string) ([]byte, error)
+}
+
+// errNoCallbackSupport is returned when a callback is configured but the
+// storage backend cannot deliver one.
+var errNoCallbackSupport = fmt.Errorf("voiceworld: storage backend does not support upload callbacks")
+
+// conditionalPutStorage is the optional ObjectStorage capability behind
+// UploadOptions.IfNoneMatch.
+type conditionalPutStorage interface {
+	// PutObjectIfAbsent stores r under key only when the key is free,
+	// failing with ErrObjectAlreadyExists otherwise.
+	PutObjectIfAbsent(key string, r io.Reader) error
+}
+
+// errNoForbidOverwriteSupport is returned when IfNoneMatch is set but the
+// storage backend cannot refuse overwrites.
+var errNoForbidOverwriteSupport = fmt.Errorf("voiceworld: storage backend does not support forbid-overwrite uploads")
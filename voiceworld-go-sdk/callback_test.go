@@ -0,0 +1,149 @@
+package voiceworld
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOSSCallbackEncode(t *testing.T) {
+	cb := &OSSCallback{
+		URL:  "https://api.example.com/notify",
+		Host: "api.example.com",
+		Body: "part=${object}:${size}:${requestID}",
+	}
+	// base64 of {"callbackUrl":"https://api.example.com/notify",
+	// "callbackHost":"api.example.com",
+	// "callbackBody":"part=${object}:${size}:req-42"} — ${object} and
+	// ${size} are left for OSS, ${requestID} is expanded client-side.
+	want := "eyJjYWxsYmFja1VybCI6Imh0dHBzOi8vYXBpLmV4YW1wbGUuY29tL25vdGlmeSIsImNhbGxiYWNrSG9zdCI6ImFwaS5leGFtcGxlLmNvbSIsImNhbGxiYWNrQm9keSI6InBhcnQ9JHtvYmplY3R9OiR7c2l6ZX06cmVxLTQyIn0="
+	if got := cb.encode("req-42/0001.wav"); got != want {
+		t.Errorf("encode = %q, want %q", got, want)
+	}
+}
+
+func TestOSSCallbackValidation(t *testing.T) {
+	cases := []struct {
+		name string
+		cb   OSSCallback
+		ok   bool
+	}{
+		{"valid form", OSSCallback{URL: "https://x.example.com/cb", Body: "k=${object}"}, true},
+		{"valid json", OSSCallback{URL: "http://x.example.com/cb", Body: "{}", BodyType: "application/json"}, true},
+		{"relative url", OSSCallback{URL: "/cb", Body: "k=v"}, false},
+		{"bad scheme", OSSCallback{URL: "oss://bucket/cb", Body: "k=v"}, false},
+		{"empty body", OSSCallback{URL: "https://x.example.com/cb"}, false},
+		{"bad body type", OSSCallback{URL: "https://x.example.com/cb", Body: "{}", BodyType: "text/plain"}, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			client, _ := newTestClient(t, nil)
+			_, err := client.UploadFile("/does/not/matter", "k", &UploadOptions{Callback: &tc.cb})
+			if tc.ok && err != nil && strings.Contains(err.Error(), "callback") {
+				t.Errorf("valid config rejected: %v", err)
+			}
+			if !tc.ok && (err == nil || !strings.Contains(err.Error(), "callback")) {
+				t.Errorf("invalid config not rejected client-side: %v", err)
+			}
+		})
+	}
+}
+
+// callbackMemoryStorage records the callback header values it was handed
+// and answers each callback with a fixed body.
+type callbackMemoryStorage struct {
+	*MemoryStorage
+	putCallback      string
+	completeCallback string
+}
+
+func (s *callbackMemoryStorage) PutObjectWithCallback(key string, r io.Reader, callback string) ([]byte, error) {
+	s.putCallback = callback
+	if err := s.MemoryStorage.PutObject(key, r); err != nil {
+		return nil, err
+	}
+	return []byte(`{"status":"ok"}`), nil
+}
+
+func (s *callbackMemoryStorage) CompleteMultipartUploadWithCallback(key, uploadID string, parts []UploadedPart, callback string) ([]byte, error) {
+	s.completeCallback = callback
+	if err := s.MemoryStorage.CompleteMultipartUpload(key, uploadID, parts); err != nil {
+		return nil, err
+	}
+	return []byte(`{"status":"ok"}`), nil
+}
+
+func TestUploadFileWithCallback(t *testing.T) {
+	store := &callbackMemoryStorage{MemoryStorage: NewMemoryStorage()}
+	var delivered *OperationReport
+	client, err := NewClient("ak", "sk", &ClientConfig{
+		Storage:    store,
+		OnComplete: func(r *OperationReport) { delivered = r },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "a.bin")
+	if err := os.WriteFile(path, []byte("audio"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cb := &OSSCallback{URL: "https://api.example.com/notify", Body: "part=${object}:${size}:${requestID}"}
+
+	if _, err := client.UploadFile(path, "req-42/a.bin", &UploadOptions{Callback: cb}); err != nil {
+		t.Fatalf("UploadFile: %v", err)
+	}
+	if store.putCallback != cb.encode("req-42/a.bin") {
+		t.Errorf("callback header = %q", store.putCallback)
+	}
+	if !bytes.Equal(delivered.CallbackResponse, []byte(`{"status":"ok"}`)) {
+		t.Errorf("CallbackResponse = %q", delivered.CallbackResponse)
+	}
+}
+
+func TestMultipartUploadFileWithCallback(t *testing.T) {
+	store := &callbackMemoryStorage{MemoryStorage: NewMemoryStorage()}
+	var delivered *OperationReport
+	client, err := NewClient("ak", "sk", &ClientConfig{
+		Storage:    store,
+		ChunkSize:  1024,
+		OnComplete: func(r *OperationReport) { delivered = r },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "big.bin")
+	if err := os.WriteFile(path, make([]byte, 3000), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cb := &OSSCallback{URL: "https://api.example.com/notify", Body: "key=${object}"}
+
+	if _, err := client.MultipartUploadFile(path, "req-42/big.bin", &UploadOptions{Callback: cb}); err != nil {
+		t.Fatalf("MultipartUploadFile: %v", err)
+	}
+	// The callback fires once, when the parts are assembled.
+	if store.completeCallback != cb.encode("req-42/big.bin") {
+		t.Errorf("callback header = %q", store.completeCallback)
+	}
+	if store.putCallback != "" {
+		t.Errorf("per-part callback fired: %q", store.putCallback)
+	}
+	if !bytes.Equal(delivered.CallbackResponse, []byte(`{"status":"ok"}`)) {
+		t.Errorf("CallbackResponse = %q", delivered.CallbackResponse)
+	}
+}
+
+func TestUploadCallbackUnsupportedBackend(t *testing.T) {
+	client, _ := newTestClient(t, nil)
+	path := filepath.Join(t.TempDir(), "a.bin")
+	if err := os.WriteFile(path, []byte("audio"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cb := &OSSCallback{URL: "https://api.example.com/notify", Body: "k=v"}
+	_, err := client.UploadFile(path, "req/a.bin", &UploadOptions{Callback: cb})
+	if err == nil || !strings.Contains(err.Error(), "does not support upload callbacks") {
+		t.Errorf("err = %v, want unsupported-backend failure", err)
+	}
+}
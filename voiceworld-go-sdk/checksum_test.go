@@ -0,0 +1,123 @@
+package voiceworld
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"hash/crc64"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestChecksummerValues(t *testing.T) {
+	const input = "hello world"
+	cases := []struct {
+		cs   Checksummer
+		name string
+		want string
+	}{
+		{ChecksumCRC64, "crc64", strconv.FormatUint(crc64.Checksum([]byte(input), crc64Table), 10)},
+		{ChecksumMD5, "md5", "5eb63bbbe01eeed093cb22bb8f5acdc3"},
+		{ChecksumSHA256, "sha256", "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"},
+	}
+	for _, tc := range cases {
+		if tc.cs.Name() != tc.name {
+			t.Errorf("Name() = %q, want %q", tc.cs.Name(), tc.name)
+		}
+		h := tc.cs.New()
+		io.WriteString(h, input)
+		if got := tc.cs.Format(h); got != tc.want {
+			t.Errorf("%s(%q) = %q, want %q", tc.name, input, got, tc.want)
+		}
+		byName, ok := checksummerByName(tc.name)
+		if !ok || byName != tc.cs {
+			t.Errorf("checksummerByName(%q) did not round-trip", tc.name)
+		}
+	}
+}
+
+func TestUploadRecordsChecksum(t *testing.T) {
+	var report *OperationReport
+	client, store := newTestClient(t, &ClientConfig{
+		OnComplete: func(r *OperationReport) { report = r },
+	})
+	path := makeWAVFile(t, 16000, 1, 0.1)
+	if _, err := client.UploadFile(path, "req/a.wav", &UploadOptions{Checksum: ChecksumSHA256}); err != nil {
+		t.Fatalf("UploadFile: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := fmt.Sprintf("%x", sha256.Sum256(data))
+	if report.Checksum == nil {
+		t.Fatal("report carries no checksum")
+	}
+	if report.Checksum.Algorithm != "sha256" || report.Checksum.Value != want {
+		t.Errorf("report checksum = %+v, want sha256 %s", report.Checksum, want)
+	}
+	meta, ok := store.ObjectMeta("req/a.wav")
+	if !ok {
+		t.Fatal("object not stored")
+	}
+	if meta["checksum-sha256"] != want {
+		t.Errorf("object metadata checksum-sha256 = %q, want %q", meta["checksum-sha256"], want)
+	}
+}
+
+func TestHashingReaderHashesReplayedBytesOnce(t *testing.T) {
+	const input = "hello world"
+	hr := newHashingReader(strings.NewReader(input), ChecksumSHA256.New())
+	// First attempt reads part of the stream, then a retry rewinds and
+	// replays it from the start.
+	if _, err := io.ReadAll(io.LimitReader(hr, 5)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := hr.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadAll(hr); err != nil {
+		t.Fatal(err)
+	}
+	want := fmt.Sprintf("%x", sha256.Sum256([]byte(input)))
+	if got := hr.value(ChecksumSHA256); got.Value != want {
+		t.Errorf("digest after replay = %s, want %s", got.Value, want)
+	}
+}
+
+func TestManifestRecordsChecksum(t *testing.T) {
+	client, store := newTestClient(t, nil)
+	result, err := client.SplitAudioFile(makeWAVFile(t, 16000, 1, 2.5), 1, &SplitOptions{
+		WriteManifest: true,
+		Checksum:      ChecksumSHA256,
+	})
+	if err != nil {
+		t.Fatalf("SplitAudioFile: %v", err)
+	}
+	m, err := client.VerifyManifest(result.RequestID)
+	if err != nil {
+		t.Fatalf("VerifyManifest: %v", err)
+	}
+	for _, part := range m.Parts {
+		if part.Checksum == nil || part.Checksum.Algorithm != "sha256" {
+			t.Fatalf("part %d checksum = %+v, want sha256", part.Index, part.Checksum)
+		}
+		data, _ := store.Object(part.ObjectKey)
+		if want := fmt.Sprintf("%x", sha256.Sum256(data)); part.Checksum.Value != want {
+			t.Errorf("part %d checksum %s, object hashes to %s", part.Index, part.Checksum.Value, want)
+		}
+	}
+
+	// Tampering with a staged chunk must fail verification.
+	key := result.Parts[0].ObjectKey
+	data, _ := store.Object(key)
+	data[len(data)-1] ^= 0xff
+	if err := store.PutObject(key, strings.NewReader(string(data))); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.VerifyManifest(result.RequestID); err == nil {
+		t.Error("verification passed over a tampered chunk")
+	}
+}
@@ -0,0 +1,763 @@
+// Package voiceworld is the official Go SDK for the VoiceWorld speech
+// recognition platform. It wraps the HTTP API (recognition, token issuing,
+// audio preprocessing) and the object storage operations used to stage audio
+// for long-running recognition tasks.
+package voiceworld
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// DefaultBaseURL is the production API endpoint used when ClientConfig
+	// does not override it.
+	DefaultBaseURL = "https://api.voiceworld.com/v1"
+
+	// DefaultTimeout bounds a single API round trip.
+	DefaultTimeout = 60 * time.Second
+
+	// DefaultMaxResponseSize caps how much of an API response body the
+	// client buffers (16 MB). JSON responses are far smaller; the cap only
+	// exists so a misconfigured proxy answering with garbage cannot OOM
+	// the process.
+	DefaultMaxResponseSize = 16 << 20
+)
+
+// ClientConfig carries the optional knobs for a Client. The zero value of
+// every field means "use the default".
+type ClientConfig struct {
+	// BaseURL overrides DefaultBaseURL, e.g. for private deployments.
+	BaseURL string
+
+	// Timeout bounds each HTTP request issued by the client.
+	Timeout time.Duration
+
+	// TokenTimeout bounds the control-plane calls that should answer in
+	// milliseconds: token issuing and server-clock sync. Zero leaves them
+	// under Timeout alone. A call past its bound fails with a
+	// *TimeoutError naming the endpoint and phase.
+	TokenTimeout time.Duration
+
+	// ASRTimeout bounds recognition calls, which may legitimately chew on
+	// half an hour of audio — set it looser than Timeout, not tighter.
+	// Zero leaves them under Timeout alone.
+	ASRTimeout time.Duration
+
+	// UploadTimeout bounds the preprocess endpoint, which receives
+	// streamed audio. Zero leaves it under Timeout alone.
+	UploadTimeout time.Duration
+
+	// Parallelism is the number of concurrent part uploads used by
+	// MultipartUploadFile and SplitAudioFile. Zero means 1 (sequential).
+	Parallelism int
+
+	// ChunkSize is the multipart upload part size in bytes. Zero means
+	// defaultChunkSize.
+	ChunkSize int64
+
+	// Storage overrides the object store used by the upload helpers.
+	// Nil means Aliyun OSS with credentials from the TokenProvider.
+	// Tests typically inject a MemoryStorage here.
+	Storage ObjectStorage
+
+	// TokenProvider overrides where object storage credentials come from.
+	// Nil means the platform's /get_oss_token endpoint.
+	TokenProvider TokenProvider
+
+	// StorageConfig selects the storage backend (Aliyun OSS, S3, ...)
+	// and its addressing. Nil means Aliyun OSS as described by the
+	// credentials. Ignored when Storage is set.
+	StorageConfig *StorageConfig
+
+	// RawPrefix is the object key prefix under which ProcessAndUploadFile
+	// stages untouched source audio when UploadOptions.UploadOriginal is
+	// set. Keeping raw and processed audio under distinct prefixes lets
+	// bucket retention policies treat them differently. Empty means "raw/".
+	RawPrefix string
+
+	// ProcessedPrefix is the object key prefix under which
+	// ProcessAndUploadFile stages its processed output. Empty means
+	// "processed/".
+	ProcessedPrefix string
+
+	// MaxResponseSize caps, in bytes, how much of a response body the
+	// client buffers: API envelopes and the staged manifest on
+	// VerifyManifest. Bodies past the cap fail with ErrResponseTooLarge
+	// instead of growing memory without bound. Streaming download helpers
+	// are not buffered and not affected. Zero means
+	// DefaultMaxResponseSize.
+	MaxResponseSize int64
+
+	// HTTPClient overrides the *http.Client used for API calls, e.g. to
+	// install a recording/replaying transport. Nil means a fresh client
+	// honoring Timeout.
+	HTTPClient *http.Client
+
+	// PinnedCertSHA256 restricts API connections to servers whose leaf
+	// certificate's SPKI hashes (hex SHA-256) appear in the list,
+	// enforced in addition to normal chain validation. Rotations list the
+	// old and new hashes together. Mismatches surface as a
+	// *CertificatePinError. The pins apply to the API transport only -
+	// the OSS SDK manages its own - and require the HTTPClient transport
+	// (when overridden) to be an *http.Transport.
+	PinnedCertSHA256 []string
+
+	// APIVersion overrides the response schema generation announced on
+	// every request via X-API-Version. Empty means LatestAPIVersion.
+	// Whatever generation the server answers with, the decoders accept
+	// the current and previous known schemas; see ServerAPIVersion.
+	APIVersion string
+
+	// DisableExpectContinue suppresses the Expect: 100-continue
+	// handshake on streamed file bodies, for servers that mishandle it.
+	DisableExpectContinue bool
+
+	// EnableCompression gzips JSON request bodies (preprocess and task
+	// submission payloads carry large hotword lists). Binary audio
+	// bodies are never compressed. Responses are decompressed regardless
+	// of this setting.
+	EnableCompression bool
+
+	// Retry decides whether failed API requests and storage part uploads
+	// are reissued, and with what delay. Nil means NoRetry. Requests
+	// whose body cannot be rewound are never retried.
+	Retry RetryPolicy
+
+	// SignRequestBody includes a SHA-256 of the request body in the
+	// signature (version 2, announced via X-VW-Signature-Version), so a
+	// relayed request cannot have its payload swapped within the
+	// timestamp window. Streamed file bodies are hashed with an extra
+	// read pass before the upload — one full extra read of the audio, no
+	// extra memory. Off by default: version 1 servers ignore the body
+	// hash entirely.
+	SignRequestBody bool
+
+	// MaxTotalRetries caps the retries one operation may spend as a
+	// whole: a MultipartUploadFile or SplitAudioFile call shares the
+	// budget across all of its parts, so per-part retries against a dying
+	// backend cannot multiply into hundreds of attempts. Once spent,
+	// further failures are terminal and the error wraps
+	// ErrRetryBudgetExhausted. Zero means unlimited. Only meaningful
+	// together with Retry.
+	MaxTotalRetries int
+
+	// MaxConcurrentTransfers caps how many part transfers the Client has
+	// in flight at once, across every concurrent operation — Parallelism
+	// bounds one call, this bounds the Client. Workers past the cap
+	// block until a slot frees up. Zero means unlimited.
+	MaxConcurrentTransfers int
+
+	// MaxRequestsPerSecond paces API calls so bursts stay inside the
+	// account's QPS contract. Calls past the budget block until the
+	// pacer admits them; retries count as calls. Object storage
+	// transfers are not API calls and are unaffected. Zero means
+	// unlimited. See also WithoutPacing for priority traffic.
+	MaxRequestsPerSecond float64
+
+	// OnThrottle is told, before each wait, how long a request is about
+	// to queue on the MaxRequestsPerSecond pacer, so sustained queuing
+	// can feed an alert. It runs on the calling goroutine.
+	OnThrottle func(wait time.Duration)
+
+	// Progress receives byte-level upload progress. Each call to
+	// UploadFile, MultipartUploadFile or SplitAudioFile reports its own
+	// cumulative totals. On the OSS backend updates stream during each
+	// transfer; other backends report once per completed part. Nil
+	// disables reporting.
+	Progress ProgressReporter
+
+	// ProgressSink, when set, persists throttled ProgressSnapshot records
+	// for every operation, so a process other than this one — a dashboard
+	// worker polling a shared directory, say — can display bytes-done and
+	// parts-done. The first and final snapshots always persist; updates in
+	// between are limited to one per ProgressSinkInterval. Persist errors
+	// are dropped: progress display must never fail a transfer. See
+	// FileProgressSink.
+	ProgressSink ProgressSink
+
+	// ProgressSinkInterval is the minimum time between persisted snapshots
+	// of one operation; zero means one second.
+	ProgressSinkInterval time.Duration
+
+	// OnComplete receives the OperationReport of every finished upload
+	// operation, successful or not. It runs synchronously at the end of
+	// the operation; see OperationReport.
+	OnComplete func(*OperationReport)
+
+	// OnWarning receives human-readable warnings about conditions the SDK
+	// compensated for, e.g. a signed-URL TTL clamped to the storage
+	// credential's remaining lifetime. Nil drops them. It may be called
+	// from transfer goroutines and must be safe for concurrent use.
+	OnWarning func(message string)
+
+	// OnRecognition receives client-side latency and real-time-factor
+	// metrics for every successful recognition call, labeled by audio
+	// format and sample rate. It runs synchronously on the calling
+	// goroutine; see RecognitionMetrics.
+	OnRecognition func(RecognitionMetrics)
+
+	// DedupeStore, when set, guards against duplicate submissions: ASR
+	// skips any call whose audio content and recognition parameters were
+	// already submitted, replaying the stored result with FromCache set
+	// instead of paying for the transcription again. See MemoryDedupeStore
+	// and FileDedupeStore. Nil disables the guard.
+	DedupeStore DedupeStore
+
+	// FlightRecorder keeps a bounded ring of recent sanitized exchanges —
+	// no headers, bodies clipped — so error reports can include the
+	// lead-up to a failure without always-on debug capture; read it back
+	// with DumpRecentExchanges. Nil records nothing at no cost.
+	FlightRecorder *FlightRecorder
+
+	// Recognizer routes recognition of local audio through an alternative
+	// transport (see the voiceworldgrpc subpackage). Nil means the REST
+	// /asr endpoint.
+	Recognizer Recognizer
+
+	// Strict turns the SDK's silent guesses into typed errors: audio
+	// whose container cannot be identified fails with ErrAmbiguousFormat
+	// instead of letting the server decide, ObjectKey tracks in a
+	// resampling multi-track batch fail with ErrTrackNotInspected instead
+	// of going up unverified, and calls that would generate a request ID
+	// fail with ErrRequestIDRequired instead of minting surprise object
+	// keys. Off by default; the lenient behaviour is unchanged.
+	Strict bool
+
+	// PreferProtobuf asks the recognition endpoints for binary protobuf
+	// responses (Accept: application/x-protobuf), which avoids JSON
+	// decode overhead for high-QPS short utterances. Servers that do not
+	// support it keep answering JSON and the client decodes whichever
+	// arrives, so mixed fleets are fine.
+	PreferProtobuf bool
+}
+
+// Client talks to the VoiceWorld API. It is safe for concurrent use.
+type Client struct {
+	appKey    string
+	secretKey string
+	config    ClientConfig
+
+	httpClient    *http.Client
+	tokenProvider TokenProvider
+
+	// idempotencyKey, when set via WithIdempotencyKey, overrides the
+	// per-call generated key on mutating requests.
+	idempotencyKey string
+
+	// sessionID, when set on a RecognitionSession's derived client, rides
+	// along on every request as the X-Session-Id header.
+	sessionID string
+
+	// clock is the shared server-clock offset; see SyncServerTime.
+	clock *clockState
+
+	// version is the shared server-reported schema version; see
+	// ServerAPIVersion.
+	version *versionState
+
+	// transfers is the client-wide transfer cap; see
+	// ClientConfig.MaxConcurrentTransfers.
+	transfers *transferLimiter
+
+	// limiter paces API calls; see ClientConfig.MaxRequestsPerSecond.
+	// unpaced marks a WithoutPacing-derived client that skips it.
+	limiter *rate.Limiter
+	unpaced bool
+
+	// sleep is time.Sleep, swapped out by tests that assert pacing.
+	sleep func(time.Duration)
+
+	// life tracks in-flight operations and shutdown state; see Close.
+	life *clientLifecycle
+
+	// stats is the shared cumulative transfer counters; see Stats.
+	stats *statsState
+
+	// now is time.Now, swapped out by WithClock and by tests that assert
+	// report timings.
+	now func() time.Time
+}
+
+// NewClient builds a Client from an application key pair. An optional
+// *ClientConfig customizes endpoints and transfer behaviour; passing more
+// than one config is an error.
+func NewClient(appKey, secretKey string, config ...*ClientConfig) (*Client, error) {
+	if appKey == "" || secretKey == "" {
+		return nil, fmt.Errorf("voiceworld: appKey and secretKey are required")
+	}
+	if len(config) > 1 {
+		return nil, fmt.Errorf("voiceworld: at most one ClientConfig may be given, got %d", len(config))
+	}
+
+	c := &Client{appKey: appKey, secretKey: secretKey, clock: &clockState{}, version: &versionState{}, stats: newStatsState(), now: time.Now}
+	if len(config) == 1 && config[0] != nil {
+		c.config = *config[0]
+	}
+	if c.config.BaseURL == "" {
+		c.config.BaseURL = DefaultBaseURL
+	}
+	base, err := normalizeBaseURL(c.config.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+	c.config.BaseURL = base
+	if c.config.Timeout <= 0 {
+		c.config.Timeout = DefaultTimeout
+	}
+	if c.config.Parallelism <= 0 {
+		c.config.Parallelism = 1
+	}
+	if c.config.ChunkSize <= 0 {
+		c.config.ChunkSize = defaultChunkSize
+	}
+	if c.config.MaxResponseSize <= 0 {
+		c.config.MaxResponseSize = DefaultMaxResponseSize
+	}
+	if c.config.RawPrefix == "" {
+		c.config.RawPrefix = "raw/"
+	}
+	if c.config.ProcessedPrefix == "" {
+		c.config.ProcessedPrefix = "processed/"
+	}
+	c.transfers = newTransferLimiter(c.config.MaxConcurrentTransfers)
+	if c.config.MaxRequestsPerSecond > 0 {
+		// The burst matches the per-second contract: a quiet client may
+		// spend a full second's budget at once, then is paced.
+		burst := int(math.Ceil(c.config.MaxRequestsPerSecond))
+		c.limiter = rate.NewLimiter(rate.Limit(c.config.MaxRequestsPerSecond), burst)
+	}
+	c.sleep = time.Sleep
+	c.life = newClientLifecycle()
+	c.life.sink = c.config.ProgressSink
+	c.life.sinkInterval = c.config.ProgressSinkInterval
+	if c.life.sinkInterval <= 0 {
+		c.life.sinkInterval = defaultProgressSinkInterval
+	}
+
+	c.httpClient = c.config.HTTPClient
+	if c.httpClient == nil {
+		c.httpClient = &http.Client{Timeout: c.config.Timeout}
+	}
+	if len(c.config.PinnedCertSHA256) > 0 {
+		if err := c.applyCertPins(); err != nil {
+			return nil, err
+		}
+	}
+	c.tokenProvider = c.config.TokenProvider
+	if c.tokenProvider == nil {
+		c.tokenProvider = &httpTokenProvider{client: c}
+	}
+	return c, nil
+}
+
+// apiEnvelope is the uniform JSON wrapper every API response uses.
+type apiEnvelope struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// parseEnvelope reports whether raw is a parseable envelope - a JSON object
+// that spells out its code field - and returns it when so. The presence check
+// keeps arbitrary JSON error pages (which would decode into a zero Code) from
+// passing as success envelopes.
+func parseEnvelope(raw []byte) (*apiEnvelope, bool) {
+	var probe struct {
+		Code    *int            `json:"code"`
+		Message string          `json:"message"`
+		Data    json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(raw, &probe); err != nil || probe.Code == nil {
+		return nil, false
+	}
+	return &apiEnvelope{Code: *probe.Code, Message: probe.Message, Data: probe.Data}, true
+}
+
+// postJSON marshals in, signs and POSTs it to path, and decodes the envelope
+// data into out (which may be nil when the caller only cares about success).
+// With EnableCompression the JSON body is gzipped; binary audio bodies never
+// go through here and are never compressed.
+func (c *Client) postJSON(path string, in, out interface{}) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("voiceworld: encode request: %w", err)
+	}
+	encoding := ""
+	if c.config.EnableCompression {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		if _, err := gz.Write(body); err != nil {
+			return fmt.Errorf("voiceworld: compress request: %w", err)
+		}
+		if err := gz.Close(); err != nil {
+			return fmt.Errorf("voiceworld: compress request: %w", err)
+		}
+		body = buf.Bytes()
+		encoding = "gzip"
+	}
+	return c.doEncoded(http.MethodPost, path, nil, "application/json", encoding, bytes.NewReader(body), out)
+}
+
+// do issues a signed request and decodes the response envelope into out.
+func (c *Client) do(method, path string, query url.Values, contentType string, body io.Reader, out interface{}) error {
+	return c.doEncoded(method, path, query, contentType, "", body, out)
+}
+
+// doEncoded is do with an explicit request Content-Encoding. Failed
+// requests are reissued under the configured RetryPolicy as long as the
+// body can be rewound for the next attempt.
+func (c *Client) doEncoded(method, path string, query url.Values, contentType, contentEncoding string, body io.Reader, out interface{}) error {
+	if c.life.isClosed() {
+		return ErrClientClosed
+	}
+	policy := c.retryPolicy()
+	budget := newRetryBudget(c.config.MaxTotalRetries)
+	// Mutating requests carry an idempotency key so a retry of a call
+	// that actually succeeded server-side does not create a second job.
+	// It is fixed before the attempt loop: every retry reuses the key,
+	// while each new doEncoded call gets its own.
+	idempotencyKey := ""
+	if method != http.MethodGet {
+		idempotencyKey = c.idempotencyKey
+		if idempotencyKey == "" {
+			idempotencyKey = newIdempotencyKey()
+		}
+	}
+	seeker, rewindable := body.(io.Seeker)
+	rewindBody := func() bool {
+		if body == nil {
+			return true
+		}
+		if !rewindable {
+			return false
+		}
+		_, serr := seeker.Seek(0, io.SeekStart)
+		return serr == nil
+	}
+	resynced := false
+	for attempt := 1; ; attempt++ {
+		c.pace()
+		resp, err := c.doOnce(method, path, query, contentType, contentEncoding, idempotencyKey, body, out)
+		if err == nil {
+			return nil
+		}
+		// A skewed local clock makes every signature expired; measure the
+		// server clock once and replay before consulting the policy.
+		if !resynced && path != "/time" && isSignatureExpired(err) {
+			if serr := c.SyncServerTime(); serr == nil {
+				resynced = true
+				if rewindBody() {
+					continue
+				}
+			}
+		}
+		delay, retry := policy.NextDelay(attempt, err, resp)
+		if !retry {
+			return err
+		}
+		if !budget.consume(err) {
+			return budget.exhausted(err)
+		}
+		if !rewindBody() {
+			return err
+		}
+		c.stats.addRetries(1)
+		c.sleep(delay)
+	}
+}
+
+// normalizeBaseURL validates and canonicalizes ClientConfig.BaseURL at
+// construction: the scheme must be http or https, the host non-empty, and
+// any path prefix is kept with its trailing slashes dropped, so joining an
+// endpoint path never produces a double slash.
+func normalizeBaseURL(raw string) (string, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("voiceworld: invalid BaseURL %q: %w", raw, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("voiceworld: BaseURL %q must use http or https, got %q", raw, u.Scheme)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("voiceworld: BaseURL %q has no host", raw)
+	}
+	u.Path = strings.TrimRight(u.Path, "/")
+	u.RawQuery = ""
+	u.Fragment = ""
+	return u.String(), nil
+}
+
+// endpointURL joins one endpoint path onto the configured base, preserving
+// any base path prefix. Every request URL in the SDK goes through here.
+func (c *Client) endpointURL(path string) string {
+	u, err := url.JoinPath(c.config.BaseURL, path)
+	if err != nil {
+		// The base was validated at construction and endpoint paths are
+		// compile-time constants; string concatenation is the harmless
+		// fallback.
+		return c.config.BaseURL + path
+	}
+	return u
+}
+
+// doOnce issues one signed request and decodes the response envelope into
+// out. The returned response, when non-nil, has its body consumed; it is
+// returned so the retry policy can inspect the status.
+func (c *Client) doOnce(method, path string, query url.Values, contentType, contentEncoding, idempotencyKey string, body io.Reader, out interface{}) (*http.Response, error) {
+	u := c.endpointURL(path)
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	ctx, cancel := c.operationContext(path)
+	defer cancel()
+	req, err := c.buildRequest(ctx, method, u, contentType, contentEncoding, idempotencyKey, body)
+	if err != nil {
+		return nil, err
+	}
+	c.stats.countAPIRequest(path)
+	// Recognition results can optionally travel as protobuf; the response
+	// Content-Type decides how the body is decoded, so JSON-only servers
+	// keep working.
+	if _, wantsResult := out.(*RecognitionResult); wantsResult && c.config.PreferProtobuf {
+		req.Header.Set("Accept", contentTypeProtobuf)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.recordExchangeError(req, err)
+		c.stats.addFailure()
+		return nil, c.wrapTimeout(path, fmt.Errorf("voiceworld: %s %s: %w", method, path, err))
+	}
+	defer resp.Body.Close()
+	// The operation deadline covers reading the body too: a server that
+	// answers headers and stalls is still a response timeout.
+	derr := c.wrapTimeout(path, c.decodeResponse(resp, out))
+	if derr != nil {
+		c.stats.addFailure()
+	}
+	return resp, derr
+}
+
+// buildRequest assembles one authenticated request: headers, streamed file
+// handling and the signature. Shared by the wrapped endpoints (via doOnce)
+// and NewSignedRequest.
+func (c *Client) buildRequest(ctx context.Context, method, url, contentType, contentEncoding, idempotencyKey string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, fmt.Errorf("voiceworld: build request: %w", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if contentEncoding != "" {
+		req.Header.Set("Content-Encoding", contentEncoding)
+	}
+	if idempotencyKey != "" {
+		req.Header.Set(headerIdempotencyKey, idempotencyKey)
+	}
+	if c.sessionID != "" {
+		req.Header.Set(headerSessionID, c.sessionID)
+	}
+	// Ask for compressed responses. Setting the header explicitly opts us
+	// out of net/http's automatic gunzip (and custom transports may never
+	// have offered it), so the response side is decompressed by hand in
+	// decodeResponse.
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set(headerAPIVersion, c.apiVersion())
+	// File bodies are streamed, not buffered: net/http cannot size them
+	// itself, so take Content-Length from Stat, and ask for 100-continue
+	// so a rejected request does not first upload the whole audio.
+	if f, ok := body.(*os.File); ok {
+		fi, err := f.Stat()
+		if err != nil {
+			return nil, fmt.Errorf("voiceworld: stat request body: %w", err)
+		}
+		req.ContentLength = fi.Size()
+		if !c.config.DisableExpectContinue {
+			req.Header.Set("Expect", "100-continue")
+		}
+	}
+	bodyHash := ""
+	if c.config.SignRequestBody {
+		bodyHash, err = hashBody(body)
+		if err != nil {
+			return nil, err
+		}
+	}
+	c.signWithBodyHash(req, bodyHash)
+	return req, nil
+}
+
+// decodeResponse reads one API response - decompressing, bounding and
+// judging it under the envelope-versus-status precedence rule - and decodes
+// the envelope data into out. The caller owns closing resp.Body.
+func (c *Client) decodeResponse(resp *http.Response, out interface{}) error {
+	c.recordServerVersion(resp.Header.Get(headerAPIVersion))
+	respBody := resp.Body
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(respBody)
+		if err != nil {
+			return fmt.Errorf("voiceworld: open gzip response: %w", err)
+		}
+		defer gz.Close()
+		respBody = gz
+	}
+	raw, err := readBounded(respBody, c.config.MaxResponseSize)
+	if err != nil {
+		if errors.Is(err, ErrResponseTooLarge) {
+			return err
+		}
+		return fmt.Errorf("voiceworld: read response: %w", err)
+	}
+	c.recordExchange(resp, raw)
+	ok := resp.StatusCode >= 200 && resp.StatusCode <= 299
+
+	if ok && strings.HasPrefix(resp.Header.Get("Content-Type"), contentTypeProtobuf) {
+		return decodeProtobufResult(raw, out)
+	}
+
+	// The envelope verdict outranks the HTTP status whenever the body parses
+	// as one: the gateway wraps some backend failures in 200s and, during
+	// failover, valid success payloads in 5xxs. Only bodies without a
+	// parseable envelope are judged by their status; see APIError.
+	env, parsed := parseEnvelope(raw)
+	if !parsed {
+		if !ok {
+			return newStatusError(resp.StatusCode, raw)
+		}
+		env = new(apiEnvelope)
+		if err := json.Unmarshal(raw, env); err != nil {
+			return fmt.Errorf("voiceworld: decode response envelope: %w", err)
+		}
+	}
+	if env.Code != 0 {
+		apiErr := &APIError{Code: env.Code, Message: env.Message}
+		if !ok {
+			apiErr.HTTPStatus = resp.StatusCode
+		}
+		return apiErr
+	}
+	if out != nil && len(env.Data) > 0 {
+		if err := json.Unmarshal(env.Data, out); err != nil {
+			return fmt.Errorf("voiceworld: decode response data: %w", err)
+		}
+		normalizeRecognitionResult(out, env.Data)
+	}
+	return nil
+}
+
+// NewSignedRequest builds an authenticated request for an API endpoint the
+// SDK does not wrap yet; path is relative to ClientConfig.BaseURL. The
+// request carries the standard auth headers (with a body-hash signature when
+// SignRequestBody is on), the session header, and an idempotency key for
+// non-GET methods; *os.File bodies stream with Content-Length from Stat and
+// Expect: 100-continue, exactly like the wrapped endpoints. Run the result
+// through DoJSON for the full request stack, or through any *http.Client
+// as-is.
+func (c *Client) NewSignedRequest(ctx context.Context, method, path string, body io.Reader, contentType string) (*http.Request, error) {
+	if c.life.isClosed() {
+		return nil, ErrClientClosed
+	}
+	idempotencyKey := ""
+	if method != http.MethodGet {
+		idempotencyKey = c.idempotencyKey
+		if idempotencyKey == "" {
+			idempotencyKey = newIdempotencyKey()
+		}
+	}
+	return c.buildRequest(ctx, method, c.endpointURL(path), contentType, "", idempotencyKey, body)
+}
+
+// DoJSON issues a request built by NewSignedRequest with the client's full
+// request stack - pacing, the retry policy and budget, server-clock resync -
+// and decodes the standard response envelope into out (nil when the caller
+// only cares about success). Retried attempts are freshly signed and rewound
+// through GetBody, which net/http provides for the usual in-memory readers;
+// streamed file bodies are sent once and not retried.
+func (c *Client) DoJSON(req *http.Request, out interface{}) error {
+	if c.life.isClosed() {
+		return ErrClientClosed
+	}
+	policy := c.retryPolicy()
+	budget := newRetryBudget(c.config.MaxTotalRetries)
+	rewindBody := func() bool {
+		if req.Body == nil {
+			return true
+		}
+		if req.GetBody == nil {
+			return false
+		}
+		fresh, err := req.GetBody()
+		if err != nil {
+			return false
+		}
+		req.Body = fresh
+		return true
+	}
+	resynced := false
+	for attempt := 1; ; attempt++ {
+		c.pace()
+		// The first attempt rides on the signature NewSignedRequest
+		// stamped; later ones need a fresh timestamp and nonce.
+		if attempt > 1 {
+			if err := c.resignRequest(req); err != nil {
+				return err
+			}
+		}
+		resp, err := c.doSignedOnce(req, out)
+		if err == nil {
+			return nil
+		}
+		if !resynced && isSignatureExpired(err) {
+			if serr := c.SyncServerTime(); serr == nil {
+				resynced = true
+				if rewindBody() {
+					continue
+				}
+			}
+		}
+		delay, retry := policy.NextDelay(attempt, err, resp)
+		if !retry {
+			return err
+		}
+		if !budget.consume(err) {
+			return budget.exhausted(err)
+		}
+		if !rewindBody() {
+			return err
+		}
+		c.stats.addRetries(1)
+		c.sleep(delay)
+	}
+}
+
+// doSignedOnce issues one already-signed request and decodes the envelope.
+func (c *Client) doSignedOnce(req *http.Request, out interface{}) (*http.Response, error) {
+	c.stats.countAPIRequest(req.URL.Path)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.recordExchangeError(req, err)
+		c.stats.addFailure()
+		return nil, fmt.Errorf("voiceworld: %s %s: %w", req.Method, req.URL.Path, err)
+	}
+	defer resp.Body.Close()
+	derr := c.decodeResponse(resp, out)
+	if derr != nil {
+		c.stats.addFailure()
+	}
+	return resp, derr
+}
@@ -0,0 +1,89 @@
+package voiceworld
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// codeSignatureExpired is the envelope code servers use to reject a
+// request whose timestamp fell outside the acceptance window.
+const codeSignatureExpired = 40101
+
+// clockState holds the client's view of how far the server's clock is
+// ahead of the local one. It lives behind a pointer so derived clients
+// (WithIdempotencyKey) share one view.
+type clockState struct {
+	mu     sync.Mutex
+	offset time.Duration
+}
+
+func (s *clockState) set(offset time.Duration) {
+	s.mu.Lock()
+	s.offset = offset
+	s.mu.Unlock()
+}
+
+func (s *clockState) get() time.Duration {
+	if s == nil {
+		return 0
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.offset
+}
+
+// ClockOffset reports how far the server's clock is ahead of the local
+// one, as last measured by SyncServerTime (or an automatic resync). Zero
+// until a sync has happened.
+func (c *Client) ClockOffset() time.Duration {
+	return c.clock.get()
+}
+
+// SyncServerTime measures the server clock and stores the offset, which
+// the client then applies to every signature timestamp. Containers whose
+// clocks drift by minutes otherwise see every request rejected as
+// "signature expired". Servers without a /time endpoint are handled by
+// falling back to the Date header of their response.
+func (c *Client) SyncServerTime() error {
+	var out struct {
+		Timestamp int64 `json:"timestamp"`
+	}
+	err := c.do(http.MethodGet, "/time", nil, "", nil, &out)
+	if err == nil && out.Timestamp > 0 {
+		c.clock.set(time.Unix(out.Timestamp, 0).Sub(c.now()))
+		return nil
+	}
+
+	// Older servers have no /time, but every HTTP response carries a
+	// Date header; second granularity is plenty for a signature window.
+	req, rerr := http.NewRequest(http.MethodGet, c.endpointURL("/time"), nil)
+	if rerr != nil {
+		return fmt.Errorf("voiceworld: sync server time: %w", err)
+	}
+	resp, rerr := c.httpClient.Do(req)
+	if rerr != nil {
+		return fmt.Errorf("voiceworld: sync server time: %w", err)
+	}
+	defer resp.Body.Close()
+	date, derr := http.ParseTime(resp.Header.Get("Date"))
+	if derr != nil {
+		return fmt.Errorf("voiceworld: sync server time: %w", err)
+	}
+	c.clock.set(date.Sub(c.now()))
+	return nil
+}
+
+// isSignatureExpired recognizes the rejection a skewed clock produces.
+func isSignatureExpired(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.Code == codeSignatureExpired ||
+		(apiErr.HTTPStatus == http.StatusUnauthorized &&
+			strings.Contains(strings.ToLower(apiErr.Message), "signature expired"))
+}
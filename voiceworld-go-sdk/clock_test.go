@@ -0,0 +1,119 @@
+package voiceworld
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSyncServerTime(t *testing.T) {
+	skew := 5 * time.Minute
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/time" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		fmt.Fprintf(w, `{"code":0,"data":{"timestamp":%d}}`, time.Now().Add(skew).Unix())
+	}))
+	defer server.Close()
+
+	client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SyncServerTime(); err != nil {
+		t.Fatalf("SyncServerTime: %v", err)
+	}
+	if offset := client.ClockOffset(); offset < skew-2*time.Second || offset > skew+2*time.Second {
+		t.Errorf("ClockOffset = %v, want ~%v", offset, skew)
+	}
+}
+
+func TestSyncServerTimeDateHeaderFallback(t *testing.T) {
+	skew := -10 * time.Minute
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", time.Now().Add(skew).UTC().Format(http.TimeFormat))
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SyncServerTime(); err != nil {
+		t.Fatalf("SyncServerTime: %v", err)
+	}
+	if offset := client.ClockOffset(); offset < skew-2*time.Second || offset > skew+2*time.Second {
+		t.Errorf("ClockOffset = %v, want ~%v", offset, skew)
+	}
+}
+
+func TestCorrectedTimestampAfterSync(t *testing.T) {
+	skew := 3 * time.Minute
+	var signedTS int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/time":
+			fmt.Fprintf(w, `{"code":0,"data":{"timestamp":%d}}`, time.Now().Add(skew).Unix())
+		default:
+			signedTS, _ = strconv.ParseInt(r.Header.Get(headerTimestamp), 10, 64)
+			w.Write([]byte(`{"code":0,"data":{"models":[]}}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SyncServerTime(); err != nil {
+		t.Fatalf("SyncServerTime: %v", err)
+	}
+	if _, err := client.ListModels(); err != nil {
+		t.Fatalf("ListModels: %v", err)
+	}
+	want := time.Now().Add(skew).Unix()
+	if signedTS < want-2 || signedTS > want+2 {
+		t.Errorf("signed timestamp = %d, want ~%d (server time)", signedTS, want)
+	}
+}
+
+func TestSignatureExpiredTriggersResync(t *testing.T) {
+	skew := 6 * time.Minute
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serverNow := time.Now().Add(skew)
+		switch r.URL.Path {
+		case "/time":
+			fmt.Fprintf(w, `{"code":0,"data":{"timestamp":%d}}`, serverNow.Unix())
+		default:
+			requests++
+			ts, _ := strconv.ParseInt(r.Header.Get(headerTimestamp), 10, 64)
+			if d := serverNow.Unix() - ts; d > 60 || d < -60 {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"code":40101,"message":"signature expired"}`))
+				return
+			}
+			w.Write([]byte(`{"code":0,"data":{"models":[{"id":"m1"}]}}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	models, err := client.ListModels()
+	if err != nil {
+		t.Fatalf("ListModels after resync: %v", err)
+	}
+	if len(models) != 1 || requests != 2 {
+		t.Errorf("models = %v after %d attempts, want 1 model on the replayed attempt", models, requests)
+	}
+	if offset := client.ClockOffset(); offset < skew-2*time.Second || offset > skew+2*time.Second {
+		t.Errorf("ClockOffset = %v, want ~%v", offset, skew)
+	}
+}
@@ -0,0 +1,179 @@
+package voiceworld
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"time"
+)
+
+// ErrClientClosed is returned by every operation started after Close.
+var ErrClientClosed = errors.New("voiceworld: client closed")
+
+// openUpload is one initiated-but-not-completed multipart upload, tracked
+// so Close can abort what an interrupted shutdown would otherwise leak.
+type openUpload struct {
+	store    ObjectStorage
+	key      string
+	uploadID string
+}
+
+// clientLifecycle carries a Client's shutdown state and operation registry:
+// whether it is closed, which operations are in flight, the open multipart
+// uploads and the SDK-created temp directory. Clients derived with
+// WithIdempotencyKey et al. share their parent's lifecycle, so closing any
+// of them closes all.
+type clientLifecycle struct {
+	mu       sync.Mutex
+	cond     *sync.Cond
+	closed   bool
+	gaveUp   bool
+	nextOpID int64
+	ops      map[int64]*liveOp
+	nextID   int
+	uploads  map[int]openUpload
+	tempDir  string
+
+	// sink and sinkInterval come from ClientConfig; operations carry them
+	// so counter updates can persist snapshots without the lifecycle lock.
+	sink         ProgressSink
+	sinkInterval time.Duration
+}
+
+func newClientLifecycle() *clientLifecycle {
+	l := &clientLifecycle{ops: map[int64]*liveOp{}, uploads: map[int]openUpload{}}
+	l.cond = sync.NewCond(&l.mu)
+	return l
+}
+
+// beginOp admits one operation of the given kind and registers it for
+// Operations, or refuses because the client is closed.
+func (l *clientLifecycle) beginOp(kind string) (*liveOp, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return nil, ErrClientClosed
+	}
+	l.nextOpID++
+	op := &liveOp{id: l.nextOpID, kind: kind, startedAt: timeNow().UTC()}
+	if l.sink != nil {
+		op.sink = l.sink
+		op.sinkInterval = l.sinkInterval
+		op.opID = newOpID(kind, op.id)
+		op.persistSnapshot(false)
+	}
+	l.ops[op.id] = op
+	return op, nil
+}
+
+// endOp retires one operation admitted by beginOp.
+func (l *clientLifecycle) endOp(op *liveOp) {
+	op.persistSnapshot(true)
+	l.mu.Lock()
+	delete(l.ops, op.id)
+	l.cond.Broadcast()
+	l.mu.Unlock()
+}
+
+// isClosed reports whether Close has been called.
+func (l *clientLifecycle) isClosed() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.closed
+}
+
+// trackUpload registers an initiated multipart upload and returns the
+// function that unregisters it once completed or aborted.
+func (l *clientLifecycle) trackUpload(store ObjectStorage, key, uploadID string) func() {
+	l.mu.Lock()
+	id := l.nextID
+	l.nextID++
+	l.uploads[id] = openUpload{store: store, key: key, uploadID: uploadID}
+	l.mu.Unlock()
+	return func() {
+		l.mu.Lock()
+		delete(l.uploads, id)
+		l.mu.Unlock()
+	}
+}
+
+// shutdown marks the lifecycle closed, waits for in-flight operations to
+// drain or ctx to give up, and returns whatever multipart uploads are still
+// open for the caller to abort.
+func (l *clientLifecycle) shutdown(ctx context.Context) []openUpload {
+	l.mu.Lock()
+	l.closed = true
+	l.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		defer close(drained)
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		for len(l.ops) > 0 && !l.gaveUp {
+			l.cond.Wait()
+		}
+	}()
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		// Deadline hit: tell the waiter to stop waiting for stragglers.
+		l.mu.Lock()
+		l.gaveUp = true
+		l.cond.Broadcast()
+		l.mu.Unlock()
+		<-drained
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	remaining := make([]openUpload, 0, len(l.uploads))
+	for _, u := range l.uploads {
+		remaining = append(remaining, u)
+	}
+	return remaining
+}
+
+// chunkTempDir lazily creates the directory the SDK stages chunk temp
+// files in; Close removes it wholesale.
+func (l *clientLifecycle) chunkTempDir() (string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.tempDir == "" {
+		dir, err := os.MkdirTemp("", "voiceworld-")
+		if err != nil {
+			return "", err
+		}
+		l.tempDir = dir
+	}
+	return l.tempDir, nil
+}
+
+// removeTempDir deletes the SDK's temp directory, if one was created.
+func (l *clientLifecycle) removeTempDir() {
+	l.mu.Lock()
+	dir := l.tempDir
+	l.tempDir = ""
+	l.mu.Unlock()
+	if dir != "" {
+		os.RemoveAll(dir)
+	}
+}
+
+// Close shuts the Client down for process exit: new operations are refused
+// with ErrClientClosed immediately, in-flight ones get until ctx gives up
+// to finish, any multipart uploads still open after that are aborted so
+// they do not leak parts, the SDK's temp directory is removed, and idle
+// HTTP connections are closed. Close returns ctx.Err when the drain timed
+// out, nil otherwise; it is safe to call more than once.
+func (c *Client) Close(ctx context.Context) error {
+	remaining := c.life.shutdown(ctx)
+	for _, u := range remaining {
+		// Best effort, like the failure path of the upload itself.
+		_ = u.store.AbortMultipartUpload(u.key, u.uploadID)
+	}
+	c.life.removeTempDir()
+	c.httpClient.CloseIdleConnections()
+	return ctx.Err()
+}
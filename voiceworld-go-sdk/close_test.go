@@ -0,0 +1,159 @@
+package voiceworld
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+// blockingStorage parks every transfer until release is closed, so tests can
+// hold an operation in flight while racing Close against it.
+type blockingStorage struct {
+	*MemoryStorage
+	entered chan struct{} // one send per transfer that started
+	release chan struct{} // close to let parked transfers proceed
+
+	mu      sync.Mutex
+	aborted []string
+}
+
+func newBlockingStorage() *blockingStorage {
+	return &blockingStorage{
+		MemoryStorage: NewMemoryStorage(),
+		entered:       make(chan struct{}, 16),
+		release:       make(chan struct{}),
+	}
+}
+
+func (s *blockingStorage) park() {
+	select {
+	case s.entered <- struct{}{}:
+	default: // nobody is waiting on this one
+	}
+	<-s.release
+}
+
+func (s *blockingStorage) PutObject(key string, r io.Reader) error {
+	s.park()
+	return s.MemoryStorage.PutObject(key, r)
+}
+
+func (s *blockingStorage) UploadPart(key, uploadID string, partNumber int, r io.Reader, size int64) (UploadedPart, error) {
+	s.park()
+	return s.MemoryStorage.UploadPart(key, uploadID, partNumber, r, size)
+}
+
+func (s *blockingStorage) AbortMultipartUpload(key, uploadID string) error {
+	s.mu.Lock()
+	s.aborted = append(s.aborted, key)
+	s.mu.Unlock()
+	return s.MemoryStorage.AbortMultipartUpload(key, uploadID)
+}
+
+func TestCloseRejectsNewOperations(t *testing.T) {
+	server := newEnvelopeServer(t, `{"code":0,"data":{"models":[]}}`)
+	client, _ := newTestClient(t, &ClientConfig{BaseURL: server.URL})
+
+	if err := client.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, err := client.UploadFile("nope.wav", "req/nope.wav"); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("UploadFile after Close = %v, want ErrClientClosed", err)
+	}
+	if _, err := client.SplitAudioFile("nope.wav", 10); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("SplitAudioFile after Close = %v, want ErrClientClosed", err)
+	}
+	if _, err := client.ListModels(); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("ListModels after Close = %v, want ErrClientClosed", err)
+	}
+	// Closing again is a no-op, not a panic or an error.
+	if err := client.Close(context.Background()); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+}
+
+func TestCloseWaitsForInFlightUpload(t *testing.T) {
+	store := newBlockingStorage()
+	client, err := NewClient("ak", "sk", &ClientConfig{Storage: store})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wav := makeWAVFile(t, 16000, 1, 1)
+
+	uploadErr := make(chan error, 1)
+	go func() {
+		_, err := client.UploadFile(wav, "req/audio.wav")
+		uploadErr <- err
+	}()
+	<-store.entered
+
+	// Let the parked transfer finish shortly after Close starts draining.
+	time.AfterFunc(20*time.Millisecond, func() { close(store.release) })
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Close(ctx); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := <-uploadErr; err != nil {
+		t.Fatalf("in-flight upload was not allowed to finish: %v", err)
+	}
+	if _, ok := store.Object("req/audio.wav"); !ok {
+		t.Error("upload drained by Close left no object behind")
+	}
+}
+
+func TestCloseAbortsInterruptedMultipart(t *testing.T) {
+	store := newBlockingStorage()
+	client, err := NewClient("ak", "sk", &ClientConfig{Storage: store, ChunkSize: 16 * 1024})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := makeWAVFile(t, 16000, 1, 1)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		client.MultipartUploadFile(path, "req/audio.wav")
+	}()
+	<-store.entered
+
+	// The deadline passes while the first part is still parked: Close gives
+	// up on the drain and aborts the open multipart upload.
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	if err := client.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Close = %v, want context.DeadlineExceeded", err)
+	}
+	store.mu.Lock()
+	aborted := len(store.aborted) > 0
+	store.mu.Unlock()
+	if !aborted {
+		t.Error("open multipart upload was not aborted")
+	}
+	close(store.release)
+	<-done
+}
+
+func TestCloseRemovesTempDir(t *testing.T) {
+	client, _ := newTestClient(t, nil)
+	if _, err := client.SplitAudioFile(makeWAVFile(t, 16000, 1, 3), 1); err != nil {
+		t.Fatalf("SplitAudioFile: %v", err)
+	}
+	dir, err := client.life.chunkTempDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(dir); err != nil {
+		t.Fatalf("temp dir missing before Close: %v", err)
+	}
+	if err := client.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("temp dir still present after Close (stat err = %v)", err)
+	}
+}
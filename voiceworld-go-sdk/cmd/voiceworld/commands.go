@@ -0,0 +1,236 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+
+	voiceworld "github.com/voiceworld/voiceworld-go-sdk"
+)
+
+func cmdToken(ctx *cmdContext, args []string) error {
+	fs := ctx.flagSet("token")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	client, err := ctx.client()
+	if err != nil {
+		return err
+	}
+	token, err := client.GetOSSToken()
+	if err != nil {
+		return err
+	}
+	return ctx.emit(token, func(w io.Writer) {
+		fmt.Fprintf(w, "bucket:     %s\n", token.Bucket)
+		fmt.Fprintf(w, "endpoint:   %s\n", token.Endpoint)
+		fmt.Fprintf(w, "expiration: %s\n", token.Expiration)
+	})
+}
+
+func cmdUpload(ctx *cmdContext, args []string) error {
+	fs := ctx.flagSet("upload")
+	object := fs.String("object", "", "object key to upload under (default: the file's base name)")
+	multipart := fs.Bool("multipart", false, "use multipart upload for large files")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("upload: expected exactly one file argument")
+	}
+	file := fs.Arg(0)
+	client, err := ctx.client()
+	if err != nil {
+		return err
+	}
+	objectName := *object
+	if objectName == "" {
+		objectName = voiceworld.NewRequestID() + "/" + filepath.Base(file)
+	}
+
+	ctx.progress("uploading %s as %s", file, objectName)
+	var url string
+	if *multipart {
+		url, err = client.MultipartUploadFile(file, objectName)
+	} else {
+		url, err = client.UploadFile(file, objectName)
+	}
+	if err != nil {
+		return err
+	}
+	out := struct {
+		ObjectKey string `json:"object_key"`
+		URL       string `json:"url"`
+	}{objectName, url}
+	return ctx.emit(out, func(w io.Writer) {
+		fmt.Fprintln(w, url)
+	})
+}
+
+func cmdSplit(ctx *cmdContext, args []string) error {
+	fs := ctx.flagSet("split")
+	duration := fs.Int("chunk-duration", voiceworld.DefaultSplitDuration, "chunk length in seconds")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("split: expected exactly one file argument")
+	}
+	client, err := ctx.client()
+	if err != nil {
+		return err
+	}
+
+	ctx.progress("splitting %s into %ds chunks", fs.Arg(0), *duration)
+	result, err := client.SplitAudioFile(fs.Arg(0), *duration)
+	if err != nil {
+		return err
+	}
+	return ctx.emit(result, func(w io.Writer) {
+		fmt.Fprintf(w, "request id: %s (%d parts, %.1fs total)\n",
+			result.RequestID, len(result.Parts), result.TotalDuration)
+		for _, p := range result.Parts {
+			fmt.Fprintf(w, "  %4d  %-30s %8d bytes  %.1fs\n", p.Index, p.ObjectKey, p.Size, p.Duration)
+		}
+	})
+}
+
+func cmdRecognize(ctx *cmdContext, args []string) error {
+	fs := ctx.flagSet("recognize")
+	url := fs.String("url", "", "recognize an already-uploaded URL instead of a local file")
+	format := fs.String("format", "", "audio format, e.g. wav")
+	sampleRate := fs.Int("sample-rate", 0, "sample rate in Hz")
+	requestID := fs.String("request-id", "", "request id to correlate results with")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if (*url == "") == (fs.NArg() != 1) {
+		return fmt.Errorf("recognize: pass exactly one local file, or --url")
+	}
+	client, err := ctx.client()
+	if err != nil {
+		return err
+	}
+	config := &voiceworld.RecognitionConfig{Format: *format, SampleRate: *sampleRate, RequestID: *requestID}
+
+	var result *voiceworld.RecognitionResult
+	if *url != "" {
+		ctx.progress("recognizing %s", *url)
+		result, err = client.RecognizeURL(*url, config)
+	} else {
+		ctx.progress("recognizing %s", fs.Arg(0))
+		result, err = client.ASR(fs.Arg(0), config)
+	}
+	if err != nil {
+		return err
+	}
+	return ctx.emit(result, func(w io.Writer) {
+		fmt.Fprintln(w, result.Result)
+	})
+}
+
+func cmdPreprocess(ctx *cmdContext, args []string) error {
+	fs := ctx.flagSet("preprocess")
+	upload := fs.Bool("upload", false, "upload the processed file and print its URL")
+	requestID := fs.String("request-id", "", "request id for --upload (default: generated)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("preprocess: expected exactly one file argument")
+	}
+
+	ctx.progress("processing %s", fs.Arg(0))
+	processed, err := voiceworld.ProcessAudio(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	out := struct {
+		Path string `json:"path"`
+		URL  string `json:"url,omitempty"`
+	}{Path: processed}
+
+	if *upload {
+		client, err := ctx.client()
+		if err != nil {
+			return err
+		}
+		id := *requestID
+		if id == "" {
+			id = voiceworld.NewRequestID()
+		}
+		ctx.progress("uploading processed audio for %s", id)
+		out.URL, err = client.UploadPreprocessedAudio(processed, id)
+		if err != nil {
+			return err
+		}
+	}
+	return ctx.emit(out, func(w io.Writer) {
+		fmt.Fprintln(w, out.Path)
+		if out.URL != "" {
+			fmt.Fprintln(w, out.URL)
+		}
+	})
+}
+
+func cmdDoctor(ctx *cmdContext, args []string) error {
+	fs := ctx.flagSet("doctor")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	client, err := ctx.client()
+	if err != nil {
+		return err
+	}
+	ctx.progress("running object storage pre-flight checks")
+	report, err := client.DiagnoseOSS()
+	if err != nil {
+		return err
+	}
+	if err := ctx.emit(report, func(w io.Writer) {
+		for _, check := range report.Checks {
+			state := "ok  "
+			switch {
+			case !check.Attempted:
+				state = "skip"
+			case !check.Passed:
+				state = "FAIL"
+			}
+			fmt.Fprintf(w, "%s  %-12s %s\n", state, check.Name, check.Detail)
+			if check.Hint != "" {
+				fmt.Fprintf(w, "      hint: %s\n", check.Hint)
+			}
+		}
+	}); err != nil {
+		return err
+	}
+	if !report.Passed() {
+		return fmt.Errorf("doctor: configuration problems found")
+	}
+	return nil
+}
+
+func cmdCleanup(ctx *cmdContext, args []string) error {
+	fs := ctx.flagSet("cleanup")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("cleanup: expected exactly one request id argument")
+	}
+	client, err := ctx.client()
+	if err != nil {
+		return err
+	}
+	ctx.progress("deleting staged objects for %s", fs.Arg(0))
+	if err := client.Cleanup(fs.Arg(0)); err != nil {
+		return err
+	}
+	out := struct {
+		RequestID string `json:"request_id"`
+		Deleted   bool   `json:"deleted"`
+	}{fs.Arg(0), true}
+	return ctx.emit(out, func(w io.Writer) {
+		fmt.Fprintf(w, "cleaned up %s\n", fs.Arg(0))
+	})
+}
@@ -0,0 +1,155 @@
+// Command voiceworld is a thin command-line wrapper over the VoiceWorld Go
+// SDK. Each subcommand maps to one public SDK flow, so the source here
+// doubles as usage documentation for the library.
+//
+// Credentials are resolved in order: flags, then VOICEWORLD_* environment
+// variables, then the --config JSON file.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	voiceworld "github.com/voiceworld/voiceworld-go-sdk"
+)
+
+const usageText = `usage: voiceworld <command> [flags] [args]
+
+commands:
+  token       fetch temporary object storage credentials
+  upload      upload an audio file and print its signed URL
+  split       split a WAV file into chunks and upload them
+  recognize   recognize a local file or an already-uploaded URL
+  preprocess  convert audio to 16 kHz mono PCM, optionally uploading it
+  cleanup     delete all staged objects for a request id
+  doctor      run pre-flight checks against the configured object storage
+
+common flags (every command):
+  --app-key, --secret-key, --base-url, --config FILE, --json, --quiet
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes one CLI invocation and returns the process exit code. It is
+// separated from main so tests can drive it directly.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usageText)
+		return 2
+	}
+	cmd, rest := args[0], args[1:]
+
+	handlers := map[string]func(*cmdContext, []string) error{
+		"token":      cmdToken,
+		"upload":     cmdUpload,
+		"split":      cmdSplit,
+		"recognize":  cmdRecognize,
+		"preprocess": cmdPreprocess,
+		"cleanup":    cmdCleanup,
+		"doctor":     cmdDoctor,
+	}
+	handler, ok := handlers[cmd]
+	if !ok {
+		fmt.Fprintf(stderr, "voiceworld: unknown command %q\n\n%s", cmd, usageText)
+		return 2
+	}
+
+	ctx := &cmdContext{stdout: stdout, stderr: stderr}
+	if err := handler(ctx, rest); err != nil {
+		if err == flag.ErrHelp {
+			return 2
+		}
+		fmt.Fprintf(stderr, "voiceworld: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// cmdContext carries the resolved common flags and output streams into each
+// subcommand handler.
+type cmdContext struct {
+	stdout, stderr io.Writer
+
+	appKey    string
+	secretKey string
+	baseURL   string
+	config    string
+	jsonOut   bool
+	quiet     bool
+}
+
+// flagSet builds a FlagSet pre-populated with the flags every subcommand
+// shares.
+func (ctx *cmdContext) flagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(ctx.stderr)
+	fs.StringVar(&ctx.appKey, "app-key", "", "application key (or VOICEWORLD_APP_KEY)")
+	fs.StringVar(&ctx.secretKey, "secret-key", "", "application secret (or VOICEWORLD_SECRET_KEY)")
+	fs.StringVar(&ctx.baseURL, "base-url", "", "API endpoint (or VOICEWORLD_BASE_URL)")
+	fs.StringVar(&ctx.config, "config", "", "JSON config file with app_key/secret_key/base_url")
+	fs.BoolVar(&ctx.jsonOut, "json", false, "print machine-readable JSON instead of text")
+	fs.BoolVar(&ctx.quiet, "quiet", false, "suppress progress output")
+	return fs
+}
+
+// fileConfig is the on-disk shape accepted by --config.
+type fileConfig struct {
+	AppKey    string `json:"app_key"`
+	SecretKey string `json:"secret_key"`
+	BaseURL   string `json:"base_url"`
+}
+
+// client resolves credentials (flags > environment > config file) and builds
+// the SDK client.
+func (ctx *cmdContext) client() (*voiceworld.Client, error) {
+	var fc fileConfig
+	if ctx.config != "" {
+		raw, err := os.ReadFile(ctx.config)
+		if err != nil {
+			return nil, fmt.Errorf("read config: %w", err)
+		}
+		if err := json.Unmarshal(raw, &fc); err != nil {
+			return nil, fmt.Errorf("parse config %s: %w", ctx.config, err)
+		}
+	}
+	appKey := firstNonEmpty(ctx.appKey, os.Getenv("VOICEWORLD_APP_KEY"), fc.AppKey)
+	secretKey := firstNonEmpty(ctx.secretKey, os.Getenv("VOICEWORLD_SECRET_KEY"), fc.SecretKey)
+	baseURL := firstNonEmpty(ctx.baseURL, os.Getenv("VOICEWORLD_BASE_URL"), fc.BaseURL)
+	if appKey == "" || secretKey == "" {
+		return nil, fmt.Errorf("no credentials: pass --app-key/--secret-key, set VOICEWORLD_APP_KEY/VOICEWORLD_SECRET_KEY, or use --config")
+	}
+	return voiceworld.NewClient(appKey, secretKey, &voiceworld.ClientConfig{BaseURL: baseURL})
+}
+
+// emit prints v as indented JSON under --json, or hands off to text for the
+// human-readable form.
+func (ctx *cmdContext) emit(v interface{}, text func(io.Writer)) error {
+	if ctx.jsonOut {
+		enc := json.NewEncoder(ctx.stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+	text(ctx.stdout)
+	return nil
+}
+
+// progress writes a status line unless --quiet is set.
+func (ctx *cmdContext) progress(format string, args ...interface{}) {
+	if !ctx.quiet {
+		fmt.Fprintf(ctx.stderr, format+"\n", args...)
+	}
+}
+
+func firstNonEmpty(vals ...string) string {
+	for _, v := range vals {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fakeAPI is a minimal VoiceWorld backend for exercising command wiring.
+func fakeAPI(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/get_oss_token", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"code":0,"message":"ok","data":{
+			"access_key_id":"id","access_key_secret":"secret","security_token":"token",
+			"expiration":"2099-01-01T00:00:00Z","endpoint":"oss.example.com","bucket":"test-bucket"}}`)
+	})
+	mux.HandleFunc("/asr", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"code":0,"message":"ok","data":{"task_id":"t1","result":"hello world","duration":1200}}`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func runCLI(t *testing.T, args ...string) (stdout, stderr string, code int) {
+	t.Helper()
+	var out, errBuf bytes.Buffer
+	code = run(args, &out, &errBuf)
+	return out.String(), errBuf.String(), code
+}
+
+func withCreds(srv *httptest.Server, args ...string) []string {
+	return append(args, "--app-key", "ak", "--secret-key", "sk", "--base-url", srv.URL)
+}
+
+func TestNoArgsPrintsUsage(t *testing.T) {
+	_, stderr, code := runCLI(t)
+	if code != 2 {
+		t.Fatalf("exit code = %d, want 2", code)
+	}
+	if !strings.Contains(stderr, "usage:") {
+		t.Errorf("stderr missing usage, got %q", stderr)
+	}
+}
+
+func TestUnknownCommand(t *testing.T) {
+	_, stderr, code := runCLI(t, "frobnicate")
+	if code != 2 {
+		t.Fatalf("exit code = %d, want 2", code)
+	}
+	if !strings.Contains(stderr, "unknown command") {
+		t.Errorf("stderr missing diagnostic, got %q", stderr)
+	}
+}
+
+func TestMissingCredentials(t *testing.T) {
+	_, stderr, code := runCLI(t, "token")
+	if code != 1 {
+		t.Fatalf("exit code = %d, want 1", code)
+	}
+	if !strings.Contains(stderr, "no credentials") {
+		t.Errorf("stderr missing credentials diagnostic, got %q", stderr)
+	}
+}
+
+func TestTokenHumanOutput(t *testing.T) {
+	srv := fakeAPI(t)
+	stdout, _, code := runCLI(t, withCreds(srv, "token")...)
+	if code != 0 {
+		t.Fatalf("exit code = %d, want 0", code)
+	}
+	if !strings.Contains(stdout, "test-bucket") {
+		t.Errorf("stdout missing bucket name, got %q", stdout)
+	}
+}
+
+func TestTokenJSONOutput(t *testing.T) {
+	srv := fakeAPI(t)
+	stdout, _, code := runCLI(t, withCreds(srv, "token", "--json")...)
+	if code != 0 {
+		t.Fatalf("exit code = %d, want 0", code)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(stdout), &decoded); err != nil {
+		t.Fatalf("--json output is not valid JSON: %v\n%s", err, stdout)
+	}
+	if decoded["bucket"] != "test-bucket" {
+		t.Errorf("bucket = %v, want test-bucket", decoded["bucket"])
+	}
+}
+
+func TestRecognizeURL(t *testing.T) {
+	srv := fakeAPI(t)
+	stdout, _, code := runCLI(t, withCreds(srv, "recognize", "--url", "https://example.com/a.wav")...)
+	if code != 0 {
+		t.Fatalf("exit code = %d, want 0", code)
+	}
+	if strings.TrimSpace(stdout) != "hello world" {
+		t.Errorf("stdout = %q, want transcript", stdout)
+	}
+}
+
+func TestRecognizeRejectsFileAndURL(t *testing.T) {
+	srv := fakeAPI(t)
+	_, _, code := runCLI(t, withCreds(srv, "recognize", "--url", "https://example.com/a.wav", "also-a-file.wav")...)
+	if code != 1 {
+		t.Fatalf("exit code = %d, want 1", code)
+	}
+}
+
+func TestQuietSuppressesProgress(t *testing.T) {
+	srv := fakeAPI(t)
+	_, stderr, code := runCLI(t, withCreds(srv, "recognize", "--quiet", "--url", "https://example.com/a.wav")...)
+	if code != 0 {
+		t.Fatalf("exit code = %d, want 0", code)
+	}
+	if stderr != "" {
+		t.Errorf("expected no progress output with --quiet, got %q", stderr)
+	}
+}
+
+func TestAPIErrorExitCode(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/asr", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"code":40001,"message":"invalid audio"}`)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	_, stderr, code := runCLI(t, withCreds(srv, "recognize", "--url", "https://example.com/a.wav")...)
+	if code != 1 {
+		t.Fatalf("exit code = %d, want 1", code)
+	}
+	if !strings.Contains(stderr, "40001") {
+		t.Errorf("stderr missing api error code, got %q", stderr)
+	}
+}
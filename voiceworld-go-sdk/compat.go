@@ -0,0 +1,174 @@
+package voiceworld
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// Response schema negotiation. Requests announce the schema generation the
+// SDK speaks via X-API-Version; the server echoes the generation it answered
+// with, which the client records for diagnostics. Decoding stays tolerant of
+// the previous known generation regardless, so a fleet mid-upgrade never
+// breaks the SDK: renamed fields are accepted under both names and
+// normalized into the current structs.
+const (
+	headerAPIVersion = "X-API-Version"
+
+	// LatestAPIVersion is the newest response schema generation this SDK
+	// understands, announced by default on every request.
+	LatestAPIVersion = "2"
+)
+
+// versionState is the server-reported schema version, shared across derived
+// clients the way clockState is.
+type versionState struct {
+	mu     sync.Mutex
+	server string
+}
+
+// apiVersion is the schema version to announce: the configured override or
+// the latest known.
+func (c *Client) apiVersion() string {
+	if c.config.APIVersion != "" {
+		return c.config.APIVersion
+	}
+	return LatestAPIVersion
+}
+
+// recordServerVersion remembers the schema version the server said it
+// answered with; empty headers leave the last observation in place.
+func (c *Client) recordServerVersion(version string) {
+	if version == "" {
+		return
+	}
+	c.version.mu.Lock()
+	c.version.server = version
+	c.version.mu.Unlock()
+}
+
+// ServerAPIVersion returns the response schema version the server most
+// recently reported, or "" before the first versioned response. A value
+// different from the announced version means the server decided the
+// generation; the tolerant decoders normalize both known ones.
+func (c *Client) ServerAPIVersion() string {
+	c.version.mu.Lock()
+	defer c.version.mu.Unlock()
+	return c.version.server
+}
+
+// UnmarshalJSON accepts both known credential schemas: the current flat
+// snake_case fields, and the previous generation's PascalCase STS fields,
+// optionally nested under a "credentials" object. Whatever arrives is
+// normalized into the current struct.
+func (t *OSSCredentials) UnmarshalJSON(data []byte) error {
+	type plain OSSCredentials
+	var p plain
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+	*t = OSSCredentials(p)
+	if t.AccessKeyID != "" && t.AccessKeySecret != "" {
+		return nil
+	}
+	var legacy struct {
+		AccessKeyID     string          `json:"AccessKeyId"`
+		AccessKeySecret string          `json:"AccessKeySecret"`
+		SecurityToken   string          `json:"SecurityToken"`
+		Expiration      string          `json:"Expiration"`
+		Endpoint        string          `json:"Endpoint"`
+		Bucket          string          `json:"Bucket"`
+		Credentials     json.RawMessage `json:"credentials"`
+	}
+	// Legacy decoding is best-effort: a shape that matches neither
+	// generation leaves the current-schema result standing.
+	if err := json.Unmarshal(data, &legacy); err != nil {
+		return nil
+	}
+	if len(legacy.Credentials) > 0 {
+		var nested OSSCredentials
+		if err := json.Unmarshal(legacy.Credentials, &nested); err == nil {
+			mergeCredentials(t, &nested)
+		}
+	}
+	mergeCredentials(t, &OSSCredentials{
+		AccessKeyID:     legacy.AccessKeyID,
+		AccessKeySecret: legacy.AccessKeySecret,
+		SecurityToken:   legacy.SecurityToken,
+		Expiration:      legacy.Expiration,
+		Endpoint:        legacy.Endpoint,
+		Bucket:          legacy.Bucket,
+	})
+	return nil
+}
+
+// mergeCredentials fills dst's empty fields from src.
+func mergeCredentials(dst, src *OSSCredentials) {
+	if dst.AccessKeyID == "" {
+		dst.AccessKeyID = src.AccessKeyID
+	}
+	if dst.AccessKeySecret == "" {
+		dst.AccessKeySecret = src.AccessKeySecret
+	}
+	if dst.SecurityToken == "" {
+		dst.SecurityToken = src.SecurityToken
+	}
+	if dst.Expiration == "" {
+		dst.Expiration = src.Expiration
+	}
+	if dst.Endpoint == "" {
+		dst.Endpoint = src.Endpoint
+	}
+	if dst.Bucket == "" {
+		dst.Bucket = src.Bucket
+	}
+}
+
+// normalizeRecognitionResult backfills a recognition result that decoded
+// from a non-current schema generation: the previous generation's field
+// names ("taskId", "text") are accepted for anything the current names left
+// empty, and the raw envelope data is retained on RawData so fields an
+// unknown generation moved elsewhere stay reachable. Responses matching the
+// current schema pass through untouched. It runs on the envelope decode
+// path, not as an UnmarshalJSON method, so types embedding
+// RecognitionResult keep their own fields decoding normally.
+func normalizeRecognitionResult(out interface{}, raw json.RawMessage) {
+	r, ok := out.(*RecognitionResult)
+	if !ok || (r.Result != "" && r.TaskID != "") {
+		return
+	}
+	r.RawData = append(json.RawMessage(nil), raw...)
+	var legacy struct {
+		TaskID string `json:"taskId"`
+		Text   string `json:"text"`
+	}
+	if err := json.Unmarshal(raw, &legacy); err != nil {
+		return
+	}
+	if r.TaskID == "" {
+		r.TaskID = legacy.TaskID
+	}
+	if r.Result == "" {
+		r.Result = legacy.Text
+	}
+}
+
+// preprocessResponse tolerates both names the preprocessed object's URL has
+// shipped under.
+type preprocessResponse struct {
+	URL string
+}
+
+func (p *preprocessResponse) UnmarshalJSON(data []byte) error {
+	var known struct {
+		URL          string `json:"url"`
+		ProcessedURL string `json:"processed_url"`
+	}
+	if err := json.Unmarshal(data, &known); err != nil {
+		return err
+	}
+	p.URL = known.URL
+	if p.URL == "" {
+		p.URL = known.ProcessedURL
+	}
+	return nil
+}
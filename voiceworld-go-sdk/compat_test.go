@@ -0,0 +1,130 @@
+package voiceworld
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIVersionNegotiation(t *testing.T) {
+	var announced string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		announced = r.Header.Get(headerAPIVersion)
+		w.Header().Set(headerAPIVersion, "3")
+		w.Write([]byte(`{"code":0}`))
+	}))
+	defer server.Close()
+	client, _ := newTestClient(t, &ClientConfig{BaseURL: server.URL})
+
+	if got := client.ServerAPIVersion(); got != "" {
+		t.Errorf("ServerAPIVersion before any call = %q", got)
+	}
+	if err := client.postJSON("/noop", struct{}{}, nil); err != nil {
+		t.Fatalf("postJSON: %v", err)
+	}
+	if announced != LatestAPIVersion {
+		t.Errorf("announced version = %q, want %q", announced, LatestAPIVersion)
+	}
+	if got := client.ServerAPIVersion(); got != "3" {
+		t.Errorf("ServerAPIVersion = %q, want %q", got, "3")
+	}
+
+	client, _ = newTestClient(t, &ClientConfig{BaseURL: server.URL, APIVersion: "1"})
+	if err := client.postJSON("/noop", struct{}{}, nil); err != nil {
+		t.Fatalf("postJSON: %v", err)
+	}
+	if announced != "1" {
+		t.Errorf("announced version = %q, want configured %q", announced, "1")
+	}
+}
+
+func TestOSSCredentialsDecodeKnownSchemas(t *testing.T) {
+	fixtures := []struct {
+		name string
+		data string
+	}{
+		{"current flat snake_case", `{
+			"access_key_id": "AKID", "access_key_secret": "SECRET",
+			"security_token": "TOKEN", "expiration": "2026-01-01T00:00:00Z",
+			"endpoint": "oss.example.com", "bucket": "audio"
+		}`},
+		{"previous flat PascalCase", `{
+			"AccessKeyId": "AKID", "AccessKeySecret": "SECRET",
+			"SecurityToken": "TOKEN", "Expiration": "2026-01-01T00:00:00Z",
+			"Endpoint": "oss.example.com", "Bucket": "audio"
+		}`},
+		{"previous nested credentials", `{
+			"credentials": {"access_key_id": "AKID", "access_key_secret": "SECRET", "security_token": "TOKEN"},
+			"expiration": "2026-01-01T00:00:00Z", "endpoint": "oss.example.com", "bucket": "audio"
+		}`},
+	}
+	for _, tt := range fixtures {
+		t.Run(tt.name, func(t *testing.T) {
+			var creds OSSCredentials
+			if err := json.Unmarshal([]byte(tt.data), &creds); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			if creds.AccessKeyID != "AKID" || creds.AccessKeySecret != "SECRET" || creds.SecurityToken != "TOKEN" {
+				t.Errorf("credentials = %+v", creds)
+			}
+			if creds.Expiration != "2026-01-01T00:00:00Z" || creds.Endpoint != "oss.example.com" || creds.Bucket != "audio" {
+				t.Errorf("metadata = %+v", creds)
+			}
+		})
+	}
+}
+
+func TestASRDecodesPreviousSchema(t *testing.T) {
+	server := newEnvelopeServer(t, `{"code":0,"data":{"taskId":"t-2","text":"bonjour"}}`)
+	client, _ := newTestClient(t, &ClientConfig{BaseURL: server.URL})
+	result, err := client.ASR(makeWAVFile(t, 16000, 1, 0.1), nil)
+	if err != nil {
+		t.Fatalf("ASR: %v", err)
+	}
+	if result.TaskID != "t-2" || result.Result != "bonjour" {
+		t.Errorf("legacy schema decoded to (%q, %q)", result.TaskID, result.Result)
+	}
+	if len(result.RawData) == 0 {
+		t.Error("legacy response did not retain RawData")
+	}
+}
+
+func TestASRKeepsRawDataForUnknownSchemas(t *testing.T) {
+	server := newEnvelopeServer(t, `{"code":0,"data":{"result":"hi","brand_new_field":{"x":1}}}`)
+	client, _ := newTestClient(t, &ClientConfig{BaseURL: server.URL})
+	result, err := client.ASR(makeWAVFile(t, 16000, 1, 0.1), nil)
+	if err != nil {
+		t.Fatalf("ASR: %v", err)
+	}
+	var probe struct {
+		Extra json.RawMessage `json:"brand_new_field"`
+	}
+	if err := json.Unmarshal(result.RawData, &probe); err != nil || len(probe.Extra) == 0 {
+		t.Errorf("unknown field not reachable through RawData: %v / %s", err, result.RawData)
+	}
+
+	// A response fully matching the current schema travels without the
+	// extra copy.
+	current := newEnvelopeServer(t, `{"code":0,"data":{"task_id":"t-1","result":"hello"}}`)
+	client, _ = newTestClient(t, &ClientConfig{BaseURL: current.URL})
+	result, err = client.ASR(makeWAVFile(t, 16000, 1, 0.1), nil)
+	if err != nil {
+		t.Fatalf("ASR: %v", err)
+	}
+	if len(result.RawData) != 0 {
+		t.Errorf("current-schema response retained RawData: %s", result.RawData)
+	}
+}
+
+func TestPreprocessResponseDecodesBothNames(t *testing.T) {
+	for _, data := range []string{`{"url":"https://a/x.wav"}`, `{"processed_url":"https://a/x.wav"}`} {
+		var resp preprocessResponse
+		if err := json.Unmarshal([]byte(data), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.URL != "https://a/x.wav" {
+			t.Errorf("decoded %s to %q", data, resp.URL)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package voiceworld
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEnableCompressionGzipsJSONBodies(t *testing.T) {
+	var gotEncoding, gotAccept string
+	var gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotEncoding = r.Header.Get("Content-Encoding")
+		gotAccept = r.Header.Get("Accept-Encoding")
+		body := r.Body
+		if gotEncoding == "gzip" {
+			gz, err := gzip.NewReader(body)
+			if err != nil {
+				t.Errorf("server gunzip: %v", err)
+				return
+			}
+			body = gz
+		}
+		raw, _ := io.ReadAll(body)
+		gotBody = string(raw)
+		fmt.Fprint(w, `{"code":0,"message":"ok","data":{"url":"u"}}`)
+	}))
+	defer srv.Close()
+
+	client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: srv.URL, EnableCompression: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.PreprocessAudio("https://example.com/a.wav", "req-1"); err != nil {
+		t.Fatalf("PreprocessAudio: %v", err)
+	}
+	if gotEncoding != "gzip" {
+		t.Errorf("Content-Encoding = %q, want gzip", gotEncoding)
+	}
+	if gotAccept != "gzip" {
+		t.Errorf("Accept-Encoding = %q, want gzip", gotAccept)
+	}
+	if !strings.Contains(gotBody, "example.com/a.wav") {
+		t.Errorf("decompressed body = %q, want the request payload", gotBody)
+	}
+}
+
+func TestCompressionOffByDefault(t *testing.T) {
+	var gotEncoding string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotEncoding = r.Header.Get("Content-Encoding")
+		fmt.Fprint(w, `{"code":0,"message":"ok","data":{"url":"u"}}`)
+	}))
+	defer srv.Close()
+
+	client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.PreprocessAudio("https://example.com/a.wav", ""); err != nil {
+		t.Fatal(err)
+	}
+	if gotEncoding != "" {
+		t.Errorf("Content-Encoding = %q, want identity", gotEncoding)
+	}
+}
+
+func TestGzippedResponsesAreDecompressed(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		fmt.Fprint(gz, `{"code":0,"message":"ok","data":{"task_id":"tz","result":"compressed hello"}}`)
+		gz.Close()
+	}))
+	defer srv.Close()
+
+	client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := client.RecognizeURL("https://example.com/a.wav", nil)
+	if err != nil {
+		t.Fatalf("RecognizeURL: %v", err)
+	}
+	if result.Result != "compressed hello" {
+		t.Errorf("Result = %q, want the decompressed payload", result.Result)
+	}
+}
+
+func TestAudioBodiesAreNeverCompressed(t *testing.T) {
+	payload := []byte{0x00, 0x01, 0x02, 0x03}
+	var gotEncoding string
+	var gotBody []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotEncoding = r.Header.Get("Content-Encoding")
+		gotBody, _ = io.ReadAll(r.Body)
+		fmt.Fprint(w, `{"code":0,"message":"ok","data":{"task_id":"t","result":"x"}}`)
+	}))
+	defer srv.Close()
+
+	client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: srv.URL, EnableCompression: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "a.pcm")
+	if err := os.WriteFile(path, payload, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ASR(path, nil); err != nil {
+		t.Fatal(err)
+	}
+	if gotEncoding != "" {
+		t.Errorf("audio body Content-Encoding = %q, want identity", gotEncoding)
+	}
+	if string(gotBody) != string(payload) {
+		t.Errorf("audio body was transformed: % x", gotBody)
+	}
+}
@@ -0,0 +1,108 @@
+package voiceworld
+
+import "strings"
+
+// ConfidenceFilterOptions tunes how FilterByConfidence treats segments the
+// server returned without a confidence value.
+type ConfidenceFilterOptions struct {
+	// IncludeMissing counts segments without a confidence as needing
+	// review. The default assumes the server was confident (treats the
+	// missing value as 1.0).
+	IncludeMissing bool
+}
+
+// FilterByConfidence returns the segments whose confidence is below min -
+// the ones a human reviewer should look at. It operates purely on the
+// decoded result. opts may be nil for the defaults.
+func (r *RecognitionResult) FilterByConfidence(min float64, opts *ConfidenceFilterOptions) []Segment {
+	if opts == nil {
+		opts = &ConfidenceFilterOptions{}
+	}
+	var low []Segment
+	for _, seg := range r.Segments {
+		if seg.Confidence == 0 {
+			if opts.IncludeMissing {
+				low = append(low, seg)
+			}
+			continue
+		}
+		if seg.Confidence < min {
+			low = append(low, seg)
+		}
+	}
+	return low
+}
+
+// Default markers for AnnotateLowConfidence; visually distinct and unlikely
+// to collide with transcript text.
+const (
+	defaultOpenMarker  = "⟦"
+	defaultCloseMarker = "⟧"
+)
+
+// AnnotateOptions tunes AnnotateLowConfidence.
+type AnnotateOptions struct {
+	// OpenMarker and CloseMarker wrap each low-confidence word. Empty
+	// means the defaults "⟦" and "⟧".
+	OpenMarker  string
+	CloseMarker string
+	// IncludeMissing marks words without a confidence value too.
+	IncludeMissing bool
+}
+
+// Annotation locates one marked word inside an annotated transcript.
+type Annotation struct {
+	// WordIndex is the word's position in the result's Words slice, so
+	// reviewer edits can be mapped back to the original timing data.
+	WordIndex int
+	// Start and End are byte offsets of the marked span (markers
+	// included) in the annotated text.
+	Start int
+	End   int
+}
+
+// AnnotatedTranscript is a transcript with low-confidence words wrapped in
+// markers, plus the index needed to map the marks back to words.
+type AnnotatedTranscript struct {
+	Text        string
+	Annotations []Annotation
+}
+
+// AnnotateLowConfidence rebuilds the transcript from the result's words,
+// wrapping every word whose confidence is below min in markers. Words
+// without a confidence value are left alone unless IncludeMissing is set.
+// opts may be nil for the defaults.
+func (r *RecognitionResult) AnnotateLowConfidence(min float64, opts *AnnotateOptions) *AnnotatedTranscript {
+	if opts == nil {
+		opts = &AnnotateOptions{}
+	}
+	open, close := opts.OpenMarker, opts.CloseMarker
+	if open == "" {
+		open = defaultOpenMarker
+	}
+	if close == "" {
+		close = defaultCloseMarker
+	}
+
+	annotated := &AnnotatedTranscript{}
+	var b strings.Builder
+	for i, word := range r.Words {
+		if i > 0 {
+			b.WriteString(" ")
+		}
+		low := word.Confidence != 0 && word.Confidence < min ||
+			word.Confidence == 0 && opts.IncludeMissing
+		if !low {
+			b.WriteString(word.Text)
+			continue
+		}
+		ann := Annotation{WordIndex: i, Start: b.Len()}
+		b.WriteString(open)
+		b.WriteString(word.Text)
+		b.WriteString(close)
+		ann.End = b.Len()
+		annotated.Annotations = append(annotated.Annotations, ann)
+	}
+	annotated.Text = b.String()
+	return annotated
+}
@@ -0,0 +1,85 @@
+package voiceworld
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFilterByConfidence(t *testing.T) {
+	result := &RecognitionResult{Segments: []Segment{
+		{Text: "sure", Confidence: 0.97},
+		{Text: "shaky", Confidence: 0.55},
+		{Text: "unscored"}, // server reported no confidence
+		{Text: "borderline", Confidence: 0.80},
+		{Text: "bad", Confidence: 0.12},
+	}}
+	for _, tc := range []struct {
+		name string
+		min  float64
+		opts *ConfidenceFilterOptions
+		want []string
+	}{
+		{name: "default skips unscored", min: 0.8, want: []string{"shaky", "bad"}},
+		{
+			name: "unscored included on request",
+			min:  0.8,
+			opts: &ConfidenceFilterOptions{IncludeMissing: true},
+			want: []string{"shaky", "unscored", "bad"},
+		},
+		{name: "min at boundary excludes equal", min: 0.97, want: []string{"shaky", "borderline", "bad"}},
+		{name: "nothing below", min: 0.05, want: nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []string
+			for _, seg := range result.FilterByConfidence(tc.min, tc.opts) {
+				got = append(got, seg.Text)
+			}
+			if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAnnotateLowConfidence(t *testing.T) {
+	result := &RecognitionResult{Words: []Word{
+		{Text: "turn", Confidence: 0.98},
+		{Text: "of", Confidence: 0.41},
+		{Text: "the", Confidence: 0.93},
+		{Text: "lights"}, // no confidence reported
+	}}
+
+	annotated := result.AnnotateLowConfidence(0.8, nil)
+	if annotated.Text != "turn ⟦of⟧ the lights" {
+		t.Errorf("Text = %q", annotated.Text)
+	}
+	if len(annotated.Annotations) != 1 {
+		t.Fatalf("Annotations = %+v", annotated.Annotations)
+	}
+	ann := annotated.Annotations[0]
+	if ann.WordIndex != 1 {
+		t.Errorf("WordIndex = %d", ann.WordIndex)
+	}
+	if got := annotated.Text[ann.Start:ann.End]; got != "⟦of⟧" {
+		t.Errorf("marked span = %q", got)
+	}
+
+	annotated = result.AnnotateLowConfidence(0.8, &AnnotateOptions{
+		OpenMarker:     "<<",
+		CloseMarker:    ">>",
+		IncludeMissing: true,
+	})
+	if annotated.Text != "turn <<of>> the <<lights>>" {
+		t.Errorf("Text = %q", annotated.Text)
+	}
+	if len(annotated.Annotations) != 2 || annotated.Annotations[1].WordIndex != 3 {
+		t.Errorf("Annotations = %+v", annotated.Annotations)
+	}
+}
+
+func TestAnnotateLowConfidenceNoWords(t *testing.T) {
+	annotated := (&RecognitionResult{Result: "no word timing"}).AnnotateLowConfidence(0.8, nil)
+	if annotated.Text != "" || len(annotated.Annotations) != 0 {
+		t.Errorf("annotated = %+v", annotated)
+	}
+}
@@ -0,0 +1,65 @@
+package voiceworld
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestContiguousTrackerRandomOrders(t *testing.T) {
+	const total = 40
+	for seed := int64(0); seed < 20; seed++ {
+		rng := rand.New(rand.NewSource(seed))
+		order := rng.Perm(total)
+
+		var reported []int
+		tracker := newContiguousTracker(total, func(n int) { reported = append(reported, n) })
+		completed := make(map[int]bool)
+		for _, zero := range order {
+			index := zero + 1
+			before := len(reported)
+			tracker.complete(index)
+			completed[index] = true
+			// Recompute the expected frontier from scratch and compare.
+			want := 0
+			for completed[want+1] {
+				want++
+			}
+			if len(reported) > before && reported[len(reported)-1] != want {
+				t.Fatalf("seed %d: after completing %d reported %d, want frontier %d",
+					seed, index, reported[len(reported)-1], want)
+			}
+		}
+		for i := 1; i < len(reported); i++ {
+			if reported[i] <= reported[i-1] {
+				t.Fatalf("seed %d: reported frontiers not strictly increasing: %v", seed, reported)
+			}
+		}
+		if len(reported) == 0 || reported[len(reported)-1] != total {
+			t.Fatalf("seed %d: final frontier reports %v, want last = %d", seed, reported, total)
+		}
+	}
+}
+
+func TestSplitReportsContiguousReady(t *testing.T) {
+	client, _ := newTestClient(t, &ClientConfig{Parallelism: 4})
+	audio := makeWAVFile(t, 16000, 1, 4)
+
+	var frontiers []int
+	result, err := client.SplitAudioFile(audio, 1, &SplitOptions{
+		ContiguousReady: func(n int) { frontiers = append(frontiers, n) },
+	})
+	if err != nil {
+		t.Fatalf("SplitAudioFile: %v", err)
+	}
+	if len(frontiers) == 0 {
+		t.Fatal("ContiguousReady never called")
+	}
+	for i := 1; i < len(frontiers); i++ {
+		if frontiers[i] <= frontiers[i-1] {
+			t.Fatalf("frontiers not strictly increasing: %v", frontiers)
+		}
+	}
+	if got := frontiers[len(frontiers)-1]; got != len(result.Parts) {
+		t.Errorf("final frontier = %d, want %d", got, len(result.Parts))
+	}
+}
@@ -0,0 +1,152 @@
+package voiceworld
+
+import (
+	"errors"
+	"fmt"
+)
+
+// multipartCopyThreshold is the object size above which CopyObject switches
+// from a single server-side copy to part-wise copy; OSS caps the single
+// form at sizes a multi-gigabyte archive exceeds. A variable so tests can
+// exercise the multipart path without staging gigabytes.
+var multipartCopyThreshold int64 = 1 << 30
+
+// ErrObjectNotFound reports an operation whose source key does not exist.
+// It is wrapped with the offending key; test with errors.Is.
+var ErrObjectNotFound = errors.New("voiceworld: object not found")
+
+// CopyOptions carries the optional knobs for CopyObject. Nil and the zero
+// value copy within the staging bucket.
+type CopyOptions struct {
+	// DestinationBucket copies into another bucket reachable with the same
+	// credentials, e.g. an archive bucket in the same account. Requires a
+	// backend with cross-bucket support (the OSS and S3 ones have it).
+	DestinationBucket string
+}
+
+// crossBucketCopyStorage is the optional capability behind
+// CopyOptions.DestinationBucket.
+type crossBucketCopyStorage interface {
+	CopyObjectTo(dstBucket, dstKey, srcKey string) error
+}
+
+// partCopyStorage is the optional capability behind multipart server-side
+// copy: part partNumber of the upload is filled from size bytes of srcKey
+// starting at offset, without the bytes passing through the client.
+type partCopyStorage interface {
+	UploadPartCopy(srcKey, dstKey, uploadID string, partNumber int, offset, size int64) (UploadedPart, error)
+}
+
+// CopyObject duplicates srcKey to dstKey server-side - no object bytes
+// travel through the client. Objects above 1 GB are copied part-wise when
+// the backend supports it. A missing source surfaces as ErrObjectNotFound;
+// anything else (permissions, region) comes back as the backend's own
+// failure. Passing more than one options value is an error.
+func (c *Client) CopyObject(srcKey, dstKey string, options ...*CopyOptions) error {
+	if len(options) > 1 {
+		return fmt.Errorf("voiceworld: at most one CopyOptions may be given, got %d", len(options))
+	}
+	opts := &CopyOptions{}
+	if len(options) == 1 && options[0] != nil {
+		opts = options[0]
+	}
+	store, err := c.storage()
+	if err != nil {
+		return err
+	}
+	exists, err := store.IsObjectExist(srcKey)
+	if err != nil {
+		return fmt.Errorf("voiceworld: check copy source %s: %w", srcKey, err)
+	}
+	if !exists {
+		return fmt.Errorf("voiceworld: copy source %s: %w", srcKey, ErrObjectNotFound)
+	}
+
+	if opts.DestinationBucket != "" {
+		cb, ok := store.(crossBucketCopyStorage)
+		if !ok {
+			return fmt.Errorf("voiceworld: storage backend does not support cross-bucket copy")
+		}
+		if err := cb.CopyObjectTo(opts.DestinationBucket, dstKey, srcKey); err != nil {
+			return fmt.Errorf("voiceworld: copy %s to %s/%s: %w", srcKey, opts.DestinationBucket, dstKey, err)
+		}
+		return nil
+	}
+
+	if h, ok := store.(headStorage); ok {
+		if size, _, err := h.HeadObject(srcKey); err == nil && size > multipartCopyThreshold {
+			if pc, ok := store.(partCopyStorage); ok {
+				return c.multipartCopy(store, pc, srcKey, dstKey, size)
+			}
+		}
+	}
+	cs, ok := store.(copyStorage)
+	if !ok {
+		return errNoCopySupport
+	}
+	if err := cs.CopyObject(srcKey, dstKey); err != nil {
+		return fmt.Errorf("voiceworld: copy %s to %s: %w", srcKey, dstKey, err)
+	}
+	return nil
+}
+
+// multipartCopy assembles dstKey from threshold-sized copy ranges of
+// srcKey, aborting the upload on failure so no parts linger.
+func (c *Client) multipartCopy(store ObjectStorage, pc partCopyStorage, srcKey, dstKey string, size int64) error {
+	uploadID, err := store.InitiateMultipartUpload(dstKey)
+	if err != nil {
+		return fmt.Errorf("voiceworld: initiate multipart copy: %w", err)
+	}
+	var parts []UploadedPart
+	for offset := int64(0); offset < size; offset += multipartCopyThreshold {
+		length := multipartCopyThreshold
+		if offset+length > size {
+			length = size - offset
+		}
+		part, err := pc.UploadPartCopy(srcKey, dstKey, uploadID, len(parts)+1, offset, length)
+		if err != nil {
+			_ = store.AbortMultipartUpload(dstKey, uploadID)
+			return fmt.Errorf("voiceworld: copy part %d of %s: %w", len(parts)+1, srcKey, err)
+		}
+		parts = append(parts, part)
+	}
+	if err := store.CompleteMultipartUpload(dstKey, uploadID, parts); err != nil {
+		_ = store.AbortMultipartUpload(dstKey, uploadID)
+		return fmt.Errorf("voiceworld: complete multipart copy of %s: %w", srcKey, err)
+	}
+	return nil
+}
+
+// MoveObject copies srcKey to dstKey within the staging bucket and deletes
+// the source, but only after verifying the copy: the two objects' sizes
+// and ECMA CRC-64 sums must match. A verification failure leaves the
+// source in place.
+func (c *Client) MoveObject(srcKey, dstKey string) error {
+	if err := c.CopyObject(srcKey, dstKey); err != nil {
+		return err
+	}
+	store, err := c.storage()
+	if err != nil {
+		return err
+	}
+	reader, ok := store.(objectReadStorage)
+	if !ok {
+		return fmt.Errorf("voiceworld: storage backend cannot verify the copy; source %s kept", srcKey)
+	}
+	srcSize, srcCRC, err := objectSizeCRC(store, reader, srcKey)
+	if err != nil {
+		return fmt.Errorf("voiceworld: verify move source %s: %w", srcKey, err)
+	}
+	dstSize, dstCRC, err := objectSizeCRC(store, reader, dstKey)
+	if err != nil {
+		return fmt.Errorf("voiceworld: verify move copy %s: %w", dstKey, err)
+	}
+	if srcSize != dstSize || srcCRC != dstCRC {
+		return fmt.Errorf("voiceworld: move verification failed for %s: copy %s differs (size %d/%d, crc64 %x/%x); source kept",
+			srcKey, dstKey, srcSize, dstSize, srcCRC, dstCRC)
+	}
+	if err := store.DeleteObjects([]string{srcKey}); err != nil {
+		return fmt.Errorf("voiceworld: delete moved source %s: %w", srcKey, err)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package voiceworld
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCopyObject(t *testing.T) {
+	client, store := newTestClient(t, nil)
+	if err := store.PutObject("2023/audio.wav", strings.NewReader("payload")); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.CopyObject("2023/audio.wav", "2024/01/audio.wav"); err != nil {
+		t.Fatalf("CopyObject: %v", err)
+	}
+	data, ok := store.Object("2024/01/audio.wav")
+	if !ok || string(data) != "payload" {
+		t.Fatalf("copy = %q, %v", data, ok)
+	}
+	if _, ok := store.Object("2023/audio.wav"); !ok {
+		t.Error("copy removed the source")
+	}
+}
+
+func TestCopyObjectSourceMissing(t *testing.T) {
+	client, _ := newTestClient(t, nil)
+	err := client.CopyObject("nope.wav", "dst.wav")
+	if !errors.Is(err, ErrObjectNotFound) {
+		t.Fatalf("err = %v, want ErrObjectNotFound", err)
+	}
+	if !strings.Contains(err.Error(), "nope.wav") {
+		t.Errorf("err %q does not name the missing key", err)
+	}
+}
+
+func TestCopyObjectMultipart(t *testing.T) {
+	prev := multipartCopyThreshold
+	multipartCopyThreshold = 1000
+	defer func() { multipartCopyThreshold = prev }()
+
+	client, store := newTestClient(t, nil)
+	payload := bytes.Repeat([]byte("abcdefghij"), 250) // 2500 bytes, 3 parts
+	if err := store.PutObject("big.bin", bytes.NewReader(payload)); err != nil {
+		t.Fatal(err)
+	}
+	// Catch a single-shot copy sneaking past the threshold.
+	copyCalled := false
+	wrapped := &copyTrackingStorage{MemoryStorage: store, copyCalled: &copyCalled}
+	client.config.Storage = wrapped
+
+	if err := client.CopyObject("big.bin", "copy.bin"); err != nil {
+		t.Fatalf("CopyObject: %v", err)
+	}
+	if copyCalled {
+		t.Error("large object used single-shot CopyObject instead of part copy")
+	}
+	data, ok := store.Object("copy.bin")
+	if !ok || !bytes.Equal(data, payload) {
+		t.Fatalf("multipart copy corrupted the object (%d bytes, ok=%v)", len(data), ok)
+	}
+}
+
+// copyTrackingStorage flags single-shot copies.
+type copyTrackingStorage struct {
+	*MemoryStorage
+	copyCalled *bool
+}
+
+func (s *copyTrackingStorage) CopyObject(srcKey, dstKey string) error {
+	*s.copyCalled = true
+	return s.MemoryStorage.CopyObject(srcKey, dstKey)
+}
+
+func TestMoveObject(t *testing.T) {
+	client, store := newTestClient(t, nil)
+	if err := store.PutObject("old/key.wav", strings.NewReader("payload")); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.MoveObject("old/key.wav", "new/key.wav"); err != nil {
+		t.Fatalf("MoveObject: %v", err)
+	}
+	if _, ok := store.Object("old/key.wav"); ok {
+		t.Error("source survived the move")
+	}
+	if data, ok := store.Object("new/key.wav"); !ok || string(data) != "payload" {
+		t.Errorf("moved object = %q, %v", data, ok)
+	}
+}
+
+// corruptingCopyStorage mangles every copy, so move verification must balk.
+type corruptingCopyStorage struct {
+	*MemoryStorage
+}
+
+func (s *corruptingCopyStorage) CopyObject(srcKey, dstKey string) error {
+	return s.MemoryStorage.PutObject(dstKey, strings.NewReader("garbage"))
+}
+
+func TestMoveObjectKeepsSourceOnBadCopy(t *testing.T) {
+	store := &corruptingCopyStorage{MemoryStorage: NewMemoryStorage()}
+	client, err := NewClient("ak", "sk", &ClientConfig{Storage: store})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.PutObject("src.wav", strings.NewReader("payload")); err != nil {
+		t.Fatal(err)
+	}
+	err = client.MoveObject("src.wav", "dst.wav")
+	if err == nil || !strings.Contains(err.Error(), "verification failed") {
+		t.Fatalf("err = %v, want verification failure", err)
+	}
+	if _, ok := store.Object("src.wav"); !ok {
+		t.Error("source deleted despite failed verification")
+	}
+}
+
+func TestCopyObjectCrossBucketUnsupported(t *testing.T) {
+	// Embedding the interface hides the optional cross-bucket method.
+	store := struct{ ObjectStorage }{NewMemoryStorage()}
+	client, err := NewClient("ak", "sk", &ClientConfig{Storage: store})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.PutObject("a.wav", strings.NewReader("x")); err != nil {
+		t.Fatal(err)
+	}
+	err = client.CopyObject("a.wav", "b.wav", &CopyOptions{DestinationBucket: "archive"})
+	if err == nil || !strings.Contains(err.Error(), "cross-bucket") {
+		t.Fatalf("err = %v, want cross-bucket unsupported failure", err)
+	}
+}
@@ -0,0 +1,55 @@
+package voiceworld
+
+import (
+	"fmt"
+)
+
+// DedupeMode selects what SplitAudioFile does with a chunk once its content
+// hash is staged under the content-addressed prefix; see
+// SplitOptions.DedupeByContent.
+type DedupeMode int
+
+const (
+	// DedupeCopy server-side-copies the content object to the usual
+	// "<requestID>/<part>" key, keeping the per-request layout that
+	// ListRequestParts and the manifest expect. This is the default.
+	DedupeCopy DedupeMode = iota
+	// DedupeReference skips the copy and records the content-addressed key
+	// directly in the result, for callers that consume parts by URL and do
+	// not care about the key layout.
+	DedupeReference
+)
+
+// Provenance values for SplitPart.Provenance.
+const (
+	// ProvenanceUploaded marks a chunk whose content was not staged yet and
+	// was uploaded by this operation.
+	ProvenanceUploaded = "uploaded"
+	// ProvenanceCopied marks a chunk served by copying an already-staged
+	// content object to the request path.
+	ProvenanceCopied = "copied"
+	// ProvenanceReferenced marks a chunk recorded as its already-staged
+	// content key, without a copy.
+	ProvenanceReferenced = "referenced"
+)
+
+// contentPrefix is where content-addressed chunk objects live, outside any
+// request's key space so they survive per-request cleanup.
+const contentPrefix = "_content/"
+
+// contentObjectKey is the content-addressed key for a chunk with the given
+// SHA-256 sum.
+func contentObjectKey(sum []byte) string {
+	return fmt.Sprintf("%s%x.wav", contentPrefix, sum)
+}
+
+// copyStorage is the optional capability backing DedupeCopy: duplicating an
+// object server-side without moving its bytes through the client. The
+// bundled backends all support it.
+type copyStorage interface {
+	CopyObject(srcKey, dstKey string) error
+}
+
+// errNoCopySupport reports a DedupeCopy split against a storage backend
+// that cannot copy server-side.
+var errNoCopySupport = fmt.Errorf("voiceworld: storage backend does not support server-side copy")
@@ -0,0 +1,35 @@
+package voiceworld
+
+import (
+	cryptorand "crypto/rand"
+	"math/rand"
+	"time"
+)
+
+// Clock supplies the current time to a client, for simulations and replay
+// harnesses that need signature timestamps, report timings and retry
+// schedules pinned to a scripted timeline. The default is the real clock.
+type Clock interface {
+	Now() time.Time
+}
+
+// WithClock returns a client whose time reads go through clock instead of
+// time.Now. Everything else is shared with the parent, which keeps the
+// clock it was built with.
+func (c *Client) WithClock(clock Clock) *Client {
+	derived := *c
+	derived.now = clock.Now
+	return &derived
+}
+
+// The entropy, wall-clock and sleep taps for the helpers that run without a
+// client in hand: request IDs, nonces, idempotency keys, operation IDs and
+// storage-retry pacing. Package level because those helpers are package
+// level; tests swap them to make generated identifiers and schedules
+// reproducible.
+var (
+	randRead  = cryptorand.Read
+	randFloat = rand.Float64
+	timeNow   = time.Now
+	timeSleep = time.Sleep
+)
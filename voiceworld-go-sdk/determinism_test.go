@@ -0,0 +1,105 @@
+package voiceworld
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// fixedClock is a Clock stuck at one instant.
+type fixedClock time.Time
+
+func (f fixedClock) Now() time.Time { return time.Time(f) }
+
+// freezeEntropy pins the package entropy tap to a constant byte for the
+// test's duration.
+func freezeEntropy(t *testing.T) {
+	t.Helper()
+	prev := randRead
+	randRead = func(b []byte) (int, error) {
+		for i := range b {
+			b[i] = 0x42
+		}
+		return len(b), nil
+	}
+	t.Cleanup(func() { randRead = prev })
+}
+
+func TestWithClockFreezesSignatures(t *testing.T) {
+	freezeEntropy(t)
+	client, err := NewClient("ak", "sk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	instant := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	frozen := client.WithClock(fixedClock(instant))
+
+	var headers [2]http.Header
+	for i := range headers {
+		req, err := frozen.NewSignedRequest(context.Background(), http.MethodGet, "/time", nil, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		headers[i] = req.Header
+	}
+	if got := headers[0].Get(headerTimestamp); got != strconv.FormatInt(instant.Unix(), 10) {
+		t.Errorf("timestamp = %s, want the frozen instant", got)
+	}
+	for _, key := range []string{headerTimestamp, headerNonce, headerSignature} {
+		if headers[0].Get(key) != headers[1].Get(key) {
+			t.Errorf("%s differs across identical frozen requests: %q vs %q",
+				key, headers[0].Get(key), headers[1].Get(key))
+		}
+	}
+
+	// The parent keeps the real clock: its timestamp is nowhere near 2026.
+	req, err := client.NewSignedRequest(context.Background(), http.MethodGet, "/time", nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Header.Get(headerTimestamp) == headers[0].Get(headerTimestamp) {
+		t.Error("parent client inherited the frozen clock")
+	}
+}
+
+func TestFrozenJitterStabilizesRetrySchedule(t *testing.T) {
+	prev := randFloat
+	randFloat = func() float64 { return 0.5 }
+	t.Cleanup(func() { randFloat = prev })
+
+	policy := ExponentialBackoff{BaseDelay: 100 * time.Millisecond}
+	err := &APIError{HTTPStatus: http.StatusServiceUnavailable}
+	// delay/2 + 0.5*delay/2 = 0.75 * (base << (attempt-1)), exactly.
+	want := []time.Duration{75 * time.Millisecond, 150 * time.Millisecond, 300 * time.Millisecond}
+	for run := 0; run < 2; run++ {
+		for attempt := 1; attempt <= 3; attempt++ {
+			delay, retry := policy.NextDelay(attempt, err, nil)
+			if !retry || delay != want[attempt-1] {
+				t.Errorf("run %d attempt %d: delay = %v retry = %v, want %v",
+					run, attempt, delay, retry, want[attempt-1])
+			}
+		}
+	}
+}
+
+func TestFrozenTapsStabilizeIdentifiers(t *testing.T) {
+	freezeEntropy(t)
+	prevNow := timeNow
+	timeNow = fixedClock(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)).Now
+	t.Cleanup(func() { timeNow = prevNow })
+
+	if a, b := NewRequestID(), NewRequestID(); a != b {
+		t.Errorf("request IDs differ under frozen taps: %q vs %q", a, b)
+	}
+	if a, b := newIdempotencyKey(), newIdempotencyKey(); a != b {
+		t.Errorf("idempotency keys differ under frozen taps: %q vs %q", a, b)
+	}
+	if a, b := newNonce(), newNonce(); a != b {
+		t.Errorf("nonces differ under frozen taps: %q vs %q", a, b)
+	}
+	if a, b := newOpID("upload", 7), newOpID("upload", 7); a != b {
+		t.Errorf("op IDs differ under frozen taps: %q vs %q", a, b)
+	}
+}
@@ -0,0 +1,192 @@
+package voiceworld
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// DiagnosticCheck records one pre-flight probe run by DiagnoseOSS. A check
+// that could not run (its prerequisite failed, or the backend lacks the
+// capability) has Attempted false and does not count against the report.
+type DiagnosticCheck struct {
+	Name      string `json:"name"`
+	Attempted bool   `json:"attempted"`
+	Passed    bool   `json:"passed"`
+	// Detail says what the check saw, pass or fail.
+	Detail string `json:"detail,omitempty"`
+	// Hint suggests a fix for a failed check.
+	Hint string `json:"hint,omitempty"`
+}
+
+// DiagnosticsReport is the outcome of DiagnoseOSS: one entry per check, in
+// the order they ran.
+type DiagnosticsReport struct {
+	Checks []DiagnosticCheck `json:"checks"`
+}
+
+// Passed reports whether every attempted check succeeded.
+func (r *DiagnosticsReport) Passed() bool {
+	for _, check := range r.Checks {
+		if check.Attempted && !check.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// locatableStorage is the optional capability behind the bucket and region
+// diagnostics. The OSS backend reports the region hosting its bucket, e.g.
+// "oss-cn-hangzhou".
+type locatableStorage interface {
+	BucketLocation() (string, error)
+}
+
+// DiagnoseOSS runs the object storage pre-flight checks behind the most
+// common configuration faults: dead or mis-scoped credentials, a bucket name
+// typo, a bucket living in a different region than the configured endpoint
+// (the classic SignatureDoesNotMatch/AccessDenied source), and an STS policy
+// that cannot actually write. Each check lands on the report with a
+// remediation hint on failure; the CLI exposes this as "voiceworld doctor".
+// An error is returned only when the storage backend cannot be built at all.
+func (c *Client) DiagnoseOSS() (*DiagnosticsReport, error) {
+	report := &DiagnosticsReport{}
+
+	var creds *OSSCredentials
+	cred := DiagnosticCheck{Name: "credentials"}
+	if c.config.Storage != nil && c.config.TokenProvider == nil {
+		cred.Detail = "skipped: using an injected storage backend"
+	} else {
+		cred.Attempted = true
+		var err error
+		creds, err = c.tokenProvider.OSSCredentials(context.Background())
+		switch {
+		case err != nil:
+			cred.Detail = fmt.Sprintf("token fetch failed: %v", err)
+			cred.Hint = "verify the app key and secret, and that the account may issue storage tokens"
+		case creds.Expired():
+			cred.Detail = fmt.Sprintf("credentials already expired at %s", creds.Expiration)
+			cred.Hint = "the local clock may be skewed; see SyncServerTime"
+		default:
+			cred.Passed = true
+			cred.Detail = fmt.Sprintf("bucket %s via %s, valid until %s",
+				creds.Bucket, creds.Endpoint, creds.Expiration)
+		}
+	}
+	report.Checks = append(report.Checks, cred)
+	if cred.Attempted && !cred.Passed {
+		report.Checks = append(report.Checks,
+			DiagnosticCheck{Name: "bucket", Detail: "skipped: no storage credentials"},
+			DiagnosticCheck{Name: "region", Detail: "skipped: no storage credentials"},
+			DiagnosticCheck{Name: "write-access", Detail: "skipped: no storage credentials"})
+		return report, nil
+	}
+
+	store, err := c.storage()
+	if err != nil {
+		return nil, err
+	}
+
+	bucket := DiagnosticCheck{Name: "bucket"}
+	region := DiagnosticCheck{Name: "region"}
+	if loc, ok := store.(locatableStorage); ok {
+		bucket.Attempted = true
+		location, err := loc.BucketLocation()
+		if err != nil {
+			bucket.Detail = fmt.Sprintf("bucket lookup failed: %v", err)
+			bucket.Hint = "check the bucket name for typos and that the policy grants GetBucketLocation"
+			region.Detail = "skipped: bucket location unknown"
+		} else {
+			bucket.Passed = true
+			bucket.Detail = fmt.Sprintf("bucket found in %s", location)
+			if creds == nil {
+				region.Detail = "skipped: no endpoint to compare against"
+			} else {
+				region.Attempted = true
+				host := endpointHost(creds.Endpoint)
+				if strings.Contains(host, location) {
+					region.Passed = true
+					region.Detail = fmt.Sprintf("endpoint %s serves %s", host, location)
+				} else {
+					region.Detail = fmt.Sprintf("bucket lives in %s but the endpoint %s serves another region", location, host)
+					region.Hint = fmt.Sprintf("point the storage endpoint at %s.aliyuncs.com", location)
+				}
+			}
+		}
+	} else {
+		bucket.Detail = "skipped: backend does not report a bucket location"
+		region.Detail = "skipped: backend does not report a bucket location"
+	}
+	report.Checks = append(report.Checks, bucket, region)
+
+	write := DiagnosticCheck{Name: "write-access", Attempted: true}
+	probeKey := "_diagnostics/" + NewRequestID()
+	if err := store.PutObject(probeKey, strings.NewReader("voiceworld doctor probe")); err != nil {
+		write.Detail = fmt.Sprintf("probe upload failed: %v", err)
+		write.Hint = "the storage policy must allow PutObject under the granted prefix"
+	} else if err := store.DeleteObjects([]string{probeKey}); err != nil {
+		write.Detail = fmt.Sprintf("probe delete failed: %v", err)
+		write.Hint = "the storage policy must allow DeleteObject under the granted prefix"
+	} else {
+		write.Passed = true
+		write.Detail = fmt.Sprintf("put and deleted probe object %s", probeKey)
+	}
+	report.Checks = append(report.Checks, write)
+
+	return report, nil
+}
+
+// PermissionProbeError reports that the pre-upload permission probe (see
+// UploadOptions.ProbePermissions) found the storage credentials unable to
+// perform an action under the target prefix. It wraps the storage backend's
+// error.
+type PermissionProbeError struct {
+	// Action is the missing permission, e.g. "oss:PutObject".
+	Action string
+	// Prefix is the object prefix the probe ran under.
+	Prefix string
+	// Err is the underlying storage error.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *PermissionProbeError) Error() string {
+	return fmt.Sprintf("voiceworld: token lacks %s on %s*: %v", e.Action, e.Prefix, e.Err)
+}
+
+// Unwrap exposes the storage backend's error.
+func (e *PermissionProbeError) Unwrap() error { return e.Err }
+
+// probeUploadPermissions writes and deletes a zero-byte probe object under
+// objectName's prefix, with credentials scoped the same way the real upload's
+// will be. The probe key is unique per call and a best-effort delete runs
+// even when the put itself failed, so no probe objects accumulate.
+func (c *Client) probeUploadPermissions(objectName string) error {
+	store, err := c.storageForScope(nil, uploadScope(objectName))
+	if err != nil {
+		return err
+	}
+	prefix := ""
+	if id := requestIDFromKey(objectName); id != "" {
+		prefix = id + "/"
+	}
+	probeKey := prefix + "_probe_" + NewRequestID()
+	if err := store.PutObject(probeKey, bytes.NewReader(nil)); err != nil {
+		// The object may have landed despite the error.
+		_ = store.DeleteObjects([]string{probeKey})
+		return &PermissionProbeError{Action: "oss:PutObject", Prefix: prefix, Err: err}
+	}
+	if err := store.DeleteObjects([]string{probeKey}); err != nil {
+		return &PermissionProbeError{Action: "oss:DeleteObject", Prefix: prefix, Err: err}
+	}
+	return nil
+}
+
+// endpointHost strips the scheme and trailing slash from a storage endpoint,
+// leaving the host for region comparison.
+func endpointHost(endpoint string) string {
+	host := strings.TrimPrefix(endpoint, "https://")
+	host = strings.TrimPrefix(host, "http://")
+	return strings.TrimSuffix(host, "/")
+}
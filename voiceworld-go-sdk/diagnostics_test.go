@@ -0,0 +1,218 @@
+package voiceworld
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// locatedStorage is a MemoryStorage that also reports a bucket location, so
+// diagnostics can exercise the region comparison.
+type locatedStorage struct {
+	*MemoryStorage
+	location string
+	locErr   error
+}
+
+func (s *locatedStorage) BucketLocation() (string, error) {
+	return s.location, s.locErr
+}
+
+func diagClient(t *testing.T, endpoint, location string) *Client {
+	t.Helper()
+	client, err := NewClient("ak", "sk", &ClientConfig{
+		Storage: &locatedStorage{MemoryStorage: NewMemoryStorage(), location: location},
+		TokenProvider: StaticTokenProvider(OSSCredentials{
+			Bucket:     "stage",
+			Endpoint:   endpoint,
+			Expiration: time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func checkByName(t *testing.T, report *DiagnosticsReport, name string) DiagnosticCheck {
+	t.Helper()
+	for _, check := range report.Checks {
+		if check.Name == name {
+			return check
+		}
+	}
+	t.Fatalf("report has no %q check: %+v", name, report.Checks)
+	return DiagnosticCheck{}
+}
+
+func TestDiagnoseOSSAllPass(t *testing.T) {
+	client := diagClient(t, "https://oss-cn-hangzhou.aliyuncs.com", "oss-cn-hangzhou")
+	report, err := client.DiagnoseOSS()
+	if err != nil {
+		t.Fatalf("DiagnoseOSS: %v", err)
+	}
+	if !report.Passed() {
+		t.Errorf("report did not pass: %+v", report.Checks)
+	}
+	for _, name := range []string{"credentials", "bucket", "region", "write-access"} {
+		check := checkByName(t, report, name)
+		if !check.Attempted || !check.Passed {
+			t.Errorf("%s = %+v, want attempted and passed", name, check)
+		}
+	}
+}
+
+func TestDiagnoseOSSRegionMismatch(t *testing.T) {
+	client := diagClient(t, "https://oss-cn-hangzhou.aliyuncs.com", "oss-cn-shanghai")
+	report, err := client.DiagnoseOSS()
+	if err != nil {
+		t.Fatalf("DiagnoseOSS: %v", err)
+	}
+	if report.Passed() {
+		t.Error("report passed despite the region mismatch")
+	}
+	region := checkByName(t, report, "region")
+	if !region.Attempted || region.Passed {
+		t.Fatalf("region = %+v, want an attempted failure", region)
+	}
+	if !strings.Contains(region.Hint, "oss-cn-shanghai") {
+		t.Errorf("hint %q does not name the bucket's region", region.Hint)
+	}
+}
+
+func TestDiagnoseOSSBadCredentials(t *testing.T) {
+	client, err := NewClient("ak", "sk", &ClientConfig{
+		Storage:       NewMemoryStorage(),
+		TokenProvider: &scriptedTokenProvider{err: errors.New("403 forbidden")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := client.DiagnoseOSS()
+	if err != nil {
+		t.Fatalf("DiagnoseOSS: %v", err)
+	}
+	if report.Passed() {
+		t.Error("report passed despite the token failure")
+	}
+	cred := checkByName(t, report, "credentials")
+	if !cred.Attempted || cred.Passed || cred.Hint == "" {
+		t.Errorf("credentials = %+v, want an attempted failure with a hint", cred)
+	}
+	for _, name := range []string{"bucket", "region", "write-access"} {
+		if check := checkByName(t, report, name); check.Attempted {
+			t.Errorf("%s ran despite missing credentials: %+v", name, check)
+		}
+	}
+}
+
+func TestDiagnoseOSSBackendWithoutLocation(t *testing.T) {
+	client, _ := newTestClient(t, nil)
+	report, err := client.DiagnoseOSS()
+	if err != nil {
+		t.Fatalf("DiagnoseOSS: %v", err)
+	}
+	if !report.Passed() {
+		t.Errorf("report did not pass: %+v", report.Checks)
+	}
+	if bucket := checkByName(t, report, "bucket"); bucket.Attempted {
+		t.Errorf("bucket check ran on a backend without locations: %+v", bucket)
+	}
+	if write := checkByName(t, report, "write-access"); !write.Attempted || !write.Passed {
+		t.Errorf("write-access = %+v, want attempted and passed", write)
+	}
+}
+
+// deniedStorage is a MemoryStorage whose writes or deletes can be switched
+// off, standing in for an STS policy missing those grants.
+type deniedStorage struct {
+	*MemoryStorage
+	denyPut    bool
+	denyDelete bool
+}
+
+func (s *deniedStorage) PutObject(key string, r io.Reader) error {
+	if s.denyPut {
+		return errors.New("oss: AccessDenied")
+	}
+	return s.MemoryStorage.PutObject(key, r)
+}
+
+func (s *deniedStorage) DeleteObjects(keys []string) error {
+	if s.denyDelete {
+		return errors.New("oss: AccessDenied")
+	}
+	return s.MemoryStorage.DeleteObjects(keys)
+}
+
+func TestProbePermissionsCleansUpProbeObject(t *testing.T) {
+	client, store := newTestClient(t, nil)
+	path := makeWAVFile(t, 16000, 1, 0.1)
+	if _, err := client.UploadFile(path, "req-1/a.wav", &UploadOptions{ProbePermissions: true}); err != nil {
+		t.Fatalf("UploadFile: %v", err)
+	}
+	keys, err := store.ListObjects("req-1/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || keys[0] != "req-1/a.wav" {
+		t.Errorf("staged keys = %v, want only the uploaded object", keys)
+	}
+}
+
+func TestProbePermissionsDeniedPut(t *testing.T) {
+	store := &deniedStorage{MemoryStorage: NewMemoryStorage(), denyPut: true}
+	client, err := NewClient("ak", "sk", &ClientConfig{Storage: store})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := makeWAVFile(t, 16000, 1, 0.1)
+	_, err = client.UploadFile(path, "req-1/a.wav", &UploadOptions{ProbePermissions: true})
+	var perr *PermissionProbeError
+	if !errors.As(err, &perr) {
+		t.Fatalf("err = %v (%T), want *PermissionProbeError", err, err)
+	}
+	if perr.Action != "oss:PutObject" || perr.Prefix != "req-1/" {
+		t.Errorf("probe error = %+v, want oss:PutObject on req-1/", perr)
+	}
+	if !strings.Contains(err.Error(), "token lacks oss:PutObject on req-1/*") {
+		t.Errorf("error text = %q", err.Error())
+	}
+}
+
+func TestProbePermissionsDeniedDelete(t *testing.T) {
+	store := &deniedStorage{MemoryStorage: NewMemoryStorage(), denyDelete: true}
+	client, err := NewClient("ak", "sk", &ClientConfig{Storage: store})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := makeWAVFile(t, 16000, 1, 0.1)
+	_, err = client.MultipartUploadFile(path, "req-2/a.wav", &UploadOptions{ProbePermissions: true})
+	var perr *PermissionProbeError
+	if !errors.As(err, &perr) {
+		t.Fatalf("err = %v (%T), want *PermissionProbeError", err, err)
+	}
+	if perr.Action != "oss:DeleteObject" {
+		t.Errorf("Action = %q, want oss:DeleteObject", perr.Action)
+	}
+}
+
+func TestProcessAndUploadProbesBeforeConversion(t *testing.T) {
+	store := &deniedStorage{MemoryStorage: NewMemoryStorage(), denyPut: true}
+	client, err := NewClient("ak", "sk", &ClientConfig{Storage: store})
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := registeredTempFiles()
+	path := makeWAVFile(t, 44100, 2, 1)
+	_, err = client.ProcessAndUploadFile(path, "req-3", &UploadOptions{ProbePermissions: true})
+	var perr *PermissionProbeError
+	if !errors.As(err, &perr) {
+		t.Fatalf("err = %v (%T), want *PermissionProbeError", err, err)
+	}
+	if got := registeredTempFiles(); len(got) != len(before) {
+		t.Errorf("conversion ran despite the failed probe: temp files %v", got)
+	}
+}
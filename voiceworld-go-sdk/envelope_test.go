@@ -0,0 +1,136 @@
+package voiceworld
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// precedenceCases spans the status-times-verdict matrix the decoder must
+// judge consistently: the envelope wins whenever the body parses as one, and
+// the HTTP status only speaks for bodies without a parseable envelope.
+var precedenceCases = []struct {
+	name       string
+	status     int
+	body       string
+	wantCode   int // 0 means the call must succeed
+	wantStatus int // expected APIError.HTTPStatus when wantCode != 0
+}{
+	{
+		name:   "200 with success envelope",
+		status: http.StatusOK,
+		body:   `{"code":0,"data":{"models":[{"id":"m1"}]}}`,
+	},
+	{
+		name:       "200 wrapping a backend failure",
+		status:     http.StatusOK,
+		body:       `{"code":40301,"message":"over quota"}`,
+		wantCode:   40301,
+		wantStatus: 0,
+	},
+	{
+		name:   "500 wrapping a success payload",
+		status: http.StatusInternalServerError,
+		body:   `{"code":0,"data":{"models":[{"id":"m1"}]}}`,
+	},
+	{
+		name:       "500 with error envelope",
+		status:     http.StatusInternalServerError,
+		body:       `{"code":40301,"message":"over quota"}`,
+		wantCode:   40301,
+		wantStatus: http.StatusInternalServerError,
+	},
+	{
+		name:       "500 without an envelope",
+		status:     http.StatusInternalServerError,
+		body:       `<html>bad gateway</html>`,
+		wantCode:   -1,
+		wantStatus: http.StatusInternalServerError,
+	},
+}
+
+func checkPrecedence(t *testing.T, wantCode, wantStatus int, err error) {
+	t.Helper()
+	if wantCode == 0 {
+		if err != nil {
+			t.Fatalf("want success, got %v", err)
+		}
+		return
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("err = %v, want *APIError", err)
+	}
+	if apiErr.Code != wantCode || apiErr.HTTPStatus != wantStatus {
+		t.Fatalf("got code %d http %d, want code %d http %d",
+			apiErr.Code, apiErr.HTTPStatus, wantCode, wantStatus)
+	}
+}
+
+func TestEnvelopeStatusPrecedenceWrappedEndpoint(t *testing.T) {
+	for _, tt := range precedenceCases {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, tt.body)
+			}))
+			defer server.Close()
+			client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: server.URL})
+			if err != nil {
+				t.Fatal(err)
+			}
+			models, err := client.ListModels()
+			checkPrecedence(t, tt.wantCode, tt.wantStatus, err)
+			if tt.wantCode == 0 && (len(models) != 1 || models[0].ID != "m1") {
+				t.Errorf("models = %+v, want the payload from the envelope", models)
+			}
+		})
+	}
+}
+
+func TestEnvelopeStatusPrecedenceDoJSON(t *testing.T) {
+	for _, tt := range precedenceCases {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, tt.body)
+			}))
+			defer server.Close()
+			client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: server.URL})
+			if err != nil {
+				t.Fatal(err)
+			}
+			req, err := client.NewSignedRequest(context.Background(), http.MethodGet, "/custom", nil, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkPrecedence(t, tt.wantCode, tt.wantStatus, client.DoJSON(req, nil))
+		})
+	}
+}
+
+func TestEnvelopeStatusPrecedenceUnparseableSuccess(t *testing.T) {
+	// A 2xx body that is valid JSON but not an envelope keeps the lenient
+	// legacy reading; one that is not JSON at all stays a decode error.
+	server := newEnvelopeServer(t, `{"hello":"world"}`)
+	client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ListModels(); err != nil {
+		t.Errorf("code-less JSON on a 200: %v, want success", err)
+	}
+
+	garbled := newEnvelopeServer(t, `not json`)
+	client, err = NewClient("ak", "sk", &ClientConfig{BaseURL: garbled.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ListModels(); err == nil || !strings.Contains(err.Error(), "decode response envelope") {
+		t.Errorf("non-JSON on a 200: %v, want a decode error", err)
+	}
+}
@@ -0,0 +1,118 @@
+package voiceworld
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Envelope codes the platform documents for failures every endpoint can
+// return. Codes specific to one flow live next to that flow, like
+// codeSignatureExpired and codeSessionExpired.
+const (
+	codeInvalidSignature = 40001
+	codeQuotaExceeded    = 40301
+	codeServerInternal   = 50001
+)
+
+// Sentinels for the well-known envelope codes, matched with errors.Is. The
+// concrete error stays an *APIError carrying the raw code and message.
+var (
+	// ErrInvalidSignature reports code 40001: the request signature was
+	// malformed or computed with the wrong secret. Not to be confused with
+	// an expired signature, which the client fixes itself by resyncing the
+	// server clock.
+	ErrInvalidSignature = errors.New("voiceworld: invalid request signature")
+	// ErrQuotaExceeded reports code 40301: the account is out of quota.
+	ErrQuotaExceeded = errors.New("voiceworld: account quota exceeded")
+	// ErrServerInternal reports code 50001: the server failed on its side.
+	// Requests failing this way are retried under the configured policy.
+	ErrServerInternal = errors.New("voiceworld: server internal error")
+)
+
+// codeSentinels maps well-known envelope codes to their sentinels.
+var codeSentinels = map[int]error{
+	codeInvalidSignature: ErrInvalidSignature,
+	codeQuotaExceeded:    ErrQuotaExceeded,
+	codeServerInternal:   ErrServerInternal,
+}
+
+// ErrResponseTooLarge reports a response body that exceeded the client's
+// read cap; see ClientConfig.MaxResponseSize. The concrete error is a
+// *ResponseTooLargeError carrying the limit that was hit.
+var ErrResponseTooLarge = errors.New("voiceworld: response body too large")
+
+// ResponseTooLargeError is the concrete error behind ErrResponseTooLarge.
+type ResponseTooLargeError struct {
+	// Limit is the cap, in bytes, that the body exceeded.
+	Limit int64
+}
+
+// Error implements the error interface.
+func (e *ResponseTooLargeError) Error() string {
+	return fmt.Sprintf("voiceworld: response body exceeds the %d byte limit", e.Limit)
+}
+
+// Unwrap exposes the sentinel to errors.Is.
+func (e *ResponseTooLargeError) Unwrap() error { return ErrResponseTooLarge }
+
+// readBounded reads r in full up to limit bytes, failing with a
+// *ResponseTooLargeError instead of buffering an unbounded body.
+func readBounded(r io.Reader, limit int64) ([]byte, error) {
+	raw, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(raw)) > limit {
+		return nil, &ResponseTooLargeError{Limit: limit}
+	}
+	return raw, nil
+}
+
+// APIError is a business-level failure reported by the API. One precedence
+// rule applies to every endpoint: when the response body parses as an
+// envelope, the envelope's verdict wins regardless of the HTTP status — a
+// gateway may wrap a backend failure in a 200 or, during failover, a valid
+// success payload in a 500. Only bodies without a parseable envelope are
+// judged by their status, with Code -1 standing in for the missing envelope
+// code.
+type APIError struct {
+	// Code is the platform error code from the envelope, or -1 when a
+	// non-2xx response carried no parseable envelope.
+	Code int
+	// Message is the human-readable description from the envelope.
+	Message string
+	// HTTPStatus is the transport status, zero when the request itself
+	// succeeded and only the envelope carried the failure.
+	HTTPStatus int
+}
+
+// Error implements the error interface.
+func (e *APIError) Error() string {
+	if e.HTTPStatus != 0 {
+		return fmt.Sprintf("voiceworld: api error %d (http %d): %s", e.Code, e.HTTPStatus, e.Message)
+	}
+	return fmt.Sprintf("voiceworld: api error %d: %s", e.Code, e.Message)
+}
+
+// Unwrap surfaces the sentinel for well-known codes, so callers can write
+// errors.Is(err, ErrQuotaExceeded) instead of matching raw code numbers.
+// Unknown codes unwrap to nothing and stay plain *APIError values.
+func (e *APIError) Unwrap() error {
+	return codeSentinels[e.Code]
+}
+
+// newStatusError turns a non-2xx response into an error, recovering the
+// envelope code and message when the body carries one.
+func newStatusError(status int, body []byte) error {
+	var env apiEnvelope
+	if err := json.Unmarshal(body, &env); err == nil && (env.Code != 0 || env.Message != "") {
+		return &APIError{Code: env.Code, Message: env.Message, HTTPStatus: status}
+	}
+	msg := string(body)
+	if len(msg) > 512 {
+		msg = msg[:512] + "..."
+	}
+	return &APIError{Code: -1, Message: msg, HTTPStatus: status}
+}
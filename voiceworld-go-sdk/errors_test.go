@@ -0,0 +1,108 @@
+package voiceworld
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIErrorCodeSentinels(t *testing.T) {
+	tests := []struct {
+		code     int
+		sentinel error
+	}{
+		{40001, ErrInvalidSignature},
+		{40301, ErrQuotaExceeded},
+		{50001, ErrServerInternal},
+	}
+	for _, tt := range tests {
+		err := error(&APIError{Code: tt.code, Message: "boom"})
+		if !errors.Is(err, tt.sentinel) {
+			t.Errorf("code %d does not match %v", tt.code, tt.sentinel)
+		}
+		// The mapping must not blur distinct sentinels together.
+		for _, other := range tests {
+			if other.code != tt.code && errors.Is(err, other.sentinel) {
+				t.Errorf("code %d also matches %v", tt.code, other.sentinel)
+			}
+		}
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.Code != tt.code {
+			t.Errorf("code %d lost its APIError detail", tt.code)
+		}
+	}
+
+	// Unknown codes stay plain APIErrors without a sentinel.
+	unknown := error(&APIError{Code: 49999})
+	for _, tt := range tests {
+		if errors.Is(unknown, tt.sentinel) {
+			t.Errorf("unknown code matches %v", tt.sentinel)
+		}
+	}
+}
+
+func TestQuotaExceededSurfacesFromEnvelope(t *testing.T) {
+	server := newEnvelopeServer(t, `{"code":40301,"message":"monthly quota exhausted"}`)
+	client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.ListModels()
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("err = %v, want ErrQuotaExceeded", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Message != "monthly quota exhausted" {
+		t.Fatalf("raw message lost: %v", err)
+	}
+}
+
+func TestServerInternalCodeIsRetried(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			fmt.Fprint(w, `{"code":50001,"message":"try again"}`)
+			return
+		}
+		fmt.Fprint(w, `{"code":0,"data":{"models":[]}}`)
+	}))
+	defer server.Close()
+	client, err := NewClient("ak", "sk", &ClientConfig{
+		BaseURL: server.URL,
+		Retry:   FixedAttempts{Retries: 3},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ListModels(); err != nil {
+		t.Fatalf("ListModels: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("server saw %d calls, want 3 (two internal errors, then success)", calls)
+	}
+}
+
+func TestInvalidSignatureCodeIsFinal(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprint(w, `{"code":40001,"message":"bad signature"}`)
+	}))
+	defer server.Close()
+	client, err := NewClient("ak", "sk", &ClientConfig{
+		BaseURL: server.URL,
+		Retry:   FixedAttempts{Retries: 3},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ListModels(); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("err = %v, want ErrInvalidSignature", err)
+	}
+	if calls != 1 {
+		t.Errorf("server saw %d calls, want 1 (deliberate rejections are final)", calls)
+	}
+}
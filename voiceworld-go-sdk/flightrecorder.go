@@ -0,0 +1,145 @@
+package voiceworld
+
+import (
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Defaults for NewFlightRecorder.
+const (
+	// DefaultFlightRecorderEntries is how many exchanges are retained.
+	DefaultFlightRecorderEntries = 16
+	// DefaultFlightRecorderBodyBytes is how much of each body is kept.
+	DefaultFlightRecorderBodyBytes = 4096
+)
+
+// Exchange is one recorded request/response pair, sanitized for attachment
+// to an error report: no headers are retained — signatures and credentials
+// travel in headers — and bodies are clipped to the recorder's cap.
+type Exchange struct {
+	Time   time.Time `json:"time"`
+	Method string    `json:"method"`
+	URL    string    `json:"url"`
+	// Status is the HTTP status, or zero when the request never got a
+	// response; Error then says why.
+	Status int    `json:"status,omitempty"`
+	Error  string `json:"error,omitempty"`
+	// RequestBody and ResponseBody hold up to the recorder's per-body
+	// byte cap; the Truncated flags report that there was more.
+	RequestBody       string `json:"request_body,omitempty"`
+	RequestTruncated  bool   `json:"request_truncated,omitempty"`
+	ResponseBody      string `json:"response_body,omitempty"`
+	ResponseTruncated bool   `json:"response_truncated,omitempty"`
+}
+
+// FlightRecorder keeps a bounded ring of the client's most recent exchanges
+// so error-handling code can attach the lead-up to a failure without running
+// full debug capture always-on. Give one to ClientConfig.FlightRecorder and
+// read it back with Client.DumpRecentExchanges. Safe for concurrent use; a
+// nil recorder records nothing at no cost.
+type FlightRecorder struct {
+	maxEntries int
+	maxBody    int
+
+	mu      sync.Mutex
+	entries []Exchange
+	next    int // ring write position once the buffer is full
+}
+
+// NewFlightRecorder retains the last maxEntries exchanges with up to
+// maxBodyBytes of each body; values <= 0 take the defaults.
+func NewFlightRecorder(maxEntries, maxBodyBytes int) *FlightRecorder {
+	if maxEntries <= 0 {
+		maxEntries = DefaultFlightRecorderEntries
+	}
+	if maxBodyBytes <= 0 {
+		maxBodyBytes = DefaultFlightRecorderBodyBytes
+	}
+	return &FlightRecorder{maxEntries: maxEntries, maxBody: maxBodyBytes}
+}
+
+// clip bounds one body to the per-body cap.
+func (r *FlightRecorder) clip(b []byte) (string, bool) {
+	if len(b) > r.maxBody {
+		return string(b[:r.maxBody]), true
+	}
+	return string(b), false
+}
+
+// record adds one exchange, evicting the oldest once the ring is full.
+func (r *FlightRecorder) record(ex Exchange) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.entries) < r.maxEntries {
+		r.entries = append(r.entries, ex)
+		return
+	}
+	r.entries[r.next] = ex
+	r.next = (r.next + 1) % r.maxEntries
+}
+
+// Dump returns the retained exchanges, oldest first.
+func (r *FlightRecorder) Dump() []Exchange {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]Exchange, 0, len(r.entries))
+	out = append(out, r.entries[r.next:]...)
+	out = append(out, r.entries[:r.next]...)
+	return out
+}
+
+// exchangeFor starts an Exchange from the request alone, capturing what can
+// be captured without consuming anything: the body only when net/http can
+// replay it (GetBody is nil for streamed files, which are not re-read).
+func (r *FlightRecorder) exchangeFor(req *http.Request) Exchange {
+	u := *req.URL
+	u.User = nil // never retain userinfo credentials
+	ex := Exchange{Time: timeNow().UTC(), Method: req.Method, URL: u.String()}
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			b, _ := io.ReadAll(io.LimitReader(body, int64(r.maxBody)+1))
+			body.Close()
+			ex.RequestBody, ex.RequestTruncated = r.clip(b)
+		}
+	}
+	return ex
+}
+
+// recordExchange captures one completed exchange; respBody is the already
+// read (and decompressed) response body.
+func (c *Client) recordExchange(resp *http.Response, respBody []byte) {
+	rec := c.config.FlightRecorder
+	if rec == nil || resp.Request == nil {
+		return
+	}
+	ex := rec.exchangeFor(resp.Request)
+	ex.Status = resp.StatusCode
+	ex.ResponseBody, ex.ResponseTruncated = rec.clip(respBody)
+	rec.record(ex)
+}
+
+// recordExchangeError captures a request that never got a response.
+func (c *Client) recordExchangeError(req *http.Request, err error) {
+	rec := c.config.FlightRecorder
+	if rec == nil {
+		return
+	}
+	ex := rec.exchangeFor(req)
+	ex.Error = err.Error()
+	rec.record(ex)
+}
+
+// DumpRecentExchanges returns the configured flight recorder's retained
+// exchanges, oldest first, for enriching an error report; nil without a
+// recorder. See FlightRecorder.
+func (c *Client) DumpRecentExchanges() []Exchange {
+	return c.config.FlightRecorder.Dump()
+}
@@ -0,0 +1,131 @@
+package voiceworld
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestFlightRecorderKeepsRecentExchanges(t *testing.T) {
+	server := newEnvelopeServer(t, `{"code":0,"message":"ok"}`)
+	recorder := NewFlightRecorder(4, 0)
+	client, _ := newTestClient(t, &ClientConfig{BaseURL: server.URL, FlightRecorder: recorder})
+
+	for i := 0; i < 6; i++ {
+		body := bytes.NewReader([]byte(fmt.Sprintf(`{"call":%d}`, i)))
+		req, err := client.NewSignedRequest(context.Background(), http.MethodPost, "/custom", body, "application/json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := client.DoJSON(req, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := client.DumpRecentExchanges()
+	if len(got) != 4 {
+		t.Fatalf("retained %d exchanges, want 4", len(got))
+	}
+	// Oldest first; the first two calls were evicted.
+	for i, ex := range got {
+		if want := fmt.Sprintf(`{"call":%d}`, i+2); ex.RequestBody != want {
+			t.Errorf("exchange %d request body = %q, want %q", i, ex.RequestBody, want)
+		}
+		if ex.Status != http.StatusOK || ex.Method != http.MethodPost {
+			t.Errorf("exchange %d = %+v", i, ex)
+		}
+		if !strings.Contains(ex.ResponseBody, `"code":0`) {
+			t.Errorf("exchange %d response body = %q", i, ex.ResponseBody)
+		}
+		if !strings.HasSuffix(ex.URL, "/custom") {
+			t.Errorf("exchange %d url = %q", i, ex.URL)
+		}
+	}
+}
+
+func TestFlightRecorderClipsBodiesAndOmitsCredentials(t *testing.T) {
+	server := newEnvelopeServer(t, `{"code":0,"message":"`+strings.Repeat("x", 200)+`"}`)
+	recorder := NewFlightRecorder(2, 16)
+	client, _ := newTestClient(t, &ClientConfig{BaseURL: server.URL, FlightRecorder: recorder})
+
+	body := bytes.NewReader(bytes.Repeat([]byte("y"), 100))
+	req, err := client.NewSignedRequest(context.Background(), http.MethodPost, "/custom", body, "application/octet-stream")
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature := req.Header.Get(headerSignature)
+	if err := client.DoJSON(req, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	got := client.DumpRecentExchanges()
+	if len(got) != 1 {
+		t.Fatalf("retained %d exchanges, want 1", len(got))
+	}
+	ex := got[0]
+	if len(ex.RequestBody) != 16 || !ex.RequestTruncated {
+		t.Errorf("request body clipped to %d bytes, truncated=%v", len(ex.RequestBody), ex.RequestTruncated)
+	}
+	if len(ex.ResponseBody) != 16 || !ex.ResponseTruncated {
+		t.Errorf("response body clipped to %d bytes, truncated=%v", len(ex.ResponseBody), ex.ResponseTruncated)
+	}
+	// Nothing in the dump may carry what the signed headers carried.
+	dump, err := json.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{signature, "test-secret", "test-app-key"} {
+		if secret != "" && strings.Contains(string(dump), secret) {
+			t.Errorf("dump retains credential material %q", secret)
+		}
+	}
+}
+
+func TestFlightRecorderCapturesTransportErrors(t *testing.T) {
+	server := newEnvelopeServer(t, `{"code":0}`)
+	base := server.URL
+	server.Close() // every request now fails at the transport
+	recorder := NewFlightRecorder(2, 0)
+	client, _ := newTestClient(t, &ClientConfig{BaseURL: base, FlightRecorder: recorder})
+
+	if _, err := client.GetTaskResult("t-1", nil); err == nil {
+		t.Fatal("request against closed server succeeded")
+	}
+	got := client.DumpRecentExchanges()
+	if len(got) == 0 {
+		t.Fatal("transport failure not recorded")
+	}
+	ex := got[len(got)-1]
+	if ex.Error == "" || ex.Status != 0 {
+		t.Errorf("failed exchange = %+v, want error and no status", ex)
+	}
+}
+
+func TestFlightRecorderConcurrentRecordAndDump(t *testing.T) {
+	recorder := NewFlightRecorder(8, 32)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				recorder.record(Exchange{Method: "POST", URL: fmt.Sprintf("/g%d/%d", g, i)})
+				if i%10 == 0 {
+					if dumped := recorder.Dump(); len(dumped) > 8 {
+						t.Errorf("dump returned %d entries, cap is 8", len(dumped))
+						return
+					}
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	if got := recorder.Dump(); len(got) != 8 {
+		t.Errorf("final dump holds %d entries, want 8", len(got))
+	}
+}
@@ -0,0 +1,100 @@
+package voiceworld
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+)
+
+// Magic prefixes of the telephony containers the SDK recognizes beyond WAV.
+var (
+	amrMagic = []byte("#!AMR") // AMR-NB, optionally "#!AMR-WB"
+	oggMagic = []byte("OggS")  // OGG container; Speex declares itself in the first page
+	speexTag = []byte("Speex   ")
+)
+
+// DetectAudioFormat sniffs a file's container from its magic bytes and
+// returns the platform format string: "wav", "amr" (AMR-NB telephony
+// recordings) or "speex" (Speex in an OGG container). Content matching none
+// of them returns "".
+func DetectAudioFormat(path string) (string, error) {
+	f, err := os.Open(longPath(path))
+	if err != nil {
+		return "", fmt.Errorf("voiceworld: open audio: %w", err)
+	}
+	defer f.Close()
+	head := make([]byte, 64)
+	n, _ := f.Read(head)
+	return detectAudioFormat(head[:n]), nil
+}
+
+// detectAudioFormat is DetectAudioFormat on an in-memory header.
+func detectAudioFormat(head []byte) string {
+	switch {
+	case len(head) >= 12 && bytes.Equal(head[0:4], []byte("RIFF")) && bytes.Equal(head[8:12], []byte("WAVE")):
+		return "wav"
+	case bytes.HasPrefix(head, amrMagic):
+		return "amr"
+	case bytes.HasPrefix(head, oggMagic) && bytes.Contains(head, speexTag):
+		return "speex"
+	}
+	return ""
+}
+
+// isTelephonyFormat reports the formats that need decoding before any of the
+// SDK's PCM machinery can touch them.
+func isTelephonyFormat(format string) bool {
+	return format == "amr" || format == "speex"
+}
+
+// minAudioFileBytes is the smallest file that can carry any audio at all in
+// the given platform format: a wav needs its 44-byte header plus at least one
+// frame, an amr file its magic line plus one frame header, a speex file one
+// OGG page with payload, and an unrecognized blob at least one byte. Anything
+// below the bound is an empty or header-only file and gets rejected before a
+// request is made of it.
+func minAudioFileBytes(format string) int64 {
+	switch format {
+	case "wav":
+		return 45
+	case "amr":
+		return int64(len(amrMagic)) + 2
+	case "speex":
+		return 28
+	default:
+		return 1
+	}
+}
+
+// transcodeToWAV decodes a telephony container into a registered temp WAV in
+// the recognition layout by shelling out to ffmpeg. The binary being absent
+// is the common failure, so it gets its own message instead of a bare exec
+// error.
+func transcodeToWAV(ctx context.Context, filePath, format string) (string, error) {
+	ffmpeg, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		return "", fmt.Errorf("voiceworld: decoding %s audio requires ffmpeg on PATH: %w", format, err)
+	}
+	out, err := newProcessedTempFile()
+	if err != nil {
+		return "", fmt.Errorf("voiceworld: create output: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		removeTempFile(out.Name())
+		return "", err
+	}
+	cmd := exec.CommandContext(ctx, ffmpeg,
+		"-hide_banner", "-loglevel", "error", "-y",
+		"-i", filePath,
+		"-ar", strconv.Itoa(targetSampleRate),
+		"-ac", strconv.Itoa(targetChannels),
+		out.Name())
+	if output, err := cmd.CombinedOutput(); err != nil {
+		removeTempFile(out.Name())
+		return "", fmt.Errorf("voiceworld: ffmpeg transcode of %s failed: %v: %s", filePath, err, bytes.TrimSpace(output))
+	}
+	return out.Name(), nil
+}
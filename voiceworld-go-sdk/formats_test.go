@@ -0,0 +1,130 @@
+package voiceworld
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// makeAMRFile writes an AMR-NB fixture: the magic header followed by a few
+// seconds worth of frame-sized filler (AMR frames are 20 ms each).
+func makeAMRFile(t *testing.T) string {
+	t.Helper()
+	var buf bytes.Buffer
+	buf.WriteString("#!AMR\n")
+	frame := bytes.Repeat([]byte{0x3C}, 32)
+	for i := 0; i < 150; i++ { // ~3 s
+		buf.Write(frame)
+	}
+	path := filepath.Join(t.TempDir(), "audio.amr")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// makeSpeexFile writes a Speex-in-OGG fixture: an OGG page whose payload
+// opens with the Speex stream header.
+func makeSpeexFile(t *testing.T) string {
+	t.Helper()
+	var buf bytes.Buffer
+	buf.WriteString("OggS")
+	buf.Write(make([]byte, 24)) // version, flags, granule, serial, sequence, checksum
+	buf.WriteString("Speex   1.2.0")
+	buf.Write(bytes.Repeat([]byte{0x00}, 4000)) // ~3 s of frames
+	path := filepath.Join(t.TempDir(), "audio.spx")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDetectAudioFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{"wav", makeWAVFile(t, 16000, 1, 0.1), "wav"},
+		{"amr", makeAMRFile(t), "amr"},
+		{"speex", makeSpeexFile(t), "speex"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DetectAudioFormat(tt.path)
+			if err != nil {
+				t.Fatalf("DetectAudioFormat: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("DetectAudioFormat = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	unknown := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(unknown, []byte("just text"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := DetectAudioFormat(unknown); err != nil || got != "" {
+		t.Errorf("DetectAudioFormat on text = (%q, %v), want empty", got, err)
+	}
+	// A plain OGG without the Speex tag stays unrecognized rather than
+	// being mislabeled.
+	if got := detectAudioFormat(append([]byte("OggS"), make([]byte, 40)...)); got != "" {
+		t.Errorf("plain OGG detected as %q", got)
+	}
+}
+
+func TestASRSniffsTelephonyFormat(t *testing.T) {
+	var formats []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		formats = append(formats, r.URL.Query().Get("format"))
+		w.Write([]byte(`{"code":0,"data":{"result":"ok"}}`))
+	}))
+	defer server.Close()
+	client, _ := newTestClient(t, &ClientConfig{BaseURL: server.URL})
+
+	if _, err := client.ASR(makeAMRFile(t), nil); err != nil {
+		t.Fatalf("ASR amr: %v", err)
+	}
+	if _, err := client.ASR(makeSpeexFile(t), nil); err != nil {
+		t.Fatalf("ASR speex: %v", err)
+	}
+	// An explicit format always wins over the sniffer.
+	if _, err := client.ASR(makeAMRFile(t), &RecognitionConfig{Format: "pcm"}); err != nil {
+		t.Fatalf("ASR with explicit format: %v", err)
+	}
+	if want := []string{"amr", "speex", "pcm"}; len(formats) != 3 ||
+		formats[0] != want[0] || formats[1] != want[1] || formats[2] != want[2] {
+		t.Errorf("submitted formats = %v, want %v", formats, want)
+	}
+}
+
+func TestSplitRefusesTelephonyFormats(t *testing.T) {
+	client, _ := newTestClient(t, nil)
+	for _, path := range []string{makeAMRFile(t), makeSpeexFile(t)} {
+		_, err := client.SplitAudioFile(path, 60)
+		var verr *AudioValidationError
+		if !errors.As(err, &verr) {
+			t.Fatalf("err = %v (%T), want *AudioValidationError", err, err)
+		}
+		if !strings.Contains(err.Error(), "transcode") {
+			t.Errorf("error %q does not suggest transcoding", err)
+		}
+	}
+}
+
+func TestProcessAudioTelephonyNeedsFFmpeg(t *testing.T) {
+	// An empty PATH guarantees the lookup fails regardless of what the
+	// host has installed.
+	t.Setenv("PATH", t.TempDir())
+	_, err := ProcessAudio(makeAMRFile(t))
+	if err == nil || !strings.Contains(err.Error(), "requires ffmpeg") {
+		t.Errorf("err = %v, want a clear ffmpeg-missing error", err)
+	}
+}
@@ -0,0 +1,232 @@
+package voiceworld
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultHealWindow is how much audio HealBoundaries re-recognizes on each
+// side of a chunk cut when the caller does not choose a window.
+const defaultHealWindow = 3 * time.Second
+
+// HealOptions tunes HealBoundaries. Nil and the zero value heal every
+// boundary with a three-second window on each side of the cut.
+type HealOptions struct {
+	// Window is how much audio to re-recognize on each side of a cut;
+	// non-positive means defaultHealWindow. The spliced region spans the
+	// cut minus Window to the cut plus Window.
+	Window time.Duration
+
+	// MaxBoundaries caps how many boundaries are re-recognized, earliest
+	// first, so callers can budget the extra recognition calls on long
+	// splits. Zero means all of them.
+	MaxBoundaries int
+
+	// Config carries the recognition parameters for the window calls;
+	// Format and SampleRate are overridden from the file's own header.
+	// Nil means platform defaults.
+	Config *RecognitionConfig
+}
+
+// HealedBoundary records one chunk seam HealBoundaries re-recognized and
+// spliced; see MergedResult.HealedBoundaries.
+type HealedBoundary struct {
+	// Boundary is 1-based: boundary N is the seam between chunk N and N+1.
+	Boundary int `json:"boundary"`
+	// At is the cut position on the merged timeline.
+	At Timestamp `json:"at"`
+	// ReplacedSegments is how many merged segments the splice removed.
+	ReplacedSegments int `json:"replaced_segments"`
+	// InsertedSegments is how many re-recognized segments took their place.
+	InsertedSegments int `json:"inserted_segments"`
+}
+
+// HealBoundaries merges the per-chunk transcripts of a split recognition like
+// MergeResults and then repairs the seams: words cut mid-utterance by the
+// split tend to come back as garbage tokens at chunk boundaries, so for each
+// boundary a small window of audio spanning the cut is extracted locally from
+// the source file, recognized on its own, and its segments are spliced over
+// the seam region by timestamp. filePath must be the same PCM WAV the chunks
+// were cut from, and results must be in chunk order (nil entries are skipped,
+// as in MergeResults). Spliced segments and words carry no speaker labels,
+// since a few seconds of audio cannot be diarized against the global speaker
+// set. The result records each healed seam in HealedBoundaries. Passing more
+// than one options value is an error.
+func (c *Client) HealBoundaries(filePath string, results []*RecognitionResult, options ...*HealOptions) (*MergedResult, error) {
+	if len(options) > 1 {
+		return nil, fmt.Errorf("voiceworld: at most one HealOptions may be given, got %d", len(options))
+	}
+	opts := &HealOptions{}
+	if len(options) == 1 && options[0] != nil {
+		opts = options[0]
+	}
+	window := opts.Window
+	if window <= 0 {
+		window = defaultHealWindow
+	}
+
+	status, err := c.life.beginOp("recognize")
+	if err != nil {
+		return nil, err
+	}
+	defer c.life.endOp(status)
+
+	info, err := ParseWAVFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	config := &RecognitionConfig{}
+	if opts.Config != nil {
+		windowConfig := *opts.Config
+		config = &windowConfig
+	}
+	config.Format = "pcm"
+	config.SampleRate = info.SampleRate
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	status.setRequestID(config.RequestID)
+
+	merged := MergeResults(results)
+
+	// Boundary N sits at the accumulated duration of chunks 1..N, the same
+	// accumulation MergeResults shifts timestamps by.
+	var cuts []time.Duration
+	var offset time.Duration
+	for _, r := range results {
+		if r == nil {
+			continue
+		}
+		if offset > 0 {
+			cuts = append(cuts, offset)
+		}
+		offset += r.Duration.Duration
+	}
+	budget := len(cuts)
+	if opts.MaxBoundaries > 0 && opts.MaxBoundaries < budget {
+		budget = opts.MaxBoundaries
+	}
+
+	for i := 0; i < budget; i++ {
+		cut := cuts[i]
+		start := cut - window
+		if start < 0 {
+			start = 0
+		}
+		end := cut + window
+		if end > offset {
+			end = offset
+		}
+		audio, err := readPCMWindow(filePath, info, start, end-start)
+		if err != nil {
+			return nil, err
+		}
+		result, err := c.recognizeChunk(config, audio)
+		if err != nil {
+			return nil, fmt.Errorf("voiceworld: heal boundary %d at %s: %w", i+1, cut, err)
+		}
+		replaced, inserted := spliceSeam(merged.RecognitionResult, result, start, end)
+		merged.HealedBoundaries = append(merged.HealedBoundaries, HealedBoundary{
+			Boundary:         i + 1,
+			At:               NewTimestamp(cut),
+			ReplacedSegments: replaced,
+			InsertedSegments: inserted,
+		})
+	}
+
+	// The chunk-joined text no longer matches the spliced segments; rebuild
+	// it from them when the transcript is segmented at all.
+	if len(merged.HealedBoundaries) > 0 && len(merged.Segments) > 0 {
+		texts := make([]string, len(merged.Segments))
+		for i, s := range merged.Segments {
+			texts[i] = s.Text
+		}
+		merged.Result = strings.Join(texts, " ")
+	}
+	return merged, nil
+}
+
+// readPCMWindow extracts length worth of PCM starting at start on the source
+// timeline, frame-aligned and clamped to the data chunk.
+func readPCMWindow(filePath string, info *WAVInfo, start, length time.Duration) ([]byte, error) {
+	bps := int64(info.BytesPerSecond())
+	offset := alignToFrames(int64(start)*bps/int64(time.Second), info)
+	size := alignToFrames(int64(length)*bps/int64(time.Second), info)
+	if offset > info.DataSize {
+		offset = info.DataSize
+	}
+	if offset+size > info.DataSize {
+		size = info.DataSize - offset
+	}
+	src, err := os.Open(longPath(filePath))
+	if err != nil {
+		return nil, fmt.Errorf("voiceworld: open audio: %w", err)
+	}
+	defer src.Close()
+	audio := make([]byte, size)
+	if _, err := src.ReadAt(audio, info.DataOffset+offset); err != nil {
+		return nil, fmt.Errorf("voiceworld: read boundary window: %w", err)
+	}
+	return audio, nil
+}
+
+// spliceSeam replaces the seam region [start, end] of merged with the window
+// recognition healed, whose timestamps are local to the window. Only spans
+// lying entirely inside the region move in either direction: merged segments
+// and words straddling an edge survive, and window output sticking past an
+// edge is dropped, since the window recognizer has cut-word artifacts of its
+// own at its rims. Spliced spans lose their speaker labels; the window's
+// local diarization cannot be reconciled with the global speaker set.
+func spliceSeam(merged *RecognitionResult, healed *RecognitionResult, start, end time.Duration) (replaced, inserted int) {
+	inside := func(begin, stop time.Duration) bool {
+		return begin >= start && stop <= end
+	}
+
+	kept := merged.Segments[:0]
+	for _, s := range merged.Segments {
+		if inside(s.BeginTime.Duration, s.EndTime.Duration) {
+			replaced++
+			continue
+		}
+		kept = append(kept, s)
+	}
+	merged.Segments = kept
+	for _, s := range healed.Segments {
+		s.BeginTime.Duration += start
+		s.EndTime.Duration += start
+		if !inside(s.BeginTime.Duration, s.EndTime.Duration) {
+			continue
+		}
+		s.SpeakerID = ""
+		merged.Segments = append(merged.Segments, s)
+		inserted++
+	}
+	sort.SliceStable(merged.Segments, func(a, b int) bool {
+		return merged.Segments[a].BeginTime.Duration < merged.Segments[b].BeginTime.Duration
+	})
+
+	keptWords := merged.Words[:0]
+	for _, w := range merged.Words {
+		if inside(w.BeginTime.Duration, w.EndTime.Duration) {
+			continue
+		}
+		keptWords = append(keptWords, w)
+	}
+	merged.Words = keptWords
+	for _, w := range healed.Words {
+		w.BeginTime.Duration += start
+		w.EndTime.Duration += start
+		if !inside(w.BeginTime.Duration, w.EndTime.Duration) {
+			continue
+		}
+		w.SpeakerID = ""
+		merged.Words = append(merged.Words, w)
+	}
+	sort.SliceStable(merged.Words, func(a, b int) bool {
+		return merged.Words[a].BeginTime.Duration < merged.Words[b].BeginTime.Duration
+	})
+	return replaced, inserted
+}
@@ -0,0 +1,173 @@
+package voiceworld
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func seg(text string, begin, end time.Duration) Segment {
+	return Segment{Text: text, BeginTime: NewTimestamp(begin), EndTime: NewTimestamp(end)}
+}
+
+func TestSpliceSeam(t *testing.T) {
+	merged := &RecognitionResult{
+		Segments: []Segment{
+			seg("hello", 0, 2*time.Second),
+			{Text: "garbage", BeginTime: NewTimestamp(2600 * time.Millisecond),
+				EndTime: NewTimestamp(3400 * time.Millisecond), SpeakerID: "S1"},
+			seg("goodbye", 4*time.Second, 6*time.Second),
+		},
+		Words: []Word{
+			{Text: "gar", BeginTime: NewTimestamp(2600 * time.Millisecond), EndTime: NewTimestamp(3 * time.Second)},
+			{Text: "goodbye", BeginTime: NewTimestamp(4 * time.Second), EndTime: NewTimestamp(6 * time.Second)},
+		},
+	}
+	healed := &RecognitionResult{
+		Segments: []Segment{
+			// Lands at [2.5s, 3.5s] globally: inside the seam, spliced in.
+			{Text: "bridge", BeginTime: NewTimestamp(1500 * time.Millisecond),
+				EndTime: NewTimestamp(2500 * time.Millisecond), SpeakerID: "1"},
+			// Lands at [4.8s, 5.5s]: past the seam's end, an edge artifact
+			// of the window recognition itself, dropped.
+			seg("artifact", 3800*time.Millisecond, 4500*time.Millisecond),
+		},
+		Words: []Word{
+			{Text: "bridge", BeginTime: NewTimestamp(1500 * time.Millisecond),
+				EndTime: NewTimestamp(2500 * time.Millisecond), SpeakerID: "1"},
+		},
+	}
+
+	replaced, inserted := spliceSeam(merged, healed, time.Second, 5*time.Second)
+	if replaced != 1 || inserted != 1 {
+		t.Errorf("replaced/inserted = %d/%d, want 1/1", replaced, inserted)
+	}
+	wantTexts := []string{"hello", "bridge", "goodbye"}
+	if len(merged.Segments) != len(wantTexts) {
+		t.Fatalf("got %d segments, want %d: %+v", len(merged.Segments), len(wantTexts), merged.Segments)
+	}
+	for i, want := range wantTexts {
+		if merged.Segments[i].Text != want {
+			t.Errorf("segment %d = %q, want %q", i, merged.Segments[i].Text, want)
+		}
+	}
+	if got := merged.Segments[1].BeginTime.Duration; got != 2500*time.Millisecond {
+		t.Errorf("spliced segment begins at %v, want the window offset applied", got)
+	}
+	if merged.Segments[1].SpeakerID != "" {
+		t.Error("spliced segment kept its window-local speaker label")
+	}
+	if len(merged.Words) != 2 || merged.Words[0].Text != "bridge" || merged.Words[1].Text != "goodbye" {
+		t.Errorf("words = %+v, want the seam word replaced and the rest kept", merged.Words)
+	}
+}
+
+func TestHealBoundariesSplicesSeam(t *testing.T) {
+	var calls int32
+	var received int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		body, _ := io.ReadAll(r.Body)
+		atomic.AddInt64(&received, int64(len(body)))
+		if got := r.URL.Query().Get("format"); got != "pcm" {
+			t.Errorf("format = %q, want pcm", got)
+		}
+		// Window-local timestamps: 0 is the start of the extracted window.
+		fmt.Fprint(w, `{"code":0,"data":{"task_id":"win","segments":[
+			{"text":"world","begin_time":100,"end_time":1900}]}}`)
+	}))
+	defer server.Close()
+	client, _ := newTestClient(t, &ClientConfig{BaseURL: server.URL})
+
+	path := makeWAVFile(t, 16000, 1, 4.0)
+	results := []*RecognitionResult{
+		{
+			Result:   "hello wor",
+			Duration: NewTimestamp(2 * time.Second),
+			Segments: []Segment{
+				seg("hello", 0, 1100*time.Millisecond),
+				seg("wor", 1200*time.Millisecond, 1900*time.Millisecond),
+			},
+		},
+		{
+			Result:   "ld friends",
+			Duration: NewTimestamp(2 * time.Second),
+			Segments: []Segment{
+				seg("ld", 50*time.Millisecond, 500*time.Millisecond),
+				seg("friends", 1100*time.Millisecond, 2000*time.Millisecond),
+			},
+		},
+	}
+
+	merged, err := client.HealBoundaries(path, results, &HealOptions{Window: time.Second})
+	if err != nil {
+		t.Fatalf("HealBoundaries: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("server saw %d recognition calls, want 1 (one boundary)", calls)
+	}
+	// The seam window spans [1s, 3s]: two seconds of 16 kHz mono PCM.
+	if want := int64(2 * 32000); received != want {
+		t.Errorf("window upload = %d bytes, want %d", received, want)
+	}
+	wantTexts := []string{"hello", "world", "friends"}
+	if len(merged.Segments) != len(wantTexts) {
+		t.Fatalf("got %d segments, want %d: %+v", len(merged.Segments), len(wantTexts), merged.Segments)
+	}
+	for i, want := range wantTexts {
+		if merged.Segments[i].Text != want {
+			t.Errorf("segment %d = %q, want %q", i, merged.Segments[i].Text, want)
+		}
+	}
+	// The window's [0.1s, 1.9s] lands at [1.1s, 2.9s] on the merged timeline.
+	if got := merged.Segments[1].BeginTime.Duration; got != 1100*time.Millisecond {
+		t.Errorf("healed segment begins at %v, want 1.1s", got)
+	}
+	if merged.Result != "hello world friends" {
+		t.Errorf("Result = %q, want the text rebuilt from the spliced segments", merged.Result)
+	}
+	if len(merged.HealedBoundaries) != 1 {
+		t.Fatalf("HealedBoundaries = %+v, want one entry", merged.HealedBoundaries)
+	}
+	hb := merged.HealedBoundaries[0]
+	if hb.Boundary != 1 || hb.At.Duration != 2*time.Second || hb.ReplacedSegments != 2 || hb.InsertedSegments != 1 {
+		t.Errorf("HealedBoundaries[0] = %+v, want boundary 1 at 2s replacing 2 segments with 1", hb)
+	}
+}
+
+func TestHealBoundariesBudgetAndClamping(t *testing.T) {
+	var calls int32
+	var received int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		body, _ := io.ReadAll(r.Body)
+		atomic.AddInt64(&received, int64(len(body)))
+		fmt.Fprint(w, `{"code":0,"data":{"task_id":"win"}}`)
+	}))
+	defer server.Close()
+	client, _ := newTestClient(t, &ClientConfig{BaseURL: server.URL})
+
+	path := makeWAVFile(t, 16000, 1, 3.0)
+	second := func() *RecognitionResult {
+		return &RecognitionResult{Duration: NewTimestamp(time.Second)}
+	}
+	merged, err := client.HealBoundaries(path, []*RecognitionResult{second(), second(), second()},
+		&HealOptions{Window: 1500 * time.Millisecond, MaxBoundaries: 1})
+	if err != nil {
+		t.Fatalf("HealBoundaries: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("server saw %d calls, want 1 (MaxBoundaries budget)", calls)
+	}
+	if len(merged.HealedBoundaries) != 1 || merged.HealedBoundaries[0].At.Duration != time.Second {
+		t.Errorf("HealedBoundaries = %+v, want only the first boundary", merged.HealedBoundaries)
+	}
+	// The window around the 1s cut is clamped to the audio: [0s, 2.5s].
+	if want := int64(2500 * 32); received != want {
+		t.Errorf("window upload = %d bytes, want %d (clamped at the file start)", received, want)
+	}
+}
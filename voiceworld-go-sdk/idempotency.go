@@ -0,0 +1,33 @@
+package voiceworld
+
+import "fmt"
+
+// headerIdempotencyKey carries the key the server uses to de-duplicate a
+// mutating request whose first attempt succeeded but whose response was
+// lost, so a retry does not create a second job.
+const headerIdempotencyKey = "Idempotency-Key"
+
+// newIdempotencyKey returns a random UUIDv4.
+func newIdempotencyKey() string {
+	var b [16]byte
+	if _, err := randRead(b[:]); err != nil {
+		// crypto/rand never fails on supported platforms; NewRequestID
+		// has its own clock fallback.
+		return NewRequestID()
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// WithIdempotencyKey returns a client that stamps key on every mutating
+// request it issues, for callers who manage their own keys (e.g. to keep a
+// task submission idempotent across process restarts). The derived client
+// shares the parent's transport and configuration; the parent keeps
+// generating a fresh key per logical call. Either way, every retry of one
+// logical call reuses the same key.
+func (c *Client) WithIdempotencyKey(key string) *Client {
+	derived := *c
+	derived.idempotencyKey = key
+	return &derived
+}
@@ -0,0 +1,119 @@
+package voiceworld
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+)
+
+// keyRecordingServer collects the Idempotency-Key header of every request
+// and fails the first failures requests with 503.
+func keyRecordingServer(t *testing.T, failures int) (*httptest.Server, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		keys = append(keys, r.Header.Get(headerIdempotencyKey))
+		n := len(keys)
+		mu.Unlock()
+		if n <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"code":0,"data":{"url":"https://example.com/out.wav"}}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), keys...)
+	}
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestIdempotencyKeyStableAcrossRetries(t *testing.T) {
+	server, keys := keyRecordingServer(t, 2)
+	client, err := NewClient("ak", "sk", &ClientConfig{
+		BaseURL: server.URL,
+		Retry:   FixedAttempts{Retries: 3, Delay: time.Millisecond},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.PreprocessAudio("https://example.com/in.wav", "req-1"); err != nil {
+		t.Fatalf("PreprocessAudio: %v", err)
+	}
+	got := keys()
+	if len(got) != 3 {
+		t.Fatalf("server saw %d requests, want 3", len(got))
+	}
+	if !uuidPattern.MatchString(got[0]) {
+		t.Errorf("key %q is not a UUIDv4", got[0])
+	}
+	if got[1] != got[0] || got[2] != got[0] {
+		t.Errorf("keys differ across retries: %v", got)
+	}
+}
+
+func TestIdempotencyKeyDiffersAcrossCalls(t *testing.T) {
+	server, keys := keyRecordingServer(t, 0)
+	client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := client.PreprocessAudio("https://example.com/in.wav", "req-1"); err != nil {
+			t.Fatalf("PreprocessAudio: %v", err)
+		}
+	}
+	got := keys()
+	if len(got) != 2 || got[0] == got[1] {
+		t.Errorf("keys across separate calls = %v, want two distinct", got)
+	}
+}
+
+func TestWithIdempotencyKey(t *testing.T) {
+	server, keys := keyRecordingServer(t, 0)
+	client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	derived := client.WithIdempotencyKey("caller-key-1")
+	if _, err := derived.PreprocessAudio("https://example.com/in.wav", "req-1"); err != nil {
+		t.Fatalf("PreprocessAudio: %v", err)
+	}
+	if _, err := client.PreprocessAudio("https://example.com/in.wav", "req-1"); err != nil {
+		t.Fatalf("PreprocessAudio: %v", err)
+	}
+	got := keys()
+	if got[0] != "caller-key-1" {
+		t.Errorf("derived client sent key %q, want caller-key-1", got[0])
+	}
+	if got[1] == "caller-key-1" {
+		t.Error("parent client reused the derived client's key")
+	}
+}
+
+func TestGetRequestsCarryNoIdempotencyKey(t *testing.T) {
+	var key string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key = r.Header.Get(headerIdempotencyKey)
+		w.Write([]byte(`{"code":0,"data":{"models":[]}}`))
+	}))
+	defer server.Close()
+	client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ListModels(); err != nil {
+		t.Fatalf("ListModels: %v", err)
+	}
+	if key != "" {
+		t.Errorf("GET request carried Idempotency-Key %q", key)
+	}
+}
@@ -0,0 +1,176 @@
+//go:build integration
+
+package voiceworld_test
+
+// End-to-end tests against a real (staging) backend, run nightly with
+//
+//	go test -tags integration -run Integration -timeout 60m .
+//
+// They read the target deployment from the environment and skip cleanly when
+// it is not configured, so a plain `go test -tags integration` on a dev
+// machine stays green. Every object staged by a test is deleted in a defer,
+// including on failure, so repeated runs do not fill the staging bucket.
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	voiceworld "github.com/voiceworld/voiceworld-go-sdk"
+	"github.com/voiceworld/voiceworld-go-sdk/voiceworldtest"
+)
+
+// integrationClient builds a client against the deployment named by the
+// VOICEWORLD_TEST_* variables, or skips the test when they are absent.
+func integrationClient(t *testing.T) *voiceworld.Client {
+	t.Helper()
+	appKey := os.Getenv("VOICEWORLD_TEST_APP_KEY")
+	secret := os.Getenv("VOICEWORLD_TEST_SECRET")
+	baseURL := os.Getenv("VOICEWORLD_TEST_BASE_URL")
+	if appKey == "" || secret == "" || baseURL == "" {
+		t.Skip("integration: set VOICEWORLD_TEST_APP_KEY, VOICEWORLD_TEST_SECRET and VOICEWORLD_TEST_BASE_URL")
+	}
+	config := &voiceworld.ClientConfig{BaseURL: baseURL, Parallelism: 4}
+	if bucket := os.Getenv("VOICEWORLD_TEST_BUCKET"); bucket != "" {
+		// Route uploads into the dedicated test bucket instead of the one
+		// the token service hands out by default.
+		tokens, err := voiceworld.NewClient(appKey, secret, &voiceworld.ClientConfig{BaseURL: baseURL})
+		if err != nil {
+			t.Fatalf("NewClient (tokens): %v", err)
+		}
+		config.TokenProvider = voiceworld.NewCachingTokenProvider(bucketOverride{tokens: tokens, bucket: bucket})
+	}
+	client, err := voiceworld.NewClient(appKey, secret, config)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		client.Close(ctx)
+	})
+	return client
+}
+
+// bucketOverride fetches real credentials and swaps in the test bucket.
+type bucketOverride struct {
+	tokens *voiceworld.Client
+	bucket string
+}
+
+func (p bucketOverride) OSSCredentials(ctx context.Context) (*voiceworld.OSSCredentials, error) {
+	creds, err := p.tokens.GetOSSToken()
+	if err != nil {
+		return nil, err
+	}
+	creds.Bucket = p.bucket
+	return creds, nil
+}
+
+func TestIntegrationToken(t *testing.T) {
+	client := integrationClient(t)
+	creds, err := client.GetOSSToken()
+	if err != nil {
+		t.Fatalf("GetOSSToken: %v", err)
+	}
+	if creds.AccessKeyID == "" || creds.Bucket == "" || creds.Endpoint == "" {
+		t.Errorf("incomplete credentials: %+v", creds)
+	}
+	if creds.Expired() {
+		t.Errorf("freshly issued credentials already expired at %s", creds.Expiration)
+	}
+}
+
+func TestIntegrationUploadFile(t *testing.T) {
+	client := integrationClient(t)
+	requestID := "it-upload-" + voiceworld.NewRequestID()
+	defer func() {
+		if err := client.Cleanup(requestID); err != nil {
+			t.Errorf("Cleanup: %v", err)
+		}
+	}()
+
+	path := filepath.Join(t.TempDir(), "small.wav")
+	if err := voiceworldtest.WriteWAVFile(path, voiceworldtest.WAVSpec{Duration: 2 * time.Second}); err != nil {
+		t.Fatalf("WriteWAVFile: %v", err)
+	}
+	key := requestID + "/small.wav"
+	url, err := client.UploadFile(path, key)
+	if err != nil {
+		t.Fatalf("UploadFile: %v", err)
+	}
+	if url == "" {
+		t.Fatal("UploadFile returned an empty signed URL")
+	}
+	rc, err := client.DownloadObject(key)
+	if err != nil {
+		t.Fatalf("DownloadObject: %v", err)
+	}
+	defer rc.Close()
+	n, err := io.Copy(io.Discard, rc)
+	if err != nil {
+		t.Fatalf("read staged object: %v", err)
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != fi.Size() {
+		t.Errorf("staged object is %d bytes, uploaded %d", n, fi.Size())
+	}
+}
+
+func TestIntegrationSplitLargeFile(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration: skipping 150 MB split in -short mode")
+	}
+	client := integrationClient(t)
+
+	// ~150 MiB of 16 kHz mono PCM; generated streaming, so the fixture
+	// costs disk, not memory.
+	path := filepath.Join(t.TempDir(), "large.wav")
+	const duration = 4915 * time.Second
+	if err := voiceworldtest.WriteWAVFile(path, voiceworldtest.WAVSpec{Duration: duration}); err != nil {
+		t.Fatalf("WriteWAVFile: %v", err)
+	}
+
+	result, err := client.SplitAudioFile(path, 60, &voiceworld.SplitOptions{WriteManifest: true})
+	if result != nil && result.RequestID != "" {
+		requestID := result.RequestID
+		defer func() {
+			if cerr := client.Cleanup(requestID); cerr != nil {
+				t.Errorf("Cleanup: %v", cerr)
+			}
+		}()
+	}
+	if err != nil {
+		t.Fatalf("SplitAudioFile: %v", err)
+	}
+	wantParts := int(duration.Seconds()+59) / 60
+	if len(result.Parts) != wantParts {
+		t.Errorf("split into %d parts, want %d", len(result.Parts), wantParts)
+	}
+	if _, err := client.VerifyManifest(result.RequestID); err != nil {
+		t.Errorf("VerifyManifest: %v", err)
+	}
+}
+
+func TestIntegrationRecognize(t *testing.T) {
+	client := integrationClient(t)
+	path := filepath.Join(t.TempDir(), "speech.wav")
+	if err := voiceworldtest.WriteWAVFile(path, voiceworldtest.WAVSpec{Duration: 3 * time.Second}); err != nil {
+		t.Fatalf("WriteWAVFile: %v", err)
+	}
+	result, err := client.ASR(path, nil)
+	if err != nil {
+		t.Fatalf("ASR: %v", err)
+	}
+	// A synthetic tone carries no speech; the backend answering with a
+	// task at all is what this exercises.
+	if result.TaskID == "" && result.Result == "" {
+		t.Error("recognition returned neither a task id nor a transcript")
+	}
+}
@@ -0,0 +1,315 @@
+// Package asrpb implements the binary wire format of the recognition API's
+// protobuf response mode (Content-Type: application/x-protobuf).
+//
+// The schema is small and owned by the platform team:
+//
+//	message Envelope {
+//	  int32  code    = 1;
+//	  string message = 2;
+//	  Result result  = 3;
+//	}
+//	message Result {
+//	  string   task_id  = 1;
+//	  string   text     = 2;
+//	  repeated Word words = 3;
+//	  int64    duration = 4;
+//	}
+//	message Word {
+//	  string text       = 1;
+//	  int64  begin_time = 2;
+//	  int64  end_time   = 3;
+//	  double confidence = 4;
+//	  string speaker_id = 5;
+//	}
+//
+// The decoder is maintained by hand on top of protowire rather than
+// generated, so the SDK does not carry a protoc toolchain for three
+// messages. Keep it in sync with the schema above.
+package asrpb
+
+import (
+	"fmt"
+	"math"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Envelope mirrors the JSON response envelope.
+type Envelope struct {
+	Code    int32
+	Message string
+	Result  *Result
+}
+
+// Result is one recognition result.
+type Result struct {
+	TaskID   string
+	Text     string
+	Words    []Word
+	Duration int64
+}
+
+// Word is one recognized token.
+type Word struct {
+	Text       string
+	BeginTime  int64
+	EndTime    int64
+	Confidence float64
+	SpeakerID  string
+}
+
+// UnmarshalEnvelope decodes a binary response envelope.
+func UnmarshalEnvelope(data []byte) (*Envelope, error) {
+	env := &Envelope{}
+	err := walkFields(data, func(num protowire.Number, typ protowire.Type, payload []byte) error {
+		switch num {
+		case 1:
+			v, err := asVarint(typ, payload)
+			env.Code = int32(v)
+			return err
+		case 2:
+			v, err := asBytes(typ, payload)
+			env.Message = string(v)
+			return err
+		case 3:
+			raw, err := asBytes(typ, payload)
+			if err != nil {
+				return err
+			}
+			result, err := unmarshalResult(raw)
+			if err != nil {
+				return err
+			}
+			env.Result = result
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("asrpb: decode envelope: %w", err)
+	}
+	return env, nil
+}
+
+func unmarshalResult(data []byte) (*Result, error) {
+	result := &Result{}
+	err := walkFields(data, func(num protowire.Number, typ protowire.Type, payload []byte) error {
+		switch num {
+		case 1:
+			v, err := asBytes(typ, payload)
+			result.TaskID = string(v)
+			return err
+		case 2:
+			v, err := asBytes(typ, payload)
+			result.Text = string(v)
+			return err
+		case 3:
+			raw, err := asBytes(typ, payload)
+			if err != nil {
+				return err
+			}
+			word, err := unmarshalWord(raw)
+			if err != nil {
+				return err
+			}
+			result.Words = append(result.Words, *word)
+		case 4:
+			v, err := asVarint(typ, payload)
+			result.Duration = int64(v)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func unmarshalWord(data []byte) (*Word, error) {
+	word := &Word{}
+	err := walkFields(data, func(num protowire.Number, typ protowire.Type, payload []byte) error {
+		switch num {
+		case 1:
+			v, err := asBytes(typ, payload)
+			word.Text = string(v)
+			return err
+		case 2:
+			v, err := asVarint(typ, payload)
+			word.BeginTime = int64(v)
+			return err
+		case 3:
+			v, err := asVarint(typ, payload)
+			word.EndTime = int64(v)
+			return err
+		case 4:
+			v, err := asDouble(typ, payload)
+			word.Confidence = v
+			return err
+		case 5:
+			v, err := asBytes(typ, payload)
+			word.SpeakerID = string(v)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return word, nil
+}
+
+// walkFields iterates the top-level fields of one message, handing each
+// field's raw payload to visit. Unknown fields are skipped, as proto
+// requires.
+func walkFields(data []byte, visit func(protowire.Number, protowire.Type, []byte) error) error {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+		size := protowire.ConsumeFieldValue(num, typ, data)
+		if size < 0 {
+			return protowire.ParseError(size)
+		}
+		if err := visit(num, typ, data[:size]); err != nil {
+			return err
+		}
+		data = data[size:]
+	}
+	return nil
+}
+
+func asVarint(typ protowire.Type, payload []byte) (uint64, error) {
+	if typ != protowire.VarintType {
+		return 0, fmt.Errorf("asrpb: field has wire type %v, want varint", typ)
+	}
+	v, n := protowire.ConsumeVarint(payload)
+	if n < 0 {
+		return 0, protowire.ParseError(n)
+	}
+	return v, nil
+}
+
+func asBytes(typ protowire.Type, payload []byte) ([]byte, error) {
+	if typ != protowire.BytesType {
+		return nil, fmt.Errorf("asrpb: field has wire type %v, want bytes", typ)
+	}
+	v, n := protowire.ConsumeBytes(payload)
+	if n < 0 {
+		return nil, protowire.ParseError(n)
+	}
+	return v, nil
+}
+
+func asDouble(typ protowire.Type, payload []byte) (float64, error) {
+	if typ != protowire.Fixed64Type {
+		return 0, fmt.Errorf("asrpb: field has wire type %v, want fixed64", typ)
+	}
+	v, n := protowire.ConsumeFixed64(payload)
+	if n < 0 {
+		return 0, protowire.ParseError(n)
+	}
+	return math.Float64frombits(v), nil
+}
+
+// RecognizeRequest is the unary gRPC request message:
+//
+//	message RecognizeRequest {
+//	  bytes  audio       = 1;
+//	  string format      = 2;
+//	  int32  sample_rate = 3;
+//	  string request_id  = 4;
+//	}
+type RecognizeRequest struct {
+	Audio      []byte
+	Format     string
+	SampleRate int32
+	RequestID  string
+}
+
+// AppendRecognizeRequest encodes req onto b.
+func AppendRecognizeRequest(b []byte, req *RecognizeRequest) []byte {
+	b = protowire.AppendTag(b, 1, protowire.BytesType)
+	b = protowire.AppendBytes(b, req.Audio)
+	b = protowire.AppendTag(b, 2, protowire.BytesType)
+	b = protowire.AppendString(b, req.Format)
+	b = protowire.AppendTag(b, 3, protowire.VarintType)
+	b = protowire.AppendVarint(b, uint64(req.SampleRate))
+	b = protowire.AppendTag(b, 4, protowire.BytesType)
+	b = protowire.AppendString(b, req.RequestID)
+	return b
+}
+
+// UnmarshalRecognizeRequest decodes a RecognizeRequest; the server side of
+// in-process tests needs it.
+func UnmarshalRecognizeRequest(data []byte) (*RecognizeRequest, error) {
+	req := &RecognizeRequest{}
+	err := walkFields(data, func(num protowire.Number, typ protowire.Type, payload []byte) error {
+		switch num {
+		case 1:
+			v, err := asBytes(typ, payload)
+			req.Audio = append([]byte(nil), v...)
+			return err
+		case 2:
+			v, err := asBytes(typ, payload)
+			req.Format = string(v)
+			return err
+		case 3:
+			v, err := asVarint(typ, payload)
+			req.SampleRate = int32(v)
+			return err
+		case 4:
+			v, err := asBytes(typ, payload)
+			req.RequestID = string(v)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("asrpb: decode recognize request: %w", err)
+	}
+	return req, nil
+}
+
+// AppendEnvelope encodes env; it exists for the test fixtures and the fake
+// backend.
+func AppendEnvelope(b []byte, env *Envelope) []byte {
+	b = protowire.AppendTag(b, 1, protowire.VarintType)
+	b = protowire.AppendVarint(b, uint64(env.Code))
+	b = protowire.AppendTag(b, 2, protowire.BytesType)
+	b = protowire.AppendString(b, env.Message)
+	if env.Result != nil {
+		b = protowire.AppendTag(b, 3, protowire.BytesType)
+		b = protowire.AppendBytes(b, appendResult(nil, env.Result))
+	}
+	return b
+}
+
+func appendResult(b []byte, r *Result) []byte {
+	b = protowire.AppendTag(b, 1, protowire.BytesType)
+	b = protowire.AppendString(b, r.TaskID)
+	b = protowire.AppendTag(b, 2, protowire.BytesType)
+	b = protowire.AppendString(b, r.Text)
+	for i := range r.Words {
+		b = protowire.AppendTag(b, 3, protowire.BytesType)
+		b = protowire.AppendBytes(b, appendWord(nil, &r.Words[i]))
+	}
+	b = protowire.AppendTag(b, 4, protowire.VarintType)
+	b = protowire.AppendVarint(b, uint64(r.Duration))
+	return b
+}
+
+func appendWord(b []byte, w *Word) []byte {
+	b = protowire.AppendTag(b, 1, protowire.BytesType)
+	b = protowire.AppendString(b, w.Text)
+	b = protowire.AppendTag(b, 2, protowire.VarintType)
+	b = protowire.AppendVarint(b, uint64(w.BeginTime))
+	b = protowire.AppendTag(b, 3, protowire.VarintType)
+	b = protowire.AppendVarint(b, uint64(w.EndTime))
+	b = protowire.AppendTag(b, 4, protowire.Fixed64Type)
+	b = protowire.AppendFixed64(b, math.Float64bits(w.Confidence))
+	b = protowire.AppendTag(b, 5, protowire.BytesType)
+	b = protowire.AppendString(b, w.SpeakerID)
+	return b
+}
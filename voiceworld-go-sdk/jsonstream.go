@@ -0,0 +1,241 @@
+package voiceworld
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+)
+
+// jsonStreamer is the io.Reader core behind NewJSONStreamer and
+// NewMergedJSONStreamer: fill appends the next bounded piece of JSON to buf
+// and reports whether more remains, so no more than one element's worth of
+// output is ever buffered.
+type jsonStreamer struct {
+	buf  bytes.Buffer
+	err  error
+	done bool
+	fill func() bool
+}
+
+func (s *jsonStreamer) Read(p []byte) (int, error) {
+	for s.err == nil && !s.done && s.buf.Len() == 0 {
+		if !s.fill() {
+			s.done = true
+		}
+	}
+	if s.buf.Len() > 0 {
+		return s.buf.Read(p)
+	}
+	if s.err != nil {
+		return 0, s.err
+	}
+	return 0, io.EOF
+}
+
+// value appends the encoding/json rendering of v.
+func (s *jsonStreamer) value(v interface{}) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		s.err = err
+		return
+	}
+	s.buf.Write(b)
+}
+
+func (s *jsonStreamer) raw(literal string) {
+	s.buf.WriteString(literal)
+}
+
+// resultHead writes the RecognitionResult fields that precede the words
+// array, reproducing the struct's tag order and omitempty behaviour.
+func resultHead(s *jsonStreamer, r *RecognitionResult) {
+	s.raw(`{"task_id":`)
+	s.value(r.TaskID)
+	s.raw(`,"result":`)
+	s.value(r.Result)
+	if r.RawResult != "" {
+		s.raw(`,"raw_result":`)
+		s.value(r.RawResult)
+	}
+}
+
+// resultTail writes the fields that follow the segments array.
+func resultTail(s *jsonStreamer, r *RecognitionResult) {
+	if r.DetectedLanguage != "" {
+		s.raw(`,"detected_language":`)
+		s.value(r.DetectedLanguage)
+	}
+	s.raw(`,"duration":`)
+	s.value(r.Duration)
+}
+
+// NewJSONStreamer returns a reader yielding exactly the bytes json.Marshal
+// would produce for result, but marshalling one word or segment at a time,
+// so exporting an hours-long transcript to a file never holds a second copy
+// of it in memory.
+func NewJSONStreamer(result *RecognitionResult) io.Reader {
+	s := &jsonStreamer{}
+	phase, wi, si := 0, 0, 0
+	s.fill = func() bool {
+		switch phase {
+		case 0:
+			resultHead(s, result)
+			phase++
+		case 1:
+			if len(result.Words) == 0 {
+				phase++
+				break
+			}
+			if wi == 0 {
+				s.raw(`,"words":[`)
+			} else {
+				s.raw(",")
+			}
+			s.value(result.Words[wi])
+			wi++
+			if wi == len(result.Words) {
+				s.raw("]")
+				phase++
+			}
+		case 2:
+			if len(result.Segments) == 0 {
+				phase++
+				break
+			}
+			if si == 0 {
+				s.raw(`,"segments":[`)
+			} else {
+				s.raw(",")
+			}
+			s.value(result.Segments[si])
+			si++
+			if si == len(result.Segments) {
+				s.raw("]")
+				phase++
+			}
+		default:
+			resultTail(s, result)
+			s.raw("}")
+			return false
+		}
+		return true
+	}
+	return s
+}
+
+// NewMergedJSONStreamer stitches per-chunk transcripts the way MergeResults
+// does and returns a reader yielding exactly the bytes json.Marshal would
+// produce for that *MergedResult — without ever materializing the merged
+// word list. A prepass over the (comparatively small) segments computes the
+// texts, offsets and speaker reconciliation; the words, which dominate an
+// hours-long transcript, are shifted and relabeled one at a time as the
+// reader is drained.
+func NewMergedJSONStreamer(results []*RecognitionResult) io.Reader {
+	merged := &RecognitionResult{}
+	var texts []string
+	var offset time.Duration
+	globalDur := map[string]time.Duration{}
+	nextGlobal := 0
+	offsets := make([]time.Duration, len(results))
+	partMapping := make([]map[string]string, len(results))
+	var speakerMap []map[string]string
+	for idx, r := range results {
+		if r == nil {
+			speakerMap = append(speakerMap, nil)
+			continue
+		}
+		if merged.TaskID == "" {
+			merged.TaskID = r.TaskID
+		}
+		if merged.DetectedLanguage == "" {
+			merged.DetectedLanguage = r.DetectedLanguage
+		}
+		if r.Result != "" {
+			texts = append(texts, r.Result)
+		}
+		shifted := make([]Segment, len(r.Segments))
+		for i, seg := range r.Segments {
+			seg.BeginTime.Duration += offset
+			seg.EndTime.Duration += offset
+			shifted[i] = seg
+		}
+		mapping := reconcileChunkSpeakers(merged.Segments, shifted, globalDur, &nextGlobal)
+		for _, seg := range shifted {
+			if g, ok := mapping[seg.SpeakerID]; ok {
+				seg.SpeakerID = g
+			}
+			globalDur[seg.SpeakerID] += seg.EndTime.Duration - seg.BeginTime.Duration
+			merged.Segments = append(merged.Segments, seg)
+		}
+		offsets[idx] = offset
+		partMapping[idx] = mapping
+		speakerMap = append(speakerMap, mapping)
+		offset += r.Duration.Duration
+	}
+	merged.Result = strings.Join(texts, " ")
+	merged.Duration = NewTimestamp(offset)
+
+	s := &jsonStreamer{}
+	phase, pi, wi, si := 0, 0, 0, 0
+	wroteWord := false
+	s.fill = func() bool {
+		switch phase {
+		case 0:
+			resultHead(s, merged)
+			phase++
+		case 1:
+			// Advance to the next part that still has words.
+			for pi < len(results) && (results[pi] == nil || wi >= len(results[pi].Words)) {
+				pi++
+				wi = 0
+			}
+			if pi == len(results) {
+				if wroteWord {
+					s.raw("]")
+				}
+				phase++
+				break
+			}
+			if !wroteWord {
+				s.raw(`,"words":[`)
+				wroteWord = true
+			} else {
+				s.raw(",")
+			}
+			w := results[pi].Words[wi]
+			w.BeginTime.Duration += offsets[pi]
+			w.EndTime.Duration += offsets[pi]
+			if g, ok := partMapping[pi][w.SpeakerID]; ok {
+				w.SpeakerID = g
+			}
+			s.value(w)
+			wi++
+		case 2:
+			if len(merged.Segments) == 0 {
+				phase++
+				break
+			}
+			if si == 0 {
+				s.raw(`,"segments":[`)
+			} else {
+				s.raw(",")
+			}
+			s.value(merged.Segments[si])
+			si++
+			if si == len(merged.Segments) {
+				s.raw("]")
+				phase++
+			}
+		default:
+			resultTail(s, merged)
+			s.raw(`,"SpeakerMap":`)
+			s.value(speakerMap)
+			s.raw("}")
+			return false
+		}
+		return true
+	}
+	return s
+}
@@ -0,0 +1,125 @@
+package voiceworld
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+)
+
+// syntheticResult builds a result with the given number of words and
+// segments, with timings, speakers and confidences filled in.
+func syntheticResult(words, segments int) *RecognitionResult {
+	r := &RecognitionResult{
+		TaskID:   "t-stream",
+		Result:   "synthetic transcript",
+		Duration: NewTimestamp(time.Duration(words) * 100 * time.Millisecond),
+	}
+	for i := 0; i < words; i++ {
+		begin := time.Duration(i) * 100 * time.Millisecond
+		r.Words = append(r.Words, Word{
+			Text:       fmt.Sprintf("word%d", i),
+			BeginTime:  NewTimestamp(begin),
+			EndTime:    NewTimestamp(begin + 90*time.Millisecond),
+			Confidence: 0.9,
+			SpeakerID:  fmt.Sprintf("spk%d", i%3),
+		})
+	}
+	for i := 0; i < segments; i++ {
+		begin := time.Duration(i) * time.Second
+		r.Segments = append(r.Segments, Segment{
+			Text:      fmt.Sprintf("segment %d", i),
+			BeginTime: NewTimestamp(begin),
+			EndTime:   NewTimestamp(begin + 900*time.Millisecond),
+			SpeakerID: fmt.Sprintf("spk%d", i%3),
+		})
+	}
+	return r
+}
+
+func TestJSONStreamerMatchesMarshal(t *testing.T) {
+	full := syntheticResult(40, 8)
+	full.RawResult = "synthetic transcript, unfiltered"
+	full.DetectedLanguage = "zh-CN"
+	cases := []struct {
+		name   string
+		result *RecognitionResult
+	}{
+		{"minimal", &RecognitionResult{TaskID: "t-1", Result: "hi"}},
+		{"all fields", full},
+		{"words only", syntheticResult(10, 0)},
+		{"segments only", syntheticResult(0, 10)},
+		{"large", syntheticResult(50000, 2000)},
+	}
+	for _, tc := range cases {
+		want, err := json.Marshal(tc.result)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := io.ReadAll(NewJSONStreamer(tc.result))
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s: streamed JSON differs from json.Marshal\n got: %.120s\nwant: %.120s", tc.name, got, want)
+		}
+	}
+}
+
+func TestJSONStreamerSmallReads(t *testing.T) {
+	result := syntheticResult(25, 5)
+	want, _ := json.Marshal(result)
+
+	// Drain through a tiny buffer so output crosses Read boundaries
+	// mid-token.
+	r := NewJSONStreamer(result)
+	var got bytes.Buffer
+	buf := make([]byte, 7)
+	for {
+		n, err := r.Read(buf)
+		got.Write(buf[:n])
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !bytes.Equal(got.Bytes(), want) {
+		t.Error("chunked reads reassemble to different JSON")
+	}
+}
+
+func TestMergedJSONStreamerMatchesMergeResults(t *testing.T) {
+	partA := syntheticResult(30, 6)
+	partA.Duration = NewTimestamp(6 * time.Second)
+	partB := syntheticResult(20, 4)
+	partB.TaskID = "t-b"
+	partB.Result = "second chunk"
+	parts := []*RecognitionResult{partA, nil, partB}
+
+	want, err := json.Marshal(MergeResults(parts))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(NewMergedJSONStreamer(parts))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("streamed merge differs from MergeResults\n got: %.160s\nwant: %.160s", got, want)
+	}
+}
+
+func TestMergedJSONStreamerEmptyInput(t *testing.T) {
+	want, _ := json.Marshal(MergeResults(nil))
+	got, err := io.ReadAll(NewMergedJSONStreamer(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("empty merge = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,36 @@
+package voiceworld
+
+import (
+	"bytes"
+	"net/http"
+)
+
+// LanguageCandidate is one language hypothesis from identification.
+type LanguageCandidate struct {
+	// Language is a BCP 47 tag, e.g. "cmn-Hans-CN", "en-US", "yue".
+	Language   string  `json:"language"`
+	Confidence float64 `json:"confidence"`
+}
+
+// LanguageIDResult ranks the languages detected in a piece of audio.
+type LanguageIDResult struct {
+	// Language is the top candidate, duplicated for convenience.
+	Language   string              `json:"language"`
+	Candidates []LanguageCandidate `json:"candidates,omitempty"`
+}
+
+// IdentifyLanguage detects which language a piece of audio is spoken in
+// without transcribing it, via the /language_id endpoint. It is cheaper than
+// full recognition and meant for routing audio to the right model before
+// transcription. config may be nil for platform defaults.
+func (c *Client) IdentifyLanguage(audio []byte, config *RecognitionConfig) (*LanguageIDResult, error) {
+	if config == nil {
+		config = &RecognitionConfig{}
+	}
+	var result LanguageIDResult
+	if err := c.do(http.MethodPost, "/language_id", config.params(), "application/octet-stream",
+		bytes.NewReader(audio), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
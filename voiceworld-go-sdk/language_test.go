@@ -0,0 +1,77 @@
+package voiceworld
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIdentifyLanguage(t *testing.T) {
+	var gotQuery, gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/language_id" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		gotQuery = r.URL.RawQuery
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		w.Write([]byte(`{"code":0,"data":{"language":"cmn-Hans-CN","candidates":[` +
+			`{"language":"cmn-Hans-CN","confidence":0.91},` +
+			`{"language":"yue","confidence":0.07}]}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := client.IdentifyLanguage([]byte("audio-bytes"),
+		&RecognitionConfig{Format: "pcm", SampleRate: 16000})
+	if err != nil {
+		t.Fatalf("IdentifyLanguage: %v", err)
+	}
+	if result.Language != "cmn-Hans-CN" {
+		t.Errorf("Language = %q", result.Language)
+	}
+	if len(result.Candidates) != 2 || result.Candidates[1].Language != "yue" {
+		t.Errorf("Candidates = %+v", result.Candidates)
+	}
+	if gotBody != "audio-bytes" {
+		t.Errorf("server saw body %q", gotBody)
+	}
+	if gotQuery != "format=pcm&sample_rate=16000" {
+		t.Errorf("query = %q", gotQuery)
+	}
+}
+
+// TestLanguageDetectionFields checks the request flag is serialized and the
+// detected-language fields decode from recognition responses.
+func TestLanguageDetectionFields(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("enable_language_detection") != "true" {
+			t.Errorf("query = %q, want enable_language_detection=true", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"code":0,"data":{"task_id":"t1","result":"hello 你好",` +
+			`"detected_language":"cmn-Hans-CN","segments":[` +
+			`{"text":"hello","begin_time":0,"end_time":400,"language":"en-US"},` +
+			`{"text":"你好","begin_time":400,"end_time":900,"language":"cmn-Hans-CN"}]}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := makeWAVFile(t, 16000, 1, 0.1)
+	result, err := client.ASR(path, &RecognitionConfig{EnableLanguageDetection: true})
+	if err != nil {
+		t.Fatalf("ASR: %v", err)
+	}
+	if result.DetectedLanguage != "cmn-Hans-CN" {
+		t.Errorf("DetectedLanguage = %q", result.DetectedLanguage)
+	}
+	if len(result.Segments) != 2 || result.Segments[0].Language != "en-US" {
+		t.Errorf("Segments = %+v", result.Segments)
+	}
+}
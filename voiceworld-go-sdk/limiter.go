@@ -0,0 +1,60 @@
+package voiceworld
+
+// transferLimiter caps how many part transfers a Client has in flight
+// across all of its concurrent operations, so fifty parallel splits cannot
+// multiply their per-operation Parallelism into an unbounded burst. A nil
+// limiter means unlimited. Clients derived with WithIdempotencyKey share
+// their parent's limiter.
+type transferLimiter struct {
+	slots chan struct{}
+}
+
+// newTransferLimiter builds a limiter of max concurrent transfers;
+// max <= 0 means unlimited.
+func newTransferLimiter(max int) *transferLimiter {
+	if max <= 0 {
+		return nil
+	}
+	return &transferLimiter{slots: make(chan struct{}, max)}
+}
+
+// acquire blocks until a transfer slot is free.
+func (l *transferLimiter) acquire() {
+	if l != nil {
+		l.slots <- struct{}{}
+	}
+}
+
+// release returns a slot taken by acquire.
+func (l *transferLimiter) release() {
+	if l != nil {
+		<-l.slots
+	}
+}
+
+// pace blocks until the client-wide request pacer admits one more API call;
+// see ClientConfig.MaxRequestsPerSecond. Nonzero waits are reported to the
+// OnThrottle hook before they happen, so sustained queuing is observable.
+func (c *Client) pace() {
+	if c.limiter == nil || c.unpaced {
+		return
+	}
+	delay := c.limiter.Reserve().Delay()
+	if delay == 0 {
+		return
+	}
+	if c.config.OnThrottle != nil {
+		c.config.OnThrottle(delay)
+	}
+	c.sleep(delay)
+}
+
+// WithoutPacing returns a Client whose calls skip the MaxRequestsPerSecond
+// pacer, for low-volume priority traffic that must not queue behind bulk
+// work. Everything else is shared with the parent, including the pacer the
+// parent's own calls keep honoring.
+func (c *Client) WithoutPacing() *Client {
+	derived := *c
+	derived.unpaced = true
+	return &derived
+}
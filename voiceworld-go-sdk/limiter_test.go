@@ -0,0 +1,178 @@
+package voiceworld
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// gaugeStorage measures how many transfers are in flight at once. The small
+// sleep inside each transfer makes overlap between concurrent workers all but
+// certain without making the test timing-sensitive: the peak can exceed the
+// cap only if the limiter is broken.
+type gaugeStorage struct {
+	*MemoryStorage
+	mu       sync.Mutex
+	inflight int
+	peak     int
+}
+
+func (s *gaugeStorage) enter() {
+	s.mu.Lock()
+	s.inflight++
+	if s.inflight > s.peak {
+		s.peak = s.inflight
+	}
+	s.mu.Unlock()
+	time.Sleep(2 * time.Millisecond)
+}
+
+func (s *gaugeStorage) exit() {
+	s.mu.Lock()
+	s.inflight--
+	s.mu.Unlock()
+}
+
+func (s *gaugeStorage) PutObject(key string, r io.Reader) error {
+	s.enter()
+	defer s.exit()
+	return s.MemoryStorage.PutObject(key, r)
+}
+
+func (s *gaugeStorage) UploadPart(key, uploadID string, partNumber int, r io.Reader, size int64) (UploadedPart, error) {
+	s.enter()
+	defer s.exit()
+	return s.MemoryStorage.UploadPart(key, uploadID, partNumber, r, size)
+}
+
+func TestMaxConcurrentTransfersCapsClient(t *testing.T) {
+	store := &gaugeStorage{MemoryStorage: NewMemoryStorage()}
+	client, err := NewClient("ak", "sk", &ClientConfig{
+		Storage:                store,
+		ChunkSize:              1024,
+		Parallelism:            4,
+		MaxConcurrentTransfers: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "big.bin")
+	if err := os.WriteFile(path, make([]byte, 4*1024), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	wav := makeWAVFile(t, 16000, 1, 5)
+
+	// Three operations at once, each allowed 4 workers of its own: the
+	// client-wide cap is the only thing holding in-flight transfers to 2.
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.MultipartUploadFile(path, "req/big.bin"); err != nil {
+				t.Errorf("MultipartUploadFile: %v", err)
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if _, err := client.SplitAudioFile(wav, 2); err != nil {
+			t.Errorf("SplitAudioFile: %v", err)
+		}
+	}()
+	wg.Wait()
+
+	if store.peak > 2 {
+		t.Errorf("in-flight peak = %d, want at most 2", store.peak)
+	}
+	if store.peak == 0 {
+		t.Error("no transfers observed")
+	}
+}
+
+func TestMaxConcurrentTransfersZeroMeansUnlimited(t *testing.T) {
+	store := &gaugeStorage{MemoryStorage: NewMemoryStorage()}
+	client, err := NewClient("ak", "sk", &ClientConfig{
+		Storage:     store,
+		Parallelism: 3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.SplitAudioFile(makeWAVFile(t, 16000, 1, 5), 2); err != nil {
+		t.Fatalf("SplitAudioFile: %v", err)
+	}
+	if store.peak == 0 {
+		t.Error("no transfers observed")
+	}
+}
+
+func TestMaxRequestsPerSecondPacesCalls(t *testing.T) {
+	server := newEnvelopeServer(t, `{"code":0,"data":{"models":[]}}`)
+	var waits []time.Duration
+	client, err := NewClient("ak", "sk", &ClientConfig{
+		BaseURL:              server.URL,
+		MaxRequestsPerSecond: 2,
+		OnThrottle:           func(wait time.Duration) { waits = append(waits, wait) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Record the pacer's verdicts instead of actually sleeping, so the
+	// test observes a second's worth of schedule in milliseconds.
+	client.sleep = func(time.Duration) {}
+
+	for i := 0; i < 4; i++ {
+		if _, err := client.ListModels(); err != nil {
+			t.Fatalf("ListModels call %d: %v", i+1, err)
+		}
+	}
+	// Burst of 2 passes immediately; calls 3 and 4 queue half a second
+	// apart, the configured 2 QPS.
+	if len(waits) != 2 {
+		t.Fatalf("throttled waits = %v, want 2 entries", waits)
+	}
+	step := 500 * time.Millisecond
+	for i, wait := range waits {
+		want := time.Duration(i+1) * step
+		if wait < want-100*time.Millisecond || wait > want+100*time.Millisecond {
+			t.Errorf("wait %d = %v, want about %v", i+1, wait, want)
+		}
+	}
+}
+
+func TestWithoutPacingSkipsLimiter(t *testing.T) {
+	server := newEnvelopeServer(t, `{"code":0,"data":{"models":[]}}`)
+	throttled := 0
+	client, err := NewClient("ak", "sk", &ClientConfig{
+		BaseURL:              server.URL,
+		MaxRequestsPerSecond: 1,
+		OnThrottle:           func(time.Duration) { throttled++ },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.sleep = func(time.Duration) {}
+
+	priority := client.WithoutPacing()
+	for i := 0; i < 5; i++ {
+		if _, err := priority.ListModels(); err != nil {
+			t.Fatalf("ListModels: %v", err)
+		}
+	}
+	if throttled != 0 {
+		t.Errorf("priority calls were throttled %d times", throttled)
+	}
+	// The parent still honors the pacer (its burst of 1 is already spent
+	// only if it makes calls of its own; the derived calls spent none).
+	if _, err := client.ListModels(); err != nil {
+		t.Fatalf("paced call: %v", err)
+	}
+	if throttled != 0 {
+		t.Errorf("first paced call should ride the burst, got %d waits", throttled)
+	}
+}
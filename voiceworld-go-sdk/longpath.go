@@ -0,0 +1,7 @@
+//go:build !windows
+
+package voiceworld
+
+// longPath returns the path unchanged: only Windows needs the \\?\ prefix
+// for paths past the legacy limit.
+func longPath(path string) string { return path }
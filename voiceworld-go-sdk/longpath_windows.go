@@ -0,0 +1,17 @@
+//go:build windows
+
+package voiceworld
+
+import "path/filepath"
+
+// longPath rewrites paths past the legacy Windows limit to their \\?\ form
+// so os.Open and friends reach them. Long relative paths are made absolute
+// first, since the \\?\ form bypasses the normalization that resolves them.
+func longPath(path string) string {
+	if len(path) >= windowsPathLimit && !filepath.IsAbs(path) {
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+	}
+	return addLongPathPrefix(path)
+}
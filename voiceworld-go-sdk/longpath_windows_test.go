@@ -0,0 +1,45 @@
+//go:build windows
+
+package voiceworld
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLongPathOpensPastLegacyLimit(t *testing.T) {
+	// Build a nested path well past MAX_PATH and check the SDK's open path
+	// reaches it.
+	dir := t.TempDir()
+	for len(dir) < windowsPathLimit+40 {
+		dir = filepath.Join(dir, strings.Repeat("d", 40))
+	}
+	if err := os.MkdirAll(longPath(dir), 0o755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	path := filepath.Join(dir, "a.wav")
+	if err := os.WriteFile(longPath(path), []byte("data"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	f, err := os.Open(longPath(path))
+	if err != nil {
+		t.Fatalf("Open via longPath: %v", err)
+	}
+	f.Close()
+	if !strings.HasPrefix(longPath(path), `\\?\`) {
+		t.Errorf("longPath(%q) = %q, want a \\\\?\\ prefix", path, longPath(path))
+	}
+}
+
+func TestLongPathMakesRelativePathsAbsolute(t *testing.T) {
+	rel := strings.Repeat("a", windowsPathLimit) + `\b.wav`
+	got := longPath(rel)
+	if !strings.HasPrefix(got, `\\?\`) {
+		t.Errorf("longPath(%q) = %q, want a \\\\?\\ prefix", rel, got)
+	}
+	if !filepath.IsAbs(got) {
+		t.Errorf("longPath(%q) = %q, want an absolute path", rel, got)
+	}
+}
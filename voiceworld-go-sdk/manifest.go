@@ -0,0 +1,248 @@
+package voiceworld
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"hash/crc64"
+	"io"
+	"strings"
+)
+
+// crc64Table is the ECMA polynomial table used for chunk checksums; it is
+// the polynomial OSS reports in x-oss-hash-crc64ecma.
+var crc64Table = crc64.MakeTable(crc64.ECMA)
+
+// ManifestPart describes one chunk in a SplitManifest.
+type ManifestPart struct {
+	// Index is the 1-based part number.
+	Index int `json:"index"`
+	// ObjectKey is where the chunk lives under the request prefix.
+	ObjectKey string `json:"object_key"`
+	// Size is the chunk object size in bytes.
+	Size int64 `json:"size"`
+	// CRC64 is the ECMA CRC-64 of the chunk object, as a decimal string
+	// in JSON so consumers without 64-bit integers stay exact.
+	CRC64 uint64 `json:"crc64,string"`
+	// Checksum is the chunk's digest under the additionally selected
+	// algorithm; see SplitOptions.Checksum. Nil when none was selected.
+	Checksum *ChecksumValue `json:"checksum,omitempty"`
+	// Begin and End bound the chunk on the source timeline, in seconds.
+	Begin float64 `json:"begin"`
+	End   float64 `json:"end"`
+}
+
+// SplitManifest is the integrity manifest SplitAudioFile stages as
+// "<requestID>/manifest.json" when SplitOptions.WriteManifest is set, so
+// consumers of the prefix can verify they fetched every part intact
+// without talking to anything else.
+type SplitManifest struct {
+	Version   int    `json:"version"`
+	RequestID string `json:"request_id"`
+	// SourceFile is the base name of the split source, for humans; the
+	// full local path is deliberately not recorded.
+	SourceFile string `json:"source_file"`
+	// Source records the split source's size and modification time, so
+	// audits can date the recording itself rather than its upload.
+	Source *SourceInfo `json:"source,omitempty"`
+	// TotalDuration is the source audio length in seconds.
+	TotalDuration float64        `json:"total_duration"`
+	Parts         []ManifestPart `json:"parts"`
+	// Signature is the hex HMAC-SHA256, keyed with the app secret, of
+	// the manifest JSON with this field empty, so tampering with any of
+	// the above is detectable.
+	Signature string `json:"signature"`
+}
+
+// manifestKey is where a request's manifest is staged.
+func manifestKey(requestID string) string {
+	return requestID + "/manifest.json"
+}
+
+// manifestSignature computes the Signature value for m, ignoring whatever
+// Signature it currently carries.
+func (c *Client) manifestSignature(m *SplitManifest) string {
+	unsigned := *m
+	unsigned.Signature = ""
+	data, _ := json.Marshal(&unsigned)
+	return c.hmacHex("manifest\n" + string(data))
+}
+
+// writeManifest checksums every planned chunk straight from the source file
+// and stages the signed manifest next to the uploaded parts.
+func (c *Client) writeManifest(filePath string, info *WAVInfo, spans []chunkSpan, result *SplitAudioFileResult, opts *SplitOptions, report *OperationReport) error {
+	m := &SplitManifest{
+		Version:       1,
+		RequestID:     result.RequestID,
+		SourceFile:    sourceBaseName(filePath),
+		TotalDuration: result.TotalDuration,
+	}
+	if source, err := SourceInfoFor(filePath); err == nil {
+		m.Source = source
+	}
+	bps := float64(info.BytesPerSecond())
+	for i, span := range spans {
+		h := crc64.New(crc64Table)
+		var w io.Writer = h
+		var extra hash.Hash
+		if opts.Checksum != nil {
+			extra = opts.Checksum.New()
+			w = io.MultiWriter(h, extra)
+		}
+		if err := writeChunk(w, filePath, info, span.offset, span.size); err != nil {
+			return fmt.Errorf("voiceworld: checksum chunk %d: %w", i+1, err)
+		}
+		part := ManifestPart{
+			Index:     i + 1,
+			ObjectKey: result.Parts[i].ObjectKey,
+			Size:      result.Parts[i].Size,
+			CRC64:     h.Sum64(),
+			Begin:     float64(span.offset) / bps,
+			End:       float64(span.offset+span.size) / bps,
+		}
+		if extra != nil {
+			part.Checksum = &ChecksumValue{
+				Algorithm: opts.Checksum.Name(),
+				Value:     opts.Checksum.Format(extra),
+			}
+		}
+		m.Parts = append(m.Parts, part)
+	}
+	m.Signature = c.manifestSignature(m)
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("voiceworld: encode manifest: %w", err)
+	}
+	store, err := c.storageWithReport(report)
+	if err != nil {
+		return err
+	}
+	if err := store.PutObject(manifestKey(result.RequestID), bytes.NewReader(append(data, '\n'))); err != nil {
+		return fmt.Errorf("voiceworld: upload manifest for %s: %w", result.RequestID, err)
+	}
+	return nil
+}
+
+// objectReadStorage is the optional ObjectStorage capability VerifyManifest
+// needs: reading staged objects back.
+type objectReadStorage interface {
+	GetObject(key string) (io.ReadCloser, error)
+}
+
+// headStorage is the optional capability of backends that can answer an
+// object's size and ECMA CRC-64 from metadata alone, sparing VerifyManifest
+// a full download per part. A zero CRC means the backend does not know it.
+type headStorage interface {
+	HeadObject(key string) (size int64, crc uint64, err error)
+}
+
+// VerifyManifest downloads the manifest staged under requestID, checks its
+// signature, and re-checks each listed object's size and CRC against the
+// store — from metadata when the backend supports it, else by streaming the
+// object. The decoded manifest is returned even when verification fails, so
+// callers can see what was claimed; the error lists every discrepancy.
+func (c *Client) VerifyManifest(requestID string) (*SplitManifest, error) {
+	if requestID == "" {
+		return nil, fmt.Errorf("voiceworld: manifest verification requires a request id")
+	}
+	store, err := c.storage()
+	if err != nil {
+		return nil, err
+	}
+	reader, ok := store.(objectReadStorage)
+	if !ok {
+		return nil, fmt.Errorf("voiceworld: storage backend does not support reading objects back")
+	}
+	rc, err := reader.GetObject(manifestKey(requestID))
+	if err != nil {
+		return nil, fmt.Errorf("voiceworld: fetch manifest for %s: %w", requestID, err)
+	}
+	defer rc.Close()
+	raw, err := readBounded(rc, c.config.MaxResponseSize)
+	if err != nil {
+		return nil, fmt.Errorf("voiceworld: read manifest for %s: %w", requestID, err)
+	}
+	var m SplitManifest
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, fmt.Errorf("voiceworld: decode manifest for %s: %w", requestID, err)
+	}
+	if !hmac.Equal([]byte(c.manifestSignature(&m)), []byte(m.Signature)) {
+		return &m, fmt.Errorf("voiceworld: manifest for %s has a bad signature (tampered, or verified with the wrong app secret)", requestID)
+	}
+
+	var problems []string
+	for _, part := range m.Parts {
+		size, crc, err := objectSizeCRC(store, reader, part.ObjectKey)
+		switch {
+		case err != nil:
+			problems = append(problems, fmt.Sprintf("part %d (%s): %v", part.Index, part.ObjectKey, err))
+			continue
+		case size != part.Size:
+			problems = append(problems, fmt.Sprintf("part %d (%s): size %d, manifest says %d", part.Index, part.ObjectKey, size, part.Size))
+		case crc != part.CRC64:
+			problems = append(problems, fmt.Sprintf("part %d (%s): crc64 %d, manifest says %d", part.Index, part.ObjectKey, crc, part.CRC64))
+		}
+		if part.Checksum == nil {
+			continue
+		}
+		cs, known := checksummerByName(part.Checksum.Algorithm)
+		if !known {
+			problems = append(problems, fmt.Sprintf("part %d (%s): unknown checksum algorithm %q", part.Index, part.ObjectKey, part.Checksum.Algorithm))
+			continue
+		}
+		value, err := objectChecksum(reader, part.ObjectKey, cs)
+		switch {
+		case err != nil:
+			problems = append(problems, fmt.Sprintf("part %d (%s): %v", part.Index, part.ObjectKey, err))
+		case value != part.Checksum.Value:
+			problems = append(problems, fmt.Sprintf("part %d (%s): %s %s, manifest says %s", part.Index, part.ObjectKey, cs.Name(), value, part.Checksum.Value))
+		}
+	}
+	if len(problems) > 0 {
+		return &m, fmt.Errorf("voiceworld: manifest for %s does not match the store: %s", requestID, strings.Join(problems, "; "))
+	}
+	return &m, nil
+}
+
+// objectChecksum streams one object through the given digest and returns
+// the recorded-form value.
+func objectChecksum(reader objectReadStorage, key string, cs Checksummer) (string, error) {
+	rc, err := reader.GetObject(key)
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+	h := cs.New()
+	if _, err := io.Copy(h, rc); err != nil {
+		return "", err
+	}
+	return cs.Format(h), nil
+}
+
+// objectSizeCRC answers one object's size and ECMA CRC-64, from metadata
+// when the backend can, else by streaming the object through the hash.
+func objectSizeCRC(store ObjectStorage, reader objectReadStorage, key string) (int64, uint64, error) {
+	if h, ok := store.(headStorage); ok {
+		size, crc, err := h.HeadObject(key)
+		if err != nil {
+			return 0, 0, err
+		}
+		if crc != 0 {
+			return size, crc, nil
+		}
+	}
+	rc, err := reader.GetObject(key)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer rc.Close()
+	h := crc64.New(crc64Table)
+	n, err := io.Copy(h, rc)
+	if err != nil {
+		return 0, 0, err
+	}
+	return n, h.Sum64(), nil
+}
@@ -0,0 +1,124 @@
+package voiceworld
+
+import (
+	"bytes"
+	"encoding/json"
+	"hash/crc64"
+	"strings"
+	"testing"
+)
+
+func splitWithManifest(t *testing.T) (*Client, *MemoryStorage, *SplitAudioFileResult) {
+	t.Helper()
+	client, store := newTestClient(t, &ClientConfig{Parallelism: 3})
+	result, err := client.SplitAudioFile(makeWAVFile(t, 16000, 1, 5), 2, &SplitOptions{WriteManifest: true})
+	if err != nil {
+		t.Fatalf("SplitAudioFile: %v", err)
+	}
+	return client, store, result
+}
+
+func TestSplitAudioFileWritesManifest(t *testing.T) {
+	client, store, result := splitWithManifest(t)
+
+	raw, ok := store.Object(manifestKey(result.RequestID))
+	if !ok {
+		t.Fatalf("no manifest staged; keys = %v", store.Keys())
+	}
+	var m SplitManifest
+	if err := json.Unmarshal(raw, &m); err != nil {
+		t.Fatalf("manifest is not JSON: %v", err)
+	}
+	if m.Version != 1 || m.RequestID != result.RequestID || m.SourceFile != "audio.wav" {
+		t.Errorf("manifest header = %+v", m)
+	}
+	if m.TotalDuration != result.TotalDuration {
+		t.Errorf("TotalDuration = %v, want %v", m.TotalDuration, result.TotalDuration)
+	}
+	if len(m.Parts) != 3 {
+		t.Fatalf("got %d manifest parts, want 3", len(m.Parts))
+	}
+	wantRanges := [][2]float64{{0, 2}, {2, 4}, {4, 5}}
+	for i, part := range m.Parts {
+		if part.ObjectKey != result.Parts[i].ObjectKey || part.Size != result.Parts[i].Size {
+			t.Errorf("part %d = %+v, want key/size of %+v", i+1, part, result.Parts[i])
+		}
+		data, _ := store.Object(part.ObjectKey)
+		if got := crc64.Checksum(data, crc64Table); got != part.CRC64 {
+			t.Errorf("part %d crc = %d, object hashes to %d", i+1, part.CRC64, got)
+		}
+		if part.Begin != wantRanges[i][0] || part.End != wantRanges[i][1] {
+			t.Errorf("part %d range = [%v, %v], want %v", i+1, part.Begin, part.End, wantRanges[i])
+		}
+	}
+	// The decimal-string CRC encoding is part of the format.
+	if !bytes.Contains(raw, []byte(`"crc64": "`)) {
+		t.Error("crc64 not serialized as a string")
+	}
+
+	verified, err := client.VerifyManifest(result.RequestID)
+	if err != nil {
+		t.Fatalf("VerifyManifest: %v", err)
+	}
+	if verified.RequestID != result.RequestID {
+		t.Errorf("verified manifest for %q", verified.RequestID)
+	}
+}
+
+func TestSplitAudioFileNoManifestByDefault(t *testing.T) {
+	client, store := newTestClient(t, nil)
+	result, err := client.SplitAudioFile(makeWAVFile(t, 16000, 1, 3), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store.Object(manifestKey(result.RequestID)); ok {
+		t.Error("manifest staged without WriteManifest")
+	}
+}
+
+func TestVerifyManifestDetectsCorruptedChunk(t *testing.T) {
+	client, store, result := splitWithManifest(t)
+
+	key := result.Parts[1].ObjectKey
+	data, _ := store.Object(key)
+	data[100] ^= 0xFF // same size, different bytes
+	if err := store.PutObject(key, bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := client.VerifyManifest(result.RequestID)
+	if err == nil || !strings.Contains(err.Error(), "part 2") || !strings.Contains(err.Error(), "crc64") {
+		t.Errorf("err = %v, want a crc discrepancy on part 2", err)
+	}
+}
+
+func TestVerifyManifestDetectsMissingChunk(t *testing.T) {
+	client, store, result := splitWithManifest(t)
+	if err := store.DeleteObjects([]string{result.Parts[2].ObjectKey}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := client.VerifyManifest(result.RequestID)
+	if err == nil || !strings.Contains(err.Error(), "part 3") {
+		t.Errorf("err = %v, want part 3 reported", err)
+	}
+}
+
+func TestVerifyManifestDetectsEditedManifest(t *testing.T) {
+	client, store, result := splitWithManifest(t)
+
+	raw, _ := store.Object(manifestKey(result.RequestID))
+	var m SplitManifest
+	if err := json.Unmarshal(raw, &m); err != nil {
+		t.Fatal(err)
+	}
+	m.Parts[0].Size += 7 // forge a size without re-signing
+	forged, _ := json.Marshal(&m)
+	if err := store.PutObject(manifestKey(result.RequestID), bytes.NewReader(forged)); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := client.VerifyManifest(result.RequestID)
+	if err == nil || !strings.Contains(err.Error(), "signature") {
+		t.Errorf("err = %v, want a signature failure", err)
+	}
+}
@@ -0,0 +1,47 @@
+package voiceworld
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrModelNotFound reports that a requested ModelID is unknown to the server.
+// It is wrapped with the offending ID; test with errors.Is.
+var ErrModelNotFound = errors.New("voiceworld: model not found")
+
+// ModelInfo describes one recognition model available to the account,
+// including domain-adapted models trained for the tenant.
+type ModelInfo struct {
+	// ID addresses the model in RecognitionConfig.ModelID.
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Languages lists the BCP 47 tags the model supports.
+	Languages []string `json:"languages,omitempty"`
+}
+
+// ListModels returns the recognition models the account may select via
+// RecognitionConfig.ModelID, for applications that present a model picker.
+func (c *Client) ListModels() ([]ModelInfo, error) {
+	var resp struct {
+		Models []ModelInfo `json:"models"`
+	}
+	if err := c.do(http.MethodGet, "/models", nil, "", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Models, nil
+}
+
+// modelSelectionError maps the server's unknown-model responses (404/422 on a
+// request that named a model) to ErrModelNotFound with the offending ID.
+func modelSelectionError(err error, modelID string) error {
+	if modelID == "" {
+		return err
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) &&
+		(apiErr.HTTPStatus == http.StatusNotFound || apiErr.HTTPStatus == http.StatusUnprocessableEntity) {
+		return fmt.Errorf("voiceworld: model %q: %w", modelID, ErrModelNotFound)
+	}
+	return err
+}
@@ -0,0 +1,80 @@
+package voiceworld
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestListModels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/models" {
+			t.Errorf("%s %s", r.Method, r.URL.Path)
+		}
+		w.Write([]byte(`{"code":0,"data":{"models":[` +
+			`{"id":"general","name":"General","languages":["cmn-Hans-CN","en-US"]},` +
+			`{"id":"finance-v2","name":"Finance (tenant)","languages":["cmn-Hans-CN"]}]}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	models, err := client.ListModels()
+	if err != nil {
+		t.Fatalf("ListModels: %v", err)
+	}
+	if len(models) != 2 || models[1].ID != "finance-v2" || len(models[0].Languages) != 2 {
+		t.Errorf("models = %+v", models)
+	}
+}
+
+func TestUnknownModelMapsToErrModelNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("model_id") != "no-such-model" {
+			t.Errorf("query = %q", r.URL.RawQuery)
+		}
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"code":42201,"message":"unknown model"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.ASR(makeWAVFile(t, 16000, 1, 0.1),
+		&RecognitionConfig{ModelID: "no-such-model"})
+	if !errors.Is(err, ErrModelNotFound) {
+		t.Fatalf("err = %v, want ErrModelNotFound", err)
+	}
+	if !strings.Contains(err.Error(), "no-such-model") {
+		t.Errorf("error %q does not name the model", err)
+	}
+}
+
+// TestUnrelatedErrorNotMappedToModelNotFound: a 422 without a ModelID in the
+// request keeps its APIError shape.
+func TestUnrelatedErrorNotMappedToModelNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"code":42202,"message":"bad sample rate"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.ASR(makeWAVFile(t, 16000, 1, 0.1), nil)
+	if errors.Is(err, ErrModelNotFound) {
+		t.Fatalf("err = %v, should not be ErrModelNotFound", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != 42202 {
+		t.Fatalf("err = %v, want APIError 42202", err)
+	}
+}
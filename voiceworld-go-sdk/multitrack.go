@@ -0,0 +1,217 @@
+package voiceworld
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// TrackInput names one participant's recording within a multi-track
+// transcription. Exactly one of Path and ObjectKey must be set: a Path is a
+// local WAV file that SubmitMultiTrackTranscription uploads first, an
+// ObjectKey an already-staged object.
+type TrackInput struct {
+	// Path is a local WAV file.
+	Path string
+	// ObjectKey is an object already staged in the bucket.
+	ObjectKey string
+	// SpeakerLabel names the participant; interleaved segments carry it
+	// as their SpeakerID.
+	SpeakerLabel string
+	// OffsetMs shifts the track's timeline relative to the conversation
+	// start, in milliseconds, for recorders that joined late.
+	OffsetMs int64
+}
+
+// validate rejects a track that names no source or two.
+func (t *TrackInput) validate(i int) error {
+	if (t.Path == "") == (t.ObjectKey == "") {
+		return fmt.Errorf("voiceworld: track %d must set exactly one of Path and ObjectKey", i)
+	}
+	return nil
+}
+
+// MultiTrackOptions carries the optional knobs for
+// SubmitMultiTrackTranscription. Nil and the zero value keep the defaults.
+type MultiTrackOptions struct {
+	// RequestID groups the uploaded tracks; empty means a generated one.
+	RequestID string
+	// ResampleLocally converts local tracks to the recognition layout
+	// when their sample rates disagree, instead of rejecting the batch.
+	// ObjectKey tracks cannot be inspected and are submitted as-is.
+	ResampleLocally bool
+}
+
+// SubmitMultiTrackTranscription transcribes several per-participant
+// recordings as one conversation: local tracks are uploaded under one
+// request ID, every track's URL, speaker label and timeline offset go to the
+// multi-track endpoint in a single submission, and the returned task's
+// result interleaves segments across tracks on the shared timeline (see
+// InterleaveTrackSegments for the merge rules). Local tracks must agree on
+// their sample rate unless MultiTrackOptions.ResampleLocally asks for
+// conversion. Passing more than one options value is an error.
+func (c *Client) SubmitMultiTrackTranscription(ctx context.Context, tracks []TrackInput, options ...*MultiTrackOptions) (string, error) {
+	if len(options) > 1 {
+		return "", fmt.Errorf("voiceworld: at most one MultiTrackOptions may be given, got %d", len(options))
+	}
+	opts := &MultiTrackOptions{}
+	if len(options) == 1 && options[0] != nil {
+		opts = options[0]
+	}
+	if len(tracks) == 0 {
+		return "", fmt.Errorf("voiceworld: at least one track is required")
+	}
+	for i := range tracks {
+		if err := tracks[i].validate(i); err != nil {
+			return "", err
+		}
+		if c.config.Strict && opts.ResampleLocally && tracks[i].ObjectKey != "" {
+			return "", fmt.Errorf("%w: track %d is an ObjectKey and cannot be checked against the batch's sample rate", ErrTrackNotInspected, i)
+		}
+	}
+	uploads, cleanup, err := localTrackPaths(ctx, tracks, opts)
+	defer cleanup()
+	if err != nil {
+		return "", err
+	}
+
+	requestID := opts.RequestID
+	if requestID == "" {
+		if c.config.Strict {
+			return "", fmt.Errorf("%w: set MultiTrackOptions.RequestID", ErrRequestIDRequired)
+		}
+		requestID = NewRequestID()
+	}
+	status, err := c.life.beginOp("recognize")
+	if err != nil {
+		return "", err
+	}
+	defer c.life.endOp(status)
+	status.setRequestID(requestID)
+
+	type wireTrack struct {
+		URL          string `json:"url"`
+		SpeakerLabel string `json:"speaker_label,omitempty"`
+		OffsetMs     int64  `json:"offset_ms,omitempty"`
+	}
+	wire := make([]wireTrack, len(tracks))
+	for i, track := range tracks {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		var url string
+		if path := uploads[i]; path != "" {
+			key := fmt.Sprintf("%s/track_%02d.wav", requestID, i+1)
+			url, err = c.UploadFile(path, key)
+		} else {
+			var store ObjectStorage
+			store, err = c.storage()
+			if err == nil {
+				url, err = store.SignURL(track.ObjectKey, signedURLExpiry)
+			}
+		}
+		if err != nil {
+			return "", err
+		}
+		wire[i] = wireTrack{URL: url, SpeakerLabel: track.SpeakerLabel, OffsetMs: track.OffsetMs}
+	}
+
+	req := struct {
+		RequestID string      `json:"request_id,omitempty"`
+		Tracks    []wireTrack `json:"tracks"`
+	}{requestID, wire}
+	var resp struct {
+		TaskID string `json:"task_id"`
+	}
+	if err := c.postJSON("/asr_multitrack", &req, &resp); err != nil {
+		return "", err
+	}
+	return resp.TaskID, nil
+}
+
+// localTrackPaths resolves which local file each track should upload: the
+// original, or — when sample rates disagree and resampling was asked for — a
+// converted temp copy. Entries for ObjectKey tracks stay empty. The caller
+// runs cleanup to drop any temp copies.
+func localTrackPaths(ctx context.Context, tracks []TrackInput, opts *MultiTrackOptions) ([]string, func(), error) {
+	paths := make([]string, len(tracks))
+	rates := make([]int, len(tracks))
+	first := -1
+	mismatched := false
+	for i, track := range tracks {
+		if track.Path == "" {
+			continue
+		}
+		info, err := ParseWAVFile(track.Path)
+		if err != nil {
+			return paths, func() {}, err
+		}
+		paths[i] = track.Path
+		rates[i] = info.SampleRate
+		if first < 0 {
+			first = i
+		} else if info.SampleRate != rates[first] {
+			mismatched = true
+			if !opts.ResampleLocally {
+				return paths, func() {}, invalidAudio(
+					"track %d sample rate %d does not match track %d's %d; set MultiTrackOptions.ResampleLocally to convert",
+					i, info.SampleRate, first, rates[first])
+			}
+		}
+	}
+	if !mismatched {
+		return paths, func() {}, nil
+	}
+	// Converting every off-target track lands them all on the recognition
+	// rate, the only rate the batch can agree on.
+	var temps []string
+	cleanup := func() {
+		for _, path := range temps {
+			removeTempFile(path)
+		}
+	}
+	for i := range tracks {
+		if paths[i] == "" || rates[i] == targetSampleRate {
+			continue
+		}
+		converted, err := ProcessAudioContext(ctx, paths[i])
+		if err != nil {
+			return paths, cleanup, err
+		}
+		temps = append(temps, converted)
+		paths[i] = converted
+	}
+	return paths, cleanup, nil
+}
+
+// InterleaveTrackSegments merges per-track transcripts into one conversation
+// timeline: each segment is shifted by its track's offset, labeled with the
+// track's speaker, and the union is ordered by begin time (stable for ties).
+// The multi-track endpoint returns results already merged this way; the
+// helper serves callers that transcribed tracks as separate tasks. tracks
+// and results correspond by index; a nil result contributes nothing.
+func InterleaveTrackSegments(tracks []TrackInput, results []*RecognitionResult) ([]Segment, error) {
+	if len(tracks) != len(results) {
+		return nil, fmt.Errorf("voiceworld: %d tracks but %d results", len(tracks), len(results))
+	}
+	var merged []Segment
+	for i, result := range results {
+		if result == nil {
+			continue
+		}
+		offset := time.Duration(tracks[i].OffsetMs) * time.Millisecond
+		for _, seg := range result.Segments {
+			seg.BeginTime = NewTimestamp(seg.BeginTime.Duration + offset)
+			seg.EndTime = NewTimestamp(seg.EndTime.Duration + offset)
+			if tracks[i].SpeakerLabel != "" {
+				seg.SpeakerID = tracks[i].SpeakerLabel
+			}
+			merged = append(merged, seg)
+		}
+	}
+	sort.SliceStable(merged, func(a, b int) bool {
+		return merged[a].BeginTime.Duration < merged[b].BeginTime.Duration
+	})
+	return merged, nil
+}
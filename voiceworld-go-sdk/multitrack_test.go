@@ -0,0 +1,184 @@
+package voiceworld
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// multiTrackServer answers /asr_multitrack with a fixed task id and captures
+// every request body it sees.
+func multiTrackServer(t *testing.T) (*httptest.Server, *[]map[string]interface{}) {
+	t.Helper()
+	var bodies []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/asr_multitrack" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		bodies = append(bodies, body)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"code": 0,
+			"data": map[string]string{"task_id": "task-7"},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server, &bodies
+}
+
+func TestSubmitMultiTrackRequestBody(t *testing.T) {
+	server, bodies := multiTrackServer(t)
+	client, store := newTestClient(t, &ClientConfig{BaseURL: server.URL})
+	if err := store.PutObject("staged/bob.wav", strings.NewReader("data")); err != nil {
+		t.Fatal(err)
+	}
+
+	taskID, err := client.SubmitMultiTrackTranscription(context.Background(), []TrackInput{
+		{Path: makeWAVFile(t, 16000, 1, 0.1), SpeakerLabel: "alice"},
+		{ObjectKey: "staged/bob.wav", SpeakerLabel: "bob", OffsetMs: 1500},
+	}, &MultiTrackOptions{RequestID: "req-1"})
+	if err != nil {
+		t.Fatalf("SubmitMultiTrackTranscription: %v", err)
+	}
+	if taskID != "task-7" {
+		t.Errorf("taskID = %q, want task-7", taskID)
+	}
+
+	if len(*bodies) != 1 {
+		t.Fatalf("got %d submissions, want 1", len(*bodies))
+	}
+	body := (*bodies)[0]
+	if body["request_id"] != "req-1" {
+		t.Errorf("request_id = %v, want req-1", body["request_id"])
+	}
+	tracks, _ := body["tracks"].([]interface{})
+	if len(tracks) != 2 {
+		t.Fatalf("tracks = %v, want 2 entries", body["tracks"])
+	}
+	first, _ := tracks[0].(map[string]interface{})
+	if first["url"] != "memory://bucket/req-1/track_01.wav" {
+		t.Errorf("track 1 url = %v", first["url"])
+	}
+	if first["speaker_label"] != "alice" {
+		t.Errorf("track 1 speaker_label = %v", first["speaker_label"])
+	}
+	if _, present := first["offset_ms"]; present {
+		t.Errorf("zero offset serialized: %v", first)
+	}
+	second, _ := tracks[1].(map[string]interface{})
+	if second["url"] != "memory://bucket/staged/bob.wav" {
+		t.Errorf("track 2 url = %v", second["url"])
+	}
+	if second["speaker_label"] != "bob" || second["offset_ms"] != float64(1500) {
+		t.Errorf("track 2 = %v", second)
+	}
+
+	// The local track really was staged under the request prefix.
+	keys, err := store.ListObjects("req-1/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || keys[0] != "req-1/track_01.wav" {
+		t.Errorf("staged keys = %v", keys)
+	}
+}
+
+func TestSubmitMultiTrackValidation(t *testing.T) {
+	server, _ := multiTrackServer(t)
+	client, _ := newTestClient(t, &ClientConfig{BaseURL: server.URL})
+	ctx := context.Background()
+
+	if _, err := client.SubmitMultiTrackTranscription(ctx, nil); err == nil {
+		t.Error("empty track list accepted")
+	}
+	if _, err := client.SubmitMultiTrackTranscription(ctx, []TrackInput{
+		{Path: "a.wav", ObjectKey: "b.wav"},
+	}); err == nil {
+		t.Error("track with both Path and ObjectKey accepted")
+	}
+	if _, err := client.SubmitMultiTrackTranscription(ctx, []TrackInput{{}}); err == nil {
+		t.Error("track with neither Path nor ObjectKey accepted")
+	}
+}
+
+func TestSubmitMultiTrackSampleRateMismatch(t *testing.T) {
+	server, bodies := multiTrackServer(t)
+	client, _ := newTestClient(t, &ClientConfig{BaseURL: server.URL})
+	tracks := []TrackInput{
+		{Path: makeWAVFile(t, 16000, 1, 0.1), SpeakerLabel: "alice"},
+		{Path: makeWAVFile(t, 8000, 1, 0.1), SpeakerLabel: "bob"},
+	}
+
+	_, err := client.SubmitMultiTrackTranscription(context.Background(), tracks)
+	var verr *AudioValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("err = %v (%T), want *AudioValidationError", err, err)
+	}
+	if len(*bodies) != 0 {
+		t.Fatal("mismatched batch reached the server")
+	}
+
+	before := registeredTempFiles()
+	if _, err := client.SubmitMultiTrackTranscription(context.Background(), tracks,
+		&MultiTrackOptions{ResampleLocally: true}); err != nil {
+		t.Fatalf("SubmitMultiTrackTranscription with ResampleLocally: %v", err)
+	}
+	if len(*bodies) != 1 {
+		t.Fatalf("got %d submissions, want 1", len(*bodies))
+	}
+	if got := registeredTempFiles(); len(got) != len(before) {
+		t.Errorf("converted temp copies leaked: %v", got)
+	}
+}
+
+func TestInterleaveTrackSegments(t *testing.T) {
+	tracks := []TrackInput{
+		{Path: "alice.wav", SpeakerLabel: "alice"},
+		{Path: "bob.wav", SpeakerLabel: "bob", OffsetMs: 2000},
+	}
+	results := []*RecognitionResult{
+		{Segments: []Segment{
+			{Text: "hello", BeginTime: NewTimestamp(0), EndTime: NewTimestamp(time.Second)},
+			{Text: "how are you", BeginTime: NewTimestamp(4 * time.Second), EndTime: NewTimestamp(5 * time.Second)},
+		}},
+		{Segments: []Segment{
+			{Text: "hi there", BeginTime: NewTimestamp(time.Second), EndTime: NewTimestamp(2 * time.Second), SpeakerID: "spk-0"},
+		}},
+	}
+
+	merged, err := InterleaveTrackSegments(tracks, results)
+	if err != nil {
+		t.Fatalf("InterleaveTrackSegments: %v", err)
+	}
+	want := []struct {
+		text    string
+		speaker string
+		begin   time.Duration
+	}{
+		{"hello", "alice", 0},
+		{"hi there", "bob", 3 * time.Second},
+		{"how are you", "alice", 4 * time.Second},
+	}
+	if len(merged) != len(want) {
+		t.Fatalf("got %d segments, want %d", len(merged), len(want))
+	}
+	for i, w := range want {
+		got := merged[i]
+		if got.Text != w.text || got.SpeakerID != w.speaker || got.BeginTime.Duration != w.begin {
+			t.Errorf("segment %d = {%q %q %v}, want {%q %q %v}",
+				i, got.Text, got.SpeakerID, got.BeginTime.Duration, w.text, w.speaker, w.begin)
+		}
+	}
+
+	if _, err := InterleaveTrackSegments(tracks, results[:1]); err == nil {
+		t.Error("mismatched track/result lengths accepted")
+	}
+}
@@ -0,0 +1,236 @@
+package voiceworld
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Object metadata keys describing a staged object's PCM layout, stamped by
+// ProcessAndUploadFile (surfacing as x-oss-meta-audio-* on OSS). They let
+// RecognizeObjectRange compute byte ranges without first fetching the
+// object's WAV header.
+const (
+	audioMetaSampleRate = "audio-sample-rate"
+	audioMetaChannels   = "audio-channels"
+	audioMetaBits       = "audio-bits"
+	audioMetaDataOffset = "audio-data-offset"
+)
+
+// rangeReadStorage is the optional ObjectStorage capability of backends that
+// can serve a byte range of a stored object without downloading the rest.
+// length is the number of bytes wanted from offset; ranges running past the
+// object's end return the bytes that exist.
+type rangeReadStorage interface {
+	GetObjectRange(key string, offset, length int64) (io.ReadCloser, error)
+}
+
+// readObjectRange reads [offset, offset+length) of key, with a true range
+// request when the backend supports it and by streaming and discarding the
+// prefix otherwise.
+func readObjectRange(store ObjectStorage, key string, offset, length int64) (io.ReadCloser, error) {
+	if rr, ok := store.(rangeReadStorage); ok {
+		return rr.GetObjectRange(key, offset, length)
+	}
+	or, ok := store.(objectReadStorage)
+	if !ok {
+		return nil, fmt.Errorf("voiceworld: storage %T cannot read objects back", store)
+	}
+	rc, err := or.GetObject(key)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.CopyN(io.Discard, rc, offset); err != nil && err != io.EOF {
+		rc.Close()
+		return nil, err
+	}
+	return &limitedReadCloser{Reader: io.LimitReader(rc, length), c: rc}, nil
+}
+
+// limitedReadCloser is a LimitReader that still closes the underlying body.
+type limitedReadCloser struct {
+	io.Reader
+	c io.Closer
+}
+
+func (l *limitedReadCloser) Close() error { return l.c.Close() }
+
+// ObjectAudioParamsError reports that a staged object's PCM layout could not
+// be determined: it carries no audio-* metadata and its WAV header could not
+// be read. Ranged recognition needs one of the two.
+type ObjectAudioParamsError struct {
+	// Key is the object whose audio parameters were needed.
+	Key string
+	// Missing names the parameter sources that were tried and unavailable.
+	Missing []string
+}
+
+func (e *ObjectAudioParamsError) Error() string {
+	return fmt.Sprintf("voiceworld: cannot determine audio parameters for %s: no %s",
+		e.Key, strings.Join(e.Missing, " and no "))
+}
+
+// audioParamsMeta renders a PCM layout as the audio-* metadata map.
+func audioParamsMeta(info *WAVInfo) map[string]string {
+	return map[string]string{
+		audioMetaSampleRate: strconv.Itoa(info.SampleRate),
+		audioMetaChannels:   strconv.Itoa(info.NumChannels),
+		audioMetaBits:       strconv.Itoa(info.BitsPerSample),
+		audioMetaDataOffset: strconv.FormatInt(info.DataOffset, 10),
+	}
+}
+
+// audioParamsFromMeta is the inverse of audioParamsMeta. The offset key is
+// optional — absent means the object is raw PCM from byte zero.
+func audioParamsFromMeta(meta map[string]string) (*WAVInfo, bool) {
+	rate, rerr := strconv.Atoi(meta[audioMetaSampleRate])
+	channels, cerr := strconv.Atoi(meta[audioMetaChannels])
+	bits, berr := strconv.Atoi(meta[audioMetaBits])
+	if rerr != nil || cerr != nil || berr != nil || rate <= 0 || channels <= 0 || bits <= 0 {
+		return nil, false
+	}
+	info := &WAVInfo{SampleRate: rate, NumChannels: channels, BitsPerSample: bits}
+	if off, err := strconv.ParseInt(meta[audioMetaDataOffset], 10, 64); err == nil && off > 0 {
+		info.DataOffset = off
+	}
+	return info, true
+}
+
+// objectAudioParams resolves a staged object's PCM layout: audio-* metadata
+// first, then a ranged read of its 44-byte WAV header. Objects offering
+// neither yield an *ObjectAudioParamsError naming what was missing.
+func objectAudioParams(store ObjectStorage, key string) (*WAVInfo, error) {
+	var missing []string
+	if mr, ok := store.(metaReadStorage); ok {
+		if meta, err := mr.GetObjectMeta(key); err == nil {
+			if info, ok := audioParamsFromMeta(meta); ok {
+				return info, nil
+			}
+		}
+	}
+	missing = append(missing, "audio-* metadata")
+	rc, err := readObjectRange(store, key, 0, 44)
+	if err == nil {
+		hdr, rerr := io.ReadAll(io.LimitReader(rc, 44))
+		rc.Close()
+		if rerr == nil {
+			if info, perr := ParseWAV(bytes.NewReader(hdr)); perr == nil {
+				return info, nil
+			}
+		}
+	}
+	missing = append(missing, "readable wav header")
+	return nil, &ObjectAudioParamsError{Key: key, Missing: missing}
+}
+
+// RecognizeObjectRange transcribes a time slice of an already-staged object
+// without downloading the rest of it. The object's PCM layout comes from the
+// audio-* metadata stamped at upload time or, failing that, from a ranged
+// read of its WAV header; the slice's frame-aligned bytes are then fetched
+// with a range request, wrapped in a synthesized WAV header and submitted as
+// sync recognition. startMs and endMs are measured from the start of the
+// audio and clamped to its actual extent; a range that selects no audio
+// after clamping is an error. Word and segment offsets in the result are
+// relative to startMs, not the whole recording.
+func (c *Client) RecognizeObjectRange(ctx context.Context, objectKey string, startMs, endMs int64, config *RecognitionConfig) (*RecognitionResult, error) {
+	if objectKey == "" {
+		return nil, fmt.Errorf("voiceworld: ranged recognition requires an object key")
+	}
+	if config == nil {
+		config = &RecognitionConfig{}
+	}
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	status, err := c.life.beginOp("recognize")
+	if err != nil {
+		return nil, err
+	}
+	defer c.life.endOp(status)
+	status.setRequestID(config.RequestID)
+
+	store, err := c.storage()
+	if err != nil {
+		return nil, err
+	}
+	hs, ok := store.(headStorage)
+	if !ok {
+		return nil, fmt.Errorf("voiceworld: storage %T cannot report object sizes for ranged recognition", store)
+	}
+	size, _, err := hs.HeadObject(objectKey)
+	if err != nil {
+		return nil, fmt.Errorf("voiceworld: head %s: %w", objectKey, err)
+	}
+	info, err := objectAudioParams(store, objectKey)
+	if err != nil {
+		return nil, err
+	}
+	dataLen := size - info.DataOffset
+	if dataLen < 0 {
+		dataLen = 0
+	}
+
+	frameBytes := int64(info.blockAlign())
+	byteRate := int64(info.BytesPerSecond())
+	if frameBytes <= 0 || byteRate <= 0 {
+		return nil, invalidAudio("object %s has a degenerate pcm layout", objectKey)
+	}
+	// Millisecond bounds land on frame boundaries rounded down, then clamp
+	// to the data that is actually there.
+	begin := startMs * byteRate / 1000
+	end := endMs * byteRate / 1000
+	begin -= begin % frameBytes
+	end -= end % frameBytes
+	begin = clampInt64(begin, 0, dataLen)
+	end = clampInt64(end, 0, dataLen)
+	if begin >= end {
+		return nil, invalidAudio("range [%dms, %dms) selects no audio in %s", startMs, endMs, objectKey)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	rc, err := readObjectRange(store, objectKey, info.DataOffset+begin, end-begin)
+	if err != nil {
+		return nil, fmt.Errorf("voiceworld: read range of %s: %w", objectKey, err)
+	}
+	var body bytes.Buffer
+	if err := writeWAVHeader(&body, info, end-begin); err != nil {
+		rc.Close()
+		return nil, err
+	}
+	if _, err := io.Copy(&body, io.LimitReader(rc, end-begin)); err != nil {
+		rc.Close()
+		return nil, fmt.Errorf("voiceworld: read range of %s: %w", objectKey, err)
+	}
+	rc.Close()
+
+	chunkConfig := *config
+	chunkConfig.Format = "wav"
+	chunkConfig.SampleRate = info.SampleRate
+	audioDur := time.Duration(float64(end-begin) / float64(byteRate) * float64(time.Second))
+	var result RecognitionResult
+	reqBegin := c.now()
+	if err := c.do(http.MethodPost, "/asr", chunkConfig.params(), "application/octet-stream",
+		bytes.NewReader(body.Bytes()), &result); err != nil {
+		return nil, modelSelectionError(err, chunkConfig.ModelID)
+	}
+	c.finishRecognition(&chunkConfig, &result, reqBegin, audioDur)
+	return &result, nil
+}
+
+// clampInt64 bounds v to [lo, hi].
+func clampInt64(v, lo, hi int64) int64 {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
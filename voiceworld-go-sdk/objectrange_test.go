@@ -0,0 +1,170 @@
+package voiceworld
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// rangeServer captures the /asr body of each call so tests can check exactly
+// which bytes left the machine.
+func rangeServer(t *testing.T) (*httptest.Server, func() [][]byte) {
+	t.Helper()
+	var mu sync.Mutex
+	var bodies [][]byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, body)
+		mu.Unlock()
+		if got := r.URL.Query().Get("format"); got != "wav" {
+			t.Errorf("format = %q, want wav", got)
+		}
+		w.Write([]byte(`{"code":0,"data":{"task_id":"t-range","result":"slice"}}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, func() [][]byte {
+		mu.Lock()
+		defer mu.Unlock()
+		return bodies
+	}
+}
+
+func TestRecognizeObjectRangeSubmitsAlignedSlice(t *testing.T) {
+	server, bodies := rangeServer(t)
+	client, store := newTestClient(t, &ClientConfig{BaseURL: server.URL})
+
+	object := makeWAVData(t, 16000, 1, 1) // 32000 data bytes after a 44-byte header
+	if err := store.PutObject("req-1/audio.wav", bytes.NewReader(object)); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := client.RecognizeObjectRange(context.Background(), "req-1/audio.wav", 250, 750, nil)
+	if err != nil {
+		t.Fatalf("RecognizeObjectRange: %v", err)
+	}
+	if result.Result != "slice" {
+		t.Errorf("result = %q, want slice", result.Result)
+	}
+	sent := bodies()
+	if len(sent) != 1 {
+		t.Fatalf("server saw %d calls, want 1", len(sent))
+	}
+	body := sent[0]
+	// 500 ms of mono 16 kHz behind a fresh 44-byte header.
+	if len(body) != 44+16000 {
+		t.Fatalf("submitted %d bytes, want %d", len(body), 44+16000)
+	}
+	info, err := ParseWAV(bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("submitted body is not a wav: %v", err)
+	}
+	if info.SampleRate != 16000 || info.NumChannels != 1 || info.DataSize != 16000 {
+		t.Errorf("synthesized header = %+v", info)
+	}
+	// The payload is the object's own samples for [250ms, 750ms).
+	if want := object[44+8000 : 44+24000]; !bytes.Equal(body[44:], want) {
+		t.Error("submitted payload does not match the requested slice")
+	}
+}
+
+func TestRecognizeObjectRangeClampsToExtent(t *testing.T) {
+	server, bodies := rangeServer(t)
+	client, store := newTestClient(t, &ClientConfig{BaseURL: server.URL})
+	if err := store.PutObject("req-2/audio.wav", bytes.NewReader(makeWAVData(t, 16000, 1, 1))); err != nil {
+		t.Fatal(err)
+	}
+
+	// Both bounds out of range: the whole second comes back.
+	if _, err := client.RecognizeObjectRange(context.Background(), "req-2/audio.wav", -500, 5000, nil); err != nil {
+		t.Fatalf("clamped range: %v", err)
+	}
+	sent := bodies()
+	if len(sent) != 1 || len(sent[0]) != 44+32000 {
+		t.Fatalf("clamped submission = %d bytes, want %d", len(sent[0]), 44+32000)
+	}
+
+	// A range entirely past the end selects nothing.
+	_, err := client.RecognizeObjectRange(context.Background(), "req-2/audio.wav", 2000, 3000, nil)
+	var verr *AudioValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("empty range err = %v, want *AudioValidationError", err)
+	}
+}
+
+func TestRecognizeObjectRangeUsesStampedMetadata(t *testing.T) {
+	server, bodies := rangeServer(t)
+	client, store := newTestClient(t, &ClientConfig{BaseURL: server.URL})
+
+	// A headerless raw PCM object: only metadata can describe it.
+	pcm := make([]byte, 32000)
+	for i := range pcm {
+		pcm[i] = byte(i)
+	}
+	if err := store.PutObject("req-3/audio.pcm", bytes.NewReader(pcm)); err != nil {
+		t.Fatal(err)
+	}
+	err := store.SetObjectMeta("req-3/audio.pcm", map[string]string{
+		audioMetaSampleRate: "16000",
+		audioMetaChannels:   "1",
+		audioMetaBits:       "16",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.RecognizeObjectRange(context.Background(), "req-3/audio.pcm", 0, 500, nil); err != nil {
+		t.Fatalf("RecognizeObjectRange: %v", err)
+	}
+	sent := bodies()
+	if len(sent) != 1 {
+		t.Fatalf("server saw %d calls, want 1", len(sent))
+	}
+	if got := sent[0]; len(got) != 44+16000 || !bytes.Equal(got[44:], pcm[:16000]) {
+		t.Errorf("submitted %d bytes, want the first 16000 pcm bytes behind a header", len(got))
+	}
+}
+
+func TestRecognizeObjectRangeMissingParams(t *testing.T) {
+	server, _ := rangeServer(t)
+	client, store := newTestClient(t, &ClientConfig{BaseURL: server.URL})
+	if err := store.PutObject("req-4/blob", strings.NewReader("not audio at all")); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := client.RecognizeObjectRange(context.Background(), "req-4/blob", 0, 1000, nil)
+	var perr *ObjectAudioParamsError
+	if !errors.As(err, &perr) {
+		t.Fatalf("err = %v, want *ObjectAudioParamsError", err)
+	}
+	if perr.Key != "req-4/blob" {
+		t.Errorf("error key = %q", perr.Key)
+	}
+	for _, want := range []string{"audio-* metadata", "readable wav header"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not name missing source %q", err, want)
+		}
+	}
+}
+
+func TestProcessAndUploadStampsAudioParams(t *testing.T) {
+	client, store := newTestClient(t, nil)
+	result, err := client.ProcessAndUploadFile(makeWAVFile(t, 16000, 1, 0.5), "req-5")
+	if err != nil {
+		t.Fatalf("ProcessAndUploadFile: %v", err)
+	}
+	meta, err := store.GetObjectMeta(result.ProcessedKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta[audioMetaSampleRate] != "16000" || meta[audioMetaChannels] != "1" ||
+		meta[audioMetaBits] != "16" || meta[audioMetaDataOffset] != "44" {
+		t.Errorf("stamped audio metadata = %v", meta)
+	}
+}
@@ -0,0 +1,149 @@
+package voiceworld
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// OperationStatus is a point-in-time snapshot of one in-flight operation,
+// as returned by Operations. Byte and part totals are zero until the
+// operation has sized its work; RequestID is empty until one is known.
+type OperationStatus struct {
+	// ID identifies the operation for the duration of its run; IDs are
+	// unique per Client and increase in start order.
+	ID int64 `json:"id"`
+	// Kind names the flow: "upload", "split" or "recognize".
+	Kind string `json:"kind"`
+	// StartedAt is when the operation was admitted.
+	StartedAt time.Time `json:"started_at"`
+	// BytesDone and BytesTotal count transferred payload bytes.
+	BytesDone  int64 `json:"bytes_done"`
+	BytesTotal int64 `json:"bytes_total"`
+	// PartsDone and PartsTotal count finished transfer units.
+	PartsDone  int `json:"parts_done"`
+	PartsTotal int `json:"parts_total"`
+	// RequestID groups the operation's staged objects, when it has one.
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// liveOp is the registry entry behind an OperationStatus. The counters are
+// atomics so concurrent transfer workers update them without taking the
+// lifecycle lock; a nil liveOp discards all updates.
+type liveOp struct {
+	id        int64
+	kind      string
+	startedAt time.Time
+
+	bytesDone  atomic.Int64
+	bytesTotal atomic.Int64
+	partsDone  atomic.Int32
+	partsTotal atomic.Int32
+	requestID  atomic.Value // string
+
+	// Snapshot persistence, set by beginOp when a ProgressSink is
+	// configured; see progresssink.go. seq numbers persisted snapshots
+	// and lastPersist throttles them.
+	sink         ProgressSink
+	sinkInterval time.Duration
+	opID         string
+	persistMu    sync.Mutex
+	seq          atomic.Uint64
+	lastPersist  atomic.Int64 // unix nanoseconds
+}
+
+// setTotals sizes the operation's work once it is known.
+func (o *liveOp) setTotals(bytes int64, parts int) {
+	if o == nil {
+		return
+	}
+	o.bytesTotal.Store(bytes)
+	o.partsTotal.Store(int32(parts))
+	o.persistSnapshot(false)
+}
+
+// addProgress records one finished transfer unit of the given size.
+func (o *liveOp) addProgress(bytes int64, parts int) {
+	if o == nil {
+		return
+	}
+	o.bytesDone.Add(bytes)
+	o.partsDone.Add(int32(parts))
+	o.persistSnapshot(false)
+}
+
+// setRequestID attaches the request ID once the operation has one.
+func (o *liveOp) setRequestID(requestID string) {
+	if o == nil || requestID == "" {
+		return
+	}
+	o.requestID.Store(requestID)
+}
+
+// status snapshots the counters into the exported form.
+func (o *liveOp) status() OperationStatus {
+	s := OperationStatus{
+		ID:         o.id,
+		Kind:       o.kind,
+		StartedAt:  o.startedAt,
+		BytesDone:  o.bytesDone.Load(),
+		BytesTotal: o.bytesTotal.Load(),
+		PartsDone:  int(o.partsDone.Load()),
+		PartsTotal: int(o.partsTotal.Load()),
+	}
+	if id, ok := o.requestID.Load().(string); ok {
+		s.RequestID = id
+	}
+	return s
+}
+
+// Operations returns a snapshot of the operations currently in flight,
+// ordered by start. Finished operations leave the registry immediately, so
+// an idle client returns an empty slice.
+func (c *Client) Operations() []OperationStatus {
+	c.life.mu.Lock()
+	ops := make([]*liveOp, 0, len(c.life.ops))
+	for _, op := range c.life.ops {
+		ops = append(ops, op)
+	}
+	c.life.mu.Unlock()
+
+	sort.Slice(ops, func(i, j int) bool { return ops[i].id < ops[j].id })
+	statuses := make([]OperationStatus, len(ops))
+	for i, op := range ops {
+		statuses[i] = op.status()
+	}
+	return statuses
+}
+
+// SubscribeOperations emits an Operations snapshot on the returned channel
+// every interval, for dashboards that poll on their own schedule. A slow
+// receiver misses ticks rather than blocking the client. The returned stop
+// function ends the feed and closes the channel; interval <= 0 defaults to
+// one second.
+func (c *Client) SubscribeOperations(interval time.Duration) (<-chan []OperationStatus, func()) {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ch := make(chan []OperationStatus, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(ch)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				select {
+				case ch <- c.Operations():
+				default: // receiver still busy with the last snapshot
+				}
+			}
+		}
+	}()
+	var once sync.Once
+	return ch, func() { once.Do(func() { close(done) }) }
+}
@@ -0,0 +1,126 @@
+package voiceworld
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestOperationsSnapshotsInFlightSplit(t *testing.T) {
+	store := newBlockingStorage()
+	client, err := NewClient("ak", "sk", &ClientConfig{Storage: store})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wav := makeWAVFile(t, 16000, 1, 3)
+	fi, err := os.Stat(wav)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if _, err := client.SplitAudioFile(wav, 1); err != nil {
+			t.Errorf("SplitAudioFile: %v", err)
+		}
+	}()
+	<-store.entered
+
+	ops := client.Operations()
+	if len(ops) != 1 {
+		t.Fatalf("Operations() = %d entries, want 1", len(ops))
+	}
+	op := ops[0]
+	if op.Kind != "split" {
+		t.Errorf("Kind = %q, want \"split\"", op.Kind)
+	}
+	if op.RequestID == "" {
+		t.Error("RequestID not set on an in-flight split")
+	}
+	if op.PartsTotal != 3 {
+		t.Errorf("PartsTotal = %d, want 3", op.PartsTotal)
+	}
+	// Each chunk gets its own 44-byte header on top of the source payload.
+	if want := fi.Size() - 44 + 3*44; op.BytesTotal != want {
+		t.Errorf("BytesTotal = %d, want %d", op.BytesTotal, want)
+	}
+	if op.PartsDone == op.PartsTotal || op.BytesDone == op.BytesTotal {
+		t.Errorf("parked operation reported as complete: %+v", op)
+	}
+	if op.StartedAt.IsZero() {
+		t.Error("StartedAt not stamped")
+	}
+
+	close(store.release)
+	<-done
+	if ops := client.Operations(); len(ops) != 0 {
+		t.Errorf("Operations() after completion = %+v, want empty", ops)
+	}
+}
+
+func TestOperationsRegistryDoesNotLeak(t *testing.T) {
+	server := newEnvelopeServer(t, `{"code":0,"data":{"text":"ok"}}`)
+	client, _ := newTestClient(t, &ClientConfig{BaseURL: server.URL})
+	wav := makeWAVFile(t, 16000, 1, 1)
+
+	for i := 0; i < 10; i++ {
+		if _, err := client.UploadFile(wav, "req/audio.wav"); err != nil {
+			t.Fatalf("UploadFile %d: %v", i, err)
+		}
+		if _, err := client.SplitAudioFile(wav, 1); err != nil {
+			t.Fatalf("SplitAudioFile %d: %v", i, err)
+		}
+		if _, err := client.RecognizeURL("https://example.com/a.wav", nil); err != nil {
+			t.Fatalf("RecognizeURL %d: %v", i, err)
+		}
+		// Failures must deregister too.
+		if _, err := client.UploadFile("does-not-exist.wav", "req/x.wav"); err == nil {
+			t.Fatal("upload of a missing file succeeded")
+		}
+	}
+
+	client.life.mu.Lock()
+	leaked := len(client.life.ops)
+	client.life.mu.Unlock()
+	if leaked != 0 {
+		t.Errorf("registry holds %d entries after all operations finished", leaked)
+	}
+}
+
+func TestSubscribeOperations(t *testing.T) {
+	store := newBlockingStorage()
+	client, err := NewClient("ak", "sk", &ClientConfig{Storage: store})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wav := makeWAVFile(t, 16000, 1, 1)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		client.UploadFile(wav, "req/audio.wav")
+	}()
+	<-store.entered
+
+	ch, stop := client.SubscribeOperations(2 * time.Millisecond)
+	defer stop()
+	deadline := time.After(5 * time.Second)
+	for {
+		select {
+		case snapshot := <-ch:
+			if len(snapshot) == 1 && snapshot[0].Kind == "upload" {
+				close(store.release)
+				<-done
+				stop()
+				// The feed channel closes once stopped; drain whatever
+				// snapshot was already buffered.
+				for range ch {
+				}
+				return
+			}
+		case <-deadline:
+			t.Fatal("no snapshot showing the in-flight upload")
+		}
+	}
+}
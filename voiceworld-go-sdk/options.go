@@ -0,0 +1,131 @@
+package voiceworld
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// A ClientOption customizes one knob of a Client under construction; see
+// NewClientWithOptions. Each option validates its input, so a bad value
+// fails construction instead of being silently patched to a default.
+type ClientOption func(*ClientConfig) error
+
+// NewClientWithOptions builds a Client from an application key pair and
+// functional options. It is the composable alternative to NewClient's
+// *ClientConfig parameter: options apply in order to a fresh config (a
+// later option overrides an earlier one for the same knob), anything no
+// option touches keeps its documented default, and the config is copied
+// into the Client at construction, so the Client stays immutable and safe
+// to share across goroutines no matter what the caller does afterwards.
+func NewClientWithOptions(appKey, secretKey string, options ...ClientOption) (*Client, error) {
+	config := &ClientConfig{}
+	for _, opt := range options {
+		if opt == nil {
+			return nil, fmt.Errorf("voiceworld: nil ClientOption")
+		}
+		if err := opt(config); err != nil {
+			return nil, err
+		}
+	}
+	return NewClient(appKey, secretKey, config)
+}
+
+// WithBaseURL points the client at an API endpoint other than
+// DefaultBaseURL, e.g. a private deployment. The URL must be non-empty;
+// its shape is validated at construction like ClientConfig.BaseURL.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(config *ClientConfig) error {
+		if baseURL == "" {
+			return fmt.Errorf("voiceworld: WithBaseURL requires a non-empty URL")
+		}
+		config.BaseURL = baseURL
+		return nil
+	}
+}
+
+// WithTimeout bounds each HTTP request issued by the client; see
+// ClientConfig.Timeout. The duration must be positive — pass nothing to
+// keep DefaultTimeout.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(config *ClientConfig) error {
+		if timeout <= 0 {
+			return fmt.Errorf("voiceworld: WithTimeout requires a positive duration, got %s", timeout)
+		}
+		config.Timeout = timeout
+		return nil
+	}
+}
+
+// WithHTTPClient installs the *http.Client used for API calls, e.g. one
+// with a recording transport; see ClientConfig.HTTPClient.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(config *ClientConfig) error {
+		if httpClient == nil {
+			return fmt.Errorf("voiceworld: WithHTTPClient requires a non-nil client")
+		}
+		config.HTTPClient = httpClient
+		return nil
+	}
+}
+
+// WithStorageConfig selects the object storage backend and its addressing;
+// see ClientConfig.StorageConfig.
+func WithStorageConfig(storageConfig *StorageConfig) ClientOption {
+	return func(config *ClientConfig) error {
+		if storageConfig == nil {
+			return fmt.Errorf("voiceworld: WithStorageConfig requires a non-nil config")
+		}
+		config.StorageConfig = storageConfig
+		return nil
+	}
+}
+
+// WithStorage injects the object store used by the upload helpers directly,
+// bypassing credential-driven construction; see ClientConfig.Storage.
+func WithStorage(storage ObjectStorage) ClientOption {
+	return func(config *ClientConfig) error {
+		if storage == nil {
+			return fmt.Errorf("voiceworld: WithStorage requires a non-nil storage")
+		}
+		config.Storage = storage
+		return nil
+	}
+}
+
+// WithTokenProvider overrides where object storage credentials come from;
+// see ClientConfig.TokenProvider.
+func WithTokenProvider(provider TokenProvider) ClientOption {
+	return func(config *ClientConfig) error {
+		if provider == nil {
+			return fmt.Errorf("voiceworld: WithTokenProvider requires a non-nil provider")
+		}
+		config.TokenProvider = provider
+		return nil
+	}
+}
+
+// WithRetry sets the retry policy for API requests and storage part
+// uploads; see ClientConfig.Retry.
+func WithRetry(policy RetryPolicy) ClientOption {
+	return func(config *ClientConfig) error {
+		if policy == nil {
+			return fmt.Errorf("voiceworld: WithRetry requires a non-nil policy")
+		}
+		config.Retry = policy
+		return nil
+	}
+}
+
+// WithConfig starts from a whole ClientConfig, replacing whatever earlier
+// options set; options given after it still win field by field. It is the
+// migration path for callers holding an existing config — give it first.
+func WithConfig(config *ClientConfig) ClientOption {
+	return func(dst *ClientConfig) error {
+		if config == nil {
+			return fmt.Errorf("voiceworld: WithConfig requires a non-nil config")
+		}
+		*dst = *config
+		return nil
+	}
+}
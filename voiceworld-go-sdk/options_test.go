@@ -0,0 +1,110 @@
+package voiceworld
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewClientWithOptionsComposes(t *testing.T) {
+	hc := &http.Client{}
+	store := NewMemoryStorage()
+	client, err := NewClientWithOptions("key", "secret",
+		WithBaseURL("https://asr.internal.example.com/v1"),
+		WithTimeout(5*time.Second),
+		WithHTTPClient(hc),
+		WithStorage(store),
+	)
+	if err != nil {
+		t.Fatalf("NewClientWithOptions: %v", err)
+	}
+	if client.config.BaseURL != "https://asr.internal.example.com/v1" {
+		t.Errorf("BaseURL = %q", client.config.BaseURL)
+	}
+	if client.config.Timeout != 5*time.Second {
+		t.Errorf("Timeout = %v", client.config.Timeout)
+	}
+	if client.httpClient != hc {
+		t.Error("HTTPClient was not installed")
+	}
+	if client.config.Storage != store {
+		t.Error("Storage was not installed")
+	}
+}
+
+func TestNewClientWithOptionsDefaults(t *testing.T) {
+	client, err := NewClientWithOptions("key", "secret")
+	if err != nil {
+		t.Fatalf("NewClientWithOptions: %v", err)
+	}
+	if client.config.BaseURL != DefaultBaseURL {
+		t.Errorf("BaseURL = %q, want the default", client.config.BaseURL)
+	}
+	if client.config.Timeout != DefaultTimeout {
+		t.Errorf("Timeout = %v, want the default", client.config.Timeout)
+	}
+}
+
+func TestNewClientWithOptionsLaterOptionWins(t *testing.T) {
+	client, err := NewClientWithOptions("key", "secret",
+		WithTimeout(time.Second),
+		WithTimeout(9*time.Second),
+	)
+	if err != nil {
+		t.Fatalf("NewClientWithOptions: %v", err)
+	}
+	if client.config.Timeout != 9*time.Second {
+		t.Errorf("Timeout = %v, want the later option's value", client.config.Timeout)
+	}
+}
+
+func TestNewClientWithOptionsValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  ClientOption
+		want string
+	}{
+		{"empty base url", WithBaseURL(""), "non-empty URL"},
+		{"malformed base url", WithBaseURL("ftp://example.com"), "http or https"},
+		{"zero timeout", WithTimeout(0), "positive duration"},
+		{"nil http client", WithHTTPClient(nil), "non-nil client"},
+		{"nil storage config", WithStorageConfig(nil), "non-nil config"},
+		{"nil storage", WithStorage(nil), "non-nil storage"},
+		{"nil token provider", WithTokenProvider(nil), "non-nil provider"},
+		{"nil retry policy", WithRetry(nil), "non-nil policy"},
+		{"nil config", WithConfig(nil), "non-nil config"},
+		{"nil option", nil, "nil ClientOption"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewClientWithOptions("key", "secret", tt.opt)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want mention of %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithConfigThenOptions(t *testing.T) {
+	base := &ClientConfig{Timeout: time.Second, Parallelism: 4}
+	client, err := NewClientWithOptions("key", "secret",
+		WithConfig(base),
+		WithTimeout(7*time.Second),
+	)
+	if err != nil {
+		t.Fatalf("NewClientWithOptions: %v", err)
+	}
+	if client.config.Timeout != 7*time.Second {
+		t.Errorf("Timeout = %v, want the option to override the config", client.config.Timeout)
+	}
+	if client.config.Parallelism != 4 {
+		t.Errorf("Parallelism = %d, want the config's value kept", client.config.Parallelism)
+	}
+	// The config struct was copied, not retained: mutating it afterwards
+	// must not reach into the constructed client.
+	base.Parallelism = 99
+	if client.config.Parallelism != 4 {
+		t.Error("client shares the caller's config struct")
+	}
+}
@@ -0,0 +1,361 @@
+package voiceworld
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultChunkSize is the multipart part size used when ClientConfig
+	// does not override it (5 MiB, the OSS minimum for non-final parts).
+	defaultChunkSize = 5 * 1024 * 1024
+
+	// signedURLExpiry is how long signed download URLs stay valid. It must
+	// outlive the longest recognition task that consumes them.
+	signedURLExpiry = time.Hour
+)
+
+// UploadFile uploads a local file to the staging bucket under objectName and
+// returns a signed download URL for it. Passing more than one options value
+// is an error.
+func (c *Client) UploadFile(filePath, objectName string, options ...*UploadOptions) (string, error) {
+	opts, err := uploadOptions(options)
+	if err != nil {
+		return "", err
+	}
+	status, err := c.life.beginOp("upload")
+	if err != nil {
+		return "", err
+	}
+	defer c.life.endOp(status)
+	status.setRequestID(requestIDFromKey(objectName))
+	if fi, err := os.Stat(longPath(filePath)); err == nil {
+		status.setTotals(fi.Size(), 1)
+	}
+	op := &uploadOp{
+		budget:      newRetryBudget(c.config.MaxTotalRetries),
+		report:      c.newOperationReport("upload_file"),
+		callback:    opts.Callback,
+		status:      status,
+		ifNoneMatch: opts.IfNoneMatch,
+		checksum:    opts.Checksum,
+		source:      opts.Source,
+	}
+	if opts.RejectIf != nil {
+		if _, qerr := c.analyzeForPolicy(filePath, opts.RejectIf); qerr != nil {
+			c.finishReport(op.report, qerr)
+			return "", qerr
+		}
+	}
+	if opts.ProbePermissions {
+		if perr := c.probeUploadPermissions(objectName); perr != nil {
+			c.finishReport(op.report, perr)
+			return "", perr
+		}
+	}
+	url, _, err := c.uploadFile(filePath, objectName, 1, op)
+	c.finishReport(op.report, err)
+	return url, err
+}
+
+// uploadFile is UploadFile with the operation state threaded through; part
+// identifies this file within the operation. A nil op.tracker means the
+// upload is an operation of its own and gets a fresh one. Alongside the
+// signed URL it returns when that URL stops working.
+func (c *Client) uploadFile(filePath, objectName string, part int, op *uploadOp) (string, time.Time, error) {
+	f, err := os.Open(longPath(filePath))
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("voiceworld: open upload source: %w", err)
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("voiceworld: stat upload source: %w", err)
+	}
+	tracker := op.tracker
+	if tracker == nil {
+		tracker = newProgressTracker(c.config.Progress, fi.Size())
+	}
+
+	store, err := c.storageForScope(op.report, uploadScope(objectName))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	var body io.ReadSeeker = f
+	var hasher *hashingReader
+	if op.checksum != nil {
+		hasher = newHashingReader(f, op.checksum.New())
+		body = hasher
+	}
+	transfer := func() error {
+		return putObject(store, objectName, body, fi.Size(), tracker, part)
+	}
+	if op.ifNoneMatch {
+		cps, ok := store.(conditionalPutStorage)
+		if !ok {
+			return "", time.Time{}, errNoForbidOverwriteSupport
+		}
+		transfer = func() error {
+			if perr := cps.PutObjectIfAbsent(objectName, body); perr != nil {
+				return perr
+			}
+			tracker.setPart(part, fi.Size())
+			return nil
+		}
+	}
+	if op.callback != nil {
+		cbStore, ok := store.(callbackStorage)
+		if !ok {
+			return "", time.Time{}, errNoCallbackSupport
+		}
+		header := op.callback.encode(objectName)
+		transfer = func() error {
+			resp, perr := cbStore.PutObjectWithCallback(objectName, body, header)
+			if perr != nil {
+				return perr
+			}
+			tracker.setPart(part, fi.Size())
+			op.report.setCallbackResponse(resp)
+			return nil
+		}
+	}
+	preport := PartReport{Index: part, Bytes: fi.Size()}
+	c.transfers.acquire()
+	begin := c.now()
+	err = retryStorageOp(c.retryPolicy(), op.budget, &preport, func() error {
+		_, serr := body.Seek(0, io.SeekStart)
+		return serr
+	}, transfer)
+	preport.Duration = c.now().Sub(begin)
+	c.transfers.release()
+	c.stats.countStorageOp("put_object")
+	c.stats.addRetries(int64(preport.Retries))
+	if err != nil {
+		preport.Error = err.Error()
+		op.report.addPart(preport)
+		c.stats.addFailure()
+		return "", time.Time{}, fmt.Errorf("voiceworld: upload %s: %w", objectName, err)
+	}
+	op.report.addPart(preport)
+	op.status.addProgress(fi.Size(), 1)
+	c.stats.addUpload(fi.Size())
+	meta := map[string]string{}
+	if hasher != nil {
+		value := hasher.value(op.checksum)
+		op.report.setChecksum(value)
+		meta[checksumMetaKey(op.checksum)] = value.Value
+	}
+	if op.source != nil {
+		for k, v := range op.source.metadata() {
+			meta[k] = v
+		}
+	}
+	if len(meta) > 0 {
+		if ms, ok := store.(metadataStorage); ok {
+			if merr := ms.SetObjectMeta(objectName, meta); merr != nil {
+				return "", time.Time{}, fmt.Errorf("voiceworld: record metadata for %s: %w", objectName, merr)
+			}
+		}
+	}
+	signed, expiresAt, err := c.signStorageURL(store, objectName, signedURLExpiry)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
+}
+
+// MultipartUploadFile uploads a large local file in ChunkSize parts, with up
+// to Parallelism parts in flight, and returns a signed download URL. The
+// multipart upload is aborted on failure so no orphaned parts accumulate.
+// Passing more than one options value is an error.
+func (c *Client) MultipartUploadFile(filePath, objectName string, options ...*UploadOptions) (string, error) {
+	opts, err := uploadOptions(options)
+	if err != nil {
+		return "", err
+	}
+	if opts.IfNoneMatch {
+		return "", fmt.Errorf("voiceworld: IfNoneMatch is not supported for multipart uploads")
+	}
+	status, err := c.life.beginOp("upload")
+	if err != nil {
+		return "", err
+	}
+	defer c.life.endOp(status)
+	status.setRequestID(requestIDFromKey(objectName))
+	report := c.newOperationReport("multipart_upload_file")
+	url, err := c.multipartUploadFile(filePath, objectName, opts, report, status)
+	c.finishReport(report, err)
+	return url, err
+}
+
+func (c *Client) multipartUploadFile(filePath, objectName string, opts *UploadOptions, report *OperationReport, status *liveOp) (string, error) {
+	fi, err := os.Stat(longPath(filePath))
+	if err != nil {
+		return "", fmt.Errorf("voiceworld: stat upload source: %w", err)
+	}
+	store, err := c.storageForScope(report, uploadScope(objectName))
+	if err != nil {
+		return "", err
+	}
+	cbStore, hasCallbacks := store.(callbackStorage)
+	if opts.Callback != nil && !hasCallbacks {
+		return "", errNoCallbackSupport
+	}
+	if opts.ProbePermissions {
+		if perr := c.probeUploadPermissions(objectName); perr != nil {
+			return "", perr
+		}
+	}
+
+	uploadID, err := c.initiateMultipart(store, objectName)
+	if err != nil {
+		return "", fmt.Errorf("voiceworld: initiate multipart upload: %w", err)
+	}
+	// Tracked so Close can abort it if shutdown interrupts the upload.
+	untrack := c.life.trackUpload(store, objectName, uploadID)
+	defer untrack()
+
+	chunkSize := c.config.ChunkSize
+	numParts := int((fi.Size() + chunkSize - 1) / chunkSize)
+	if numParts == 0 {
+		numParts = 1 // empty file still needs one (empty) part
+	}
+	status.setTotals(fi.Size(), numParts)
+	parts := make([]UploadedPart, numParts)
+	op := &uploadOp{
+		tracker: newProgressTracker(c.config.Progress, fi.Size()),
+		budget:  newRetryBudget(c.config.MaxTotalRetries),
+		report:  report,
+		status:  status,
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	sem := make(chan struct{}, c.config.Parallelism)
+	for i := 0; i < numParts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			part, err := c.uploadFilePart(store, filePath, objectName, uploadID, i, chunkSize, fi.Size(), op)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			parts[i] = part
+		}(i)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		// Best effort: the abort failing should not mask the upload error.
+		c.abortMultipart(store, objectName, uploadID)
+		return "", firstErr
+	}
+	if opts.Callback != nil {
+		body, cerr := cbStore.CompleteMultipartUploadWithCallback(objectName, uploadID, parts, opts.Callback.encode(objectName))
+		if cerr != nil {
+			c.abortMultipart(store, objectName, uploadID)
+			return "", fmt.Errorf("voiceworld: complete multipart upload of %d bytes: %w", fi.Size(), cerr)
+		}
+		report.setCallbackResponse(body)
+	} else if err := c.completeMultipart(store, objectName, uploadID, parts); err != nil {
+		c.abortMultipart(store, objectName, uploadID)
+		return "", fmt.Errorf("voiceworld: complete multipart upload of %d bytes: %w", fi.Size(), err)
+	}
+	if opts.Source != nil {
+		if ms, ok := store.(metadataStorage); ok {
+			if merr := ms.SetObjectMeta(objectName, opts.Source.metadata()); merr != nil {
+				return "", fmt.Errorf("voiceworld: record metadata for %s: %w", objectName, merr)
+			}
+		}
+	}
+	signed, _, err := c.signStorageURL(store, objectName, signedURLExpiry)
+	if err != nil {
+		return "", err
+	}
+	return signed, nil
+}
+
+// uploadFilePart sends the i-th chunkSize slice of the file as part i+1,
+// retrying under the client's policy with the slice rewound between
+// attempts.
+func (c *Client) uploadFilePart(store ObjectStorage, filePath, objectName, uploadID string, i int, chunkSize, fileSize int64, op *uploadOp) (UploadedPart, error) {
+	offset := int64(i) * chunkSize
+	size := chunkSize
+	if offset+size > fileSize {
+		size = fileSize - offset
+	}
+	f, err := os.Open(longPath(filePath))
+	if err != nil {
+		return UploadedPart{}, fmt.Errorf("voiceworld: open upload source: %w", err)
+	}
+	defer f.Close()
+	section := io.NewSectionReader(f, offset, size)
+	preport := PartReport{Index: i + 1, Bytes: size}
+	c.transfers.acquire()
+	begin := c.now()
+	var part UploadedPart
+	err = retryStorageOp(c.retryPolicy(), op.budget, &preport, func() error {
+		_, serr := section.Seek(0, io.SeekStart)
+		return serr
+	}, func() error {
+		var perr error
+		part, perr = uploadPart(store, objectName, uploadID, i+1, section, size, op.tracker)
+		return perr
+	})
+	preport.Duration = c.now().Sub(begin)
+	c.transfers.release()
+	c.stats.countStorageOp("upload_part")
+	c.stats.addRetries(int64(preport.Retries))
+	if err != nil {
+		preport.Error = err.Error()
+		op.report.addPart(preport)
+		c.stats.addFailure()
+		return UploadedPart{}, fmt.Errorf("voiceworld: upload part %d: %w", i+1, err)
+	}
+	op.report.addPart(preport)
+	op.status.addProgress(size, 1)
+	c.stats.addUpload(size)
+	return part, nil
+}
+
+// UploadPreprocessedAudio uploads the output of ProcessAudio under the
+// preprocessed/ prefix for the given request and returns its signed URL.
+func (c *Client) UploadPreprocessedAudio(localPath, requestID string) (string, error) {
+	return c.UploadFile(localPath, "preprocessed/"+requestID+".wav")
+}
+
+// Cleanup deletes every staged object whose key starts with requestID. It is
+// meant to run after recognition results have been collected.
+func (c *Client) Cleanup(requestID string) error {
+	if requestID == "" {
+		return fmt.Errorf("voiceworld: cleanup requires a request id")
+	}
+	store, err := c.storage()
+	if err != nil {
+		return err
+	}
+	keys, err := store.ListObjects(requestID + "/")
+	if err != nil {
+		return fmt.Errorf("voiceworld: list objects for %s: %w", requestID, err)
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	if err := store.DeleteObjects(keys); err != nil {
+		return fmt.Errorf("voiceworld: delete objects for %s: %w", requestID, err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package voiceworld
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+// Sentinel errors for the object storage failures callers most often need to
+// branch on. They are reached through errors.Is on any error coming out of
+// an upload or storage helper backed by OSS.
+var (
+	// ErrNoSuchBucket reports a staging bucket that does not exist —
+	// usually a bucket name typo in the deployment configuration.
+	ErrNoSuchBucket = errors.New("voiceworld: storage bucket does not exist")
+
+	// ErrOSSAccessDenied reports an operation the granted STS policy does
+	// not allow.
+	ErrOSSAccessDenied = errors.New("voiceworld: storage access denied")
+
+	// ErrOSSTokenExpired reports storage credentials that are no longer
+	// valid; fetching a fresh token fixes it.
+	ErrOSSTokenExpired = errors.New("voiceworld: storage credentials expired")
+
+	// ErrRequestTimeSkewed reports a local clock too far from the storage
+	// server's; see SyncServerTime.
+	ErrRequestTimeSkewed = errors.New("voiceworld: request time skewed beyond the allowed window")
+
+	// ErrObjectAlreadyExists reports an upload refused because the key is
+	// already taken; see UploadOptions.IfNoneMatch.
+	ErrObjectAlreadyExists = errors.New("voiceworld: object already exists")
+)
+
+// ossCodeSentinels maps OSS service error codes to the sentinels above.
+var ossCodeSentinels = map[string]error{
+	"NoSuchBucket":         ErrNoSuchBucket,
+	"AccessDenied":         ErrOSSAccessDenied,
+	"InvalidAccessKeyId":   ErrOSSTokenExpired,
+	"SecurityTokenExpired": ErrOSSTokenExpired,
+	"InvalidSecurityToken": ErrOSSTokenExpired,
+	"RequestTimeTooSkewed": ErrRequestTimeSkewed,
+	"FileAlreadyExists":    ErrObjectAlreadyExists,
+}
+
+// OSSError is a decoded storage service failure. The Code selects which
+// sentinel errors.Is matches; RequestID and HostID identify the failed call
+// in OSS server logs, which support asks for on every storage ticket.
+type OSSError struct {
+	Code      string
+	Message   string
+	RequestID string
+	HostID    string
+	Status    int
+}
+
+func (e *OSSError) Error() string {
+	msg := fmt.Sprintf("voiceworld: oss %s: %s", e.Code, e.Message)
+	if e.RequestID != "" {
+		msg += fmt.Sprintf(" (request id %s, host %s)", e.RequestID, e.HostID)
+	}
+	return msg
+}
+
+// Unwrap surfaces the sentinel for well-known codes, so errors.Is works on
+// wrapped storage failures. Unmapped codes unwrap to nothing.
+func (e *OSSError) Unwrap() error {
+	return ossCodeSentinels[e.Code]
+}
+
+// translateOSSError rewrites an aliyun SDK service failure as an *OSSError;
+// every other error (including nil) passes through untouched. The OSS
+// storage backend applies it to each call's result.
+func translateOSSError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var svc oss.ServiceError
+	if !errors.As(err, &svc) {
+		return err
+	}
+	return &OSSError{
+		Code:      svc.Code,
+		Message:   svc.Message,
+		RequestID: svc.RequestID,
+		HostID:    svc.HostID,
+		Status:    svc.StatusCode,
+	}
+}
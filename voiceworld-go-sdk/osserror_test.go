@@ -0,0 +1,76 @@
+package voiceworld
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+func TestTranslateOSSErrorMapsCodesToSentinels(t *testing.T) {
+	tests := []struct {
+		code string
+		want error
+	}{
+		{"NoSuchBucket", ErrNoSuchBucket},
+		{"AccessDenied", ErrOSSAccessDenied},
+		{"InvalidAccessKeyId", ErrOSSTokenExpired},
+		{"SecurityTokenExpired", ErrOSSTokenExpired},
+		{"InvalidSecurityToken", ErrOSSTokenExpired},
+		{"RequestTimeTooSkewed", ErrRequestTimeSkewed},
+	}
+	for _, tt := range tests {
+		err := translateOSSError(oss.ServiceError{Code: tt.code, StatusCode: 403})
+		if !errors.Is(err, tt.want) {
+			t.Errorf("translated %s = %v, want errors.Is %v", tt.code, err, tt.want)
+		}
+	}
+}
+
+func TestTranslateOSSErrorKeepsSupportFields(t *testing.T) {
+	svc := oss.ServiceError{
+		Code:       "AccessDenied",
+		Message:    "You have no right to access this object.",
+		RequestID:  "5C3D9175B6FC201293AD4521",
+		HostID:     "stage.oss-cn-hangzhou.aliyuncs.com",
+		StatusCode: 403,
+	}
+	// The SDK hands service errors up wrapped; translation must see through.
+	err := translateOSSError(fmt.Errorf("put object: %w", svc))
+
+	var ossErr *OSSError
+	if !errors.As(err, &ossErr) {
+		t.Fatalf("err = %T, want *OSSError", err)
+	}
+	if ossErr.RequestID != svc.RequestID || ossErr.HostID != svc.HostID || ossErr.Status != 403 {
+		t.Errorf("support fields lost: %+v", ossErr)
+	}
+	if !strings.Contains(err.Error(), svc.RequestID) {
+		t.Errorf("message %q does not carry the request id", err)
+	}
+}
+
+func TestTranslateOSSErrorPassesOthersThrough(t *testing.T) {
+	if err := translateOSSError(nil); err != nil {
+		t.Errorf("translated nil = %v", err)
+	}
+	plain := errors.New("dial tcp: i/o timeout")
+	if err := translateOSSError(plain); err != plain {
+		t.Errorf("translated %v = %v, want it untouched", plain, err)
+	}
+}
+
+func TestTranslateOSSErrorUnknownCode(t *testing.T) {
+	err := translateOSSError(oss.ServiceError{Code: "EntityTooLarge", StatusCode: 400})
+	var ossErr *OSSError
+	if !errors.As(err, &ossErr) {
+		t.Fatalf("err = %T, want *OSSError", err)
+	}
+	for _, sentinel := range []error{ErrNoSuchBucket, ErrOSSAccessDenied, ErrOSSTokenExpired, ErrRequestTimeSkewed} {
+		if errors.Is(err, sentinel) {
+			t.Errorf("unmapped code matched %v", sentinel)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package voiceworld
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestUploadIfNoneMatchNewObject(t *testing.T) {
+	client, store := newTestClient(t, nil)
+	url, err := client.UploadFile(makeWAVFile(t, 16000, 1, 0.1), "req/a.wav", &UploadOptions{IfNoneMatch: true})
+	if err != nil {
+		t.Fatalf("UploadFile: %v", err)
+	}
+	if url == "" {
+		t.Error("no signed URL returned")
+	}
+	if _, ok := store.Object("req/a.wav"); !ok {
+		t.Error("object not stored")
+	}
+}
+
+func TestUploadIfNoneMatchExistingObject(t *testing.T) {
+	client, store := newTestClient(t, nil)
+	if err := store.PutObject("req/a.wav", strings.NewReader("precious")); err != nil {
+		t.Fatal(err)
+	}
+	_, err := client.UploadFile(makeWAVFile(t, 16000, 1, 0.1), "req/a.wav", &UploadOptions{IfNoneMatch: true})
+	if !errors.Is(err, ErrObjectAlreadyExists) {
+		t.Fatalf("err = %v, want ErrObjectAlreadyExists", err)
+	}
+	data, _ := store.Object("req/a.wav")
+	if string(data) != "precious" {
+		t.Errorf("existing object was overwritten: %q", data)
+	}
+}
+
+func TestUploadIfNoneMatchUnsupportedBackend(t *testing.T) {
+	// Embedding the interface hides the optional conditional-put method.
+	store := struct{ ObjectStorage }{NewMemoryStorage()}
+	client, err := NewClient("ak", "sk", &ClientConfig{Storage: store})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.UploadFile(makeWAVFile(t, 16000, 1, 0.1), "a.wav", &UploadOptions{IfNoneMatch: true})
+	if !errors.Is(err, errNoForbidOverwriteSupport) {
+		t.Fatalf("err = %v, want errNoForbidOverwriteSupport", err)
+	}
+}
+
+func TestUploadIfNoneMatchRejectsCallback(t *testing.T) {
+	client, _ := newTestClient(t, nil)
+	opts := &UploadOptions{
+		IfNoneMatch: true,
+		Callback:    &OSSCallback{URL: "https://example.com/hook", Body: "object=${object}"},
+	}
+	_, err := client.UploadFile(makeWAVFile(t, 16000, 1, 0.1), "a.wav", opts)
+	if err == nil || !strings.Contains(err.Error(), "cannot be combined") {
+		t.Fatalf("err = %v, want combination rejection", err)
+	}
+}
+
+func TestMultipartUploadRejectsIfNoneMatch(t *testing.T) {
+	client, _ := newTestClient(t, nil)
+	_, err := client.MultipartUploadFile(makeWAVFile(t, 16000, 1, 0.1), "a.wav", &UploadOptions{IfNoneMatch: true})
+	if err == nil || !strings.Contains(err.Error(), "not supported for multipart") {
+		t.Fatalf("err = %v, want multipart rejection", err)
+	}
+}
+
+func TestOSSFileAlreadyExistsMapsToSentinel(t *testing.T) {
+	err := (&OSSError{Code: "FileAlreadyExists", Status: 409}).Unwrap()
+	if !errors.Is(err, ErrObjectAlreadyExists) {
+		t.Fatalf("FileAlreadyExists unwraps to %v, want ErrObjectAlreadyExists", err)
+	}
+}
+
+func TestNewRequestIDUnique(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		id := NewRequestID()
+		if seen[id] {
+			t.Fatalf("duplicate request id %q after %d draws", id, i)
+		}
+		seen[id] = true
+	}
+}
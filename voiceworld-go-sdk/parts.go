@@ -0,0 +1,103 @@
+package voiceworld
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// PartStatus reports how far the recognition of one chunk of a long-audio
+// task has come.
+type PartStatus string
+
+const (
+	// PartStatusPending means the part has not finished recognition yet.
+	PartStatusPending PartStatus = "pending"
+	// PartStatusDone means the part's transcript is available.
+	PartStatusDone PartStatus = "done"
+	// PartStatusFailed means recognition of the part failed for good;
+	// TranscriptionPart.Error says why.
+	PartStatusFailed PartStatus = "failed"
+)
+
+// TranscriptionPart is the state of one chunk of a long-audio task, as
+// returned by GetTranscriptionPart.
+type TranscriptionPart struct {
+	// Index is the 1-based chunk position, as in SplitPart.
+	Index int `json:"part_index"`
+	// Status says whether Result is there yet.
+	Status PartStatus `json:"status"`
+	// Error describes a failed part.
+	Error string `json:"error,omitempty"`
+	// Result holds the part's transcript once Status is done.
+	Result *RecognitionResult `json:"result,omitempty"`
+}
+
+// GetTranscriptionPart fetches the state of one chunk of a long-audio task
+// without pulling the whole result, so a display can render parts as they
+// complete. partIndex is 1-based, matching SplitPart.Index. A pending part
+// is not an error: the returned Status says pending and Result is nil. Feed
+// the parts collected so far to MergeAvailableResults to assemble what is
+// already known.
+func (c *Client) GetTranscriptionPart(ctx context.Context, taskID string, partIndex int) (*TranscriptionPart, error) {
+	if partIndex < 1 {
+		return nil, fmt.Errorf("voiceworld: part index %d is not a 1-based chunk position", partIndex)
+	}
+	req, err := c.NewSignedRequest(ctx, http.MethodGet, "/asr_result_part", nil, "")
+	if err != nil {
+		return nil, err
+	}
+	req.URL.RawQuery = url.Values{
+		"task_id":    {taskID},
+		"part_index": {strconv.Itoa(partIndex)},
+	}.Encode()
+	var part TranscriptionPart
+	if err := c.DoJSON(req, &part); err != nil {
+		return nil, err
+	}
+	return &part, nil
+}
+
+// GapRange is a run of chunk positions that contributed nothing to a
+// partially assembled transcript. FirstPart and LastPart are 1-based and
+// inclusive.
+type GapRange struct {
+	FirstPart int `json:"first_part"`
+	LastPart  int `json:"last_part"`
+}
+
+// PartialMergedResult is MergeResults' output for a subset of a task's
+// parts, with the missing runs called out so a progressive display can show
+// where text is still to come.
+type PartialMergedResult struct {
+	*MergedResult
+	// GapRanges lists the missing runs in chunk order; empty means every
+	// part contributed and the transcript is complete.
+	GapRanges []GapRange `json:"gap_ranges,omitempty"`
+}
+
+// MergeAvailableResults assembles a transcript from whatever subset of a
+// task's parts is done so far. results corresponds by index to chunk
+// positions 1..len(results); nil entries are parts that are still pending,
+// failed, or simply not fetched, and are reported as GapRanges instead of
+// vanishing silently. Available parts are stitched exactly as MergeResults
+// stitches them, so re-merging once the gaps fill in yields the transcript
+// the complete merge would.
+func MergeAvailableResults(results []*RecognitionResult) *PartialMergedResult {
+	partial := &PartialMergedResult{MergedResult: MergeResults(results)}
+	for i := 0; i < len(results); {
+		if results[i] != nil {
+			i++
+			continue
+		}
+		gap := GapRange{FirstPart: i + 1}
+		for i < len(results) && results[i] == nil {
+			i++
+		}
+		gap.LastPart = i
+		partial.GapRanges = append(partial.GapRanges, gap)
+	}
+	return partial
+}
@@ -0,0 +1,165 @@
+package voiceworld
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// partServer serves /asr_result_part for one task whose parts complete in
+// whatever order the test scripts via complete and fail.
+type partServer struct {
+	*httptest.Server
+	mu    sync.Mutex
+	parts map[int]*TranscriptionPart
+}
+
+func newPartServer(t *testing.T, totalParts int) *partServer {
+	t.Helper()
+	ps := &partServer{parts: map[int]*TranscriptionPart{}}
+	for i := 1; i <= totalParts; i++ {
+		ps.parts[i] = &TranscriptionPart{Index: i, Status: PartStatusPending}
+	}
+	ps.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/asr_result_part" || r.URL.Query().Get("task_id") != "task-parts" {
+			t.Errorf("unexpected request %s %s", r.URL.Path, r.URL.RawQuery)
+		}
+		index, _ := strconv.Atoi(r.URL.Query().Get("part_index"))
+		ps.mu.Lock()
+		part, ok := ps.parts[index]
+		if !ok {
+			ps.mu.Unlock()
+			w.Write([]byte(`{"code":40000,"message":"no such part"}`))
+			return
+		}
+		data, _ := json.Marshal(part)
+		ps.mu.Unlock()
+		w.Write([]byte(`{"code":0,"data":` + string(data) + `}`))
+	}))
+	t.Cleanup(ps.Close)
+	return ps
+}
+
+// complete marks one part done with a one-segment transcript.
+func (ps *partServer) complete(index int) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	ps.parts[index].Status = PartStatusDone
+	ps.parts[index].Result = &RecognitionResult{
+		TaskID:   "task-parts",
+		Result:   fmt.Sprintf("part %d text", index),
+		Duration: ms(10000),
+		Segments: []Segment{{
+			Text:      fmt.Sprintf("part %d text", index),
+			BeginTime: ms(0),
+			EndTime:   ms(10000),
+		}},
+	}
+}
+
+func (ps *partServer) fail(index int, reason string) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	ps.parts[index].Status = PartStatusFailed
+	ps.parts[index].Error = reason
+}
+
+// fetchAll collects every part's result, nil where not done yet.
+func fetchAll(t *testing.T, client *Client, totalParts int) []*RecognitionResult {
+	t.Helper()
+	results := make([]*RecognitionResult, totalParts)
+	for i := 1; i <= totalParts; i++ {
+		part, err := client.GetTranscriptionPart(context.Background(), "task-parts", i)
+		if err != nil {
+			t.Fatalf("part %d: %v", i, err)
+		}
+		if part.Status == PartStatusDone {
+			results[i-1] = part.Result
+		}
+	}
+	return results
+}
+
+func TestGetTranscriptionPartProgressiveAssembly(t *testing.T) {
+	server := newPartServer(t, 4)
+	client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Parts complete out of order: 3 and 1 first.
+	server.complete(3)
+	server.complete(1)
+
+	part2, err := client.GetTranscriptionPart(context.Background(), "task-parts", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if part2.Status != PartStatusPending || part2.Result != nil {
+		t.Errorf("unfinished part = %+v, want pending without result", part2)
+	}
+
+	partial := MergeAvailableResults(fetchAll(t, client, 4))
+	wantGaps := []GapRange{{FirstPart: 2, LastPart: 2}, {FirstPart: 4, LastPart: 4}}
+	if len(partial.GapRanges) != 2 || partial.GapRanges[0] != wantGaps[0] || partial.GapRanges[1] != wantGaps[1] {
+		t.Errorf("gaps = %+v, want %+v", partial.GapRanges, wantGaps)
+	}
+	if partial.Result != "part 1 text part 3 text" {
+		t.Errorf("partial transcript = %q", partial.Result)
+	}
+
+	// The stragglers finish; the re-merge is complete and gap-free.
+	server.complete(4)
+	server.complete(2)
+	results := fetchAll(t, client, 4)
+	full := MergeAvailableResults(results)
+	if len(full.GapRanges) != 0 {
+		t.Errorf("complete merge still reports gaps %+v", full.GapRanges)
+	}
+	if want := MergeResults(results); full.Result != want.Result || len(full.Segments) != len(want.Segments) {
+		t.Errorf("complete assembly diverges from MergeResults: %q vs %q", full.Result, want.Result)
+	}
+}
+
+func TestGetTranscriptionPartReportsFailure(t *testing.T) {
+	server := newPartServer(t, 2)
+	server.complete(1)
+	server.fail(2, "codec not supported")
+	client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	part, err := client.GetTranscriptionPart(context.Background(), "task-parts", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if part.Status != PartStatusFailed || part.Error != "codec not supported" {
+		t.Errorf("failed part = %+v", part)
+	}
+	if _, err := client.GetTranscriptionPart(context.Background(), "task-parts", 0); err == nil {
+		t.Error("part index 0 accepted")
+	}
+}
+
+func TestMergeAvailableResultsCoalescesGapRuns(t *testing.T) {
+	one := &RecognitionResult{Result: "only part", Duration: ms(1000)}
+	partial := MergeAvailableResults([]*RecognitionResult{nil, nil, one, nil, one, nil, nil, nil})
+	want := []GapRange{{1, 2}, {4, 4}, {6, 8}}
+	if len(partial.GapRanges) != len(want) {
+		t.Fatalf("gaps = %+v, want %+v", partial.GapRanges, want)
+	}
+	for i, gap := range partial.GapRanges {
+		if gap != want[i] {
+			t.Errorf("gap %d = %+v, want %+v", i, gap, want[i])
+		}
+	}
+	if empty := MergeAvailableResults(nil); len(empty.GapRanges) != 0 {
+		t.Errorf("empty input reports gaps %+v", empty.GapRanges)
+	}
+}
@@ -0,0 +1,51 @@
+package voiceworld
+
+import "strings"
+
+// windowsPathLimit is the classic MAX_PATH boundary past which Windows file
+// APIs need the \\?\ long-path prefix. The limit is applied a little early
+// (248, the directory limit) so paths near the edge do not depend on which
+// API touches them.
+const windowsPathLimit = 248
+
+// addLongPathPrefix rewrites an absolute Windows path to its \\?\ long-path
+// form when it exceeds the legacy limit: drive-letter paths gain the \\?\
+// prefix, UNC shares become \\?\UNC\<server>\<share>\.... Short paths,
+// already-prefixed paths and anything else come back unchanged. The pure
+// string logic lives here so it is testable on every platform; longPath
+// applies it on Windows only.
+func addLongPathPrefix(path string) string {
+	if len(path) < windowsPathLimit || strings.HasPrefix(path, `\\?\`) {
+		return path
+	}
+	if strings.HasPrefix(path, `\\`) {
+		return `\\?\UNC\` + path[2:]
+	}
+	if len(path) >= 2 && path[1] == ':' {
+		return `\\?\` + path
+	}
+	return path
+}
+
+// sourceBaseName is filepath.Base for user-supplied source paths: it
+// understands both separator styles as well as \\?\ and UNC prefixes, so an
+// object name derived from a recording on a Windows file share does not
+// swallow the share prefix — even when the SDK runs on another platform.
+func sourceBaseName(path string) string {
+	path = strings.TrimPrefix(path, `\\?\UNC\`)
+	path = strings.TrimPrefix(path, `\\?\`)
+	for len(path) > 0 && (path[len(path)-1] == '/' || path[len(path)-1] == '\\') {
+		path = path[:len(path)-1]
+	}
+	if i := strings.LastIndexAny(path, `/\`); i >= 0 {
+		path = path[i+1:]
+	}
+	// A drive letter alone ("C:") names no file.
+	if len(path) == 2 && path[1] == ':' {
+		path = ""
+	}
+	if path == "" {
+		return "."
+	}
+	return path
+}
@@ -0,0 +1,51 @@
+package voiceworld
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAddLongPathPrefix(t *testing.T) {
+	longTail := strings.Repeat("a", windowsPathLimit) // pushes any prefix past the limit
+	cases := []struct {
+		name string
+		path string
+		want string
+	}{
+		{"short drive path", `C:\audio\a.wav`, `C:\audio\a.wav`},
+		{"short unc path", `\\nas01\audio\a.wav`, `\\nas01\audio\a.wav`},
+		{"long drive path", `C:\audio\` + longTail, `\\?\C:\audio\` + longTail},
+		{"long unc path", `\\nas01\audio\` + longTail, `\\?\UNC\nas01\audio\` + longTail},
+		{"already prefixed", `\\?\C:\audio\` + longTail, `\\?\C:\audio\` + longTail},
+		{"long relative path", `audio\` + longTail, `audio\` + longTail},
+	}
+	for _, tc := range cases {
+		if got := addLongPathPrefix(tc.path); got != tc.want {
+			t.Errorf("%s: addLongPathPrefix = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestSourceBaseName(t *testing.T) {
+	cases := []struct {
+		path string
+		want string
+	}{
+		{"/data/audio/a.wav", "a.wav"},
+		{"a.wav", "a.wav"},
+		{"/data/audio/", "audio"},
+		{`C:\audio\a.wav`, "a.wav"},
+		{`\\nas01\audio\meeting 1.wav`, "meeting 1.wav"},
+		{`\\nas01\audio\`, "audio"},
+		{`\\?\C:\audio\a.wav`, "a.wav"},
+		{`\\?\UNC\nas01\audio\a.wav`, "a.wav"},
+		{`C:\mixed/separators\a.wav`, "a.wav"},
+		{`C:`, "."},
+		{"", "."},
+	}
+	for _, tc := range cases {
+		if got := sourceBaseName(tc.path); got != tc.want {
+			t.Errorf("sourceBaseName(%q) = %q, want %q", tc.path, got, tc.want)
+		}
+	}
+}
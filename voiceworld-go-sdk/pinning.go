@@ -0,0 +1,96 @@
+package voiceworld
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrCertificatePinMismatch is the sentinel every pin rejection unwraps to;
+// match it with errors.Is and read the observed hash off the
+// *CertificatePinError for rotation.
+var ErrCertificatePinMismatch = errors.New("voiceworld: server certificate matches no pinned key")
+
+// CertificatePinError reports a TLS connection rejected because the server's
+// leaf certificate key is not in ClientConfig.PinnedCertSHA256. It carries
+// the hash the server actually presented, so rotating to a new certificate
+// is a matter of adding that value to the pin list.
+type CertificatePinError struct {
+	// ObservedSHA256 is the hex SHA-256 of the presented leaf
+	// certificate's SPKI.
+	ObservedSHA256 string
+}
+
+// Error implements the error interface.
+func (e *CertificatePinError) Error() string {
+	return fmt.Sprintf("voiceworld: server certificate matches no pinned key (observed spki sha256 %s)", e.ObservedSHA256)
+}
+
+// Unwrap exposes the sentinel to errors.Is.
+func (e *CertificatePinError) Unwrap() error { return ErrCertificatePinMismatch }
+
+// applyCertPins rebuilds the client's HTTP transport so every TLS handshake
+// additionally checks the leaf SPKI hash against PinnedCertSHA256. The
+// client and transport are cloned, never mutated, so a caller-supplied
+// HTTPClient stays usable elsewhere without pinning.
+func (c *Client) applyCertPins() error {
+	verify, err := pinnedVerifier(c.config.PinnedCertSHA256)
+	if err != nil {
+		return err
+	}
+	transport := http.DefaultTransport
+	if c.httpClient.Transport != nil {
+		transport = c.httpClient.Transport
+	}
+	t, ok := transport.(*http.Transport)
+	if !ok {
+		return fmt.Errorf("voiceworld: certificate pinning requires an *http.Transport, got %T", transport)
+	}
+	t = t.Clone()
+	if t.TLSClientConfig == nil {
+		t.TLSClientConfig = &tls.Config{}
+	}
+	t.TLSClientConfig.VerifyPeerCertificate = verify
+	pinned := *c.httpClient
+	pinned.Transport = t
+	c.httpClient = &pinned
+	return nil
+}
+
+// pinnedVerifier compiles the pin list into a VerifyPeerCertificate hook.
+// Pins are hex SPKI SHA-256 hashes, compared case-insensitively; listing the
+// outgoing and incoming certificate's hashes together is how a rotation
+// stays zero-downtime.
+func pinnedVerifier(pins []string) (func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error, error) {
+	allowed := make(map[string]bool, len(pins))
+	for _, pin := range pins {
+		normalized := strings.ToLower(pin)
+		if len(normalized) != sha256.Size*2 {
+			return nil, fmt.Errorf("voiceworld: pinned certificate hash %q is not a hex sha-256", pin)
+		}
+		if _, err := hex.DecodeString(normalized); err != nil {
+			return nil, fmt.Errorf("voiceworld: pinned certificate hash %q is not a hex sha-256", pin)
+		}
+		allowed[normalized] = true
+	}
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return ErrCertificatePinMismatch
+		}
+		cert, err := x509.ParseCertificate(rawCerts[0])
+		if err != nil {
+			return fmt.Errorf("voiceworld: parse server certificate: %w", err)
+		}
+		sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+		observed := hex.EncodeToString(sum[:])
+		if !allowed[observed] {
+			return &CertificatePinError{ObservedSHA256: observed}
+		}
+		return nil
+	}, nil
+}
@@ -0,0 +1,102 @@
+package voiceworld
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// pinnedTestServer starts a TLS envelope server and returns it with its
+// certificate's SPKI pin and an HTTPClient that trusts its self-signed CA.
+func pinnedTestServer(t *testing.T) (*httptest.Server, string, *http.Client) {
+	t.Helper()
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":0}`))
+	}))
+	t.Cleanup(server.Close)
+	sum := sha256.Sum256(server.Certificate().RawSubjectPublicKeyInfo)
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	httpClient := &http.Client{Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{RootCAs: pool},
+	}}
+	return server, hex.EncodeToString(sum[:]), httpClient
+}
+
+func (c *Client) pingForTest(t *testing.T) error {
+	t.Helper()
+	req, err := c.NewSignedRequest(context.Background(), http.MethodGet, "/ping", nil, "")
+	if err != nil {
+		t.Fatalf("NewSignedRequest: %v", err)
+	}
+	return c.DoJSON(req, nil)
+}
+
+func TestCertificatePinAccepted(t *testing.T) {
+	server, pin, httpClient := pinnedTestServer(t)
+	client, _ := newTestClient(t, &ClientConfig{
+		BaseURL:          server.URL,
+		HTTPClient:       httpClient,
+		PinnedCertSHA256: []string{strings.ToUpper(pin)}, // pins compare case-insensitively
+	})
+	if err := client.pingForTest(t); err != nil {
+		t.Fatalf("pinned request failed: %v", err)
+	}
+}
+
+func TestCertificatePinMismatch(t *testing.T) {
+	server, pin, httpClient := pinnedTestServer(t)
+	client, _ := newTestClient(t, &ClientConfig{
+		BaseURL:          server.URL,
+		HTTPClient:       httpClient,
+		PinnedCertSHA256: []string{strings.Repeat("ab", 32)},
+	})
+	err := client.pingForTest(t)
+	if !errors.Is(err, ErrCertificatePinMismatch) {
+		t.Fatalf("err = %v, want ErrCertificatePinMismatch", err)
+	}
+	if !strings.Contains(err.Error(), pin) {
+		t.Errorf("error %q does not report the observed hash %s", err, pin)
+	}
+}
+
+func TestCertificatePinRotationListsBothHashes(t *testing.T) {
+	server, pin, httpClient := pinnedTestServer(t)
+	client, _ := newTestClient(t, &ClientConfig{
+		BaseURL:          server.URL,
+		HTTPClient:       httpClient,
+		PinnedCertSHA256: []string{strings.Repeat("cd", 32), pin},
+	})
+	if err := client.pingForTest(t); err != nil {
+		t.Fatalf("request failed during rotation window: %v", err)
+	}
+}
+
+func TestCertificatePinValidation(t *testing.T) {
+	_, err := NewClient("k", "s", &ClientConfig{PinnedCertSHA256: []string{"not-a-hash"}})
+	if err == nil || !strings.Contains(err.Error(), "not a hex sha-256") {
+		t.Fatalf("err = %v, want a pin validation error", err)
+	}
+}
+
+func TestCertificatePinDoesNotMutateCallerClient(t *testing.T) {
+	server, pin, httpClient := pinnedTestServer(t)
+	if _, err := NewClient("k", "s", &ClientConfig{
+		BaseURL:          server.URL,
+		HTTPClient:       httpClient,
+		PinnedCertSHA256: []string{pin},
+	}); err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	transport := httpClient.Transport.(*http.Transport)
+	if transport.TLSClientConfig.VerifyPeerCertificate != nil {
+		t.Error("pinning leaked into the caller's transport")
+	}
+}
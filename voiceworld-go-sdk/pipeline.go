@@ -0,0 +1,290 @@
+package voiceworld
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"sync"
+)
+
+// streamBlockFrames is how many source frames the pipeline converter decodes
+// per block before handing output to the uploader. At 44.1 kHz stereo that is
+// roughly 128 KiB of input per block.
+const streamBlockFrames = 32 * 1024
+
+// canStreamProcessed reports whether the conversion output for info has a
+// size computable from the header alone, which is what lets
+// ProcessAndUploadFile pipe the converter straight into the multipart
+// uploader. Callback and IfNoneMatch uploads need the single-put path, so
+// they take the temp-file route regardless.
+func canStreamProcessed(info *WAVInfo, opts *UploadOptions) bool {
+	return info.BitsPerSample == 16 &&
+		info.DataSize > 0 &&
+		info.DataSize%int64(info.blockAlign()) == 0 &&
+		opts.Callback == nil &&
+		!opts.IfNoneMatch
+}
+
+// processedDataSize is the exact PCM payload size streamProcessedWAV will
+// produce for info: 16-bit PCM conversion is deterministic, so the frame
+// count falls out of the header arithmetic.
+func processedDataSize(info *WAVInfo) int64 {
+	frames := info.DataSize / int64(info.blockAlign())
+	return frames * int64(targetSampleRate) / int64(info.SampleRate) * 2
+}
+
+// streamProcessedWAV converts the PCM payload of filePath to the recognition
+// layout and writes a canonical WAV to w block by block, so a consumer on
+// the other end of a pipe can upload early blocks while later ones are still
+// being converted. The output is byte-for-byte what ProcessAudio writes to
+// its temp file, and its payload is exactly processedDataSize(info) long.
+func streamProcessedWAV(w io.Writer, filePath string, info *WAVInfo) error {
+	src, err := os.Open(longPath(filePath))
+	if err != nil {
+		return fmt.Errorf("voiceworld: open audio: %w", err)
+	}
+	defer src.Close()
+	if _, err := src.Seek(info.DataOffset, io.SeekStart); err != nil {
+		return err
+	}
+	outInfo := &WAVInfo{NumChannels: targetChannels, SampleRate: targetSampleRate, BitsPerSample: targetBits}
+	if err := writeWAVHeader(w, outInfo, processedDataSize(info)); err != nil {
+		return err
+	}
+
+	ba := info.blockAlign()
+	frames := int(info.DataSize / int64(ba))
+	outLen := int(int64(frames) * int64(targetSampleRate) / int64(info.SampleRate))
+	// The mono signal accumulates so interpolation can reach back across
+	// block boundaries; this mirrors the memory profile of ProcessAudio,
+	// which holds every sample at once anyway.
+	mono := make([]int16, 0, frames)
+	emitted := 0
+	block := make([]byte, streamBlockFrames*ba)
+	out := make([]byte, 0, 2*streamBlockFrames)
+	for read := 0; read < frames; {
+		want := streamBlockFrames
+		if frames-read < want {
+			want = frames - read
+		}
+		if _, err := io.ReadFull(src, block[:want*ba]); err != nil {
+			return fmt.Errorf("voiceworld: read pcm data: %w", err)
+		}
+		for f := 0; f < want; f++ {
+			var sum int64
+			for ch := 0; ch < info.NumChannels; ch++ {
+				sum += int64(int16(binary.LittleEndian.Uint16(block[(f*info.NumChannels+ch)*2:])))
+			}
+			mono = append(mono, int16(sum/int64(info.NumChannels)))
+		}
+		read += want
+
+		// Emit every output sample whose interpolation window is in hand;
+		// the tail that reads past the final sample waits for end of input.
+		out = out[:0]
+		for ; emitted < outLen; emitted++ {
+			// The same arithmetic as resampleLinear, in the same order, so
+			// the streamed output matches the batch path bit for bit.
+			pos := float64(emitted) * float64(info.SampleRate) / float64(targetSampleRate)
+			j := int(pos)
+			var s int16
+			switch {
+			case j >= frames-1:
+				if len(mono) < frames {
+					goto flush
+				}
+				s = mono[frames-1]
+			case j+1 >= len(mono):
+				goto flush
+			default:
+				frac := pos - float64(j)
+				s = int16(float64(mono[j])*(1-frac) + float64(mono[j+1])*frac)
+			}
+			out = binary.LittleEndian.AppendUint16(out, uint16(s))
+		}
+	flush:
+		if len(out) > 0 {
+			if _, err := w.Write(out); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// processAndUploadStream runs the convert→upload pipeline: the converter
+// writes into a pipe the multipart uploader drains, overlapping CPU-bound
+// conversion with network transfer and skipping the on-disk temp copy.
+func (c *Client) processAndUploadStream(filePath string, info *WAVInfo, objectName string, opts *UploadOptions) (string, error) {
+	status, err := c.life.beginOp("upload")
+	if err != nil {
+		return "", err
+	}
+	defer c.life.endOp(status)
+	status.setRequestID(requestIDFromKey(objectName))
+	report := c.newOperationReport("process_upload_stream")
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(streamProcessedWAV(pw, filePath, info))
+	}()
+	url, err := c.uploadStream(pr, 44+processedDataSize(info), objectName, opts, report, status)
+	if err != nil {
+		// Unblock the converter if the upload bailed out first.
+		pr.CloseWithError(err)
+	}
+	c.finishReport(report, err)
+	return url, err
+}
+
+// uploadStream stages size bytes from r under objectName as a multipart
+// upload. The stream is read sequentially into part-sized buffers, so up to
+// Parallelism parts can be in flight while the producer is still writing,
+// and each buffered part can be rewound for retries without the source
+// being seekable.
+func (c *Client) uploadStream(r io.Reader, size int64, objectName string, opts *UploadOptions, report *OperationReport, status *liveOp) (string, error) {
+	store, err := c.storageForScope(report, uploadScope(objectName))
+	if err != nil {
+		return "", err
+	}
+	var hasher hash.Hash
+	if opts.Checksum != nil {
+		hasher = opts.Checksum.New()
+		r = io.TeeReader(r, hasher)
+	}
+	uploadID, err := c.initiateMultipart(store, objectName)
+	if err != nil {
+		return "", fmt.Errorf("voiceworld: initiate multipart upload: %w", err)
+	}
+	untrack := c.life.trackUpload(store, objectName, uploadID)
+	defer untrack()
+
+	chunkSize := c.config.ChunkSize
+	numParts := int((size + chunkSize - 1) / chunkSize)
+	if numParts == 0 {
+		numParts = 1 // empty stream still needs one (empty) part
+	}
+	status.setTotals(size, numParts)
+	parts := make([]UploadedPart, numParts)
+	op := &uploadOp{
+		tracker: newProgressTracker(c.config.Progress, size),
+		budget:  newRetryBudget(c.config.MaxTotalRetries),
+		report:  report,
+		status:  status,
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	sem := make(chan struct{}, c.config.Parallelism)
+	for i := 0; i < numParts; i++ {
+		mu.Lock()
+		failed := firstErr != nil
+		mu.Unlock()
+		if failed {
+			break
+		}
+		partSize := chunkSize
+		if int64(i)*chunkSize+partSize > size {
+			partSize = size - int64(i)*chunkSize
+		}
+		data := make([]byte, partSize)
+		if _, err := io.ReadFull(r, data); err != nil {
+			mu.Lock()
+			if firstErr == nil {
+				firstErr = fmt.Errorf("voiceworld: read part %d of stream: %w", i+1, err)
+			}
+			mu.Unlock()
+			break
+		}
+		wg.Add(1)
+		// Acquiring the slot here, not in the goroutine, caps how many
+		// part buffers exist at once.
+		sem <- struct{}{}
+		go func(i int, data []byte) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			part, err := c.uploadStreamPart(store, objectName, uploadID, i, data, op)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			parts[i] = part
+		}(i, data)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		// Best effort: the abort failing should not mask the upload error.
+		c.abortMultipart(store, objectName, uploadID)
+		return "", firstErr
+	}
+	if err := c.completeMultipart(store, objectName, uploadID, parts); err != nil {
+		c.abortMultipart(store, objectName, uploadID)
+		return "", fmt.Errorf("voiceworld: complete multipart upload of %d bytes: %w", size, err)
+	}
+	meta := map[string]string{}
+	if hasher != nil {
+		value := ChecksumValue{Algorithm: opts.Checksum.Name(), Value: opts.Checksum.Format(hasher)}
+		report.setChecksum(value)
+		meta[checksumMetaKey(opts.Checksum)] = value.Value
+	}
+	if opts.Source != nil {
+		for k, v := range opts.Source.metadata() {
+			meta[k] = v
+		}
+	}
+	if len(meta) > 0 {
+		if ms, ok := store.(metadataStorage); ok {
+			if merr := ms.SetObjectMeta(objectName, meta); merr != nil {
+				return "", fmt.Errorf("voiceworld: record metadata for %s: %w", objectName, merr)
+			}
+		}
+	}
+	signed, _, err := c.signStorageURL(store, objectName, signedURLExpiry)
+	if err != nil {
+		return "", err
+	}
+	return signed, nil
+}
+
+// uploadStreamPart sends one buffered part, rewinding the buffer between
+// retry attempts.
+func (c *Client) uploadStreamPart(store ObjectStorage, objectName, uploadID string, i int, data []byte, op *uploadOp) (UploadedPart, error) {
+	br := bytes.NewReader(data)
+	preport := PartReport{Index: i + 1, Bytes: int64(len(data))}
+	c.transfers.acquire()
+	begin := c.now()
+	var part UploadedPart
+	err := retryStorageOp(c.retryPolicy(), op.budget, &preport, func() error {
+		_, serr := br.Seek(0, io.SeekStart)
+		return serr
+	}, func() error {
+		var perr error
+		part, perr = uploadPart(store, objectName, uploadID, i+1, br, int64(len(data)), op.tracker)
+		return perr
+	})
+	preport.Duration = c.now().Sub(begin)
+	c.transfers.release()
+	c.stats.countStorageOp("upload_part")
+	c.stats.addRetries(int64(preport.Retries))
+	if err != nil {
+		preport.Error = err.Error()
+		op.report.addPart(preport)
+		c.stats.addFailure()
+		return UploadedPart{}, fmt.Errorf("voiceworld: upload part %d: %w", i+1, err)
+	}
+	op.report.addPart(preport)
+	op.status.addProgress(int64(len(data)), 1)
+	c.stats.addUpload(int64(len(data)))
+	return part, nil
+}
@@ -0,0 +1,118 @@
+package voiceworld
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"sync/atomic"
+	"testing"
+)
+
+// countingMultipartStore wraps MemoryStorage to observe which upload path a
+// call took: the pipeline initiates a multipart upload, the temp-file
+// fallback does not.
+type countingMultipartStore struct {
+	*MemoryStorage
+	initiated int32
+}
+
+func (s *countingMultipartStore) InitiateMultipartUpload(key string) (string, error) {
+	atomic.AddInt32(&s.initiated, 1)
+	return s.MemoryStorage.InitiateMultipartUpload(key)
+}
+
+func TestPipelineMatchesTempFilePath(t *testing.T) {
+	// Small parts force the pipeline through several sequential reads of
+	// the converter's pipe.
+	client, store := newTestClient(t, &ClientConfig{ChunkSize: 16 << 10, Parallelism: 3})
+	path := makeWAVFile(t, 44100, 2, 1.2)
+
+	result, err := client.ProcessAndUploadFile(path, "req-pipe")
+	if err != nil {
+		t.Fatalf("ProcessAndUploadFile: %v", err)
+	}
+	got, ok := store.Object(result.ProcessedKey)
+	if !ok {
+		t.Fatalf("object %s not stored", result.ProcessedKey)
+	}
+
+	processed, err := ProcessAudio(path)
+	if err != nil {
+		t.Fatalf("ProcessAudio: %v", err)
+	}
+	defer os.Remove(processed)
+	want, err := os.ReadFile(processed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) <= int(client.config.ChunkSize) {
+		t.Fatalf("fixture too small to exercise multiple parts: %d bytes", len(got))
+	}
+	if string(got) != string(want) {
+		t.Errorf("streamed object (%d bytes) differs from temp-file conversion (%d bytes)", len(got), len(want))
+	}
+}
+
+func TestPipelineSingleFrameTail(t *testing.T) {
+	// A duration that does not divide evenly into parts or blocks, mono and
+	// already at the target rate, exercises the copy-through tail path.
+	client, store := newTestClient(t, &ClientConfig{ChunkSize: 8 << 10})
+	path := makeWAVFile(t, 16000, 1, 0.73)
+
+	result, err := client.ProcessAndUploadFile(path, "req-tail")
+	if err != nil {
+		t.Fatalf("ProcessAndUploadFile: %v", err)
+	}
+	got, _ := store.Object(result.ProcessedKey)
+	src, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(src) {
+		t.Errorf("already-canonical input should round-trip unchanged: got %d bytes, want %d", len(got), len(src))
+	}
+}
+
+func TestPipelineRecordsChecksumAndSource(t *testing.T) {
+	client, store := newTestClient(t, &ClientConfig{ChunkSize: 16 << 10})
+	path := makeWAVFile(t, 44100, 1, 0.5)
+
+	result, err := client.ProcessAndUploadFile(path, "req-meta", &UploadOptions{Checksum: ChecksumSHA256})
+	if err != nil {
+		t.Fatalf("ProcessAndUploadFile: %v", err)
+	}
+	data, _ := store.Object(result.ProcessedKey)
+	meta, ok := store.ObjectMeta(result.ProcessedKey)
+	if !ok {
+		t.Fatal("object not stored")
+	}
+	sum := sha256.Sum256(data)
+	if meta["checksum-sha256"] != hex.EncodeToString(sum[:]) {
+		t.Errorf("checksum-sha256 = %q, want %s", meta["checksum-sha256"], hex.EncodeToString(sum[:]))
+	}
+	if meta["source-name"] != "audio.wav" {
+		t.Errorf("source-name = %q, want audio.wav", meta["source-name"])
+	}
+}
+
+func TestPipelineFallsBackForSinglePutOptions(t *testing.T) {
+	store := &countingMultipartStore{MemoryStorage: NewMemoryStorage()}
+	client, err := NewClient("test-app-key", "test-secret", &ClientConfig{Storage: store})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	path := makeWAVFile(t, 16000, 1, 0.2)
+
+	if _, err := client.ProcessAndUploadFile(path, "req-a", &UploadOptions{IfNoneMatch: true}); err != nil {
+		t.Fatalf("ProcessAndUploadFile with IfNoneMatch: %v", err)
+	}
+	if n := atomic.LoadInt32(&store.initiated); n != 0 {
+		t.Errorf("IfNoneMatch upload initiated %d multipart uploads, want the single-put fallback", n)
+	}
+	if _, err := client.ProcessAndUploadFile(path, "req-b"); err != nil {
+		t.Fatalf("ProcessAndUploadFile: %v", err)
+	}
+	if n := atomic.LoadInt32(&store.initiated); n == 0 {
+		t.Error("default upload did not take the multipart pipeline")
+	}
+}
@@ -0,0 +1,405 @@
+package voiceworld
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Target format for recognition: the platform's models expect 16 kHz mono
+// 16-bit little-endian PCM.
+const (
+	targetSampleRate = 16000
+	targetChannels   = 1
+	targetBits       = 16
+)
+
+// ProcessAudio converts a PCM WAV file to the 16 kHz mono 16-bit layout the
+// recognition models expect, writing the result to a temp file and returning
+// its path. The caller owns the returned file and should remove it when done.
+// Files already in the target layout are still rewritten so the output is
+// always a canonical 44-byte-header WAV.
+func ProcessAudio(filePath string) (string, error) {
+	return ProcessAudioContext(context.Background(), filePath)
+}
+
+// ProcessAudioContext is ProcessAudio with a cancellation point between
+// every copy buffer, so a cancelled caller stops promptly instead of
+// finishing a long conversion. The temp file is registered for
+// CleanupTempFiles, and on any error — cancellation included — the partial
+// output is removed before returning.
+func ProcessAudioContext(ctx context.Context, filePath string) (string, error) {
+	// Telephony containers (AMR, Speex) carry no PCM to convert directly;
+	// they go through ffmpeg first, which already lands them on the
+	// target layout, and the canonical rewrite below still runs.
+	if format, ferr := DetectAudioFormat(filePath); ferr == nil && isTelephonyFormat(format) {
+		converted, terr := transcodeToWAV(ctx, filePath, format)
+		if terr != nil {
+			return "", terr
+		}
+		defer removeTempFile(converted)
+		filePath = converted
+	}
+	info, err := ParseWAVFile(filePath)
+	if err != nil {
+		return "", err
+	}
+	if info.BitsPerSample != 16 {
+		return "", fmt.Errorf("voiceworld: unsupported bit depth %d (only 16-bit PCM is supported)", info.BitsPerSample)
+	}
+
+	src, err := os.Open(longPath(filePath))
+	if err != nil {
+		return "", fmt.Errorf("voiceworld: open audio: %w", err)
+	}
+	defer src.Close()
+	if _, err := src.Seek(info.DataOffset, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	samples, err := readSamplesContext(ctx, src, info)
+	if err != nil {
+		return "", err
+	}
+	mono := mixToMono(samples, info.NumChannels)
+	resampled := resampleLinear(mono, info.SampleRate, targetSampleRate)
+
+	out, err := newProcessedTempFile()
+	if err != nil {
+		return "", fmt.Errorf("voiceworld: create output: %w", err)
+	}
+	outInfo := &WAVInfo{NumChannels: targetChannels, SampleRate: targetSampleRate, BitsPerSample: targetBits}
+	err = writeSamplesContext(ctx, out, outInfo, resampled)
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		removeTempFile(out.Name())
+		return "", fmt.Errorf("voiceworld: write processed audio: %w", err)
+	}
+	return out.Name(), nil
+}
+
+// UploadResult names the objects staged by ProcessAndUploadFile. The
+// processed object is the one recognition should consume; the raw object is
+// only present when UploadOptions.UploadOriginal asked for it.
+type UploadResult struct {
+	RequestID    string `json:"request_id"`
+	ProcessedKey string `json:"processed_key"`
+	ProcessedURL string `json:"processed_url"`
+	RawKey       string `json:"raw_key,omitempty"`
+	RawURL       string `json:"raw_url,omitempty"`
+	// URLExpiresAt is when ProcessedURL (and RawURL, signed moments
+	// later) stop working, so callers can refresh or warn before playback
+	// 403s. Zero when the output was split into Parts, which carry their
+	// own expiries.
+	URLExpiresAt time.Time `json:"url_expires_at,omitempty"`
+	// Source describes the original recording both objects derive from;
+	// the same info is attached to them as audit metadata.
+	Source *SourceInfo `json:"source,omitempty"`
+	// Analysis is the source recording's loudness report, present when
+	// UploadOptions.RejectIf ran one; see AnalyzeAudio.
+	Analysis *AnalysisReport `json:"analysis,omitempty"`
+	// Parts lists the staged chunks when UploadOptions.MaxObjectBytes
+	// forced a split; ProcessedKey and ProcessedURL are empty then, and
+	// each part names its own object. Empty when a single processed
+	// object was staged.
+	Parts []SplitPart `json:"parts,omitempty"`
+}
+
+// ObjectTooLargeError reports a processed output larger than
+// UploadOptions.MaxObjectBytes with the splitting fallback disabled by
+// FailInsteadOfSplit.
+type ObjectTooLargeError struct {
+	// Key is the object that would have been staged.
+	Key string
+	// Size is the processed output size in bytes.
+	Size int64
+	// Limit is the configured single-object cap.
+	Limit int64
+}
+
+func (e *ObjectTooLargeError) Error() string {
+	return fmt.Sprintf("voiceworld: processed object %s would be %d bytes, over the %d-byte single-object cap",
+		e.Key, e.Size, e.Limit)
+}
+
+// ProcessAndUploadFile converts filePath to the recognition layout and
+// uploads the result under ClientConfig.ProcessedPrefix for requestID (empty
+// means a generated one). When the converted size is computable from the WAV
+// header the converter streams straight into a multipart upload, overlapping
+// conversion with transfer; otherwise the output goes through a temp file
+// first. With UploadOptions.UploadOriginal the untouched source goes up as
+// well, under ClientConfig.RawPrefix, so retention policies can keep
+// originals and derived audio on separate schedules. Passing more than one
+// options value is an error.
+func (c *Client) ProcessAndUploadFile(filePath, requestID string, options ...*UploadOptions) (*UploadResult, error) {
+	opts, err := uploadOptions(options)
+	if err != nil {
+		return nil, err
+	}
+	if requestID == "" {
+		if c.config.Strict {
+			return nil, fmt.Errorf("%w: pass an explicit requestID", ErrRequestIDRequired)
+		}
+		requestID = NewRequestID()
+	}
+	info, err := ParseWAVFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &UploadResult{
+		RequestID:    requestID,
+		ProcessedKey: c.config.ProcessedPrefix + requestID + ".wav",
+	}
+	// Both staged objects derive from the original recording, so they
+	// carry the original's audit metadata, not the temp copy's.
+	if opts.Source == nil {
+		source, serr := SourceInfoFor(filePath)
+		if serr != nil {
+			return nil, serr
+		}
+		withSource := *opts
+		withSource.Source = source
+		opts = &withSource
+	}
+	result.Source = opts.Source
+	if opts.RejectIf != nil {
+		// Analyze the original, not the converted temp copy, and before
+		// any conversion work; the nested uploads then skip their own
+		// pass.
+		report, qerr := c.analyzeForPolicy(filePath, opts.RejectIf)
+		if qerr != nil {
+			return nil, qerr
+		}
+		result.Analysis = report
+		unchecked := *opts
+		unchecked.RejectIf = nil
+		opts = &unchecked
+	}
+	if opts.ProbePermissions {
+		// Probe before any conversion work, which is the expensive part
+		// this option exists to protect. The nested uploads then skip
+		// their own probes.
+		if perr := c.probeUploadPermissions(result.ProcessedKey); perr != nil {
+			return nil, perr
+		}
+		probed := *opts
+		probed.ProbePermissions = false
+		opts = &probed
+	}
+	switch {
+	case opts.MaxObjectBytes > 0:
+		err = c.uploadProcessedCapped(filePath, info, requestID, result, opts)
+	case canStreamProcessed(info, opts):
+		result.ProcessedURL, err = c.processAndUploadStream(filePath, info, result.ProcessedKey, opts)
+	default:
+		processed, perr := ProcessAudio(filePath)
+		if perr != nil {
+			return nil, perr
+		}
+		defer os.Remove(processed)
+		result.ProcessedURL, err = c.UploadFile(processed, result.ProcessedKey, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// The processed object always lands in the canonical target layout;
+	// stamp it as audio-* metadata so RecognizeObjectRange can compute
+	// byte ranges without fetching the header first. Best effort — the
+	// header itself remains authoritative. Split chunks carry their own
+	// headers and are skipped.
+	if len(result.Parts) == 0 {
+		if store, serr := c.storage(); serr == nil {
+			result.URLExpiresAt = c.now().Add(c.signedURLTTL(store, signedURLExpiry)).UTC()
+			if ms, ok := store.(metadataStorage); ok {
+				_ = ms.SetObjectMeta(result.ProcessedKey, audioParamsMeta(&WAVInfo{
+					NumChannels:   targetChannels,
+					SampleRate:    targetSampleRate,
+					BitsPerSample: targetBits,
+					DataOffset:    44,
+				}))
+			}
+		}
+	}
+	if opts.UploadOriginal {
+		result.RawKey = c.config.RawPrefix + requestID + "/" + sourceBaseName(filePath)
+		result.RawURL, err = c.UploadFile(filePath, result.RawKey, opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// uploadProcessedCapped is the MaxObjectBytes path of ProcessAndUploadFile:
+// it sizes the processed output up front, stages it as a single object when
+// it fits the cap, and otherwise falls back to SplitAudioFile under the same
+// request id, recording the chunks on result.Parts.
+func (c *Client) uploadProcessedCapped(filePath string, info *WAVInfo, requestID string, result *UploadResult, opts *UploadOptions) error {
+	size := int64(-1)
+	streamable := canStreamProcessed(info, opts)
+	if streamable {
+		size = 44 + processedDataSize(info)
+	}
+	var processed string
+	if size < 0 {
+		// The header cannot predict the converted size, so convert
+		// first and measure the real output.
+		var perr error
+		processed, perr = ProcessAudio(filePath)
+		if perr != nil {
+			return perr
+		}
+		defer os.Remove(processed)
+		fi, serr := os.Stat(longPath(processed))
+		if serr != nil {
+			return serr
+		}
+		size = fi.Size()
+	}
+	if size <= opts.MaxObjectBytes {
+		var err error
+		if streamable {
+			result.ProcessedURL, err = c.processAndUploadStream(filePath, info, result.ProcessedKey, opts)
+		} else {
+			result.ProcessedURL, err = c.UploadFile(processed, result.ProcessedKey, opts)
+		}
+		return err
+	}
+	if opts.FailInsteadOfSplit {
+		return &ObjectTooLargeError{Key: result.ProcessedKey, Size: size, Limit: opts.MaxObjectBytes}
+	}
+	// The longest whole-second chunk that fits the cap with its header.
+	bytesPerSecond := int64(targetSampleRate * targetChannels * targetBits / 8)
+	chunkSeconds := (opts.MaxObjectBytes - 44) / bytesPerSecond
+	if chunkSeconds < 1 {
+		return fmt.Errorf("voiceworld: MaxObjectBytes %d cannot hold one second of processed audio (%d bytes)",
+			opts.MaxObjectBytes, 44+bytesPerSecond)
+	}
+	if processed == "" {
+		var perr error
+		processed, perr = ProcessAudio(filePath)
+		if perr != nil {
+			return perr
+		}
+		defer os.Remove(processed)
+	}
+	split, err := c.SplitAudioFile(processed, int(chunkSeconds), &SplitOptions{RequestID: requestID})
+	if err != nil {
+		return err
+	}
+	result.ProcessedKey, result.ProcessedURL = "", ""
+	result.Parts = split.Parts
+	return nil
+}
+
+// PreprocessAudio asks the platform to preprocess audio that is already
+// staged (denoise, normalize loudness) and returns the URL of the processed
+// object.
+func (c *Client) PreprocessAudio(audioURL, requestID string) (string, error) {
+	req := struct {
+		URL       string `json:"url"`
+		RequestID string `json:"request_id,omitempty"`
+	}{audioURL, requestID}
+	var resp preprocessResponse
+	if err := c.postJSON("/preprocess_audio", &req, &resp); err != nil {
+		return "", err
+	}
+	return resp.URL, nil
+}
+
+// copyBlockSize is how much PCM the conversion helpers move per step; each
+// block boundary is a cancellation point.
+const copyBlockSize = 256 * 1024
+
+// readSamplesContext pulls the whole 16-bit PCM payload into memory,
+// interleaved, a block at a time so cancellation lands between copies.
+func readSamplesContext(ctx context.Context, r io.Reader, info *WAVInfo) ([]int16, error) {
+	data := make([]byte, info.DataSize)
+	for read := int64(0); read < info.DataSize; {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("voiceworld: read pcm data: %w", err)
+		}
+		n := int64(copyBlockSize)
+		if info.DataSize-read < n {
+			n = info.DataSize - read
+		}
+		if _, err := io.ReadFull(r, data[read:read+n]); err != nil {
+			return nil, fmt.Errorf("voiceworld: read pcm data: %w", err)
+		}
+		read += n
+	}
+	samples := make([]int16, len(data)/2)
+	for i := range samples {
+		samples[i] = int16(binary.LittleEndian.Uint16(data[2*i:]))
+	}
+	return samples, nil
+}
+
+// mixToMono averages interleaved channels into one.
+func mixToMono(samples []int16, channels int) []int16 {
+	if channels <= 1 {
+		return samples
+	}
+	mono := make([]int16, len(samples)/channels)
+	for i := range mono {
+		var sum int64
+		for ch := 0; ch < channels; ch++ {
+			sum += int64(samples[i*channels+ch])
+		}
+		mono[i] = int16(sum / int64(channels))
+	}
+	return mono
+}
+
+// resampleLinear converts mono samples from one rate to another by linear
+// interpolation. Good enough for speech; not a general-purpose resampler.
+func resampleLinear(in []int16, from, to int) []int16 {
+	if from == to || len(in) == 0 {
+		return in
+	}
+	outLen := int(int64(len(in)) * int64(to) / int64(from))
+	out := make([]int16, outLen)
+	for i := range out {
+		pos := float64(i) * float64(from) / float64(to)
+		j := int(pos)
+		if j >= len(in)-1 {
+			out[i] = in[len(in)-1]
+			continue
+		}
+		frac := pos - float64(j)
+		out[i] = int16(float64(in[j])*(1-frac) + float64(in[j+1])*frac)
+	}
+	return out
+}
+
+// writeSamplesContext emits a WAV header and the PCM payload, one copy
+// block at a time with a cancellation check before each.
+func writeSamplesContext(ctx context.Context, w io.Writer, info *WAVInfo, samples []int16) error {
+	if err := writeWAVHeader(w, info, int64(len(samples)*2)); err != nil {
+		return err
+	}
+	buf := make([]byte, copyBlockSize)
+	for len(samples) > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		n := copyBlockSize / 2
+		if len(samples) < n {
+			n = len(samples)
+		}
+		for i, s := range samples[:n] {
+			binary.LittleEndian.PutUint16(buf[2*i:], uint16(s))
+		}
+		if _, err := w.Write(buf[:2*n]); err != nil {
+			return err
+		}
+		samples = samples[n:]
+	}
+	return nil
+}
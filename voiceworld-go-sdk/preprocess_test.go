@@ -0,0 +1,183 @@
+package voiceworld
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProcessAndUploadFile(t *testing.T) {
+	client, store := newTestClient(t, nil)
+	src := makeWAVFile(t, 44100, 2, 0.5)
+
+	result, err := client.ProcessAndUploadFile(src, "req-1")
+	if err != nil {
+		t.Fatalf("ProcessAndUploadFile: %v", err)
+	}
+	if result.ProcessedKey != "processed/req-1.wav" {
+		t.Errorf("ProcessedKey = %q", result.ProcessedKey)
+	}
+	if result.RawKey != "" || result.RawURL != "" {
+		t.Errorf("raw object staged without UploadOriginal: %+v", result)
+	}
+	if keys, _ := store.ListObjects(""); len(keys) != 1 {
+		t.Errorf("store holds %v, want only the processed object", keys)
+	}
+
+	data, ok := store.Object(result.ProcessedKey)
+	if !ok {
+		t.Fatal("processed object missing")
+	}
+	tmp := filepath.Join(t.TempDir(), "processed.wav")
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	info, err := ParseWAVFile(tmp)
+	if err != nil {
+		t.Fatalf("processed object is not a WAV: %v", err)
+	}
+	if info.SampleRate != 16000 || info.NumChannels != 1 {
+		t.Errorf("processed layout = %d Hz, %d ch, want 16000 Hz mono", info.SampleRate, info.NumChannels)
+	}
+}
+
+func TestProcessAndUploadFileKeepsOriginal(t *testing.T) {
+	client, store := newTestClient(t, &ClientConfig{RawPrefix: "orig/", ProcessedPrefix: "norm/"})
+	src := makeWAVFile(t, 8000, 1, 0.5)
+
+	result, err := client.ProcessAndUploadFile(src, "req-2", &UploadOptions{UploadOriginal: true})
+	if err != nil {
+		t.Fatalf("ProcessAndUploadFile: %v", err)
+	}
+	if result.ProcessedKey != "norm/req-2.wav" {
+		t.Errorf("ProcessedKey = %q", result.ProcessedKey)
+	}
+	if result.RawKey != "orig/req-2/audio.wav" {
+		t.Errorf("RawKey = %q", result.RawKey)
+	}
+	if result.RawURL == "" || result.ProcessedURL == "" {
+		t.Errorf("missing signed URLs: %+v", result)
+	}
+
+	raw, ok := store.Object(result.RawKey)
+	if !ok {
+		t.Fatal("raw object missing")
+	}
+	want, err := os.ReadFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(raw, want) {
+		t.Errorf("raw object differs from the source file (%d vs %d bytes)", len(raw), len(want))
+	}
+	if _, ok := store.Object(result.ProcessedKey); !ok {
+		t.Fatal("processed object missing")
+	}
+	for _, key := range mustList(t, store) {
+		if !strings.HasPrefix(key, "orig/") && !strings.HasPrefix(key, "norm/") {
+			t.Errorf("object %q escaped the configured prefixes", key)
+		}
+	}
+}
+
+func TestProcessAndUploadFileGeneratesRequestID(t *testing.T) {
+	client, _ := newTestClient(t, nil)
+	result, err := client.ProcessAndUploadFile(makeWAVFile(t, 16000, 1, 0.2), "")
+	if err != nil {
+		t.Fatalf("ProcessAndUploadFile: %v", err)
+	}
+	if result.RequestID == "" {
+		t.Error("no request id generated")
+	}
+	if !strings.HasPrefix(result.ProcessedKey, "processed/"+result.RequestID) {
+		t.Errorf("ProcessedKey %q does not embed the request id", result.ProcessedKey)
+	}
+}
+
+func mustList(t *testing.T, store *MemoryStorage) []string {
+	t.Helper()
+	keys, err := store.ListObjects("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keys
+}
+
+func TestProcessAndUploadFileUnderObjectCapStaysSingle(t *testing.T) {
+	client, store := newTestClient(t, nil)
+	src := makeWAVFile(t, 16000, 1, 1) // processed: 44 + 32000 bytes
+
+	// Just under and exactly at the cap both stay on the single-object
+	// path.
+	for i, cap := range []int64{32045, 32044} {
+		requestID := NewRequestID()
+		result, err := client.ProcessAndUploadFile(src, requestID, &UploadOptions{MaxObjectBytes: cap})
+		if err != nil {
+			t.Fatalf("cap %d: %v", cap, err)
+		}
+		if len(result.Parts) != 0 {
+			t.Errorf("cap %d: split into %d parts, want a single object", cap, len(result.Parts))
+		}
+		if result.ProcessedKey == "" || result.ProcessedURL == "" {
+			t.Errorf("cap %d: single-object result incomplete: %+v", cap, result)
+		}
+		data, ok := store.Object(result.ProcessedKey)
+		if !ok || int64(len(data)) != 32044 {
+			t.Errorf("cap %d: processed object %d has %d bytes", cap, i, len(data))
+		}
+	}
+}
+
+func TestProcessAndUploadFileSplitsOverObjectCap(t *testing.T) {
+	client, store := newTestClient(t, nil)
+	src := makeWAVFile(t, 16000, 1, 2) // processed: 44 + 64000 bytes
+
+	result, err := client.ProcessAndUploadFile(src, "req-cap", &UploadOptions{MaxObjectBytes: 32044})
+	if err != nil {
+		t.Fatalf("ProcessAndUploadFile: %v", err)
+	}
+	if result.ProcessedKey != "" || result.ProcessedURL != "" {
+		t.Errorf("split result still names a single object: %+v", result)
+	}
+	if len(result.Parts) != 2 {
+		t.Fatalf("split into %d parts, want 2", len(result.Parts))
+	}
+	for _, part := range result.Parts {
+		if !strings.HasPrefix(part.ObjectKey, "req-cap/") {
+			t.Errorf("part key %q does not share the request prefix", part.ObjectKey)
+		}
+		data, ok := store.Object(part.ObjectKey)
+		if !ok {
+			t.Fatalf("part %d missing from store", part.Index)
+		}
+		if int64(len(data)) > 32044 {
+			t.Errorf("part %d is %d bytes, over the cap", part.Index, len(data))
+		}
+		if part.Size != int64(len(data)) {
+			t.Errorf("part %d reports %d bytes, stored %d", part.Index, part.Size, len(data))
+		}
+	}
+}
+
+func TestProcessAndUploadFileFailsInsteadOfSplit(t *testing.T) {
+	client, store := newTestClient(t, nil)
+	src := makeWAVFile(t, 16000, 1, 2)
+
+	_, err := client.ProcessAndUploadFile(src, "req-nofit", &UploadOptions{
+		MaxObjectBytes:     32044,
+		FailInsteadOfSplit: true,
+	})
+	var terr *ObjectTooLargeError
+	if !errors.As(err, &terr) {
+		t.Fatalf("err = %v, want *ObjectTooLargeError", err)
+	}
+	if terr.Size != 64044 || terr.Limit != 32044 {
+		t.Errorf("error figures = %+v", terr)
+	}
+	if keys, _ := store.ListObjects(""); len(keys) != 0 {
+		t.Errorf("failed upload staged objects: %v", keys)
+	}
+}
@@ -0,0 +1,98 @@
+package voiceworld
+
+import (
+	"io"
+	"sync"
+)
+
+// ProgressReporter receives byte-level progress for one upload operation
+// (one UploadFile, MultipartUploadFile or SplitAudioFile call).
+// Implementations must be safe for concurrent use: parts of one operation
+// upload in parallel.
+type ProgressReporter interface {
+	// ReportProgress is called with the cumulative bytes transferred so
+	// far and the operation's total size. transferred never decreases.
+	ReportProgress(transferred, total int64)
+}
+
+// progressStorage is implemented by backends whose SDK can stream
+// byte-level progress during a single transfer (Aliyun OSS via
+// oss.Progress). Backends without it fall back to one whole-part update
+// after each call returns.
+type progressStorage interface {
+	PutObjectWithProgress(key string, r io.Reader, progress func(consumed int64)) error
+	UploadPartWithProgress(key, uploadID string, partNumber int, r io.Reader, size int64, progress func(consumed int64)) (UploadedPart, error)
+}
+
+// progressTracker aggregates per-part byte counts for one operation and
+// forwards monotonic totals to the reporter. Byte-level events from the
+// backend and our own whole-part accounting both go through setPart, which
+// keeps a high-water mark per part so the two sources never count the same
+// bytes twice. A nil tracker discards all updates.
+type progressTracker struct {
+	reporter ProgressReporter
+	total    int64
+
+	mu          sync.Mutex
+	parts       map[int]int64 // high-water mark of consumed bytes per part
+	transferred int64
+}
+
+// newProgressTracker builds the tracker for one operation of total bytes,
+// or nil when no reporter is configured.
+func newProgressTracker(reporter ProgressReporter, total int64) *progressTracker {
+	if reporter == nil {
+		return nil
+	}
+	return &progressTracker{reporter: reporter, total: total, parts: map[int]int64{}}
+}
+
+// setPart records that consumed bytes of the given part have been
+// transferred so far. Updates at or below the part's high-water mark are
+// dropped, so replayed or duplicated events cannot inflate the total.
+func (t *progressTracker) setPart(part int, consumed int64) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	prev := t.parts[part]
+	if consumed <= prev {
+		t.mu.Unlock()
+		return
+	}
+	t.parts[part] = consumed
+	t.transferred += consumed - prev
+	transferred := t.transferred
+	t.mu.Unlock()
+	t.reporter.ReportProgress(transferred, t.total)
+}
+
+// putObject stores r under key, streaming byte-level progress when the
+// backend supports it and otherwise reporting the whole part on return.
+func putObject(store ObjectStorage, key string, r io.Reader, size int64, tracker *progressTracker, part int) error {
+	if ps, ok := store.(progressStorage); ok && tracker != nil {
+		return ps.PutObjectWithProgress(key, r, func(consumed int64) {
+			tracker.setPart(part, consumed)
+		})
+	}
+	if err := store.PutObject(key, r); err != nil {
+		return err
+	}
+	tracker.setPart(part, size)
+	return nil
+}
+
+// uploadPart is putObject's multipart counterpart.
+func uploadPart(store ObjectStorage, key, uploadID string, partNumber int, r io.Reader, size int64, tracker *progressTracker) (UploadedPart, error) {
+	if ps, ok := store.(progressStorage); ok && tracker != nil {
+		return ps.UploadPartWithProgress(key, uploadID, partNumber, r, size, func(consumed int64) {
+			tracker.setPart(partNumber, consumed)
+		})
+	}
+	part, err := store.UploadPart(key, uploadID, partNumber, r, size)
+	if err != nil {
+		return UploadedPart{}, err
+	}
+	tracker.setPart(partNumber, size)
+	return part, nil
+}
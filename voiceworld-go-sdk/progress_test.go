@@ -0,0 +1,172 @@
+package voiceworld
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// recordingReporter collects every (transferred, total) pair it receives.
+type recordingReporter struct {
+	mu      sync.Mutex
+	reports [][2]int64
+}
+
+func (r *recordingReporter) ReportProgress(transferred, total int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reports = append(r.reports, [2]int64{transferred, total})
+}
+
+func (r *recordingReporter) snapshot() [][2]int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([][2]int64(nil), r.reports...)
+}
+
+// progressMemoryStorage wraps MemoryStorage with the byte-level progress
+// capability, emitting events the way the OSS SDK does: cumulative consumed
+// bytes per transfer, with the final count repeated on completion.
+type progressMemoryStorage struct {
+	*MemoryStorage
+}
+
+func emitProgress(r io.Reader, progress func(consumed int64)) (io.Reader, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	half := int64(len(data)) / 2
+	if half > 0 {
+		progress(half)
+	}
+	progress(int64(len(data)))
+	progress(int64(len(data))) // completion event repeats the final count
+	return bytes.NewReader(data), nil
+}
+
+func (s *progressMemoryStorage) PutObjectWithProgress(key string, r io.Reader, progress func(consumed int64)) error {
+	buffered, err := emitProgress(r, progress)
+	if err != nil {
+		return err
+	}
+	return s.PutObject(key, buffered)
+}
+
+func (s *progressMemoryStorage) UploadPartWithProgress(key, uploadID string, partNumber int, r io.Reader, size int64, progress func(consumed int64)) (UploadedPart, error) {
+	buffered, err := emitProgress(r, progress)
+	if err != nil {
+		return UploadedPart{}, err
+	}
+	return s.UploadPart(key, uploadID, partNumber, buffered, size)
+}
+
+// checkMonotonic asserts the reports never decrease, never pass total, and
+// end exactly at total (no duplicated bytes).
+func checkMonotonic(t *testing.T, reports [][2]int64, total int64) {
+	t.Helper()
+	if len(reports) == 0 {
+		t.Fatal("no progress reported")
+	}
+	var prev int64
+	for i, rep := range reports {
+		if rep[1] != total {
+			t.Errorf("report %d total = %d, want %d", i, rep[1], total)
+		}
+		if rep[0] <= prev {
+			t.Errorf("report %d transferred = %d, not above previous %d", i, rep[0], prev)
+		}
+		if rep[0] > total {
+			t.Errorf("report %d transferred = %d, exceeds total %d", i, rep[0], total)
+		}
+		prev = rep[0]
+	}
+	if prev != total {
+		t.Errorf("final transferred = %d, want %d", prev, total)
+	}
+}
+
+func TestUploadFileStreamsNativeProgress(t *testing.T) {
+	reporter := &recordingReporter{}
+	store := &progressMemoryStorage{MemoryStorage: NewMemoryStorage()}
+	client, err := NewClient("ak", "sk", &ClientConfig{Storage: store, Progress: reporter})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "a.bin")
+	if err := os.WriteFile(path, bytes.Repeat([]byte{1}, 1000), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.UploadFile(path, "req/a.bin"); err != nil {
+		t.Fatalf("UploadFile: %v", err)
+	}
+	reports := reporter.snapshot()
+	checkMonotonic(t, reports, 1000)
+	if len(reports) != 2 { // half, full; the repeated completion is deduplicated
+		t.Errorf("got %d reports %v, want 2", len(reports), reports)
+	}
+}
+
+func TestUploadFileFallbackReportsWholeObject(t *testing.T) {
+	reporter := &recordingReporter{}
+	client, _ := newTestClient(t, &ClientConfig{Progress: reporter})
+	path := filepath.Join(t.TempDir(), "a.bin")
+	if err := os.WriteFile(path, []byte("some audio bytes"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.UploadFile(path, "req/a.bin"); err != nil {
+		t.Fatalf("UploadFile: %v", err)
+	}
+	reports := reporter.snapshot()
+	if len(reports) != 1 || reports[0] != [2]int64{16, 16} {
+		t.Errorf("reports = %v, want one whole-object update", reports)
+	}
+}
+
+func TestMultipartUploadProgressAcrossConcurrentParts(t *testing.T) {
+	reporter := &recordingReporter{}
+	store := &progressMemoryStorage{MemoryStorage: NewMemoryStorage()}
+	client, err := NewClient("ak", "sk", &ClientConfig{
+		Storage: store, Progress: reporter, ChunkSize: 1024, Parallelism: 3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	size := int64(2*1024 + 512)
+	path := filepath.Join(t.TempDir(), "big.bin")
+	if err := os.WriteFile(path, bytes.Repeat([]byte{2}, int(size)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.MultipartUploadFile(path, "req/big.bin"); err != nil {
+		t.Fatalf("MultipartUploadFile: %v", err)
+	}
+	checkMonotonic(t, reporter.snapshot(), size)
+}
+
+func TestSplitAudioFileReportsOneOperation(t *testing.T) {
+	reporter := &recordingReporter{}
+	store := &progressMemoryStorage{MemoryStorage: NewMemoryStorage()}
+	client, err := NewClient("ak", "sk", &ClientConfig{
+		Storage: store, Progress: reporter, Parallelism: 3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := makeWAVFile(t, 16000, 1, 5)
+
+	result, err := client.SplitAudioFile(path, 2)
+	if err != nil {
+		t.Fatalf("SplitAudioFile: %v", err)
+	}
+	var total int64
+	for _, part := range result.Parts {
+		total += part.Size
+	}
+	checkMonotonic(t, reporter.snapshot(), total)
+}
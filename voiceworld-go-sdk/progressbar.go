@@ -0,0 +1,88 @@
+package voiceworld
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// TerminalProgressReporter is a ProgressReporter that renders a single-line
+// progress bar in place, redrawing on every update. Route concurrent log
+// output through Writer() — e.g. log.SetOutput(reporter.Writer()) — and each
+// log line gets a clean line of its own: the bar is cleared, the line
+// printed, the bar redrawn. Safe for concurrent use.
+type TerminalProgressReporter struct {
+	mu  sync.Mutex
+	out io.Writer
+
+	// active reports a bar line currently on screen; transferred/total
+	// are the last figures drawn, for redraws after log writes.
+	active      bool
+	transferred int64
+	total       int64
+}
+
+// NewTerminalProgressReporter renders to out, typically os.Stderr.
+func NewTerminalProgressReporter(out io.Writer) *TerminalProgressReporter {
+	return &TerminalProgressReporter{out: out}
+}
+
+// ReportProgress implements ProgressReporter. The final update (transferred
+// reaching total) closes the bar line with a newline.
+func (r *TerminalProgressReporter) ReportProgress(transferred, total int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.transferred, r.total = transferred, total
+	r.draw()
+	if total > 0 && transferred >= total {
+		io.WriteString(r.out, "\n")
+		r.active = false
+	}
+}
+
+// draw repaints the bar on the current line. Callers hold r.mu.
+func (r *TerminalProgressReporter) draw() {
+	const width = 30
+	frac := 0.0
+	if r.total > 0 {
+		frac = float64(r.transferred) / float64(r.total)
+		if frac > 1 {
+			frac = 1
+		}
+	}
+	filled := int(frac * width)
+	fmt.Fprintf(r.out, "\r\x1b[2K[%s%s] %5.1f%% (%d/%d bytes)",
+		strings.Repeat("=", filled), strings.Repeat(" ", width-filled),
+		100*frac, r.transferred, r.total)
+	r.active = true
+}
+
+// Writer returns the coordinated log writer: while a bar is on screen each
+// write clears it first and redraws it after, so bar and log output never
+// interleave; with no bar active, writes pass straight through.
+func (r *TerminalProgressReporter) Writer() io.Writer {
+	return &coordinatedWriter{reporter: r}
+}
+
+// coordinatedWriter serializes foreign output with bar redraws.
+type coordinatedWriter struct {
+	reporter *TerminalProgressReporter
+}
+
+func (w *coordinatedWriter) Write(p []byte) (int, error) {
+	r := w.reporter
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	redraw := r.active
+	if redraw {
+		if _, err := io.WriteString(r.out, "\r\x1b[2K"); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.out.Write(p)
+	if err == nil && redraw {
+		r.draw()
+	}
+	return n, err
+}
@@ -0,0 +1,68 @@
+package voiceworld
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const clearLine = "\r\x1b[2K"
+
+func TestTerminalProgressWriterPassesThroughWithoutBar(t *testing.T) {
+	var buf bytes.Buffer
+	reporter := NewTerminalProgressReporter(&buf)
+	if _, err := reporter.Writer().Write([]byte("plain log line\n")); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != "plain log line\n" {
+		t.Errorf("passthrough output = %q", got)
+	}
+}
+
+func TestTerminalProgressClearsBarAroundLogLines(t *testing.T) {
+	var buf bytes.Buffer
+	reporter := NewTerminalProgressReporter(&buf)
+
+	reporter.ReportProgress(50, 100)
+	if _, err := reporter.Writer().Write([]byte("halfway there\n")); err != nil {
+		t.Fatal(err)
+	}
+	reporter.ReportProgress(100, 100)
+	out := buf.String()
+
+	// The log line must appear contiguously, directly after a clear
+	// sequence, and be followed by a bar redraw — never spliced into the
+	// middle of a bar line.
+	i := strings.Index(out, "halfway there\n")
+	if i < 0 {
+		t.Fatalf("log line not contiguous in output %q", out)
+	}
+	if !strings.HasSuffix(out[:i], clearLine) {
+		t.Errorf("log line not preceded by a clear sequence: %q", out[:i])
+	}
+	rest := out[i+len("halfway there\n"):]
+	if !strings.HasPrefix(rest, clearLine+"[") {
+		t.Errorf("bar not redrawn after log line: %q", rest)
+	}
+	// The closed bar ends its line.
+	if !strings.HasSuffix(out, "\n") {
+		t.Errorf("final bar line not terminated: %q", out)
+	}
+	if !strings.Contains(out, "100.0%") {
+		t.Errorf("final redraw missing completion: %q", out)
+	}
+}
+
+func TestTerminalProgressBarRedrawsInPlace(t *testing.T) {
+	var buf bytes.Buffer
+	reporter := NewTerminalProgressReporter(&buf)
+	reporter.ReportProgress(0, 200)
+	reporter.ReportProgress(100, 200)
+	out := buf.String()
+	if strings.Count(out, clearLine) != 2 {
+		t.Errorf("want one clear per redraw, got %q", out)
+	}
+	if strings.Contains(out, "\n") {
+		t.Errorf("unfinished bar emitted a newline: %q", out)
+	}
+}
@@ -0,0 +1,147 @@
+package voiceworld
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// defaultProgressSinkInterval is the snapshot throttle when
+// ClientConfig.ProgressSinkInterval is zero.
+const defaultProgressSinkInterval = time.Second
+
+// ProgressSnapshot is one persisted view of an operation's progress, the
+// record a ProgressSink stores for external display. Seq increases by one
+// per persisted snapshot of the operation, so a reader comparing it against
+// the last value it saw can detect stale or out-of-order reads.
+type ProgressSnapshot struct {
+	Seq        uint64 `json:"seq"`
+	Kind       string `json:"kind"`
+	RequestID  string `json:"request_id,omitempty"`
+	BytesDone  int64  `json:"bytes_done"`
+	BytesTotal int64  `json:"bytes_total"`
+	PartsDone  int    `json:"parts_done"`
+	PartsTotal int    `json:"parts_total"`
+	// Done marks the operation's final snapshot.
+	Done      bool      `json:"done"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ProgressSink persists operation progress where a process other than the
+// uploading one can read it — a database row, a file on shared disk. opID
+// is unique per operation, across processes; the snapshot carries the
+// request id for correlation. Persist is called from transfer goroutines
+// and must be safe for concurrent use across operations; its errors are
+// dropped by the caller.
+type ProgressSink interface {
+	Persist(opID string, snapshot ProgressSnapshot) error
+}
+
+// newOpID labels one operation for ProgressSink persistence. The random
+// suffix keeps uploader processes sharing one sink from overwriting each
+// other's snapshots.
+func newOpID(kind string, id int64) string {
+	var b [4]byte
+	if _, err := randRead(b[:]); err != nil {
+		return fmt.Sprintf("%s-%d-%d", kind, os.Getpid(), id)
+	}
+	return fmt.Sprintf("%s-%d-%s", kind, id, hex.EncodeToString(b[:]))
+}
+
+// persistSnapshot writes the operation's current counters to the sink:
+// always for the final snapshot, and at most once per sinkInterval
+// otherwise. Snapshots are serialized per operation so sequence numbers
+// reach the sink in order.
+func (o *liveOp) persistSnapshot(final bool) {
+	if o == nil || o.sink == nil {
+		return
+	}
+	now := timeNow()
+	if !final {
+		last := o.lastPersist.Load()
+		if now.UnixNano()-last < int64(o.sinkInterval) {
+			return
+		}
+		if !o.lastPersist.CompareAndSwap(last, now.UnixNano()) {
+			return // another goroutine took this tick
+		}
+	}
+	o.persistMu.Lock()
+	defer o.persistMu.Unlock()
+	snapshot := ProgressSnapshot{
+		Seq:        o.seq.Add(1),
+		Kind:       o.kind,
+		BytesDone:  o.bytesDone.Load(),
+		BytesTotal: o.bytesTotal.Load(),
+		PartsDone:  int(o.partsDone.Load()),
+		PartsTotal: int(o.partsTotal.Load()),
+		Done:       final,
+		UpdatedAt:  now.UTC(),
+	}
+	if id, ok := o.requestID.Load().(string); ok {
+		snapshot.RequestID = id
+	}
+	_ = o.sink.Persist(o.opID, snapshot)
+}
+
+// FileProgressSink persists snapshots as one JSON file per operation in a
+// directory, each replaced atomically, so a reader never observes partial
+// JSON no matter when it looks. Point dashboards at the directory, or use
+// Load from a Go reader.
+type FileProgressSink struct {
+	dir string
+}
+
+// NewFileProgressSink persists snapshots under dir, creating it if needed.
+func NewFileProgressSink(dir string) (*FileProgressSink, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("voiceworld: create progress sink dir: %w", err)
+	}
+	return &FileProgressSink{dir: dir}, nil
+}
+
+// Persist implements ProgressSink: the snapshot is written to a fresh temp
+// file and renamed over <opID>.json, so concurrent writers and readers only
+// ever see complete documents.
+func (s *FileProgressSink) Persist(opID string, snapshot ProgressSnapshot) error {
+	if err := validateRequestID(opID); err != nil {
+		return err
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(s.dir, opID+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(s.dir, opID+".json"))
+}
+
+// Load reads the last persisted snapshot of opID.
+func (s *FileProgressSink) Load(opID string) (ProgressSnapshot, error) {
+	var snapshot ProgressSnapshot
+	if err := validateRequestID(opID); err != nil {
+		return snapshot, err
+	}
+	data, err := os.ReadFile(filepath.Join(s.dir, opID+".json"))
+	if err != nil {
+		return snapshot, err
+	}
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return snapshot, fmt.Errorf("voiceworld: progress snapshot for %s is corrupt: %w", opID, err)
+	}
+	return snapshot, nil
+}
@@ -0,0 +1,165 @@
+package voiceworld
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryProgressSink records every persisted snapshot per operation.
+type memoryProgressSink struct {
+	mu    sync.Mutex
+	snaps map[string][]ProgressSnapshot
+}
+
+func newMemoryProgressSink() *memoryProgressSink {
+	return &memoryProgressSink{snaps: map[string][]ProgressSnapshot{}}
+}
+
+func (s *memoryProgressSink) Persist(opID string, snapshot ProgressSnapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.snaps[opID] = append(s.snaps[opID], snapshot)
+	return nil
+}
+
+func (s *memoryProgressSink) all() map[string][]ProgressSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := map[string][]ProgressSnapshot{}
+	for k, v := range s.snaps {
+		out[k] = append([]ProgressSnapshot(nil), v...)
+	}
+	return out
+}
+
+func TestUploadPersistsProgressSnapshots(t *testing.T) {
+	sink := newMemoryProgressSink()
+	client, _ := newTestClient(t, &ClientConfig{
+		ProgressSink:         sink,
+		ProgressSinkInterval: time.Nanosecond,
+	})
+	if _, err := client.UploadFile(makeWAVFile(t, 16000, 1, 0.5), "req-1/a.wav", nil); err != nil {
+		t.Fatalf("UploadFile: %v", err)
+	}
+
+	var got []ProgressSnapshot
+	for opID, snaps := range sink.all() {
+		if strings.HasPrefix(opID, "upload-") {
+			got = snaps
+		}
+	}
+	if len(got) < 2 {
+		t.Fatalf("persisted %d snapshots, want at least first and final", len(got))
+	}
+	for i := 1; i < len(got); i++ {
+		if got[i].Seq != got[i-1].Seq+1 {
+			t.Errorf("seq jumped from %d to %d", got[i-1].Seq, got[i].Seq)
+		}
+	}
+	final := got[len(got)-1]
+	if !final.Done {
+		t.Error("final snapshot not marked done")
+	}
+	if final.BytesDone != final.BytesTotal || final.BytesTotal != 16044 {
+		t.Errorf("final bytes = %d/%d, want 16044/16044", final.BytesDone, final.BytesTotal)
+	}
+	if final.PartsDone != 1 || final.RequestID != "req-1" {
+		t.Errorf("final snapshot = %+v", final)
+	}
+}
+
+func TestSplitPersistsThrottledSnapshots(t *testing.T) {
+	sink := newMemoryProgressSink()
+	client, _ := newTestClient(t, &ClientConfig{
+		ProgressSink:         sink,
+		ProgressSinkInterval: time.Hour, // only the forced snapshots get through
+	})
+	if _, err := client.SplitAudioFile(makeWAVFile(t, 16000, 1, 2), 1, &SplitOptions{RequestID: "req-2"}); err != nil {
+		t.Fatalf("SplitAudioFile: %v", err)
+	}
+
+	var got []ProgressSnapshot
+	for opID, snaps := range sink.all() {
+		if strings.HasPrefix(opID, "split-") {
+			got = snaps
+		}
+	}
+	if len(got) != 2 {
+		t.Fatalf("persisted %d snapshots, want exactly first and final", len(got))
+	}
+	final := got[1]
+	if !final.Done || final.PartsDone != 2 || final.Kind != "split" {
+		t.Errorf("final snapshot = %+v", final)
+	}
+}
+
+func TestFileProgressSinkAtomicUnderRapidUpdates(t *testing.T) {
+	dir := t.TempDir()
+	sink, err := NewFileProgressSink(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const opID = "upload-1-test"
+	path := filepath.Join(dir, opID+".json")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 1; i <= 400; i++ {
+			if err := sink.Persist(opID, ProgressSnapshot{Seq: uint64(i), BytesDone: int64(i)}); err != nil {
+				t.Errorf("Persist: %v", err)
+				return
+			}
+		}
+	}()
+	// Every concurrent read must see a complete JSON document: the rename
+	// either happened or it did not.
+	var lastSeq uint64
+	for {
+		select {
+		case <-done:
+			snapshot, err := sink.Load(opID)
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if snapshot.Seq != 400 {
+				t.Errorf("final seq = %d, want 400", snapshot.Seq)
+			}
+			if leftovers, _ := filepath.Glob(filepath.Join(dir, "*.tmp")); len(leftovers) != 0 {
+				t.Errorf("temp files left behind: %v", leftovers)
+			}
+			return
+		default:
+			data, err := os.ReadFile(path)
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				t.Fatalf("read snapshot: %v", err)
+			}
+			var snapshot ProgressSnapshot
+			if err := json.Unmarshal(data, &snapshot); err != nil {
+				t.Fatalf("partial snapshot observed: %q", data)
+			}
+			if snapshot.Seq < lastSeq {
+				t.Fatalf("seq went backwards: %d after %d", snapshot.Seq, lastSeq)
+			}
+			lastSeq = snapshot.Seq
+		}
+	}
+}
+
+func TestFileProgressSinkRejectsPathEscapes(t *testing.T) {
+	sink, err := NewFileProgressSink(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Persist("../escape", ProgressSnapshot{}); err == nil {
+		t.Error("path-escaping op id accepted")
+	}
+}
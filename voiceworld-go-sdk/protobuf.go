@@ -0,0 +1,48 @@
+package voiceworld
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/voiceworld/voiceworld-go-sdk/internal/asrpb"
+)
+
+// contentTypeProtobuf is the binary response mode of the recognition
+// endpoints; see ClientConfig.PreferProtobuf.
+const contentTypeProtobuf = "application/x-protobuf"
+
+// decodeProtobufResult decodes a binary response envelope into out, which
+// must be a *RecognitionResult - the only response type the binary mode
+// covers.
+func decodeProtobufResult(raw []byte, out interface{}) error {
+	env, err := asrpb.UnmarshalEnvelope(raw)
+	if err != nil {
+		return fmt.Errorf("voiceworld: %w", err)
+	}
+	if env.Code != 0 {
+		return &APIError{Code: int(env.Code), Message: env.Message}
+	}
+	if out == nil || env.Result == nil {
+		return nil
+	}
+	result, ok := out.(*RecognitionResult)
+	if !ok {
+		return fmt.Errorf("voiceworld: server sent protobuf for %T, which has no binary decoding", out)
+	}
+	// The binary schema carries times as integer milliseconds.
+	*result = RecognitionResult{
+		TaskID:   env.Result.TaskID,
+		Result:   env.Result.Text,
+		Duration: NewTimestamp(time.Duration(env.Result.Duration) * time.Millisecond),
+	}
+	for _, w := range env.Result.Words {
+		result.Words = append(result.Words, Word{
+			Text:       w.Text,
+			BeginTime:  NewTimestamp(time.Duration(w.BeginTime) * time.Millisecond),
+			EndTime:    NewTimestamp(time.Duration(w.EndTime) * time.Millisecond),
+			Confidence: w.Confidence,
+			SpeakerID:  w.SpeakerID,
+		})
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package voiceworld
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/voiceworld/voiceworld-go-sdk/internal/asrpb"
+)
+
+// protoEnvelope builds a binary fixture equivalent to the canonical JSON one.
+func protoEnvelope() []byte {
+	return asrpb.AppendEnvelope(nil, &asrpb.Envelope{
+		Code:    0,
+		Message: "success",
+		Result: &asrpb.Result{
+			TaskID:   "task-9",
+			Text:     "hello proto",
+			Duration: 2100,
+			Words: []asrpb.Word{
+				{Text: "hello", BeginTime: 0, EndTime: 900, Confidence: 0.97, SpeakerID: "spk1"},
+				{Text: "proto", BeginTime: 950, EndTime: 2100, Confidence: 0.84, SpeakerID: "spk1"},
+			},
+		},
+	})
+}
+
+const jsonEnvelope = `{"code":0,"message":"success","data":{
+	"task_id":"task-9","result":"hello proto","duration":2100,
+	"words":[
+		{"text":"hello","begin_time":0,"end_time":900,"confidence":0.97,"speaker_id":"spk1"},
+		{"text":"proto","begin_time":950,"end_time":2100,"confidence":0.84,"speaker_id":"spk1"}
+	]}}`
+
+// TestProtobufAndJSONDecodeIdentically is the conformance check: the same
+// logical response through either wire format must produce identical structs.
+func TestProtobufAndJSONDecodeIdentically(t *testing.T) {
+	var fromJSON RecognitionResult
+	var env apiEnvelope
+	if err := json.Unmarshal([]byte(jsonEnvelope), &env); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(env.Data, &fromJSON); err != nil {
+		t.Fatal(err)
+	}
+
+	var fromProto RecognitionResult
+	if err := decodeProtobufResult(protoEnvelope(), &fromProto); err != nil {
+		t.Fatalf("decodeProtobufResult: %v", err)
+	}
+
+	if !reflect.DeepEqual(fromJSON, fromProto) {
+		t.Errorf("decoded structs differ:\n json: %+v\nproto: %+v", fromJSON, fromProto)
+	}
+}
+
+func TestPreferProtobufNegotiation(t *testing.T) {
+	var gotAccept string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAccept = r.Header.Get("Accept")
+		if gotAccept == contentTypeProtobuf {
+			w.Header().Set("Content-Type", contentTypeProtobuf)
+			w.Write(protoEnvelope())
+			return
+		}
+		fmt.Fprint(w, jsonEnvelope)
+	}))
+	defer srv.Close()
+
+	client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: srv.URL, PreferProtobuf: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := client.RecognizeURL("https://example.com/a.wav", nil)
+	if err != nil {
+		t.Fatalf("RecognizeURL: %v", err)
+	}
+	if gotAccept != contentTypeProtobuf {
+		t.Errorf("Accept = %q, want %q", gotAccept, contentTypeProtobuf)
+	}
+	if result.Result != "hello proto" || len(result.Words) != 2 {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+// TestProtobufFallsBackToJSON covers mixed fleets: the client asks for
+// protobuf but the server only speaks JSON.
+func TestProtobufFallsBackToJSON(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, jsonEnvelope)
+	}))
+	defer srv.Close()
+
+	client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: srv.URL, PreferProtobuf: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := client.RecognizeURL("https://example.com/a.wav", nil)
+	if err != nil {
+		t.Fatalf("RecognizeURL: %v", err)
+	}
+	if result.TaskID != "task-9" {
+		t.Errorf("TaskID = %q", result.TaskID)
+	}
+}
+
+func TestProtobufErrorEnvelope(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentTypeProtobuf)
+		w.Write(asrpb.AppendEnvelope(nil, &asrpb.Envelope{Code: 40001, Message: "invalid audio"}))
+	}))
+	defer srv.Close()
+
+	client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: srv.URL, PreferProtobuf: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.RecognizeURL("https://example.com/a.wav", nil)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != 40001 {
+		t.Errorf("err = %v, want APIError 40001", err)
+	}
+}
@@ -0,0 +1,117 @@
+package voiceworld
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newScriptedOSSServer fakes just enough of the OSS wire protocol for a
+// PutObject: every request signed with rejectAK fails with the given error
+// code, everything else succeeds.
+func newScriptedOSSServer(t *testing.T, rejectAK, code string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var puts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			puts.Add(1)
+		}
+		io.Copy(io.Discard, r.Body)
+		if strings.Contains(r.Header.Get("Authorization"), rejectAK) {
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`<?xml version="1.0"?><Error><Code>` + code +
+				`</Code><Message>scripted rejection</Message><RequestId>test-request</RequestId><HostId>stage.local</HostId></Error>`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server, &puts
+}
+
+func ossTestCreds(serverURL, ak string) OSSCredentials {
+	return OSSCredentials{
+		AccessKeyID:     ak,
+		AccessKeySecret: "sk",
+		Bucket:          "stage",
+		Endpoint:        serverURL,
+		Expiration:      expiringAt(time.Hour),
+	}
+}
+
+func TestUploadReauthenticatesOnExpiredCredentials(t *testing.T) {
+	server, puts := newScriptedOSSServer(t, "stale-ak", "InvalidAccessKeyId")
+	inner := &scriptedTokenProvider{creds: []OSSCredentials{
+		ossTestCreds(server.URL, "stale-ak"),
+		ossTestCreds(server.URL, "fresh-ak"),
+	}}
+	client, err := NewClient("ak", "sk", &ClientConfig{
+		TokenProvider: NewCachingTokenProvider(inner),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	url, err := client.UploadFile(makeWAVFile(t, 16000, 1, 0.1), "req/audio.wav")
+	if err != nil {
+		t.Fatalf("UploadFile did not recover from expired credentials: %v", err)
+	}
+	if url == "" {
+		t.Error("recovered upload returned no signed URL")
+	}
+	if inner.calls != 2 {
+		t.Errorf("token provider asked %d times, want 2 (initial + refresh)", inner.calls)
+	}
+	if got := puts.Load(); got != 2 {
+		t.Errorf("server saw %d PUTs, want 2 (rejected + retried)", got)
+	}
+}
+
+func TestUploadDoesNotReauthenticateOnOtherFailures(t *testing.T) {
+	server, puts := newScriptedOSSServer(t, "only-ak", "AccessDenied")
+	inner := &scriptedTokenProvider{creds: []OSSCredentials{
+		ossTestCreds(server.URL, "only-ak"),
+	}}
+	client, err := NewClient("ak", "sk", &ClientConfig{
+		TokenProvider: NewCachingTokenProvider(inner),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.UploadFile(makeWAVFile(t, 16000, 1, 0.1), "req/audio.wav")
+	if !errors.Is(err, ErrOSSAccessDenied) {
+		t.Fatalf("err = %v, want ErrOSSAccessDenied", err)
+	}
+	if inner.calls != 1 {
+		t.Errorf("token provider asked %d times, want 1", inner.calls)
+	}
+	if got := puts.Load(); got != 1 {
+		t.Errorf("server saw %d PUTs, want 1 (no blind retry)", got)
+	}
+}
+
+func TestCachingTokenProviderInvalidate(t *testing.T) {
+	inner := &scriptedTokenProvider{creds: []OSSCredentials{
+		{AccessKeyID: "first", Expiration: expiringAt(time.Hour)},
+		{AccessKeyID: "second", Expiration: expiringAt(time.Hour)},
+	}}
+	p := NewCachingTokenProvider(inner)
+	if _, err := p.OSSCredentials(nil); err != nil {
+		t.Fatal(err)
+	}
+	p.(CredentialInvalidator).InvalidateCredentials()
+	creds, err := p.OSSCredentials(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if creds.AccessKeyID != "second" {
+		t.Errorf("post-invalidate credentials = %q, want the refreshed set", creds.AccessKeyID)
+	}
+}
@@ -0,0 +1,150 @@
+package voiceworld
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// PartReport records the outcome of one part upload within an operation.
+type PartReport struct {
+	// Index is the 1-based part number.
+	Index int `json:"index"`
+	// Bytes is the part's payload size.
+	Bytes int64 `json:"bytes"`
+	// Duration covers the part's upload including all of its retries.
+	Duration time.Duration `json:"duration"`
+	// Retries is how many times the part was reattempted.
+	Retries int `json:"retries"`
+	// Error is the part's terminal failure, empty on success.
+	Error string `json:"error,omitempty"`
+}
+
+// OperationReport describes how one upload operation (UploadFile,
+// MultipartUploadFile or SplitAudioFile) spent its time: per-phase
+// timings, part-level outcomes and credential activity. It is a
+// single-operation artifact meant to be archived next to the audio —
+// JSON-serializable and free of secrets — not a metrics feed. Durations
+// serialize as nanoseconds.
+type OperationReport struct {
+	// Operation names the flow: "upload_file", "multipart_upload_file"
+	// or "split_audio_file".
+	Operation string `json:"operation"`
+	// RequestID groups the operation's staged objects, when it has one.
+	RequestID string `json:"request_id,omitempty"`
+
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+
+	// HeaderParse is the time spent parsing the source WAV header
+	// (SplitAudioFile only).
+	HeaderParse time.Duration `json:"header_parse,omitempty"`
+	// Validation is the time spent validating the plan before any bytes
+	// moved (SplitAudioFile only).
+	Validation time.Duration `json:"validation,omitempty"`
+
+	// CredentialRefreshes counts fetches from the TokenProvider during
+	// the operation. Always zero with an injected Storage.
+	CredentialRefreshes int `json:"credential_refreshes"`
+
+	// Error is the operation's terminal failure, empty on success.
+	Error string `json:"error,omitempty"`
+
+	// Parts holds one entry per attempted part, ordered by index.
+	Parts []PartReport `json:"parts,omitempty"`
+
+	// CallbackResponse is the object store's response to the configured
+	// upload callback; see UploadOptions.Callback.
+	CallbackResponse []byte `json:"callback_response,omitempty"`
+
+	// Checksum is the digest of the uploaded file, computed while it
+	// streamed; see UploadOptions.Checksum.
+	Checksum *ChecksumValue `json:"checksum,omitempty"`
+
+	mu sync.Mutex `json:"-"`
+}
+
+// addPart records one finished part; parts complete concurrently.
+func (r *OperationReport) addPart(part PartReport) {
+	r.mu.Lock()
+	r.Parts = append(r.Parts, part)
+	r.mu.Unlock()
+}
+
+// addCredentialRefresh counts one TokenProvider fetch.
+func (r *OperationReport) addCredentialRefresh() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.CredentialRefreshes++
+	r.mu.Unlock()
+}
+
+// setChecksum records the digest of the operation's uploaded file.
+func (r *OperationReport) setChecksum(v ChecksumValue) {
+	r.mu.Lock()
+	r.Checksum = &v
+	r.mu.Unlock()
+}
+
+// setCallbackResponse records the callback response of the operation's
+// final transfer.
+func (r *OperationReport) setCallbackResponse(body []byte) {
+	r.mu.Lock()
+	r.CallbackResponse = body
+	r.mu.Unlock()
+}
+
+// newOperationReport opens the report for one operation.
+func (c *Client) newOperationReport(operation string) *OperationReport {
+	return &OperationReport{Operation: operation, StartedAt: c.now().UTC()}
+}
+
+// finishReport stamps the end time, orders the parts and hands the report
+// to the OnComplete callback.
+func (c *Client) finishReport(report *OperationReport, err error) {
+	report.FinishedAt = c.now().UTC()
+	if err != nil {
+		report.Error = err.Error()
+	}
+	sort.Slice(report.Parts, func(i, j int) bool {
+		return report.Parts[i].Index < report.Parts[j].Index
+	})
+	if c.config.OnComplete != nil {
+		c.config.OnComplete(report)
+	}
+}
+
+// uploadOp bundles the state threaded through one upload operation's
+// helpers: byte progress, the shared retry budget, the report and the
+// operation's registry entry.
+type uploadOp struct {
+	tracker  *progressTracker
+	budget   *retryBudget
+	report   *OperationReport
+	callback *OSSCallback
+	status   *liveOp
+
+	// ifNoneMatch forbids overwriting an existing object; see
+	// UploadOptions.IfNoneMatch.
+	ifNoneMatch bool
+
+	// checksum, when set, has the upload digest its stream; see
+	// UploadOptions.Checksum.
+	checksum Checksummer
+
+	// source, when set, is attached to the object as audit metadata; see
+	// UploadOptions.Source.
+	source *SourceInfo
+}
+
+// warnf delivers one formatted warning to ClientConfig.OnWarning; without a
+// hook the warning is dropped.
+func (c *Client) warnf(format string, args ...interface{}) {
+	if c.config.OnWarning == nil {
+		return
+	}
+	c.config.OnWarning("voiceworld: " + fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,157 @@
+package voiceworld
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// stepClock returns a now func that advances by step on every call, so
+// every measured phase in a sequential operation lasts exactly one step.
+func stepClock(start time.Time, step time.Duration) func() time.Time {
+	var mu sync.Mutex
+	t := start
+	return func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		now := t
+		t = t.Add(step)
+		return now
+	}
+}
+
+func TestSplitAudioFileOperationReport(t *testing.T) {
+	var delivered *OperationReport
+	client, _ := newTestClient(t, &ClientConfig{
+		OnComplete: func(r *OperationReport) { delivered = r },
+	})
+	client.now = stepClock(time.Date(2026, 8, 30, 12, 0, 0, 0, time.UTC), 10*time.Millisecond)
+
+	result, err := client.SplitAudioFile(makeWAVFile(t, 16000, 1, 5), 2)
+	if err != nil {
+		t.Fatalf("SplitAudioFile: %v", err)
+	}
+	report := result.Report
+	if report == nil || report != delivered {
+		t.Fatal("report not attached to the result and delivered to OnComplete")
+	}
+	if report.Operation != "split_audio_file" || report.RequestID != result.RequestID {
+		t.Errorf("report header = %q/%q", report.Operation, report.RequestID)
+	}
+	if report.HeaderParse != 10*time.Millisecond || report.Validation != 10*time.Millisecond {
+		t.Errorf("phase timings = %v/%v, want one clock step each", report.HeaderParse, report.Validation)
+	}
+	if !report.FinishedAt.After(report.StartedAt) {
+		t.Errorf("timestamps = %v .. %v", report.StartedAt, report.FinishedAt)
+	}
+	if len(report.Parts) != 3 {
+		t.Fatalf("got %d part reports, want 3", len(report.Parts))
+	}
+	for i, part := range report.Parts {
+		if part.Index != i+1 || part.Retries != 0 || part.Error != "" {
+			t.Errorf("part %d = %+v", i, part)
+		}
+		if part.Duration != 10*time.Millisecond {
+			t.Errorf("part %d duration = %v, want one clock step", i, part.Duration)
+		}
+		if part.Bytes != result.Parts[i].Size {
+			t.Errorf("part %d bytes = %d, want %d", i, part.Bytes, result.Parts[i].Size)
+		}
+	}
+	// Injected storage: no credentials were ever fetched.
+	if report.CredentialRefreshes != 0 {
+		t.Errorf("CredentialRefreshes = %d, want 0", report.CredentialRefreshes)
+	}
+	if _, err := json.Marshal(report); err != nil {
+		t.Errorf("report is not JSON-serializable: %v", err)
+	}
+}
+
+func TestOperationReportRecordsRetries(t *testing.T) {
+	var delivered *OperationReport
+	store := &flakyStorage{MemoryStorage: NewMemoryStorage(), failed: map[int]bool{}}
+	client, err := NewClient("ak", "sk", &ClientConfig{
+		Storage:    store,
+		ChunkSize:  1024,
+		Retry:      FixedAttempts{Retries: 2, Delay: time.Millisecond},
+		OnComplete: func(r *OperationReport) { delivered = r },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "big.bin")
+	if err := os.WriteFile(path, make([]byte, 2*1024+512), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.MultipartUploadFile(path, "req/big.bin"); err != nil {
+		t.Fatalf("MultipartUploadFile: %v", err)
+	}
+	if delivered == nil || delivered.Operation != "multipart_upload_file" || delivered.Error != "" {
+		t.Fatalf("report = %+v", delivered)
+	}
+	if len(delivered.Parts) != 3 {
+		t.Fatalf("got %d part reports, want 3", len(delivered.Parts))
+	}
+	for i, part := range delivered.Parts {
+		if part.Retries != 1 {
+			t.Errorf("part %d retries = %d, want 1", i, part.Retries)
+		}
+	}
+}
+
+func TestOperationReportCountsCredentialRefreshes(t *testing.T) {
+	var delivered *OperationReport
+	client, err := NewClient("ak", "sk", &ClientConfig{
+		TokenProvider: StaticTokenProvider(OSSCredentials{Bucket: "b"}),
+		StorageConfig: &StorageConfig{Provider: "fs", RootDir: t.TempDir()},
+		OnComplete:    func(r *OperationReport) { delivered = r },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "a.bin")
+	if err := os.WriteFile(path, []byte("audio"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.UploadFile(path, "req/a.bin"); err != nil {
+		t.Fatalf("UploadFile: %v", err)
+	}
+	if delivered.CredentialRefreshes != 1 {
+		t.Errorf("CredentialRefreshes = %d, want 1", delivered.CredentialRefreshes)
+	}
+}
+
+func TestOperationReportOnFailure(t *testing.T) {
+	var delivered *OperationReport
+	client, err := NewClient("ak", "sk", &ClientConfig{
+		Storage:    &brokenPartStorage{MemoryStorage: NewMemoryStorage()},
+		ChunkSize:  1024,
+		OnComplete: func(r *OperationReport) { delivered = r },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "big.bin")
+	if err := os.WriteFile(path, make([]byte, 2048), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.MultipartUploadFile(path, "req/big.bin"); err == nil {
+		t.Fatal("expected upload failure")
+	}
+	if delivered == nil || delivered.Error == "" {
+		t.Fatalf("report = %+v, want the failure recorded", delivered)
+	}
+	failed := 0
+	for _, part := range delivered.Parts {
+		if part.Error != "" {
+			failed++
+		}
+	}
+	if failed == 0 {
+		t.Error("no part-level failure recorded")
+	}
+}
@@ -0,0 +1,88 @@
+package voiceworld
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewRequestIDUniqueUnderParallelGeneration(t *testing.T) {
+	const perGoroutine = 200
+	var (
+		mu  sync.Mutex
+		ids []string
+		wg  sync.WaitGroup
+	)
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			local := make([]string, 0, perGoroutine)
+			for i := 0; i < perGoroutine; i++ {
+				local = append(local, NewRequestID())
+			}
+			mu.Lock()
+			ids = append(ids, local...)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			t.Fatalf("duplicate request id %q", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestNewRequestIDSortsByCreationTime(t *testing.T) {
+	first := NewRequestID()
+	time.Sleep(5 * time.Millisecond)
+	second := NewRequestID()
+	if !sort.StringsAreSorted([]string{first, second}) {
+		t.Errorf("ids not in creation order: %q then %q", first, second)
+	}
+	if err := validateRequestID(first); err != nil {
+		t.Errorf("generated id %q fails validation: %v", first, err)
+	}
+}
+
+func TestValidateRequestID(t *testing.T) {
+	valid := []string{"req-1", "Batch_2026.08", "a"}
+	for _, id := range valid {
+		if err := validateRequestID(id); err != nil {
+			t.Errorf("validateRequestID(%q) = %v, want nil", id, err)
+		}
+	}
+	invalid := []string{"", "a/b", `a\b`, "..", "up..dir", "id with space", "id#1"}
+	for _, id := range invalid {
+		if err := validateRequestID(id); err == nil {
+			t.Errorf("validateRequestID(%q) accepted", id)
+		}
+	}
+}
+
+func TestSplitHonorsSuppliedRequestID(t *testing.T) {
+	client, _ := newTestClient(t, nil)
+	audio := makeWAVFile(t, 16000, 1, 2)
+
+	result, err := client.SplitAudioFile(audio, 1, &SplitOptions{RequestID: "batch-42"})
+	if err != nil {
+		t.Fatalf("SplitAudioFile: %v", err)
+	}
+	if result.RequestID != "batch-42" {
+		t.Errorf("RequestID = %q, want %q", result.RequestID, "batch-42")
+	}
+	for _, part := range result.Parts {
+		if !strings.HasPrefix(part.ObjectKey, "batch-42/") {
+			t.Errorf("part key %q not under supplied prefix", part.ObjectKey)
+		}
+	}
+
+	if _, err := client.SplitAudioFile(audio, 1, &SplitOptions{RequestID: "../escape"}); err == nil {
+		t.Error("path-escaping request id accepted")
+	}
+}
@@ -0,0 +1,91 @@
+package voiceworld
+
+import (
+	"net/url"
+	"strings"
+)
+
+// keyedURLStorage is the optional capability behind expired-URL recovery: a
+// backend that can recognize URLs it signed itself and name the object key
+// inside them. URLs pointing anywhere else report ok false and pass through
+// untouched.
+type keyedURLStorage interface {
+	ObjectKeyFromURL(rawURL string) (key string, ok bool)
+}
+
+// objectKeyFromSignedURL extracts the object key a signed URL names, when
+// the URL points into the given bucket. It understands the URL shapes the
+// backends produce: virtual-hosted (bucket.endpoint/key), path-style
+// (endpoint/bucket/key) and a custom base (base/key, the CNAME case). The
+// query string — where the stale signature lives — is discarded.
+func objectKeyFromSignedURL(rawURL, bucket, endpoint, base string) (string, bool) {
+	if base != "" {
+		prefix := strings.TrimRight(base, "/") + "/"
+		if strings.HasPrefix(rawURL, prefix) {
+			key := strings.TrimPrefix(rawURL, prefix)
+			if i := strings.IndexByte(key, '?'); i >= 0 {
+				key = key[:i]
+			}
+			return key, key != ""
+		}
+		return "", false
+	}
+	if bucket == "" || endpoint == "" {
+		return "", false
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", false
+	}
+	key := strings.TrimPrefix(u.Path, "/")
+	host := endpointHost(endpoint)
+	switch u.Host {
+	case bucket + "." + host: // virtual-hosted style
+		return key, key != ""
+	case host: // path style
+		if strings.HasPrefix(key, bucket+"/") {
+			key = key[len(bucket)+1:]
+			return key, key != ""
+		}
+	}
+	return "", false
+}
+
+// resignAudioURL replaces a URL the SDK signed earlier with a freshly signed
+// one for the same object, so a submission that sat in a queue for an hour
+// does not hand the server an expired link. The returned key is empty — and
+// the URL unchanged — when the URL is foreign, the backend cannot match
+// URLs, or re-signing failed.
+func (c *Client) resignAudioURL(audioURL string) (string, string) {
+	store, err := c.storage()
+	if err != nil {
+		return audioURL, ""
+	}
+	ks, ok := store.(keyedURLStorage)
+	if !ok {
+		return audioURL, ""
+	}
+	key, ok := ks.ObjectKeyFromURL(audioURL)
+	if !ok {
+		return audioURL, ""
+	}
+	fresh, _, err := c.signStorageURL(store, key, signedURLExpiry)
+	if err != nil {
+		return audioURL, ""
+	}
+	return fresh, key
+}
+
+// isAudioFetchDenied recognizes the server's report that it could not pull
+// the submitted URL: the bucket answered its fetch with a 403 because the
+// signature expired or never authorized the read.
+func isAudioFetchDenied(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "audio fetch forbidden") ||
+		strings.Contains(msg, "audio fetch expired") ||
+		strings.Contains(msg, "signature expired") ||
+		strings.Contains(msg, "url expired")
+}
@@ -0,0 +1,161 @@
+package voiceworld
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestObjectKeyFromSignedURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		bucket   string
+		endpoint string
+		base     string
+		wantKey  string
+		wantOK   bool
+	}{
+		{
+			name:     "virtual-hosted",
+			url:      "https://stage.oss-cn-hangzhou.aliyuncs.com/req-1/a.wav?Expires=1&Signature=x",
+			bucket:   "stage",
+			endpoint: "https://oss-cn-hangzhou.aliyuncs.com",
+			wantKey:  "req-1/a.wav",
+			wantOK:   true,
+		},
+		{
+			name:     "path style",
+			url:      "https://oss-cn-hangzhou.aliyuncs.com/stage/req-1/a.wav?sig=y",
+			bucket:   "stage",
+			endpoint: "https://oss-cn-hangzhou.aliyuncs.com",
+			wantKey:  "req-1/a.wav",
+			wantOK:   true,
+		},
+		{
+			name:    "cname base",
+			url:     "https://audio.example.com/req-1/a.wav?Expires=1",
+			base:    "https://audio.example.com",
+			wantKey: "req-1/a.wav",
+			wantOK:  true,
+		},
+		{
+			name:     "foreign host",
+			url:      "https://elsewhere.example.com/req-1/a.wav",
+			bucket:   "stage",
+			endpoint: "https://oss-cn-hangzhou.aliyuncs.com",
+		},
+		{
+			name:     "other bucket",
+			url:      "https://other.oss-cn-hangzhou.aliyuncs.com/req-1/a.wav",
+			bucket:   "stage",
+			endpoint: "https://oss-cn-hangzhou.aliyuncs.com",
+		},
+		{
+			name:     "path style for another bucket",
+			url:      "https://oss-cn-hangzhou.aliyuncs.com/other/a.wav",
+			bucket:   "stage",
+			endpoint: "https://oss-cn-hangzhou.aliyuncs.com",
+		},
+		{
+			name:     "bucket root without key",
+			url:      "https://stage.oss-cn-hangzhou.aliyuncs.com/",
+			bucket:   "stage",
+			endpoint: "https://oss-cn-hangzhou.aliyuncs.com",
+		},
+		{
+			name: "cname mismatch",
+			url:  "https://cdn.example.com/req-1/a.wav",
+			base: "https://audio.example.com",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, ok := objectKeyFromSignedURL(tt.url, tt.bucket, tt.endpoint, tt.base)
+			if key != tt.wantKey || ok != tt.wantOK {
+				t.Errorf("objectKeyFromSignedURL = (%q, %v), want (%q, %v)", key, ok, tt.wantKey, tt.wantOK)
+			}
+		})
+	}
+}
+
+// recognizeURLServer answers /asr, failing the first failures calls with an
+// audio-fetch error, and records every submitted url.
+func recognizeURLServer(t *testing.T, failures int) (*httptest.Server, *[]string) {
+	t.Helper()
+	var urls []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		url, _ := body["url"].(string)
+		urls = append(urls, url)
+		if len(urls) <= failures {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"code":    403,
+				"message": "audio fetch forbidden: url expired",
+			})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"code": 0,
+			"data": map[string]string{"result": "ok"},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server, &urls
+}
+
+func TestRecognizeURLResignsOwnURLs(t *testing.T) {
+	server, urls := recognizeURLServer(t, 0)
+	client, _ := newTestClient(t, &ClientConfig{BaseURL: server.URL})
+
+	stale := "memory://bucket/req-1/a.wav?Expires=1&Signature=old"
+	if _, err := client.RecognizeURL(stale, nil); err != nil {
+		t.Fatalf("RecognizeURL: %v", err)
+	}
+	if len(*urls) != 1 || (*urls)[0] != "memory://bucket/req-1/a.wav" {
+		t.Errorf("submitted urls = %v, want the re-signed url", *urls)
+	}
+}
+
+func TestRecognizeURLPassesForeignURLsThrough(t *testing.T) {
+	server, urls := recognizeURLServer(t, 0)
+	client, _ := newTestClient(t, &ClientConfig{BaseURL: server.URL})
+
+	foreign := "https://elsewhere.example.com/a.wav?token=abc"
+	if _, err := client.RecognizeURL(foreign, nil); err != nil {
+		t.Fatalf("RecognizeURL: %v", err)
+	}
+	if len(*urls) != 1 || (*urls)[0] != foreign {
+		t.Errorf("submitted urls = %v, want %q untouched", *urls, foreign)
+	}
+}
+
+func TestRecognizeURLRetriesExpiredFetchOnce(t *testing.T) {
+	server, urls := recognizeURLServer(t, 1)
+	client, _ := newTestClient(t, &ClientConfig{BaseURL: server.URL})
+
+	result, err := client.RecognizeURL("memory://bucket/req-1/a.wav", nil)
+	if err != nil {
+		t.Fatalf("RecognizeURL: %v", err)
+	}
+	if result.Result != "ok" {
+		t.Errorf("Result = %q, want ok", result.Result)
+	}
+	if len(*urls) != 2 {
+		t.Errorf("server saw %d submissions, want 2", len(*urls))
+	}
+
+	// A foreign URL gets no retry: the SDK cannot mint it a new signature.
+	server2, urls2 := recognizeURLServer(t, 1)
+	client2, _ := newTestClient(t, &ClientConfig{BaseURL: server2.URL})
+	if _, err := client2.RecognizeURL("https://elsewhere.example.com/a.wav", nil); err == nil {
+		t.Fatal("expected the fetch error to surface for a foreign URL")
+	}
+	if len(*urls2) != 1 {
+		t.Errorf("server saw %d submissions for a foreign URL, want 1", len(*urls2))
+	}
+}
@@ -0,0 +1,62 @@
+package voiceworld
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestResponseTooLarge(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":0,"message":"success","data":{"padding":"`))
+		chunk := strings.Repeat("x", 1024)
+		for i := 0; i < 64; i++ {
+			w.Write([]byte(chunk))
+		}
+		w.Write([]byte(`"}}`))
+	}))
+	t.Cleanup(server.Close)
+	client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: server.URL, MaxResponseSize: 4096})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.GetOSSToken()
+	if !errors.Is(err, ErrResponseTooLarge) {
+		t.Fatalf("err = %v, want ErrResponseTooLarge", err)
+	}
+	var tooLarge *ResponseTooLargeError
+	if !errors.As(err, &tooLarge) || tooLarge.Limit != 4096 {
+		t.Errorf("err = %#v, want *ResponseTooLargeError with the 4096 limit", err)
+	}
+}
+
+func TestResponseUnderLimitPasses(t *testing.T) {
+	server := newEnvelopeServer(t, `{"code":0,"message":"success","data":{"task_id":"t","result":"ok"}}`)
+	client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: server.URL, MaxResponseSize: 4096})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := client.RecognizeURL("https://example.com/a.wav", nil)
+	if err != nil {
+		t.Fatalf("RecognizeURL: %v", err)
+	}
+	if result.Result != "ok" {
+		t.Errorf("Result = %q, want ok", result.Result)
+	}
+}
+
+func TestVerifyManifestBoundsDownload(t *testing.T) {
+	client, store := newTestClient(t, &ClientConfig{MaxResponseSize: 128})
+	huge := fmt.Sprintf(`{"version":1,"request_id":"req","parts":[],"signature":"%s"}`,
+		strings.Repeat("a", 512))
+	if err := store.PutObject(manifestKey("req"), strings.NewReader(huge)); err != nil {
+		t.Fatal(err)
+	}
+	_, err := client.VerifyManifest("req")
+	if !errors.Is(err, ErrResponseTooLarge) {
+		t.Fatalf("err = %v, want ErrResponseTooLarge", err)
+	}
+}
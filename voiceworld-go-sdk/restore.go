@@ -0,0 +1,179 @@
+package voiceworld
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// ErrObjectArchived reports an object that transitioned to Archive storage
+// class and must be restored before it can be read or recognized. It is
+// wrapped with the offending key or URL; test with errors.Is, then trigger
+// RestoreObject and wait with WaitForRestore.
+var ErrObjectArchived = errors.New("voiceworld: object archived; restore required before access")
+
+// restoreStorage is the optional capability behind RestoreObject and
+// WaitForRestore. The OSS backend has it; injected fakes can implement it
+// to simulate the restore state machine.
+type restoreStorage interface {
+	// RestoreObject asks the backend to thaw key for days days.
+	RestoreObject(key string, days int) error
+	// RestoreDone reports whether key is readable: true for normal-class
+	// objects and completed restores, false while a restore is running or
+	// none has been requested yet.
+	RestoreDone(key string) (bool, error)
+}
+
+// RestoreObject asks the storage backend to thaw an Archive-class object
+// for days days (at least 1). The restore is asynchronous; poll with
+// WaitForRestore before reading the object.
+func (c *Client) RestoreObject(key string, days int) error {
+	if days < 1 {
+		days = 1
+	}
+	store, err := c.storage()
+	if err != nil {
+		return err
+	}
+	rs, ok := store.(restoreStorage)
+	if !ok {
+		return fmt.Errorf("voiceworld: storage backend does not support archive restore")
+	}
+	if err := rs.RestoreObject(key, days); err != nil {
+		return fmt.Errorf("voiceworld: restore %s: %w", key, err)
+	}
+	return nil
+}
+
+// WaitForRestore polls the backend every pollInterval until the restore of
+// key completes, or fails once maxWait has passed. pollInterval <= 0 polls
+// every 30 seconds; maxWait <= 0 allows 24 hours, comfortably above OSS's
+// worst advertised thaw time.
+func (c *Client) WaitForRestore(key string, pollInterval, maxWait time.Duration) error {
+	if pollInterval <= 0 {
+		pollInterval = 30 * time.Second
+	}
+	if maxWait <= 0 {
+		maxWait = 24 * time.Hour
+	}
+	store, err := c.storage()
+	if err != nil {
+		return err
+	}
+	rs, ok := store.(restoreStorage)
+	if !ok {
+		return fmt.Errorf("voiceworld: storage backend does not support archive restore")
+	}
+	deadline := c.now().Add(maxWait)
+	for {
+		done, err := rs.RestoreDone(key)
+		if err != nil {
+			return fmt.Errorf("voiceworld: poll restore of %s: %w", key, err)
+		}
+		if done {
+			return nil
+		}
+		if !c.now().Add(pollInterval).Before(deadline) {
+			return fmt.Errorf("voiceworld: restore of %s did not complete within %v", key, maxWait)
+		}
+		c.sleep(pollInterval)
+	}
+}
+
+// DownloadObject streams the staged object at key. Reading an Archive-class
+// object that has not been restored yet fails with ErrObjectArchived.
+func (c *Client) DownloadObject(key string) (io.ReadCloser, error) {
+	store, err := c.storage()
+	if err != nil {
+		return nil, err
+	}
+	reader, ok := store.(objectReadStorage)
+	if !ok {
+		return nil, fmt.Errorf("voiceworld: storage backend does not support object reads")
+	}
+	c.stats.countStorageOp("get_object")
+	rc, err := reader.GetObject(key)
+	if err != nil {
+		c.stats.addFailure()
+		if isObjectArchived(err) {
+			return nil, fmt.Errorf("voiceworld: download %s: %w", key, ErrObjectArchived)
+		}
+		return nil, fmt.Errorf("voiceworld: download %s: %w", key, err)
+	}
+	return &countingReadCloser{ReadCloser: rc, stats: c.stats}, nil
+}
+
+// DownloadOptions carries the optional knobs for DownloadObjectToFile. Nil
+// and the zero value just write the bytes.
+type DownloadOptions struct {
+	// RestoreModTime sets the written file's modification time to the
+	// source-mtime recorded on the object (see UploadOptions.Source), so a
+	// downloaded recording keeps the timestamp of the original. Without
+	// recorded metadata, or on a backend that cannot read metadata back,
+	// the file keeps its write time.
+	RestoreModTime bool
+}
+
+// DownloadObjectToFile streams the staged object at key into a local file
+// at path, creating or truncating it. Passing more than one options value
+// is an error.
+func (c *Client) DownloadObjectToFile(key, path string, options ...*DownloadOptions) error {
+	if len(options) > 1 {
+		return fmt.Errorf("voiceworld: at most one DownloadOptions may be given, got %d", len(options))
+	}
+	opts := &DownloadOptions{}
+	if len(options) == 1 && options[0] != nil {
+		opts = options[0]
+	}
+	rc, err := c.DownloadObject(key)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	f, err := os.Create(longPath(path))
+	if err != nil {
+		return fmt.Errorf("voiceworld: create download target: %w", err)
+	}
+	_, err = io.Copy(f, rc)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(path)
+		return fmt.Errorf("voiceworld: download %s: %w", key, err)
+	}
+	if opts.RestoreModTime {
+		store, serr := c.storage()
+		if serr != nil {
+			return serr
+		}
+		if mr, ok := store.(metaReadStorage); ok {
+			meta, merr := mr.GetObjectMeta(key)
+			if merr != nil {
+				return fmt.Errorf("voiceworld: read metadata of %s: %w", key, merr)
+			}
+			if mtime, ok := sourceModTimeFromMeta(meta); ok {
+				if cherr := os.Chtimes(path, mtime, mtime); cherr != nil {
+					return fmt.Errorf("voiceworld: restore mtime of %s: %w", path, cherr)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// isObjectArchived recognizes the cold-storage rejection in a backend or
+// API failure: OSS answers reads of unrestored archives with a 403 carrying
+// ObjectNotAccessible and a "restore" hint.
+func isObjectArchived(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "objectnotaccessible") ||
+		strings.Contains(msg, "restore required") ||
+		strings.Contains(msg, "restore has not been completed")
+}
@@ -0,0 +1,168 @@
+package voiceworld
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// archiveStorage simulates OSS cold storage: reads of archived keys fail
+// until a restore has been requested and enough polls have passed.
+type archiveStorage struct {
+	*MemoryStorage
+	mu        sync.Mutex
+	archived  map[string]bool
+	polls     map[string]int // polls remaining until the restore lands
+	restored  map[string]bool
+	daysAsked map[string]int
+}
+
+func newArchiveStorage() *archiveStorage {
+	return &archiveStorage{
+		MemoryStorage: NewMemoryStorage(),
+		archived:      map[string]bool{},
+		polls:         map[string]int{},
+		restored:      map[string]bool{},
+		daysAsked:     map[string]int{},
+	}
+}
+
+func (s *archiveStorage) archive(key string) {
+	s.mu.Lock()
+	s.archived[key] = true
+	s.mu.Unlock()
+}
+
+func (s *archiveStorage) GetObject(key string) (io.ReadCloser, error) {
+	s.mu.Lock()
+	blocked := s.archived[key] && !s.restored[key]
+	s.mu.Unlock()
+	if blocked {
+		return nil, fmt.Errorf("oss: service returned 403: ObjectNotAccessible, restore required for %s", key)
+	}
+	return s.MemoryStorage.GetObject(key)
+}
+
+func (s *archiveStorage) RestoreObject(key string, days int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.archived[key] {
+		return fmt.Errorf("oss: object %s is not archived", key)
+	}
+	s.daysAsked[key] = days
+	s.polls[key] = 2
+	return nil
+}
+
+func (s *archiveStorage) RestoreDone(key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.archived[key] || s.restored[key] {
+		return true, nil
+	}
+	remaining, requested := s.polls[key]
+	if !requested {
+		return false, nil
+	}
+	if remaining > 0 {
+		s.polls[key] = remaining - 1
+		return false, nil
+	}
+	s.restored[key] = true
+	return true, nil
+}
+
+func TestRestoreObjectAndWait(t *testing.T) {
+	store := newArchiveStorage()
+	client, err := NewClient("ak", "sk", &ClientConfig{Storage: store})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.PutObject("req/0001.wav", strings.NewReader("frozen")); err != nil {
+		t.Fatal(err)
+	}
+	store.archive("req/0001.wav")
+
+	if _, err := client.DownloadObject("req/0001.wav"); !errors.Is(err, ErrObjectArchived) {
+		t.Fatalf("DownloadObject = %v, want ErrObjectArchived", err)
+	}
+
+	if err := client.RestoreObject("req/0001.wav", 3); err != nil {
+		t.Fatalf("RestoreObject: %v", err)
+	}
+	if store.daysAsked["req/0001.wav"] != 3 {
+		t.Errorf("restore asked for %d days, want 3", store.daysAsked["req/0001.wav"])
+	}
+
+	sleeps := 0
+	client.sleep = func(time.Duration) { sleeps++ }
+	if err := client.WaitForRestore("req/0001.wav", time.Second, time.Minute); err != nil {
+		t.Fatalf("WaitForRestore: %v", err)
+	}
+	if sleeps == 0 {
+		t.Error("restore completed without any polling")
+	}
+
+	rc, err := client.DownloadObject("req/0001.wav")
+	if err != nil {
+		t.Fatalf("DownloadObject after restore: %v", err)
+	}
+	data, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(data) != "frozen" {
+		t.Errorf("restored object = %q", data)
+	}
+}
+
+func TestWaitForRestoreTimesOut(t *testing.T) {
+	store := newArchiveStorage()
+	client, err := NewClient("ak", "sk", &ClientConfig{Storage: store})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.PutObject("req/0001.wav", strings.NewReader("frozen")); err != nil {
+		t.Fatal(err)
+	}
+	store.archive("req/0001.wav")
+	// Never request the restore: RestoreDone stays false forever. Virtual
+	// time keeps the test instant.
+	now := time.Unix(0, 0)
+	client.now = func() time.Time { return now }
+	client.sleep = func(d time.Duration) { now = now.Add(d) }
+
+	err = client.WaitForRestore("req/0001.wav", time.Minute, 10*time.Minute)
+	if err == nil || !strings.Contains(err.Error(), "did not complete within") {
+		t.Fatalf("err = %v, want timeout failure", err)
+	}
+}
+
+func TestRecognizeURLArchived(t *testing.T) {
+	server := newEnvelopeServer(t, `{"code":40302,"message":"ObjectNotAccessible: the object's restore has not been completed"}`)
+	client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.RecognizeURL("https://bucket/req/0001.wav", nil)
+	if !errors.Is(err, ErrObjectArchived) {
+		t.Fatalf("err = %v, want ErrObjectArchived", err)
+	}
+	if !strings.Contains(err.Error(), "req/0001.wav") {
+		t.Errorf("err %q does not name the audio", err)
+	}
+}
+
+func TestRestoreUnsupportedBackend(t *testing.T) {
+	store := struct{ ObjectStorage }{NewMemoryStorage()}
+	client, err := NewClient("ak", "sk", &ClientConfig{Storage: store})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.RestoreObject("a.wav", 1); err == nil ||
+		!strings.Contains(err.Error(), "archive restore") {
+		t.Fatalf("err = %v, want restore-unsupported failure", err)
+	}
+}
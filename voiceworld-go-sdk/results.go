@@ -0,0 +1,96 @@
+package voiceworld
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// defaultSegmentPageLimit is how many segments per page the pagination
+// helpers request when the caller does not choose a limit.
+const defaultSegmentPageLimit = 500
+
+// ResultPageOptions selects one window of a paginated transcript.
+type ResultPageOptions struct {
+	// Offset is the index of the first segment to return.
+	Offset int
+	// Limit caps the number of segments in the page. Zero means
+	// defaultSegmentPageLimit.
+	Limit int
+}
+
+// ResultPage is one window of a long transcript. Segments holds only the
+// requested window; TotalSegments is the full count so callers can page.
+type ResultPage struct {
+	RecognitionResult
+	TotalSegments int `json:"total_segments"`
+}
+
+// GetTaskResult fetches the transcript of a finished task. For long audio
+// the server paginates segments; opts selects the window (nil means the
+// first defaultSegmentPageLimit segments). Use ForEachSegment to stream a
+// whole transcript without holding it in memory, or GetFullTaskResult to
+// merge it into one struct.
+func (c *Client) GetTaskResult(taskID string, opts *ResultPageOptions) (*ResultPage, error) {
+	if opts == nil {
+		opts = &ResultPageOptions{}
+	}
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = defaultSegmentPageLimit
+	}
+	query := url.Values{
+		"task_id":        {taskID},
+		"segment_offset": {strconv.Itoa(opts.Offset)},
+		"segment_limit":  {strconv.Itoa(limit)},
+	}
+	var page ResultPage
+	if err := c.do(http.MethodGet, "/asr_result", query, "", nil, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// ForEachSegment streams every segment of a task's transcript in order,
+// fetching pages as needed so only one page is in memory at a time. fn
+// returning an error stops the iteration and surfaces that error.
+func (c *Client) ForEachSegment(taskID string, fn func(Segment) error) error {
+	for offset := 0; ; {
+		page, err := c.GetTaskResult(taskID, &ResultPageOptions{Offset: offset})
+		if err != nil {
+			return err
+		}
+		for _, seg := range page.Segments {
+			if err := fn(seg); err != nil {
+				return err
+			}
+		}
+		offset += len(page.Segments)
+		if len(page.Segments) == 0 || offset >= page.TotalSegments {
+			return nil
+		}
+	}
+}
+
+// GetFullTaskResult fetches a task's transcript and follows pagination until
+// all segments are collected into one result. Fine for small transcripts;
+// for hours-long audio prefer ForEachSegment.
+func (c *Client) GetFullTaskResult(taskID string) (*RecognitionResult, error) {
+	var result *RecognitionResult
+	for offset := 0; ; {
+		page, err := c.GetTaskResult(taskID, &ResultPageOptions{Offset: offset})
+		if err != nil {
+			return nil, err
+		}
+		if result == nil {
+			full := page.RecognitionResult
+			result = &full
+		} else {
+			result.Segments = append(result.Segments, page.Segments...)
+		}
+		offset += len(page.Segments)
+		if len(page.Segments) == 0 || offset >= page.TotalSegments {
+			return result, nil
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package voiceworld
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// newPagedResultServer serves a transcript of total segments in pages capped
+// at pageMax, regardless of the limit the client asks for.
+func newPagedResultServer(t *testing.T, total, pageMax int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/asr_result" || r.URL.Query().Get("task_id") != "task-long" {
+			t.Errorf("unexpected request %s %s", r.URL.Path, r.URL.RawQuery)
+		}
+		offset, _ := strconv.Atoi(r.URL.Query().Get("segment_offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("segment_limit"))
+		if limit > pageMax {
+			limit = pageMax
+		}
+		var segments []Segment
+		for i := offset; i < total && i < offset+limit; i++ {
+			segments = append(segments, Segment{
+				Text:      fmt.Sprintf("segment %d", i),
+				BeginTime: ms(int64(i) * 1000),
+				EndTime:   ms(int64(i+1) * 1000),
+			})
+		}
+		data, _ := json.Marshal(map[string]interface{}{
+			"task_id":        "task-long",
+			"result":         "long transcript",
+			"duration":       total * 1000,
+			"segments":       segments,
+			"total_segments": total,
+		})
+		w.Write([]byte(`{"code":0,"data":` + string(data) + `}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestForEachSegmentStreamsAllPages(t *testing.T) {
+	server := newPagedResultServer(t, 7, 3) // 3 pages: 3+3+1
+	client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var texts []string
+	err = client.ForEachSegment("task-long", func(seg Segment) error {
+		texts = append(texts, seg.Text)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ForEachSegment: %v", err)
+	}
+	if len(texts) != 7 {
+		t.Fatalf("got %d segments: %v", len(texts), texts)
+	}
+	for i, text := range texts {
+		if text != fmt.Sprintf("segment %d", i) {
+			t.Errorf("segment %d = %q", i, text)
+		}
+	}
+}
+
+func TestForEachSegmentStopsOnCallbackError(t *testing.T) {
+	server := newPagedResultServer(t, 7, 3)
+	client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stop := errors.New("enough")
+	seen := 0
+	err = client.ForEachSegment("task-long", func(Segment) error {
+		seen++
+		if seen == 2 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) {
+		t.Fatalf("err = %v, want the callback error", err)
+	}
+	if seen != 2 {
+		t.Errorf("callback ran %d times, want 2", seen)
+	}
+}
+
+func TestGetFullTaskResultMergesPages(t *testing.T) {
+	server := newPagedResultServer(t, 7, 3)
+	client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := client.GetFullTaskResult("task-long")
+	if err != nil {
+		t.Fatalf("GetFullTaskResult: %v", err)
+	}
+	if result.TaskID != "task-long" || result.Result != "long transcript" {
+		t.Errorf("result = %+v", result)
+	}
+	if len(result.Segments) != 7 {
+		t.Fatalf("got %d segments", len(result.Segments))
+	}
+	if result.Segments[6].BeginTime != ms(6000) {
+		t.Errorf("last segment = %+v", result.Segments[6])
+	}
+}
+
+func TestGetTaskResultWindow(t *testing.T) {
+	server := newPagedResultServer(t, 7, 3)
+	client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	page, err := client.GetTaskResult("task-long", &ResultPageOptions{Offset: 5, Limit: 2})
+	if err != nil {
+		t.Fatalf("GetTaskResult: %v", err)
+	}
+	if page.TotalSegments != 7 || len(page.Segments) != 2 {
+		t.Fatalf("page = %+v", page)
+	}
+	if page.Segments[0].Text != "segment 5" {
+		t.Errorf("first segment = %+v", page.Segments[0])
+	}
+}
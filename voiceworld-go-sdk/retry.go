@@ -0,0 +1,248 @@
+package voiceworld
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RetryPolicy decides whether a failed attempt is retried and how long to
+// wait first. attempt is 1 for the first failure, 2 for the second, and so
+// on. err is the failure; resp, when non-nil, is the HTTP response that
+// produced it, with the body already consumed (only status and headers are
+// meaningful). Storage operations carry no response and pass nil.
+// Implementations must be safe for concurrent use: one policy serves every
+// request the client issues.
+type RetryPolicy interface {
+	NextDelay(attempt int, err error, resp *http.Response) (time.Duration, bool)
+}
+
+// ExponentialBackoff retries transient failures with exponentially growing,
+// jittered delays. The zero value is a usable default: up to 4 retries
+// starting around 500ms and capped at 30s.
+type ExponentialBackoff struct {
+	// BaseDelay seeds the schedule: retry n waits about BaseDelay*2^(n-1).
+	// Zero means 500ms.
+	BaseDelay time.Duration
+
+	// MaxDelay caps a single wait. Zero means 30s.
+	MaxDelay time.Duration
+
+	// MaxRetries bounds how many times one call is retried. Zero means 4.
+	MaxRetries int
+
+	// jitter returns a factor in [0, 1]; tests pin it for determinism.
+	// Nil means math/rand.
+	jitter func() float64
+}
+
+func (p ExponentialBackoff) NextDelay(attempt int, err error, resp *http.Response) (time.Duration, bool) {
+	maxRetries := p.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = 4
+	}
+	if attempt > maxRetries || !retryableFailure(err, resp) {
+		return 0, false
+	}
+	base := p.BaseDelay
+	if base == 0 {
+		base = 500 * time.Millisecond
+	}
+	maxDelay := p.MaxDelay
+	if maxDelay == 0 {
+		maxDelay = 30 * time.Second
+	}
+	// 429 is a demand signal, not a hiccup: skip one step of the schedule
+	// so a throttled caller backs off harder than one seeing 503s.
+	step := attempt
+	if failureStatus(err, resp) == http.StatusTooManyRequests {
+		step++
+	}
+	delay := base << uint(step-1)
+	if delay <= 0 || delay > maxDelay {
+		delay = maxDelay
+	}
+	jitter := p.jitter
+	if jitter == nil {
+		jitter = randFloat
+	}
+	return delay/2 + time.Duration(jitter()*float64(delay/2)), true
+}
+
+// FixedAttempts retries transient failures up to Retries times with a
+// constant delay, for interactive callers that want quick, predictable
+// behaviour.
+type FixedAttempts struct {
+	// Retries is how many retries one call gets. Zero means 1.
+	Retries int
+
+	// Delay is the wait before each retry. Zero means 1s.
+	Delay time.Duration
+}
+
+func (p FixedAttempts) NextDelay(attempt int, err error, resp *http.Response) (time.Duration, bool) {
+	retries := p.Retries
+	if retries == 0 {
+		retries = 1
+	}
+	if attempt > retries || !retryableFailure(err, resp) {
+		return 0, false
+	}
+	delay := p.Delay
+	if delay == 0 {
+		delay = time.Second
+	}
+	return delay, true
+}
+
+// NoRetry fails on the first error. It is the default policy.
+type NoRetry struct{}
+
+func (NoRetry) NextDelay(int, error, *http.Response) (time.Duration, bool) {
+	return 0, false
+}
+
+// retryableFailure reports whether a failed attempt looks transient:
+// transport errors, timeouts, throttling, server-side 5xx and the
+// envelope's own internal-error code. Deliberate answers — 4xx other than
+// 408/429, and other envelope-level API errors riding a 200 — are final.
+func retryableFailure(err error, resp *http.Response) bool {
+	if errors.Is(err, ErrServerInternal) {
+		return true
+	}
+	status := failureStatus(err, resp)
+	if status == 0 {
+		// No response at all: the request never completed.
+		return err != nil
+	}
+	return status == http.StatusRequestTimeout ||
+		status == http.StatusTooManyRequests ||
+		status >= 500
+}
+
+// failureStatus extracts the HTTP status behind a failed attempt: from the
+// response when one arrived, else from an APIError carried by err. Zero
+// means the failure happened below the HTTP layer.
+func failureStatus(err error, resp *http.Response) int {
+	if resp != nil {
+		return resp.StatusCode
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.HTTPStatus
+	}
+	return 0
+}
+
+// ErrRetryBudgetExhausted marks a failure that would have been retried,
+// except the operation had already spent its MaxTotalRetries budget.
+var ErrRetryBudgetExhausted = errors.New("voiceworld: operation retry budget exhausted")
+
+// retryBudget caps the total retries one operation may spend across all of
+// its parts and requests, and counts what they were spent on. A nil budget
+// never runs out.
+type retryBudget struct {
+	mu    sync.Mutex
+	left  int
+	spent map[string]int // retries per failure category
+}
+
+// newRetryBudget builds a budget of max retries; max <= 0 means unlimited.
+func newRetryBudget(max int) *retryBudget {
+	if max <= 0 {
+		return nil
+	}
+	return &retryBudget{left: max, spent: map[string]int{}}
+}
+
+// consume records one retry caused by err. It reports false when the
+// budget is already spent, in which case the retry must not happen.
+func (b *retryBudget) consume(err error) bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.left == 0 {
+		return false
+	}
+	b.left--
+	b.spent[failureCategory(err)]++
+	return true
+}
+
+// exhausted wraps the failure that could not be retried with the budget's
+// spending breakdown.
+func (b *retryBudget) exhausted(err error) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	total := 0
+	var categories []string
+	for category := range b.spent {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	parts := make([]string, len(categories))
+	for i, category := range categories {
+		parts[i] = fmt.Sprintf("%s: %d", category, b.spent[category])
+		total += b.spent[category]
+	}
+	return fmt.Errorf("%w after %d retries (%s): %v",
+		ErrRetryBudgetExhausted, total, strings.Join(parts, ", "), err)
+}
+
+// failureCategory buckets a failure for the budget's accounting.
+func failureCategory(err error) string {
+	switch status := failureStatus(err, nil); {
+	case status == 0:
+		return "transport"
+	case status == http.StatusTooManyRequests:
+		return "throttled"
+	case status >= 500:
+		return "server"
+	default:
+		return "client"
+	}
+}
+
+// retryPolicy returns the configured policy, or NoRetry.
+func (c *Client) retryPolicy() RetryPolicy {
+	if c.config.Retry != nil {
+		return c.config.Retry
+	}
+	return NoRetry{}
+}
+
+// retryStorageOp runs one storage operation under policy, drawing every
+// retry from the operation's shared budget and counting retries on the
+// part's report entry. rewind restores the operation's input between
+// attempts and may be nil when there is nothing to rewind; a failed
+// rewind ends the retries.
+func retryStorageOp(policy RetryPolicy, budget *retryBudget, preport *PartReport, rewind func() error, op func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := op()
+		if err == nil {
+			return nil
+		}
+		delay, retry := policy.NextDelay(attempt, err, nil)
+		if !retry {
+			return err
+		}
+		if !budget.consume(err) {
+			return budget.exhausted(err)
+		}
+		if preport != nil {
+			preport.Retries++
+		}
+		if rewind != nil {
+			if rerr := rewind(); rerr != nil {
+				return err
+			}
+		}
+		timeSleep(delay)
+	}
+}
@@ -0,0 +1,360 @@
+package voiceworld
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// drivePolicy feeds the same failure to the policy until it gives up and
+// returns the delay sequence.
+func drivePolicy(policy RetryPolicy, err error, resp *http.Response) []time.Duration {
+	var delays []time.Duration
+	for attempt := 1; attempt < 100; attempt++ {
+		delay, retry := policy.NextDelay(attempt, err, resp)
+		if !retry {
+			return delays
+		}
+		delays = append(delays, delay)
+	}
+	return delays
+}
+
+func TestExponentialBackoffSchedule(t *testing.T) {
+	policy := ExponentialBackoff{
+		BaseDelay:  100 * time.Millisecond,
+		MaxDelay:   time.Second,
+		MaxRetries: 5,
+		jitter:     func() float64 { return 1 }, // pin to the upper edge
+	}
+	got := drivePolicy(policy, &APIError{HTTPStatus: 503, Message: "unavailable"}, nil)
+	want := []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second, // capped
+	}
+	if len(got) != len(want) {
+		t.Fatalf("delays = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("delay %d = %v, want %v", i+1, got[i], want[i])
+		}
+	}
+}
+
+func TestExponentialBackoffThrottleBacksOffHarder(t *testing.T) {
+	policy := ExponentialBackoff{
+		BaseDelay:  100 * time.Millisecond,
+		MaxDelay:   time.Second,
+		MaxRetries: 2,
+		jitter:     func() float64 { return 1 },
+	}
+	overloaded, ok := policy.NextDelay(1, &APIError{HTTPStatus: 503}, nil)
+	if !ok {
+		t.Fatal("503 not retried")
+	}
+	throttled, ok := policy.NextDelay(1, &APIError{HTTPStatus: 429}, nil)
+	if !ok {
+		t.Fatal("429 not retried")
+	}
+	if throttled != 2*overloaded {
+		t.Errorf("429 delay = %v, 503 delay = %v; want one schedule step apart", throttled, overloaded)
+	}
+}
+
+func TestExponentialBackoffJitterStaysInRange(t *testing.T) {
+	for _, factor := range []float64{0, 0.5, 1} {
+		policy := ExponentialBackoff{
+			BaseDelay: 100 * time.Millisecond,
+			jitter:    func() float64 { return factor },
+		}
+		delay, ok := policy.NextDelay(1, &APIError{HTTPStatus: 503}, nil)
+		if !ok {
+			t.Fatal("503 not retried")
+		}
+		if delay < 50*time.Millisecond || delay > 100*time.Millisecond {
+			t.Errorf("jitter %v: delay = %v, want within [50ms, 100ms]", factor, delay)
+		}
+	}
+}
+
+func TestPoliciesSkipPermanentFailures(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		err  error
+		resp *http.Response
+	}{
+		{name: "bad request", err: &APIError{HTTPStatus: 400}},
+		{name: "not found", err: &APIError{HTTPStatus: 404}},
+		{name: "envelope error on 200", err: &APIError{Code: 40000},
+			resp: &http.Response{StatusCode: 200}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if delays := drivePolicy(ExponentialBackoff{}, tc.err, tc.resp); len(delays) != 0 {
+				t.Errorf("ExponentialBackoff retried: %v", delays)
+			}
+			if delays := drivePolicy(FixedAttempts{}, tc.err, tc.resp); len(delays) != 0 {
+				t.Errorf("FixedAttempts retried: %v", delays)
+			}
+		})
+	}
+}
+
+func TestFixedAttemptsSequence(t *testing.T) {
+	policy := FixedAttempts{Retries: 3, Delay: 50 * time.Millisecond}
+	got := drivePolicy(policy, errors.New("connection reset"), nil)
+	want := []time.Duration{50 * time.Millisecond, 50 * time.Millisecond, 50 * time.Millisecond}
+	if len(got) != len(want) || got[0] != want[0] {
+		t.Errorf("delays = %v, want %v", got, want)
+	}
+}
+
+func TestNoRetry(t *testing.T) {
+	if delays := drivePolicy(NoRetry{}, &APIError{HTTPStatus: 503}, nil); len(delays) != 0 {
+		t.Errorf("NoRetry retried: %v", delays)
+	}
+}
+
+func TestClientRetriesTransientStatus(t *testing.T) {
+	var mu sync.Mutex
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		n := requests
+		mu.Unlock()
+		if n < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"code":0,"data":{"models":[{"id":"m1"}]}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("ak", "sk", &ClientConfig{
+		BaseURL: server.URL,
+		Retry:   FixedAttempts{Retries: 3, Delay: time.Millisecond},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	models, err := client.ListModels()
+	if err != nil {
+		t.Fatalf("ListModels: %v", err)
+	}
+	if len(models) != 1 || requests != 3 {
+		t.Errorf("models = %v after %d requests, want 1 model after 3 requests", models, requests)
+	}
+}
+
+func TestClientDoesNotRetryAPIErrors(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"code":40000,"message":"bad model"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("ak", "sk", &ClientConfig{
+		BaseURL: server.URL,
+		Retry:   FixedAttempts{Retries: 3, Delay: time.Millisecond},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.ListModels()
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != 40000 {
+		t.Fatalf("err = %v, want the API error", err)
+	}
+	if requests != 1 {
+		t.Errorf("server saw %d requests, want 1", requests)
+	}
+}
+
+// flakyStorage fails the first UploadPart attempt of every part after
+// consuming half its reader, so a successful retry proves the slice was
+// rewound.
+type flakyStorage struct {
+	*MemoryStorage
+	mu     sync.Mutex
+	failed map[int]bool
+}
+
+func (s *flakyStorage) UploadPart(key, uploadID string, partNumber int, r io.Reader, size int64) (UploadedPart, error) {
+	s.mu.Lock()
+	first := !s.failed[partNumber]
+	s.failed[partNumber] = true
+	s.mu.Unlock()
+	if first {
+		io.CopyN(io.Discard, r, size/2)
+		return UploadedPart{}, fmt.Errorf("simulated part failure")
+	}
+	return s.MemoryStorage.UploadPart(key, uploadID, partNumber, r, size)
+}
+
+func TestMultipartUploadRetriesParts(t *testing.T) {
+	store := &flakyStorage{MemoryStorage: NewMemoryStorage(), failed: map[int]bool{}}
+	client, err := NewClient("ak", "sk", &ClientConfig{
+		Storage:     store,
+		ChunkSize:   1024,
+		Parallelism: 2,
+		Retry:       FixedAttempts{Retries: 2, Delay: time.Millisecond},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := make([]byte, 2*1024+512)
+	for i := range content {
+		content[i] = byte(i)
+	}
+	path := filepath.Join(t.TempDir(), "big.bin")
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.MultipartUploadFile(path, "req/big.bin"); err != nil {
+		t.Fatalf("MultipartUploadFile: %v", err)
+	}
+	got, ok := store.Object("req/big.bin")
+	if !ok {
+		t.Fatal("object not stored")
+	}
+	if !bytes.Equal(got, content) {
+		t.Errorf("assembled object differs from source (%d vs %d bytes)", len(got), len(content))
+	}
+}
+
+func TestMultipartUploadExhaustsRetries(t *testing.T) {
+	store := &brokenPartStorage{MemoryStorage: NewMemoryStorage()}
+	client, err := NewClient("ak", "sk", &ClientConfig{
+		Storage:   store,
+		ChunkSize: 1024,
+		Retry:     FixedAttempts{Retries: 2, Delay: time.Millisecond},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "big.bin")
+	if err := os.WriteFile(path, make([]byte, 2048), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.MultipartUploadFile(path, "req/big.bin"); err == nil {
+		t.Fatal("expected error when every attempt fails")
+	}
+	if store.attempts != 3 { // initial try plus two retries for part 1
+		t.Errorf("part 1 was attempted %d times, want 3", store.attempts)
+	}
+}
+
+// brokenPartStorage fails every UploadPart; Parallelism is 1 in its test so
+// the attempt count is exact for the first part.
+type brokenPartStorage struct {
+	*MemoryStorage
+	attempts int
+}
+
+func (s *brokenPartStorage) UploadPart(key, uploadID string, partNumber int, r io.Reader, size int64) (UploadedPart, error) {
+	if partNumber == 1 {
+		s.attempts++
+	}
+	return UploadedPart{}, fmt.Errorf("simulated outage")
+}
+
+// countingBrokenStorage fails every UploadPart and counts attempts across
+// all parts.
+type countingBrokenStorage struct {
+	*MemoryStorage
+	mu       sync.Mutex
+	attempts int
+}
+
+func (s *countingBrokenStorage) UploadPart(key, uploadID string, partNumber int, r io.Reader, size int64) (UploadedPart, error) {
+	s.mu.Lock()
+	s.attempts++
+	s.mu.Unlock()
+	return UploadedPart{}, fmt.Errorf("simulated outage")
+}
+
+func TestRetryBudgetSharedAcrossParts(t *testing.T) {
+	store := &countingBrokenStorage{MemoryStorage: NewMemoryStorage()}
+	client, err := NewClient("ak", "sk", &ClientConfig{
+		Storage:         store,
+		ChunkSize:       1024,
+		Retry:           FixedAttempts{Retries: 10, Delay: time.Millisecond},
+		MaxTotalRetries: 3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "big.bin")
+	if err := os.WriteFile(path, make([]byte, 4*1024), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.MultipartUploadFile(path, "req/big.bin")
+	if !errors.Is(err, ErrRetryBudgetExhausted) {
+		t.Fatalf("err = %v, want ErrRetryBudgetExhausted", err)
+	}
+	// One part burns the whole budget (1 attempt + 3 retries); the other
+	// three fail terminally on their first attempt.
+	if store.attempts != 7 {
+		t.Errorf("backend saw %d attempts, want 7", store.attempts)
+	}
+}
+
+func TestRetryBudgetCapsRequestRetries(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client, err := NewClient("ak", "sk", &ClientConfig{
+		BaseURL:         server.URL,
+		Retry:           FixedAttempts{Retries: 5, Delay: time.Millisecond},
+		MaxTotalRetries: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.ListModels()
+	if !errors.Is(err, ErrRetryBudgetExhausted) {
+		t.Fatalf("err = %v, want ErrRetryBudgetExhausted", err)
+	}
+	if requests != 3 {
+		t.Errorf("server saw %d requests, want 3", requests)
+	}
+}
+
+func TestRetryBudgetReportsCategories(t *testing.T) {
+	budget := newRetryBudget(3)
+	budget.consume(&APIError{HTTPStatus: 503})
+	budget.consume(&APIError{HTTPStatus: 503})
+	budget.consume(errors.New("connection reset"))
+	if budget.consume(errors.New("one too many")) {
+		t.Fatal("budget did not run out after 3 retries")
+	}
+	err := budget.exhausted(errors.New("connection reset"))
+	if !errors.Is(err, ErrRetryBudgetExhausted) {
+		t.Fatalf("err = %v, want ErrRetryBudgetExhausted", err)
+	}
+	for _, want := range []string{"after 3 retries", "server: 2", "transport: 1"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q missing %q", err, want)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package voiceworld
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// headerSessionID carries the recognition session id on every request a
+// session issues.
+const headerSessionID = "X-Session-Id"
+
+// codeSessionExpired is the envelope code servers use when a recognition
+// session has been reaped between turns.
+const codeSessionExpired = 41001
+
+// ErrSessionBusy is returned by Recognize and Close when another Recognize
+// call is still in flight. Sessions are sequential by design: the server
+// builds context turn by turn, so overlapping utterances have no meaningful
+// order.
+var ErrSessionBusy = errors.New("voiceworld: recognition session busy: one Recognize call at a time")
+
+// ErrRecognitionSessionClosed is returned by Recognize after Close.
+var ErrRecognitionSessionClosed = errors.New("voiceworld: recognition session closed")
+
+// RecognitionSession threads one server-side context across many short
+// utterances, so earlier turns improve the recognition of later ones — the
+// IVR pattern, where dozens of clips belong to one call. The server-issued
+// session id rides along as the X-Session-Id header and the task id of the
+// first turn is reused for all subsequent ones.
+//
+// A session is meant for sequential use; concurrent Recognize calls are
+// rejected with ErrSessionBusy rather than interleaved. If the server
+// reports the session expired between turns, the session re-establishes
+// itself transparently (at the cost of the accumulated context).
+type RecognitionSession struct {
+	parent *Client
+	config *RecognitionConfig
+
+	mu     sync.Mutex
+	client *Client // parent with the session id baked in
+	id     string
+	taskID string
+	turns  int
+	closed bool
+}
+
+// NewRecognitionSession opens a recognition session on the server and
+// returns the handle for submitting its utterances. config applies to every
+// turn; nil means platform defaults.
+func (c *Client) NewRecognitionSession(config *RecognitionConfig) (*RecognitionSession, error) {
+	if config == nil {
+		config = &RecognitionConfig{}
+	}
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	s := &RecognitionSession{parent: c, config: config}
+	if err := s.establish(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// establish asks the server for a fresh session id and rebinds the
+// session's derived client to it.
+func (s *RecognitionSession) establish() error {
+	req := struct {
+		ModelID string `json:"model_id,omitempty"`
+	}{ModelID: s.config.ModelID}
+	var data struct {
+		SessionID string `json:"session_id"`
+	}
+	if err := s.parent.postJSON("/session", &req, &data); err != nil {
+		return fmt.Errorf("voiceworld: start recognition session: %w", err)
+	}
+	if data.SessionID == "" {
+		return fmt.Errorf("voiceworld: server returned no session id")
+	}
+	derived := *s.parent
+	derived.sessionID = data.SessionID
+	s.client = &derived
+	s.id = data.SessionID
+	return nil
+}
+
+// ID returns the server-issued session id. It changes when an expired
+// session is re-established.
+func (s *RecognitionSession) ID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.id
+}
+
+// Turns returns how many utterances have been recognized so far.
+func (s *RecognitionSession) Turns() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.turns
+}
+
+// Recognize submits the session's next utterance and returns its
+// transcript. The audio travels like an ASR call, with the session id,
+// the session's task id and the 1-based turn number attached.
+func (s *RecognitionSession) Recognize(audio []byte) (*RecognitionResult, error) {
+	if !s.mu.TryLock() {
+		return nil, ErrSessionBusy
+	}
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, ErrRecognitionSessionClosed
+	}
+	turn := s.turns + 1
+	result, err := s.recognizeTurn(audio, turn)
+	if isSessionExpired(err) {
+		// The server reaped the session between turns; its context is
+		// gone either way, so start fresh and replay this utterance.
+		s.taskID = ""
+		if rerr := s.establish(); rerr != nil {
+			return nil, rerr
+		}
+		result, err = s.recognizeTurn(audio, turn)
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.turns = turn
+	if s.taskID == "" {
+		s.taskID = result.TaskID
+	}
+	return result, nil
+}
+
+// recognizeTurn issues one session-bound /asr request.
+func (s *RecognitionSession) recognizeTurn(audio []byte, turn int) (*RecognitionResult, error) {
+	params := s.config.params()
+	params.Set("turn", strconv.Itoa(turn))
+	if s.taskID != "" {
+		params.Set("task_id", s.taskID)
+	}
+	var result RecognitionResult
+	if err := s.client.do(http.MethodPost, "/asr", params, "application/octet-stream",
+		bytes.NewReader(audio), &result); err != nil {
+		return nil, modelSelectionError(err, s.config.ModelID)
+	}
+	return &result, nil
+}
+
+// Close ends the session server-side. Closing twice is a no-op; recognizing
+// after Close returns ErrRecognitionSessionClosed.
+func (s *RecognitionSession) Close() error {
+	if !s.mu.TryLock() {
+		return ErrSessionBusy
+	}
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	req := struct {
+		SessionID string `json:"session_id"`
+	}{s.id}
+	if err := s.parent.postJSON("/session/close", &req, nil); err != nil {
+		return fmt.Errorf("voiceworld: close recognition session: %w", err)
+	}
+	return nil
+}
+
+// isSessionExpired recognizes the server's session-expired answer.
+func isSessionExpired(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.Code == codeSessionExpired ||
+		strings.Contains(strings.ToLower(apiErr.Message), "session expired")
+}
@@ -0,0 +1,181 @@
+package voiceworld
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// sessionServer is a fake session-aware backend: /session mints ids,
+// /session/close records the closed id, and /asr records each turn's
+// headers and parameters. expireFirst makes it reject the first session id
+// it issued with the session-expired code, to exercise re-establishment.
+type sessionServer struct {
+	t           *testing.T
+	expireFirst bool
+
+	mu      sync.Mutex
+	minted  int
+	closed  []string
+	turns   []string // "session|task|turn" per /asr call
+	asrSeen int
+}
+
+func (s *sessionServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/session", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.minted++
+		id := fmt.Sprintf("sess-%d", s.minted)
+		s.mu.Unlock()
+		fmt.Fprintf(w, `{"code":0,"data":{"session_id":%q}}`, id)
+	})
+	mux.HandleFunc("/session/close", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			SessionID string `json:"session_id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			s.t.Errorf("decode close request: %v", err)
+		}
+		s.mu.Lock()
+		s.closed = append(s.closed, req.SessionID)
+		s.mu.Unlock()
+		w.Write([]byte(`{"code":0,"data":{}}`))
+	})
+	mux.HandleFunc("/asr", func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(headerSessionID)
+		s.mu.Lock()
+		s.asrSeen++
+		s.turns = append(s.turns, id+"|"+r.URL.Query().Get("task_id")+"|"+r.URL.Query().Get("turn"))
+		s.mu.Unlock()
+		if s.expireFirst && id == "sess-1" {
+			w.Write([]byte(`{"code":41001,"message":"session expired"}`))
+			return
+		}
+		fmt.Fprintf(w, `{"code":0,"data":{"task_id":"task-%s","result":"ok"}}`, id)
+	})
+	return mux
+}
+
+func newSessionTestClient(t *testing.T, srv *sessionServer) *Client {
+	t.Helper()
+	server := httptest.NewServer(srv.handler())
+	t.Cleanup(server.Close)
+	client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestRecognitionSessionReusesIDs(t *testing.T) {
+	srv := &sessionServer{t: t}
+	client := newSessionTestClient(t, srv)
+
+	session, err := client.NewRecognitionSession(nil)
+	if err != nil {
+		t.Fatalf("NewRecognitionSession: %v", err)
+	}
+	if session.ID() != "sess-1" {
+		t.Fatalf("session id = %q", session.ID())
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := session.Recognize([]byte("utterance")); err != nil {
+			t.Fatalf("Recognize turn %d: %v", i+1, err)
+		}
+	}
+	if session.Turns() != 3 {
+		t.Errorf("Turns = %d, want 3", session.Turns())
+	}
+	want := []string{
+		"sess-1||1", // first turn: server has not issued a task id yet
+		"sess-1|task-sess-1|2",
+		"sess-1|task-sess-1|3",
+	}
+	for i, turn := range srv.turns {
+		if turn != want[i] {
+			t.Errorf("asr call %d = %q, want %q", i+1, turn, want[i])
+		}
+	}
+
+	if err := session.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if len(srv.closed) != 1 || srv.closed[0] != "sess-1" {
+		t.Errorf("closed sessions = %v, want [sess-1]", srv.closed)
+	}
+	if err := session.Close(); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+	if _, err := session.Recognize([]byte("late")); !errors.Is(err, ErrRecognitionSessionClosed) {
+		t.Errorf("Recognize after Close = %v, want ErrRecognitionSessionClosed", err)
+	}
+}
+
+func TestRecognitionSessionReestablishesOnExpiry(t *testing.T) {
+	srv := &sessionServer{t: t, expireFirst: true}
+	client := newSessionTestClient(t, srv)
+
+	session, err := client.NewRecognitionSession(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := session.Recognize([]byte("utterance"))
+	if err != nil {
+		t.Fatalf("Recognize: %v", err)
+	}
+	if result.TaskID != "task-sess-2" {
+		t.Errorf("TaskID = %q, want the re-established session's", result.TaskID)
+	}
+	if session.ID() != "sess-2" {
+		t.Errorf("session id = %q, want sess-2 after re-establishment", session.ID())
+	}
+	if srv.minted != 2 || srv.asrSeen != 2 {
+		t.Errorf("minted %d sessions, saw %d asr calls; want 2 and 2", srv.minted, srv.asrSeen)
+	}
+	if got := srv.turns[1]; got != "sess-2||1" {
+		t.Errorf("replayed turn = %q, want a fresh session with no stale task id", got)
+	}
+}
+
+func TestRecognitionSessionRejectsConcurrentRecognize(t *testing.T) {
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/session", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":0,"data":{"session_id":"sess-1"}}`))
+	})
+	mux.HandleFunc("/asr", func(w http.ResponseWriter, r *http.Request) {
+		close(entered)
+		<-release
+		w.Write([]byte(`{"code":0,"data":{"task_id":"t","result":"ok"}}`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := client.NewRecognitionSession(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := session.Recognize([]byte("slow"))
+		done <- err
+	}()
+	<-entered
+	if _, err := session.Recognize([]byte("overlapping")); !errors.Is(err, ErrSessionBusy) {
+		t.Errorf("concurrent Recognize = %v, want ErrSessionBusy", err)
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Errorf("first Recognize: %v", err)
+	}
+}
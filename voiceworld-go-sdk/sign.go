@@ -0,0 +1,123 @@
+package voiceworld
+
+import (
+	"fmt"
+	"time"
+)
+
+// credentialExpiryStorage is the optional ObjectStorage capability behind
+// signed-URL TTL clamping: backends whose signing authority dies with a
+// temporary credential (OSS buckets dialed with STS tokens) report when that
+// is, so URLs are never advertised as outliving the token that signed them.
+type credentialExpiryStorage interface {
+	// credentialExpiry returns when the signing credential expires; zero
+	// means the credential does not expire.
+	credentialExpiry() time.Time
+}
+
+// signedURLTTL returns the lifetime a URL signed now against store really
+// has: the requested ttl, clamped to the signing credential's remaining
+// lifetime when the backend reports one.
+func (c *Client) signedURLTTL(store ObjectStorage, ttl time.Duration) time.Duration {
+	ces, ok := store.(credentialExpiryStorage)
+	if !ok {
+		return ttl
+	}
+	bound := ces.credentialExpiry()
+	if bound.IsZero() {
+		return ttl
+	}
+	if remaining := bound.Sub(c.now()); remaining > 0 && remaining < ttl {
+		return remaining
+	}
+	return ttl
+}
+
+// signStorageURL signs a download URL for key and reports when it stops
+// working. The requested ttl is clamped to the signing credential's
+// remaining lifetime — a URL signed with STS credentials dies with the
+// token regardless of the TTL asked for — with a warning so the shortened
+// window does not surprise whoever displays the URL.
+func (c *Client) signStorageURL(store ObjectStorage, key string, ttl time.Duration) (string, time.Time, error) {
+	effective := c.signedURLTTL(store, ttl)
+	if effective < ttl {
+		c.warnf("signed url for %s: requested ttl %s clamped to the storage credential's remaining %s", key, ttl, effective)
+	}
+	c.stats.countStorageOp("sign_url")
+	signed, err := store.SignURL(key, effective)
+	if err != nil {
+		c.stats.addFailure()
+		return "", time.Time{}, fmt.Errorf("voiceworld: sign url for %s: %w", key, err)
+	}
+	// UTC also strips the monotonic reading, so the expiry survives a JSON
+	// round trip intact.
+	return signed, c.now().Add(effective).UTC(), nil
+}
+
+// SignedPart is one entry of SignRequestPartURLs: a staged chunk together
+// with a presigned URL for it.
+type SignedPart struct {
+	// Index is the 1-based part number parsed from the object key.
+	Index int `json:"index"`
+	// Key is the object key under the request prefix. Empty when the index
+	// is missing from the store.
+	Key string `json:"key,omitempty"`
+	// URL is the presigned GET URL. Empty when the part is missing.
+	URL string `json:"url,omitempty"`
+	// ExpiresAt is when URL stops working, so consumers can schedule a
+	// refresh. Zero when the part is missing.
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+}
+
+// SignRequestPartURLs lists every chunk staged under requestID once and
+// signs a download URL for each, valid for ttl (<= 0 means the default
+// signing expiry). Entries come back ordered by part index. A gap in the
+// index sequence — a part that was deleted or never uploaded — is reported
+// as an entry with an empty Key and URL rather than silently omitted, so
+// consumers notice incomplete requests. Non-part objects under the prefix,
+// such as the manifest, are ignored.
+func (c *Client) SignRequestPartURLs(requestID string, ttl time.Duration) ([]SignedPart, error) {
+	if requestID == "" {
+		return nil, fmt.Errorf("voiceworld: signing part urls requires a request id")
+	}
+	if ttl <= 0 {
+		ttl = signedURLExpiry
+	}
+	store, err := c.storage()
+	if err != nil {
+		return nil, err
+	}
+	keys, err := store.ListObjects(requestID + "/")
+	if err != nil {
+		return nil, fmt.Errorf("voiceworld: list objects for %s: %w", requestID, err)
+	}
+	byIndex := make(map[int]string)
+	maxIndex := 0
+	for _, key := range keys {
+		index, ok := partNumberFromKey(key)
+		if !ok {
+			continue
+		}
+		byIndex[index] = key
+		if index > maxIndex {
+			maxIndex = index
+		}
+	}
+	if maxIndex == 0 {
+		return nil, fmt.Errorf("voiceworld: no parts staged under %s", requestID)
+	}
+	parts := make([]SignedPart, 0, maxIndex)
+	for index := 1; index <= maxIndex; index++ {
+		key, ok := byIndex[index]
+		if !ok {
+			parts = append(parts, SignedPart{Index: index})
+			continue
+		}
+		signed, expiresAt, err := c.signStorageURL(store, key, ttl)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, SignedPart{Index: index, Key: key, URL: signed, ExpiresAt: expiresAt})
+	}
+	return parts, nil
+}
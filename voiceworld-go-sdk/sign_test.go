@@ -0,0 +1,73 @@
+package voiceworld
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignRequestPartURLs(t *testing.T) {
+	client, store := newTestClient(t, nil)
+	now := time.Date(2026, 8, 30, 12, 0, 0, 0, time.UTC)
+	client.now = func() time.Time { return now }
+	for _, key := range []string{"req-1/0001.wav", "req-1/0002.wav", "req-1/0004.wav"} {
+		if err := store.PutObject(key, strings.NewReader("pcm")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.PutObject("req-1/manifest.json", strings.NewReader("{}")); err != nil {
+		t.Fatal(err)
+	}
+
+	parts, err := client.SignRequestPartURLs("req-1", 10*time.Minute)
+	if err != nil {
+		t.Fatalf("SignRequestPartURLs: %v", err)
+	}
+	if len(parts) != 4 {
+		t.Fatalf("got %d parts, want 4", len(parts))
+	}
+	for i, part := range parts {
+		if part.Index != i+1 {
+			t.Errorf("parts[%d].Index = %d, want %d", i, part.Index, i+1)
+		}
+	}
+	if parts[0].URL != "memory://bucket/req-1/0001.wav" {
+		t.Errorf("parts[0].URL = %q", parts[0].URL)
+	}
+	if !parts[0].ExpiresAt.Equal(now.Add(10 * time.Minute)) {
+		t.Errorf("parts[0].ExpiresAt = %v, want %v", parts[0].ExpiresAt, now.Add(10*time.Minute))
+	}
+	if parts[2].Key != "" || parts[2].URL != "" || !parts[2].ExpiresAt.IsZero() {
+		t.Errorf("missing part 3 should have empty fields, got %+v", parts[2])
+	}
+}
+
+func TestSignRequestPartURLsOffsetNames(t *testing.T) {
+	client, store := newTestClient(t, nil)
+	if err := store.PutObject("req-2/part_0002_0000060000ms.wav", strings.NewReader("pcm")); err != nil {
+		t.Fatal(err)
+	}
+	parts, err := client.SignRequestPartURLs("req-2", 0)
+	if err != nil {
+		t.Fatalf("SignRequestPartURLs: %v", err)
+	}
+	if len(parts) != 2 || parts[1].Key != "req-2/part_0002_0000060000ms.wav" {
+		t.Fatalf("got %+v, want index 1 missing and index 2 signed", parts)
+	}
+	if parts[0].URL != "" {
+		t.Errorf("part 1 was never staged but got URL %q", parts[0].URL)
+	}
+}
+
+func TestSignRequestPartURLsNoParts(t *testing.T) {
+	client, store := newTestClient(t, nil)
+	if _, err := client.SignRequestPartURLs("", time.Minute); err == nil {
+		t.Error("empty request id should be rejected")
+	}
+	if err := store.PutObject("req-3/manifest.json", strings.NewReader("{}")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.SignRequestPartURLs("req-3", time.Minute); err == nil {
+		t.Error("a prefix with no parts should be an error")
+	}
+}
@@ -0,0 +1,152 @@
+package voiceworld
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNewSignedRequestHeaders(t *testing.T) {
+	client, _ := newTestClient(t, &ClientConfig{BaseURL: "https://example.com/v1"})
+	req, err := client.NewSignedRequest(context.Background(), http.MethodPost, "/custom_endpoint",
+		bytes.NewReader([]byte(`{"x":1}`)), "application/json")
+	if err != nil {
+		t.Fatalf("NewSignedRequest: %v", err)
+	}
+	if got := req.URL.String(); got != "https://example.com/v1/custom_endpoint" {
+		t.Errorf("URL = %q", got)
+	}
+	if got := req.Header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if req.Header.Get(headerAppKey) != "test-app-key" {
+		t.Errorf("app key header = %q", req.Header.Get(headerAppKey))
+	}
+	ts := req.Header.Get(headerTimestamp)
+	nonce := req.Header.Get(headerNonce)
+	if ts == "" || nonce == "" {
+		t.Fatalf("timestamp/nonce headers = (%q, %q)", ts, nonce)
+	}
+	want := client.signature(http.MethodPost, "/v1/custom_endpoint", ts, nonce)
+	if got := req.Header.Get(headerSignature); got != want {
+		t.Errorf("signature = %q, want %q", got, want)
+	}
+	if req.Header.Get(headerIdempotencyKey) == "" {
+		t.Error("POST request missing idempotency key")
+	}
+
+	get, err := client.NewSignedRequest(context.Background(), http.MethodGet, "/custom_endpoint", nil, "")
+	if err != nil {
+		t.Fatalf("NewSignedRequest GET: %v", err)
+	}
+	if get.Header.Get(headerIdempotencyKey) != "" {
+		t.Error("GET request carries an idempotency key")
+	}
+}
+
+func TestNewSignedRequestBodyHash(t *testing.T) {
+	client, _ := newTestClient(t, &ClientConfig{
+		BaseURL:         "https://example.com",
+		SignRequestBody: true,
+	})
+	body := []byte(`{"payload":true}`)
+	req, err := client.NewSignedRequest(context.Background(), http.MethodPost, "/custom",
+		bytes.NewReader(body), "application/json")
+	if err != nil {
+		t.Fatalf("NewSignedRequest: %v", err)
+	}
+	if got := req.Header.Get(headerSignatureVersion); got != signatureVersion2 {
+		t.Fatalf("signature version = %q, want %q", got, signatureVersion2)
+	}
+	sum := sha256.Sum256(body)
+	want := client.signatureV2(http.MethodPost, "/custom",
+		req.Header.Get(headerTimestamp), req.Header.Get(headerNonce), hex.EncodeToString(sum[:]))
+	if got := req.Header.Get(headerSignature); got != want {
+		t.Errorf("signature = %q, want %q", got, want)
+	}
+}
+
+func TestDoJSONDecodesEnvelope(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(headerSignature) == "" {
+			t.Error("request arrived unsigned")
+		}
+		w.Write([]byte(`{"code":0,"data":{"value":"hello"}}`))
+	}))
+	defer server.Close()
+	client, _ := newTestClient(t, &ClientConfig{BaseURL: server.URL})
+
+	req, err := client.NewSignedRequest(context.Background(), http.MethodPost, "/unwrapped",
+		bytes.NewReader([]byte(`{}`)), "application/json")
+	if err != nil {
+		t.Fatalf("NewSignedRequest: %v", err)
+	}
+	var out struct {
+		Value string `json:"value"`
+	}
+	if err := client.DoJSON(req, &out); err != nil {
+		t.Fatalf("DoJSON: %v", err)
+	}
+	if out.Value != "hello" {
+		t.Errorf("decoded value = %q, want %q", out.Value, "hello")
+	}
+}
+
+func TestDoJSONSurfacesEnvelopeError(t *testing.T) {
+	server := newEnvelopeServer(t, `{"code":41002,"message":"quota exceeded"}`)
+	client, _ := newTestClient(t, &ClientConfig{BaseURL: server.URL})
+	req, err := client.NewSignedRequest(context.Background(), http.MethodGet, "/unwrapped", nil, "")
+	if err != nil {
+		t.Fatalf("NewSignedRequest: %v", err)
+	}
+	err = client.DoJSON(req, nil)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != 41002 {
+		t.Fatalf("err = %v, want *APIError with code 41002", err)
+	}
+}
+
+func TestDoJSONRetriesWithFreshSignature(t *testing.T) {
+	var nonces []string
+	var bodies []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nonces = append(nonces, r.Header.Get(headerNonce))
+		buf := new(bytes.Buffer)
+		buf.ReadFrom(r.Body)
+		bodies = append(bodies, buf.Len())
+		if len(nonces) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"code":0}`))
+	}))
+	defer server.Close()
+	client, _ := newTestClient(t, &ClientConfig{
+		BaseURL: server.URL,
+		Retry:   ExponentialBackoff{BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, MaxRetries: 2},
+	})
+
+	req, err := client.NewSignedRequest(context.Background(), http.MethodPost, "/unwrapped",
+		bytes.NewReader([]byte(`{"a":1}`)), "application/json")
+	if err != nil {
+		t.Fatalf("NewSignedRequest: %v", err)
+	}
+	if err := client.DoJSON(req, nil); err != nil {
+		t.Fatalf("DoJSON: %v", err)
+	}
+	if len(nonces) != 2 {
+		t.Fatalf("server saw %d attempts, want 2", len(nonces))
+	}
+	if nonces[0] == nonces[1] {
+		t.Error("retry reused the first attempt's nonce")
+	}
+	if bodies[0] != bodies[1] {
+		t.Errorf("retry body length = %d, first attempt = %d", bodies[1], bodies[0])
+	}
+}
@@ -0,0 +1,66 @@
+package voiceworld
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// SourceInfo records where an uploaded object came from: the original
+// recording's base name, byte size and modification time. Audits need the
+// time the audio was created, not the time it happened to be uploaded, so
+// the info rides along as object metadata (source-name, source-size and
+// source-mtime entries, surfacing as x-oss-meta-source-* on OSS).
+type SourceInfo struct {
+	// Name is the source file's base name; see sourceBaseName for how
+	// share and drive prefixes are handled.
+	Name string `json:"name"`
+	// Size is the source file's byte size.
+	Size int64 `json:"size"`
+	// ModTime is the source file's modification time, in UTC.
+	ModTime time.Time `json:"mod_time"`
+}
+
+// SourceInfoFor captures the SourceInfo of a local file, for
+// UploadOptions.Source.
+func SourceInfoFor(path string) (*SourceInfo, error) {
+	fi, err := os.Stat(longPath(path))
+	if err != nil {
+		return nil, fmt.Errorf("voiceworld: stat source %s: %w", path, err)
+	}
+	return &SourceInfo{
+		Name:    sourceBaseName(path),
+		Size:    fi.Size(),
+		ModTime: fi.ModTime().UTC(),
+	}, nil
+}
+
+// metadata renders the info as object metadata entries.
+func (s *SourceInfo) metadata() map[string]string {
+	return map[string]string{
+		"source-name":  s.Name,
+		"source-size":  strconv.FormatInt(s.Size, 10),
+		"source-mtime": s.ModTime.UTC().Format(time.RFC3339Nano),
+	}
+}
+
+// sourceModTimeFromMeta recovers the recorded modification time from object
+// metadata, when present and well-formed.
+func sourceModTimeFromMeta(meta map[string]string) (time.Time, bool) {
+	raw, ok := meta["source-mtime"]
+	if !ok {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339Nano, raw)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// metaReadStorage is the optional ObjectStorage capability of backends that
+// can read an object's user metadata back.
+type metaReadStorage interface {
+	GetObjectMeta(key string) (map[string]string, error)
+}
@@ -0,0 +1,123 @@
+package voiceworld
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// fixtureMtime is an arbitrary past timestamp tests stamp onto source files.
+var fixtureMtime = time.Date(2023, 4, 5, 6, 7, 8, 0, time.UTC)
+
+func makeDatedWAVFile(t *testing.T) string {
+	t.Helper()
+	path := makeWAVFile(t, 16000, 1, 0.1)
+	if err := os.Chtimes(path, fixtureMtime, fixtureMtime); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestUploadAttachesSourceMetadata(t *testing.T) {
+	client, store := newTestClient(t, nil)
+	path := makeDatedWAVFile(t)
+	source, err := SourceInfoFor(path)
+	if err != nil {
+		t.Fatalf("SourceInfoFor: %v", err)
+	}
+	if _, err := client.UploadFile(path, "req/a.wav", &UploadOptions{Source: source}); err != nil {
+		t.Fatalf("UploadFile: %v", err)
+	}
+	meta, ok := store.ObjectMeta("req/a.wav")
+	if !ok {
+		t.Fatal("object not stored")
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta["source-name"] != "audio.wav" {
+		t.Errorf("source-name = %q, want audio.wav", meta["source-name"])
+	}
+	if meta["source-size"] != strconv.FormatInt(fi.Size(), 10) {
+		t.Errorf("source-size = %q, want %d", meta["source-size"], fi.Size())
+	}
+	mtime, err := time.Parse(time.RFC3339Nano, meta["source-mtime"])
+	if err != nil || !mtime.Equal(fixtureMtime) {
+		t.Errorf("source-mtime = %q (%v), want %v", meta["source-mtime"], err, fixtureMtime)
+	}
+}
+
+func TestProcessAndUploadRecordsSource(t *testing.T) {
+	client, store := newTestClient(t, nil)
+	path := makeDatedWAVFile(t)
+	result, err := client.ProcessAndUploadFile(path, "req-1", &UploadOptions{UploadOriginal: true})
+	if err != nil {
+		t.Fatalf("ProcessAndUploadFile: %v", err)
+	}
+	if result.Source == nil || result.Source.Name != "audio.wav" || !result.Source.ModTime.Equal(fixtureMtime) {
+		t.Fatalf("result source = %+v, want audio.wav at %v", result.Source, fixtureMtime)
+	}
+	for _, key := range []string{result.ProcessedKey, result.RawKey} {
+		meta, ok := store.ObjectMeta(key)
+		if !ok {
+			t.Fatalf("object %s not stored", key)
+		}
+		if mtime, ok := sourceModTimeFromMeta(meta); !ok || !mtime.Equal(fixtureMtime) {
+			t.Errorf("%s source-mtime = %q, want %v", key, meta["source-mtime"], fixtureMtime)
+		}
+	}
+}
+
+func TestDownloadObjectToFileRestoresModTime(t *testing.T) {
+	client, store := newTestClient(t, nil)
+	path := makeDatedWAVFile(t)
+	source, err := SourceInfoFor(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.UploadFile(path, "req/a.wav", &UploadOptions{Source: source}); err != nil {
+		t.Fatalf("UploadFile: %v", err)
+	}
+	dest := filepath.Join(t.TempDir(), "restored.wav")
+	if err := client.DownloadObjectToFile("req/a.wav", dest, &DownloadOptions{RestoreModTime: true}); err != nil {
+		t.Fatalf("DownloadObjectToFile: %v", err)
+	}
+	want, _ := store.Object("req/a.wav")
+	got, err := os.ReadFile(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Error("downloaded bytes differ from the stored object")
+	}
+	fi, err := os.Stat(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fi.ModTime().UTC().Equal(fixtureMtime) {
+		t.Errorf("restored mtime = %v, want %v", fi.ModTime().UTC(), fixtureMtime)
+	}
+}
+
+func TestManifestRecordsSource(t *testing.T) {
+	client, _ := newTestClient(t, nil)
+	path := makeDatedWAVFile(t)
+	result, err := client.SplitAudioFile(path, 1, &SplitOptions{WriteManifest: true})
+	if err != nil {
+		t.Fatalf("SplitAudioFile: %v", err)
+	}
+	m, err := client.VerifyManifest(result.RequestID)
+	if err != nil {
+		t.Fatalf("VerifyManifest: %v", err)
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Source == nil || m.Source.Size != fi.Size() || !m.Source.ModTime.Equal(fixtureMtime) {
+		t.Errorf("manifest source = %+v, want size %d at %v", m.Source, fi.Size(), fixtureMtime)
+	}
+}
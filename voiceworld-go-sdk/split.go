@@ -0,0 +1,804 @@
+package voiceworld
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultSplitDuration is the chunk length SplitAudioFile uses when the
+// caller passes a non-positive duration, in seconds.
+const DefaultSplitDuration = 60
+
+// SplitPart describes one uploaded chunk of a split audio file.
+type SplitPart struct {
+	// Index is the 1-based position of the chunk in the original audio.
+	Index int `json:"index"`
+	// ObjectKey is the staging bucket key the chunk was uploaded under.
+	ObjectKey string `json:"object_key"`
+	// URL is a signed download URL for the chunk.
+	URL string `json:"url"`
+	// ExpiresAt is when URL stops working, so consumers can schedule a
+	// refresh (see SplitAudioFileResult.RefreshURLs). Zero on parts that
+	// have no URL.
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+	// Size is the chunk file size in bytes, including the WAV header.
+	Size int64 `json:"size"`
+	// Duration is the chunk audio length in seconds.
+	Duration float64 `json:"duration"`
+	// Error is set only in ContinueOnError mode, on parts whose upload
+	// failed. Such parts keep their Index, Size and Duration but have no
+	// ObjectKey or URL.
+	Error string `json:"error,omitempty"`
+	// Provenance is set only in DedupeByContent mode: "uploaded",
+	// "copied" or "referenced"; see the Provenance constants.
+	Provenance string `json:"provenance,omitempty"`
+}
+
+// SplitAudioFileResult is the outcome of splitting and staging one file.
+type SplitAudioFileResult struct {
+	RequestID string `json:"request_id"`
+	// Parts holds one entry per planned chunk, in chunk order, so
+	// Parts[i] always describes chunk i+1 even when some uploads failed
+	// in ContinueOnError mode.
+	Parts []SplitPart `json:"parts"`
+	// FailedParts counts the Parts entries that carry an Error. It is
+	// only ever non-zero in ContinueOnError mode.
+	FailedParts int `json:"failed_parts,omitempty"`
+	// TotalDuration is the source audio length in seconds.
+	TotalDuration float64 `json:"total_duration"`
+	// Report describes how the operation spent its time; see
+	// OperationReport.
+	Report *OperationReport `json:"report,omitempty"`
+}
+
+// SplitOptions carries the optional knobs for SplitAudioFile. Nil and the
+// zero value keep the historical behaviour.
+type SplitOptions struct {
+	// RequestID stages the chunks under a caller-chosen prefix instead of
+	// a generated one, so related operations can share a request id. It
+	// must satisfy the object-key-safe character set (see
+	// validateRequestID); empty means generate. Either way the result
+	// reports the id actually used.
+	RequestID string
+
+	// CleanupOnFailure deletes the chunks that were already staged when
+	// the operation fails midway, instead of leaving them orphaned in
+	// the bucket. Either way the returned PartialUploadError lists the
+	// staged keys, so callers can clean up or resume themselves.
+	CleanupOnFailure bool
+
+	// ContinueOnError records per-chunk upload failures on the result
+	// (Parts[i].Error) instead of aborting the operation. The call only
+	// fails when every chunk does. Feed the surviving parts to
+	// recognition as usual: MergeResults already skips nil entries, so
+	// indices stay meaningful end to end.
+	ContinueOnError bool
+
+	// ContiguousReady, when non-nil, is called after each chunk upload
+	// that extends the contiguous completed prefix, with the highest part
+	// index N such that parts 1..N are all staged. Reported values are
+	// strictly increasing even when Parallelism finishes chunks out of
+	// order, so downstream recognition can start consuming parts 1..N
+	// while later parts still upload. It runs on an uploading goroutine
+	// under the operation's lock; keep it fast.
+	ContiguousReady func(n int)
+
+	// PartNameFunc generates the object key for each chunk. index is
+	// 1-based, total is the number of planned chunks, and startMs is
+	// where the chunk begins in the source audio. Nil means
+	// DefaultPartName. The generated names must be unique within the
+	// request and should keep the requestID prefix so Cleanup can find
+	// them.
+	PartNameFunc func(requestID string, index, total int, startMs int64) string
+
+	// WriteManifest stages a signed manifest.json next to the chunks
+	// once they have all uploaded, so consumers of the prefix can verify
+	// their downloads offline; see SplitManifest and VerifyManifest.
+	// Nothing is written when any chunk failed (ContinueOnError).
+	WriteManifest bool
+
+	// DedupeByContent hashes each chunk (SHA-256) and stages it under the
+	// content-addressed "_content/" prefix at most once, so resubmitting
+	// the same recording under a new requestID moves no duplicate bytes.
+	// DedupeMode selects how an already-staged chunk lands in the result;
+	// each part's Provenance says which way it went. Content objects are
+	// shared across requests and are never removed by CleanupOnFailure.
+	DedupeByContent bool
+	DedupeMode      DedupeMode
+
+	// Checksum additionally records each chunk's digest in the manifest
+	// (WriteManifest) with the selected algorithm, next to the
+	// always-present CRC-64, and VerifyManifest then re-checks both. Nil
+	// records CRC-64 only.
+	Checksum Checksummer
+}
+
+// DefaultPartName is the chunk naming SplitAudioFile uses when
+// SplitOptions.PartNameFunc is nil: "<requestID>/0001.wav", zero-padded so
+// lexical and part order agree.
+func DefaultPartName(requestID string, index, total int, startMs int64) string {
+	return fmt.Sprintf("%s/%04d.wav", requestID, index)
+}
+
+// OffsetPartName names chunks "<requestID>/part_0001_0000000000ms.wav",
+// embedding the chunk's start offset for consumers that need to place a
+// chunk on the source timeline from its key alone. Lexical and part order
+// agree.
+func OffsetPartName(requestID string, index, total int, startMs int64) string {
+	return fmt.Sprintf("%s/part_%04d_%010dms.wav", requestID, index, startMs)
+}
+
+// contiguousTracker turns out-of-order chunk completions into the strictly
+// increasing frontier SplitOptions.ContiguousReady promises: the highest
+// part index N with parts 1..N all done.
+type contiguousTracker struct {
+	done     []bool
+	frontier int
+	notify   func(n int)
+}
+
+// newContiguousTracker tracks total 1-based part indices, reporting frontier
+// advances to notify (nil disables reporting).
+func newContiguousTracker(total int, notify func(int)) *contiguousTracker {
+	return &contiguousTracker{done: make([]bool, total+1), notify: notify}
+}
+
+// complete marks one part done and fires the callback when the frontier
+// advanced. Callers must serialize calls (splitAudioFile holds its result
+// mutex), which is what keeps reported values from skipping or regressing.
+func (ct *contiguousTracker) complete(index int) {
+	ct.done[index] = true
+	advanced := false
+	for ct.frontier+1 < len(ct.done) && ct.done[ct.frontier+1] {
+		ct.frontier++
+		advanced = true
+	}
+	if advanced && ct.notify != nil {
+		ct.notify(ct.frontier)
+	}
+}
+
+// PartialUploadError reports a split upload that failed after some chunks
+// were already staged.
+type PartialUploadError struct {
+	// RequestID is the prefix the chunks were staged under.
+	RequestID string
+	// UploadedKeys are the object keys staged before the operation
+	// stopped, in index order. With CleanupOnFailure they have been
+	// deleted (unless CleanupErr says otherwise).
+	UploadedKeys []string
+	// Err is the failure that stopped the operation.
+	Err error
+	// CleanupErr is a failure encountered while deleting the staged
+	// chunks; it never masks Err.
+	CleanupErr error
+}
+
+// Error implements the error interface.
+func (e *PartialUploadError) Error() string {
+	msg := fmt.Sprintf("voiceworld: split upload failed with %d chunks staged under %s: %v",
+		len(e.UploadedKeys), e.RequestID, e.Err)
+	if e.CleanupErr != nil {
+		msg += fmt.Sprintf(" (cleanup also failed: %v)", e.CleanupErr)
+	}
+	return msg
+}
+
+// Unwrap exposes the original cause to errors.Is/As.
+func (e *PartialUploadError) Unwrap() error { return e.Err }
+
+// SplitAudioFile cuts a PCM WAV file into chunks of roughly chunkDuration
+// seconds (aligned to frame boundaries), uploads each chunk to the staging
+// bucket under "<requestID>/<index>.wav", and returns the uploaded parts in
+// order. chunkDuration <= 0 means DefaultSplitDuration. Passing more than
+// one options value is an error.
+func (c *Client) SplitAudioFile(filePath string, chunkDuration int, options ...*SplitOptions) (*SplitAudioFileResult, error) {
+	if len(options) > 1 {
+		return nil, fmt.Errorf("voiceworld: at most one SplitOptions may be given, got %d", len(options))
+	}
+	opts := &SplitOptions{}
+	if len(options) == 1 && options[0] != nil {
+		opts = options[0]
+	}
+	status, err := c.life.beginOp("split")
+	if err != nil {
+		return nil, err
+	}
+	defer c.life.endOp(status)
+	report := c.newOperationReport("split_audio_file")
+	result, err := c.splitAudioFile(filePath, chunkDuration, opts, report, status)
+	c.finishReport(report, err)
+	return result, err
+}
+
+// splitAudioFile is SplitAudioFile with the options and operation report
+// threaded through.
+func (c *Client) splitAudioFile(filePath string, chunkDuration int, opts *SplitOptions, report *OperationReport, status *liveOp) (*SplitAudioFileResult, error) {
+	begin := c.now()
+	// Frame-compressed telephony containers have no byte boundaries safe
+	// to cut at; refusing them beats shipping chunks no decoder accepts.
+	if format, ferr := DetectAudioFormat(filePath); ferr == nil && isTelephonyFormat(format) {
+		return nil, invalidAudio("%s audio cannot be split at byte boundaries; transcode it to wav first (ProcessAudio does this when ffmpeg is installed)", format)
+	}
+	info, err := ParseWAVFile(filePath)
+	report.HeaderParse = c.now().Sub(begin)
+	if err != nil {
+		return nil, err
+	}
+	requestID := opts.RequestID
+	if requestID == "" {
+		requestID = NewRequestID()
+	} else if err := validateRequestID(requestID); err != nil {
+		return nil, err
+	}
+	begin = c.now()
+	plans, err := PlanSplit(info, requestID, chunkDuration, opts)
+	report.Validation = c.now().Sub(begin)
+	if err != nil {
+		return nil, err
+	}
+
+	// One tracker spans the whole split; totals cover every chunk file
+	// including its fresh WAV header.
+	var totalBytes int64
+	spans := make([]chunkSpan, len(plans))
+	for i, plan := range plans {
+		totalBytes += plan.Size + 44
+		spans[i] = chunkSpan{offset: plan.Offset, size: plan.Size}
+	}
+	status.setTotals(totalBytes, len(plans))
+	op := &uploadOp{
+		tracker: newProgressTracker(c.config.Progress, totalBytes),
+		budget:  newRetryBudget(c.config.MaxTotalRetries),
+		report:  report,
+		status:  status,
+	}
+
+	report.RequestID = requestID
+	status.setRequestID(requestID)
+	result := &SplitAudioFileResult{
+		RequestID:     requestID,
+		Parts:         make([]SplitPart, len(plans)),
+		TotalDuration: info.Duration(),
+		Report:        report,
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		failed   int
+	)
+	contiguous := newContiguousTracker(len(plans), opts.ContiguousReady)
+	sem := make(chan struct{}, c.config.Parallelism)
+	for i := range spans {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			part, err := c.uploadChunk(filePath, info, plans[i].ObjectKey, i, spans[i], op, opts)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				failed++
+				if opts.ContinueOnError {
+					// Keep the slot aligned: the failed chunk keeps its
+					// identity, just without a staged object.
+					result.Parts[i] = SplitPart{
+						Index:    plans[i].Index,
+						Size:     plans[i].Size + 44,
+						Duration: plans[i].Duration,
+						Error:    err.Error(),
+					}
+				}
+				return
+			}
+			result.Parts[i] = *part
+			contiguous.complete(plans[i].Index)
+		}(i)
+	}
+	wg.Wait()
+	if opts.ContinueOnError {
+		if failed == len(spans) {
+			return nil, fmt.Errorf("voiceworld: all %d chunks failed to upload: %w", failed, firstErr)
+		}
+		result.FailedParts = failed
+	} else if firstErr != nil {
+		return nil, c.failSplit(result, firstErr, opts, report)
+	}
+	if opts.WriteManifest && failed == 0 {
+		if err := c.writeManifest(filePath, info, spans, result, opts, report); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// failSplit turns a mid-split failure into a PartialUploadError listing the
+// chunks that made it into the bucket, deleting them first when the caller
+// asked for CleanupOnFailure. When nothing was staged the cause is returned
+// as-is.
+func (c *Client) failSplit(result *SplitAudioFileResult, cause error, opts *SplitOptions, report *OperationReport) error {
+	var uploaded []string
+	for _, part := range result.Parts {
+		if part.ObjectKey != "" {
+			uploaded = append(uploaded, part.ObjectKey)
+		}
+	}
+	if len(uploaded) == 0 {
+		return cause
+	}
+	perr := &PartialUploadError{
+		RequestID:    result.RequestID,
+		UploadedKeys: uploaded,
+		Err:          cause,
+	}
+	if opts.CleanupOnFailure {
+		// Content-addressed objects are shared across requests and stay.
+		deletable := make([]string, 0, len(uploaded))
+		for _, key := range uploaded {
+			if !strings.HasPrefix(key, contentPrefix) {
+				deletable = append(deletable, key)
+			}
+		}
+		store, err := c.storageWithReport(report)
+		if err == nil && len(deletable) > 0 {
+			err = store.DeleteObjects(deletable)
+		}
+		if err != nil {
+			perr.CleanupErr = err
+		}
+	}
+	return perr
+}
+
+// ListRequestParts returns the chunks staged under requestID, in part
+// order, by parsing part numbers back out of the object keys. Both the
+// DefaultPartName pattern ("0001.wav") and part_-prefixed names are
+// understood, with or without zero padding; keys that carry no part number
+// are skipped. Only Index and ObjectKey are populated — sizes and durations
+// are not recoverable from a listing.
+func (c *Client) ListRequestParts(requestID string) ([]SplitPart, error) {
+	if requestID == "" {
+		return nil, fmt.Errorf("voiceworld: listing parts requires a request id")
+	}
+	store, err := c.storage()
+	if err != nil {
+		return nil, err
+	}
+	keys, err := store.ListObjects(requestID + "/")
+	if err != nil {
+		return nil, fmt.Errorf("voiceworld: list objects for %s: %w", requestID, err)
+	}
+	var parts []SplitPart
+	for _, key := range keys {
+		index, ok := partNumberFromKey(key)
+		if !ok {
+			continue
+		}
+		parts = append(parts, SplitPart{Index: index, ObjectKey: key})
+	}
+	sort.Slice(parts, func(i, j int) bool { return parts[i].Index < parts[j].Index })
+	return parts, nil
+}
+
+// partNumberFromKey parses the 1-based part number out of a chunk object
+// key: the first digit run of the key's base name, after an optional
+// "part_" prefix. "req/0002.wav", "req/part_2.wav" and offset-bearing names
+// like "req/part_0002_0000060000ms.wav" all yield 2.
+func partNumberFromKey(key string) (int, bool) {
+	name := key
+	if i := strings.LastIndexByte(name, '/'); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.TrimPrefix(name, "part_")
+	end := 0
+	for end < len(name) && name[end] >= '0' && name[end] <= '9' {
+		end++
+	}
+	n, err := strconv.Atoi(name[:end])
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// chunkSpan is one planned chunk: a byte range relative to the start of the
+// PCM data.
+type chunkSpan struct {
+	offset int64
+	size   int64
+}
+
+// ChunkPlan describes one chunk SplitAudioFile will cut, before any I/O
+// happens. Offset and Size are relative to the PCM data; the staged chunk
+// file is Size plus its fresh 44-byte WAV header.
+type ChunkPlan struct {
+	// Index is the 1-based chunk position.
+	Index int `json:"index"`
+	// ObjectKey is the key the chunk will be staged under.
+	ObjectKey string `json:"object_key"`
+	// Offset is the chunk's byte offset into the source PCM data.
+	Offset int64 `json:"offset"`
+	// Size is the chunk's PCM byte count, excluding the WAV header.
+	Size int64 `json:"size"`
+	// StartMs is where the chunk begins on the source timeline.
+	StartMs int64 `json:"start_ms"`
+	// Duration is the chunk audio length in seconds.
+	Duration float64 `json:"duration"`
+}
+
+// PlanSplit computes the exact chunks SplitAudioFile would stage under
+// requestID, without touching any file or the network, so external
+// schedulers can pre-create one task per chunk. The splitter itself runs on
+// a PlanSplit plan, so the two can never drift apart. chunkDuration <= 0
+// means DefaultSplitDuration; passing more than one options value is an
+// error.
+func PlanSplit(info *WAVInfo, requestID string, chunkDuration int, options ...*SplitOptions) ([]ChunkPlan, error) {
+	if len(options) > 1 {
+		return nil, fmt.Errorf("voiceworld: at most one SplitOptions may be given, got %d", len(options))
+	}
+	opts := &SplitOptions{}
+	if len(options) == 1 && options[0] != nil {
+		opts = options[0]
+	}
+	spans, err := planChunks(info, chunkDuration)
+	if err != nil {
+		return nil, err
+	}
+	nameFunc := opts.PartNameFunc
+	if nameFunc == nil {
+		nameFunc = DefaultPartName
+	}
+	plans := make([]ChunkPlan, len(spans))
+	for i, span := range spans {
+		startMs := span.offset * 1000 / int64(info.BytesPerSecond())
+		plans[i] = ChunkPlan{
+			Index:     i + 1,
+			ObjectKey: nameFunc(requestID, i+1, len(spans), startMs),
+			Offset:    span.offset,
+			Size:      span.size,
+			StartMs:   startMs,
+			Duration:  float64(span.size) / float64(info.BytesPerSecond()),
+		}
+	}
+	return plans, nil
+}
+
+// planChunks computes the byte spans SplitAudioFile will cut, without
+// touching the file beyond the already-parsed header. chunkDuration <= 0
+// means DefaultSplitDuration. The plan is validated end to end: spans are
+// frame-aligned, within the data chunk, and non-empty.
+func planChunks(info *WAVInfo, chunkDuration int) ([]chunkSpan, error) {
+	if err := info.validate(); err != nil {
+		return nil, err
+	}
+	if chunkDuration <= 0 {
+		chunkDuration = DefaultSplitDuration
+	}
+	if info.DataSize == 0 {
+		return nil, invalidAudio("no pcm data to split")
+	}
+	chunkBytes := alignToFrames(int64(info.BytesPerSecond())*int64(chunkDuration), info)
+	if chunkBytes <= 0 {
+		return nil, invalidAudio("chunk duration %ds is shorter than one frame", chunkDuration)
+	}
+
+	var spans []chunkSpan
+	for offset := int64(0); offset < info.DataSize; offset += chunkBytes {
+		size := chunkBytes
+		if offset+size > info.DataSize {
+			size = info.DataSize - offset
+		}
+		spans = append(spans, chunkSpan{offset: offset, size: size})
+	}
+	return spans, nil
+}
+
+// uploadChunk extracts one planned chunk of the source file into a temp WAV
+// and uploads it under objectKey, hashing it along the way when the split
+// dedupes by content.
+func (c *Client) uploadChunk(filePath string, info *WAVInfo, objectKey string, i int, span chunkSpan, op *uploadOp, opts *SplitOptions) (*SplitPart, error) {
+	dir, err := c.life.chunkTempDir()
+	if err != nil {
+		return nil, fmt.Errorf("voiceworld: create chunk temp dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, "chunk-*.wav")
+	if err != nil {
+		return nil, fmt.Errorf("voiceworld: create chunk temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	var w io.Writer = tmp
+	hasher := ChecksumSHA256.New()
+	if opts.DedupeByContent {
+		w = io.MultiWriter(tmp, hasher)
+	}
+	err = writeChunk(w, filePath, info, span.offset, span.size)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("voiceworld: write chunk %d: %w", i+1, err)
+	}
+
+	if opts.DedupeByContent {
+		return c.uploadChunkDeduped(tmpPath, hasher.Sum(nil), objectKey, i, span, info, op, opts)
+	}
+	url, expiresAt, err := c.uploadFile(tmpPath, objectKey, i+1, op)
+	if err != nil {
+		return nil, err
+	}
+	return &SplitPart{
+		Index:     i + 1,
+		ObjectKey: objectKey,
+		URL:       url,
+		ExpiresAt: expiresAt,
+		Size:      span.size + 44,
+		Duration:  float64(span.size) / float64(info.BytesPerSecond()),
+	}, nil
+}
+
+// uploadChunkDeduped stages one chunk content-addressed: the bytes move
+// only when no object with the chunk's hash exists yet, and an
+// already-staged chunk is either copied to objectKey or recorded under its
+// content key, per SplitOptions.DedupeMode.
+func (c *Client) uploadChunkDeduped(tmpPath string, sum []byte, objectKey string, i int, span chunkSpan, info *WAVInfo, op *uploadOp, opts *SplitOptions) (*SplitPart, error) {
+	store, err := c.storageWithReport(op.report)
+	if err != nil {
+		return nil, err
+	}
+	contentKey := contentObjectKey(sum)
+	exists, err := store.IsObjectExist(contentKey)
+	if err != nil {
+		return nil, fmt.Errorf("voiceworld: check content object %s: %w", contentKey, err)
+	}
+	provenance := ProvenanceUploaded
+	if !exists {
+		if _, _, err := c.uploadFile(tmpPath, contentKey, i+1, op); err != nil {
+			return nil, err
+		}
+	} else {
+		// The skipped upload still counts as finished work.
+		op.tracker.setPart(i+1, span.size+44)
+		op.status.addProgress(span.size+44, 1)
+	}
+
+	finalKey := contentKey
+	switch {
+	case opts.DedupeMode == DedupeCopy:
+		cs, ok := store.(copyStorage)
+		if !ok {
+			return nil, errNoCopySupport
+		}
+		if err := cs.CopyObject(contentKey, objectKey); err != nil {
+			return nil, fmt.Errorf("voiceworld: copy content object to %s: %w", objectKey, err)
+		}
+		finalKey = objectKey
+		if exists {
+			provenance = ProvenanceCopied
+		}
+	case exists:
+		provenance = ProvenanceReferenced
+	}
+
+	url, expiresAt, err := c.signStorageURL(store, finalKey, signedURLExpiry)
+	if err != nil {
+		return nil, err
+	}
+	return &SplitPart{
+		Index:      i + 1,
+		ObjectKey:  finalKey,
+		URL:        url,
+		ExpiresAt:  expiresAt,
+		Size:       span.size + 44,
+		Duration:   float64(span.size) / float64(info.BytesPerSecond()),
+		Provenance: provenance,
+	}, nil
+}
+
+// writeChunk copies size bytes of PCM data starting at offset (relative to
+// the data chunk) from the source file into w behind a fresh WAV header.
+func writeChunk(w io.Writer, filePath string, info *WAVInfo, offset, size int64) error {
+	src, err := os.Open(longPath(filePath))
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	if _, err := src.Seek(info.DataOffset+offset, io.SeekStart); err != nil {
+		return err
+	}
+	if err := writeWAVHeader(w, info, size); err != nil {
+		return err
+	}
+	_, err = io.CopyN(w, src, size)
+	return err
+}
+
+// alignToFrames rounds n down to a whole number of PCM frames so a chunk
+// never ends mid-sample.
+func alignToFrames(n int64, info *WAVInfo) int64 {
+	align := int64(info.blockAlign())
+	if align <= 0 {
+		return n
+	}
+	return n - n%align
+}
+
+// MergedResult is the outcome of stitching per-chunk transcripts together,
+// with metadata about how it was assembled.
+type MergedResult struct {
+	*RecognitionResult
+	// SpeakerMap records, per input chunk, how that chunk's local
+	// diarization labels were relabeled to the stable global speaker set
+	// ("S1", "S2", ...), for auditing. Nil input chunks get a nil entry.
+	SpeakerMap []map[string]string
+	// HealedBoundaries records the chunk seams HealBoundaries
+	// re-recognized and spliced, in boundary order. Nil when no healing
+	// pass ran, and omitted from JSON then, so plain merges serialize
+	// exactly as before healing existed.
+	HealedBoundaries []HealedBoundary `json:"healed_boundaries,omitempty"`
+}
+
+// MergeResults stitches the per-chunk transcripts of a split recognition
+// back into one result. Word and segment timestamps are shifted by the
+// accumulated duration of the preceding chunks; per-segment annotations
+// (language, emotion, speech rate) are carried through unchanged. Because
+// each chunk is diarized independently, speaker labels are reconciled across
+// chunks: overlapping identical words pin a chunk's speakers to the previous
+// chunk's, and chunks without overlap fall back to matching speakers by
+// speaking-time rank. Segments and words are relabeled to the resulting
+// global IDs. Nil entries are skipped. The merged TaskID is taken from the
+// first result.
+func MergeResults(results []*RecognitionResult) *MergedResult {
+	merged := &MergedResult{RecognitionResult: &RecognitionResult{}}
+	var texts []string
+	var offset time.Duration
+	globalDur := map[string]time.Duration{} // speaking time per global speaker
+	nextGlobal := 0
+	for _, r := range results {
+		if r == nil {
+			merged.SpeakerMap = append(merged.SpeakerMap, nil)
+			continue
+		}
+		if merged.TaskID == "" {
+			merged.TaskID = r.TaskID
+		}
+		if merged.DetectedLanguage == "" {
+			merged.DetectedLanguage = r.DetectedLanguage
+		}
+		if r.Result != "" {
+			texts = append(texts, r.Result)
+		}
+
+		shifted := make([]Segment, len(r.Segments))
+		for i, s := range r.Segments {
+			s.BeginTime.Duration += offset
+			s.EndTime.Duration += offset
+			shifted[i] = s
+		}
+		mapping := reconcileChunkSpeakers(merged.Segments, shifted, globalDur, &nextGlobal)
+		for _, s := range shifted {
+			if g, ok := mapping[s.SpeakerID]; ok {
+				s.SpeakerID = g
+			}
+			globalDur[s.SpeakerID] += s.EndTime.Duration - s.BeginTime.Duration
+			merged.Segments = append(merged.Segments, s)
+		}
+		for _, w := range r.Words {
+			w.BeginTime.Duration += offset
+			w.EndTime.Duration += offset
+			if g, ok := mapping[w.SpeakerID]; ok {
+				w.SpeakerID = g
+			}
+			merged.Words = append(merged.Words, w)
+		}
+		merged.SpeakerMap = append(merged.SpeakerMap, mapping)
+		offset += r.Duration.Duration
+	}
+	merged.Result = strings.Join(texts, " ")
+	merged.Duration = NewTimestamp(offset)
+	return merged
+}
+
+// reconcileChunkSpeakers maps one chunk's local speaker labels onto the
+// global set built so far. prev holds the already-relabeled segments of the
+// preceding chunks; cur holds this chunk's segments with timestamps already
+// shifted into the global timeline. Preference order: a vote from the
+// overlap region (the same word at the same time must be the same person),
+// then matching by speaking-time rank, then a fresh global ID.
+func reconcileChunkSpeakers(prev, cur []Segment, globalDur map[string]time.Duration, nextGlobal *int) map[string]string {
+	// Local speakers with their speaking time, in order of appearance.
+	var locals []string
+	localDur := map[string]time.Duration{}
+	for _, s := range cur {
+		if s.SpeakerID == "" {
+			continue
+		}
+		if _, seen := localDur[s.SpeakerID]; !seen {
+			locals = append(locals, s.SpeakerID)
+		}
+		localDur[s.SpeakerID] += s.EndTime.Duration - s.BeginTime.Duration
+	}
+	if len(locals) == 0 {
+		return map[string]string{}
+	}
+
+	// Votes from the overlap region: identical text at intersecting times.
+	votes := map[string]map[string]int{}
+	for _, c := range cur {
+		if c.SpeakerID == "" {
+			continue
+		}
+		for _, p := range prev {
+			if p.SpeakerID == "" || c.Text != p.Text {
+				continue
+			}
+			if c.BeginTime.Duration < p.EndTime.Duration && p.BeginTime.Duration < c.EndTime.Duration {
+				if votes[c.SpeakerID] == nil {
+					votes[c.SpeakerID] = map[string]int{}
+				}
+				votes[c.SpeakerID][p.SpeakerID]++
+			}
+		}
+	}
+
+	mapping := map[string]string{}
+	taken := map[string]bool{}
+	for _, local := range locals {
+		best, bestVotes := "", 0
+		for global, n := range votes[local] {
+			if !taken[global] && (n > bestVotes || n == bestVotes && global < best) {
+				best, bestVotes = global, n
+			}
+		}
+		if best != "" {
+			mapping[local] = best
+			taken[best] = true
+		}
+	}
+
+	// Remaining locals: match by speaking-time rank against the unclaimed
+	// global speakers, then invent new IDs.
+	var unmapped []string
+	for _, local := range locals {
+		if _, ok := mapping[local]; !ok {
+			unmapped = append(unmapped, local)
+		}
+	}
+	sort.SliceStable(unmapped, func(i, j int) bool {
+		return localDur[unmapped[i]] > localDur[unmapped[j]]
+	})
+	var free []string
+	for global := range globalDur {
+		if !taken[global] {
+			free = append(free, global)
+		}
+	}
+	sort.Slice(free, func(i, j int) bool {
+		if globalDur[free[i]] != globalDur[free[j]] {
+			return globalDur[free[i]] > globalDur[free[j]]
+		}
+		return free[i] < free[j]
+	})
+	for i, local := range unmapped {
+		if i < len(free) {
+			mapping[local] = free[i]
+			continue
+		}
+		*nextGlobal++
+		mapping[local] = fmt.Sprintf("S%d", *nextGlobal)
+	}
+	return mapping
+}
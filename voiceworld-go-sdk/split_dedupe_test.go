@@ -0,0 +1,175 @@
+package voiceworld
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// putCountingStorage counts PutObject calls, so dedupe tests can assert
+// that duplicate chunk bytes never travel again.
+type putCountingStorage struct {
+	*MemoryStorage
+	puts int32
+}
+
+func (s *putCountingStorage) PutObject(key string, r io.Reader) error {
+	atomic.AddInt32(&s.puts, 1)
+	return s.MemoryStorage.PutObject(key, r)
+}
+
+func dedupeTestClient(t *testing.T) (*Client, *putCountingStorage) {
+	t.Helper()
+	store := &putCountingStorage{MemoryStorage: NewMemoryStorage()}
+	client, err := NewClient("ak", "sk", &ClientConfig{Storage: store})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, store
+}
+
+func TestSplitDedupeAllDuplicates(t *testing.T) {
+	client, store := dedupeTestClient(t)
+	wav := makeWAVFile(t, 16000, 1, 3)
+	opts := &SplitOptions{DedupeByContent: true}
+
+	first, err := client.SplitAudioFile(wav, 1, opts)
+	if err != nil {
+		t.Fatalf("first split: %v", err)
+	}
+	for _, part := range first.Parts {
+		if part.Provenance != ProvenanceUploaded {
+			t.Errorf("first split part %d provenance = %q, want %q", part.Index, part.Provenance, ProvenanceUploaded)
+		}
+		if !strings.HasPrefix(part.ObjectKey, first.RequestID+"/") {
+			t.Errorf("copy mode part key = %q, want under %s/", part.ObjectKey, first.RequestID)
+		}
+	}
+	content, err := store.ListObjects("_content/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(content) != 3 {
+		t.Fatalf("content objects after first split = %v, want 3", content)
+	}
+	putsAfterFirst := atomic.LoadInt32(&store.puts)
+
+	second, err := client.SplitAudioFile(wav, 1, opts)
+	if err != nil {
+		t.Fatalf("second split: %v", err)
+	}
+	for _, part := range second.Parts {
+		if part.Provenance != ProvenanceCopied {
+			t.Errorf("second split part %d provenance = %q, want %q", part.Index, part.Provenance, ProvenanceCopied)
+		}
+	}
+	if puts := atomic.LoadInt32(&store.puts); puts != putsAfterFirst {
+		t.Errorf("second split issued %d fresh PutObject calls", puts-putsAfterFirst)
+	}
+	// The copies still land under the new request's prefix.
+	keys, err := client.ListRequestParts(second.RequestID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 3 {
+		t.Errorf("ListRequestParts(%s) = %d parts, want 3", second.RequestID, len(keys))
+	}
+}
+
+func TestSplitDedupeMixed(t *testing.T) {
+	client, _ := dedupeTestClient(t)
+	wav := makeWAVFile(t, 16000, 1, 2)
+	opts := &SplitOptions{DedupeByContent: true}
+	if _, err := client.SplitAudioFile(wav, 1, opts); err != nil {
+		t.Fatalf("first split: %v", err)
+	}
+
+	// Same first second of audio, different second one: only the changed
+	// chunk should move bytes again.
+	data, err := os.ReadFile(wav)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bytesPerSecond := 16000 * 2
+	for i := 44 + bytesPerSecond; i < len(data); i += 97 {
+		data[i] ^= 0xff
+	}
+	edited := filepath.Join(t.TempDir(), "edited.wav")
+	if err := os.WriteFile(edited, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := client.SplitAudioFile(edited, 1, opts)
+	if err != nil {
+		t.Fatalf("second split: %v", err)
+	}
+	if len(result.Parts) != 2 {
+		t.Fatalf("parts = %d, want 2", len(result.Parts))
+	}
+	if result.Parts[0].Provenance != ProvenanceCopied {
+		t.Errorf("unchanged chunk provenance = %q, want %q", result.Parts[0].Provenance, ProvenanceCopied)
+	}
+	if result.Parts[1].Provenance != ProvenanceUploaded {
+		t.Errorf("changed chunk provenance = %q, want %q", result.Parts[1].Provenance, ProvenanceUploaded)
+	}
+}
+
+func TestSplitDedupeReferenceMode(t *testing.T) {
+	client, store := dedupeTestClient(t)
+	wav := makeWAVFile(t, 16000, 1, 2)
+	opts := &SplitOptions{DedupeByContent: true, DedupeMode: DedupeReference}
+
+	first, err := client.SplitAudioFile(wav, 1, opts)
+	if err != nil {
+		t.Fatalf("first split: %v", err)
+	}
+	for _, part := range first.Parts {
+		if !strings.HasPrefix(part.ObjectKey, "_content/") {
+			t.Errorf("reference mode key = %q, want a _content/ key", part.ObjectKey)
+		}
+		if part.Provenance != ProvenanceUploaded {
+			t.Errorf("fresh part provenance = %q, want %q", part.Provenance, ProvenanceUploaded)
+		}
+		if part.URL == "" {
+			t.Error("referenced part has no signed URL")
+		}
+	}
+
+	second, err := client.SplitAudioFile(wav, 1, opts)
+	if err != nil {
+		t.Fatalf("second split: %v", err)
+	}
+	for i, part := range second.Parts {
+		if part.Provenance != ProvenanceReferenced {
+			t.Errorf("part %d provenance = %q, want %q", part.Index, part.Provenance, ProvenanceReferenced)
+		}
+		if part.ObjectKey != first.Parts[i].ObjectKey {
+			t.Errorf("part %d key = %q, want the shared %q", part.Index, part.ObjectKey, first.Parts[i].ObjectKey)
+		}
+	}
+	// Nothing landed under the second request's own prefix.
+	keys, err := store.ListObjects(second.RequestID + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 0 {
+		t.Errorf("reference mode staged %v under the request prefix", keys)
+	}
+}
+
+func TestSplitDedupeCopyUnsupportedBackend(t *testing.T) {
+	// Embedding the interface hides MemoryStorage's CopyObject method.
+	store := struct{ ObjectStorage }{NewMemoryStorage()}
+	client, err := NewClient("ak", "sk", &ClientConfig{Storage: store})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wav := makeWAVFile(t, 16000, 1, 1)
+	_, err = client.SplitAudioFile(wav, 1, &SplitOptions{DedupeByContent: true})
+	if err == nil || !strings.Contains(err.Error(), "server-side copy") {
+		t.Fatalf("err = %v, want copy-unsupported failure", err)
+	}
+}
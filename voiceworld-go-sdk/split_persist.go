@@ -0,0 +1,87 @@
+package voiceworld
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// splitResultSchemaVersion is the current Save format. Loaders accept newer
+// versions too: unknown fields are ignored and missing ones default, so a
+// file written by a newer SDK still yields the parts this one understands.
+const splitResultSchemaVersion = 1
+
+// splitResultFile is the on-disk envelope around a saved split result.
+type splitResultFile struct {
+	Version  int                   `json:"version"`
+	Checksum string                `json:"checksum"`
+	Result   *SplitAudioFileResult `json:"result"`
+}
+
+// partsChecksum fingerprints the parts list so a truncated or hand-edited
+// file is caught before a submitter trusts it.
+func partsChecksum(parts []SplitPart) string {
+	// Marshalling is deterministic for a struct slice, so the JSON bytes
+	// are a stable canonical form.
+	data, _ := json.Marshal(parts)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Save writes the result to path so a separate process (typically the
+// recognition submitter) can pick it up with LoadSplitResult.
+func (r *SplitAudioFileResult) Save(path string) error {
+	data, err := json.MarshalIndent(&splitResultFile{
+		Version:  splitResultSchemaVersion,
+		Checksum: partsChecksum(r.Parts),
+		Result:   r,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("voiceworld: encode split result: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("voiceworld: save split result: %w", err)
+	}
+	return nil
+}
+
+// LoadSplitResult reads a result saved by Save, verifying the parts
+// checksum. Files written by newer SDK versions load as long as the parts
+// this version understands are intact.
+func LoadSplitResult(path string) (*SplitAudioFileResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("voiceworld: load split result: %w", err)
+	}
+	var file splitResultFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("voiceworld: decode split result: %w", err)
+	}
+	if file.Version < 1 || file.Result == nil {
+		return nil, fmt.Errorf("voiceworld: %s is not a saved split result", path)
+	}
+	if got := partsChecksum(file.Result.Parts); got != file.Checksum {
+		return nil, fmt.Errorf("voiceworld: split result %s failed its checksum (truncated or edited?)", path)
+	}
+	return file.Result, nil
+}
+
+// RefreshURLs re-signs every part's download URL from its stored object
+// key, for results loaded long after the original signed URLs expired.
+func (r *SplitAudioFileResult) RefreshURLs(client *Client) error {
+	store, err := client.storage()
+	if err != nil {
+		return err
+	}
+	for i := range r.Parts {
+		signed, expiresAt, err := client.signStorageURL(store, r.Parts[i].ObjectKey, signedURLExpiry)
+		if err != nil {
+			return err
+		}
+		r.Parts[i].URL = signed
+		r.Parts[i].ExpiresAt = expiresAt
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package voiceworld
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func savedSplitResult(t *testing.T) (*SplitAudioFileResult, string) {
+	t.Helper()
+	client, _ := newTestClient(t, &ClientConfig{Parallelism: 2})
+	result, err := client.SplitAudioFile(makeWAVFile(t, 16000, 1, 5), 2)
+	if err != nil {
+		t.Fatalf("SplitAudioFile: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "split.json")
+	if err := result.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	return result, path
+}
+
+func TestSplitResultRoundTrip(t *testing.T) {
+	saved, path := savedSplitResult(t)
+	loaded, err := LoadSplitResult(path)
+	if err != nil {
+		t.Fatalf("LoadSplitResult: %v", err)
+	}
+	if !reflect.DeepEqual(loaded, saved) {
+		t.Errorf("loaded = %+v\nwant %+v", loaded, saved)
+	}
+}
+
+func TestLoadSplitResultDetectsTruncation(t *testing.T) {
+	_, path := savedSplitResult(t)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Drop one part; the document stays valid JSON but the checksum no
+	// longer matches.
+	edited := strings.Replace(string(data), "0002.wav", "9999.wav", 1)
+	if err := os.WriteFile(path, []byte(edited), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadSplitResult(path); err == nil || !strings.Contains(err.Error(), "checksum") {
+		t.Errorf("err = %v, want a checksum failure", err)
+	}
+}
+
+func TestLoadSplitResultVersionSkew(t *testing.T) {
+	// A file written by a future SDK: higher version, unknown fields at
+	// every level. The parts we understand must still load.
+	doc := `{
+  "version": 7,
+  "checksum": "` + partsChecksum([]SplitPart{{Index: 1, ObjectKey: "req-x/0001.wav", Size: 100}}) + `",
+  "compression": "zstd",
+  "result": {
+    "request_id": "req-x",
+    "total_duration": 1.5,
+    "shard_hints": ["a", "b"],
+    "parts": [{"index": 1, "object_key": "req-x/0001.wav", "size": 100, "etag": "abc"}]
+  }
+}`
+	path := filepath.Join(t.TempDir(), "future.json")
+	if err := os.WriteFile(path, []byte(doc), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadSplitResult(path)
+	if err != nil {
+		t.Fatalf("LoadSplitResult: %v", err)
+	}
+	if loaded.RequestID != "req-x" || len(loaded.Parts) != 1 || loaded.Parts[0].ObjectKey != "req-x/0001.wav" {
+		t.Errorf("loaded = %+v", loaded)
+	}
+}
+
+func TestLoadSplitResultRejectsForeignJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "other.json")
+	if err := os.WriteFile(path, []byte(`{"hello":"world"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadSplitResult(path); err == nil {
+		t.Error("expected error for a non-split-result file")
+	}
+}
+
+func TestRefreshURLs(t *testing.T) {
+	saved, path := savedSplitResult(t)
+	loaded, err := LoadSplitResult(path)
+	if err != nil {
+		t.Fatalf("LoadSplitResult: %v", err)
+	}
+	for i := range loaded.Parts {
+		loaded.Parts[i].URL = "https://expired.example.com/" + loaded.Parts[i].ObjectKey
+	}
+
+	// A fresh client in the "submitter" process, sharing nothing but the
+	// storage backend.
+	client, _ := newTestClient(t, nil)
+	if err := loaded.RefreshURLs(client); err != nil {
+		t.Fatalf("RefreshURLs: %v", err)
+	}
+	for i, part := range loaded.Parts {
+		if !strings.HasSuffix(part.URL, part.ObjectKey) || strings.Contains(part.URL, "expired") {
+			t.Errorf("part %d URL = %q, want a fresh signed URL for %s", i, part.URL, saved.Parts[i].ObjectKey)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package voiceworld
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestPlanSplitBoundaries(t *testing.T) {
+	info, err := ParseWAVFile(makeWAVFile(t, 16000, 1, 2.5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plans, err := PlanSplit(info, "req", 1)
+	if err != nil {
+		t.Fatalf("PlanSplit: %v", err)
+	}
+	if len(plans) != 3 {
+		t.Fatalf("got %d chunks, want 3", len(plans))
+	}
+	bps := int64(info.BytesPerSecond())
+	want := []ChunkPlan{
+		{Index: 1, ObjectKey: "req/0001.wav", Offset: 0, Size: bps, StartMs: 0, Duration: 1},
+		{Index: 2, ObjectKey: "req/0002.wav", Offset: bps, Size: bps, StartMs: 1000, Duration: 1},
+		{Index: 3, ObjectKey: "req/0003.wav", Offset: 2 * bps, Size: bps / 2, StartMs: 2000, Duration: 0.5},
+	}
+	for i, plan := range plans {
+		if plan != want[i] {
+			t.Errorf("chunk %d = %+v, want %+v", i+1, plan, want[i])
+		}
+	}
+}
+
+func TestPlanSplitFrameAlignment(t *testing.T) {
+	// 44100 Hz stereo: one second is not a multiple of typical chunk sizes,
+	// so alignment actually has to do something.
+	info, err := ParseWAVFile(makeWAVFile(t, 44100, 2, 2.3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plans, err := PlanSplit(info, "req", 1)
+	if err != nil {
+		t.Fatalf("PlanSplit: %v", err)
+	}
+	align := int64(info.blockAlign())
+	var total int64
+	for _, plan := range plans {
+		if plan.Offset%align != 0 {
+			t.Errorf("chunk %d offset %d is not frame-aligned", plan.Index, plan.Offset)
+		}
+		if plan.Size%align != 0 {
+			t.Errorf("chunk %d size %d is not frame-aligned", plan.Index, plan.Size)
+		}
+		if plan.Size <= 0 {
+			t.Errorf("chunk %d is empty", plan.Index)
+		}
+		total += plan.Size
+	}
+	if total != info.DataSize {
+		t.Errorf("chunks cover %d bytes, want all %d", total, info.DataSize)
+	}
+	last := plans[len(plans)-1]
+	if last.Offset+last.Size != info.DataSize {
+		t.Errorf("last chunk ends at %d, want %d", last.Offset+last.Size, info.DataSize)
+	}
+}
+
+func TestPlanSplitCustomNames(t *testing.T) {
+	info, err := ParseWAVFile(makeWAVFile(t, 16000, 1, 2.5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plans, err := PlanSplit(info, "req", 1, &SplitOptions{PartNameFunc: OffsetPartName})
+	if err != nil {
+		t.Fatalf("PlanSplit: %v", err)
+	}
+	for _, plan := range plans {
+		want := fmt.Sprintf("req/part_%04d_%010dms.wav", plan.Index, plan.StartMs)
+		if plan.ObjectKey != want {
+			t.Errorf("chunk %d key = %q, want %q", plan.Index, plan.ObjectKey, want)
+		}
+	}
+}
+
+func TestPlanSplitDefaultDuration(t *testing.T) {
+	info, err := ParseWAVFile(makeWAVFile(t, 16000, 1, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plans, err := PlanSplit(info, "req", 0)
+	if err != nil {
+		t.Fatalf("PlanSplit: %v", err)
+	}
+	if len(plans) != 1 {
+		t.Fatalf("got %d chunks, want 1 at DefaultSplitDuration", len(plans))
+	}
+}
+
+func TestPlanSplitRejectsEmptyData(t *testing.T) {
+	info := &WAVInfo{NumChannels: 1, SampleRate: 16000, BitsPerSample: 16}
+	if _, err := PlanSplit(info, "req", 60); err == nil {
+		t.Fatal("no error for empty pcm data")
+	}
+}
+
+func TestPlanSplitRejectsMultipleOptions(t *testing.T) {
+	info, err := ParseWAVFile(makeWAVFile(t, 16000, 1, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = PlanSplit(info, "req", 60, &SplitOptions{}, &SplitOptions{})
+	if err == nil || !strings.Contains(err.Error(), "at most one") {
+		t.Fatalf("err = %v, want at-most-one rejection", err)
+	}
+}
+
+func TestPlanSplitMatchesSplitAudioFile(t *testing.T) {
+	client, _ := newTestClient(t, nil)
+	path := makeWAVFile(t, 16000, 1, 2.5)
+	opts := &SplitOptions{PartNameFunc: OffsetPartName}
+	result, err := client.SplitAudioFile(path, 1, opts)
+	if err != nil {
+		t.Fatalf("SplitAudioFile: %v", err)
+	}
+	info, err := ParseWAVFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plans, err := PlanSplit(info, result.RequestID, 1, opts)
+	if err != nil {
+		t.Fatalf("PlanSplit: %v", err)
+	}
+	if len(plans) != len(result.Parts) {
+		t.Fatalf("plan has %d chunks, split uploaded %d", len(plans), len(result.Parts))
+	}
+	for i, plan := range plans {
+		part := result.Parts[i]
+		if plan.ObjectKey != part.ObjectKey {
+			t.Errorf("chunk %d key = %q, split used %q", i+1, plan.ObjectKey, part.ObjectKey)
+		}
+		if plan.Size+44 != part.Size {
+			t.Errorf("chunk %d size %d+header, split staged %d", i+1, plan.Size, part.Size)
+		}
+		if plan.Duration != part.Duration {
+			t.Errorf("chunk %d duration %v, split reports %v", i+1, plan.Duration, part.Duration)
+		}
+	}
+}
+
+func TestPlanSplitFileSmallerThanOneChunk(t *testing.T) {
+	info, err := ParseWAVFile(makeWAVFile(t, 16000, 1, 0.5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plans, err := PlanSplit(info, "req", 60)
+	if err != nil {
+		t.Fatalf("PlanSplit: %v", err)
+	}
+	if len(plans) != 1 {
+		t.Fatalf("got %d chunks, want 1", len(plans))
+	}
+	if plans[0].Size != info.DataSize || plans[0].Offset != 0 {
+		t.Errorf("chunk = %+v, want the whole data chunk", plans[0])
+	}
+}
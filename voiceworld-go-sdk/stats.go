@@ -0,0 +1,171 @@
+package voiceworld
+
+import (
+	"io"
+	"sync"
+)
+
+// ClientStats is a snapshot of the client's cumulative transfer counters
+// since construction (or the last ResetStats). Byte counts are exact
+// application-level payload bytes — HTTP and protocol overhead is not
+// included. Derived clients (WithIdempotencyKey, WithClock, sessions) share
+// their parent's counters.
+type ClientStats struct {
+	// BytesUploaded is the payload bytes moved into object storage.
+	BytesUploaded int64
+	// BytesDownloaded is the payload bytes read back out of object
+	// storage through DownloadObject and DownloadObjectToFile.
+	BytesDownloaded int64
+	// APIRequests counts issued API requests by endpoint path, retried
+	// attempts included.
+	APIRequests map[string]int64
+	// StorageRequests counts object storage operations by name, e.g.
+	// "put_object" or "upload_part".
+	StorageRequests map[string]int64
+	// Retries is how many retry attempts were spent, across API requests
+	// and storage part transfers.
+	Retries int64
+	// Failures is how many API requests and storage operations ended in
+	// a terminal error.
+	Failures int64
+}
+
+// statsState holds the live counters behind Stats, shared across derived
+// clients the way clockState is. One mutex guards everything: the counters
+// bump once per request or per transferred part, never per byte, so
+// contention is not a concern.
+type statsState struct {
+	mu              sync.Mutex
+	bytesUploaded   int64
+	bytesDownloaded int64
+	apiRequests     map[string]int64
+	storageRequests map[string]int64
+	retries         int64
+	failures        int64
+}
+
+func newStatsState() *statsState {
+	return &statsState{
+		apiRequests:     map[string]int64{},
+		storageRequests: map[string]int64{},
+	}
+}
+
+func (s *statsState) addUpload(bytes int64) {
+	s.mu.Lock()
+	s.bytesUploaded += bytes
+	s.mu.Unlock()
+}
+
+func (s *statsState) addDownload(bytes int64) {
+	s.mu.Lock()
+	s.bytesDownloaded += bytes
+	s.mu.Unlock()
+}
+
+func (s *statsState) countAPIRequest(path string) {
+	s.mu.Lock()
+	s.apiRequests[path]++
+	s.mu.Unlock()
+}
+
+func (s *statsState) countStorageOp(op string) {
+	s.mu.Lock()
+	s.storageRequests[op]++
+	s.mu.Unlock()
+}
+
+func (s *statsState) addRetries(n int64) {
+	if n == 0 {
+		return
+	}
+	s.mu.Lock()
+	s.retries += n
+	s.mu.Unlock()
+}
+
+func (s *statsState) addFailure() {
+	s.mu.Lock()
+	s.failures++
+	s.mu.Unlock()
+}
+
+// Stats returns a snapshot of the cumulative counters. The maps are copies;
+// mutating them does not touch the live state.
+func (c *Client) Stats() ClientStats {
+	s := c.stats
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snap := ClientStats{
+		BytesUploaded:   s.bytesUploaded,
+		BytesDownloaded: s.bytesDownloaded,
+		APIRequests:     make(map[string]int64, len(s.apiRequests)),
+		StorageRequests: make(map[string]int64, len(s.storageRequests)),
+		Retries:         s.retries,
+		Failures:        s.failures,
+	}
+	for k, v := range s.apiRequests {
+		snap.APIRequests[k] = v
+	}
+	for k, v := range s.storageRequests {
+		snap.StorageRequests[k] = v
+	}
+	return snap
+}
+
+// ResetStats zeroes every counter, e.g. at the start of a billing window.
+func (c *Client) ResetStats() {
+	s := c.stats
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bytesUploaded = 0
+	s.bytesDownloaded = 0
+	s.apiRequests = map[string]int64{}
+	s.storageRequests = map[string]int64{}
+	s.retries = 0
+	s.failures = 0
+}
+
+// The multipart lifecycle helpers below wrap the corresponding
+// ObjectStorage calls so every call site counts uniformly.
+
+func (c *Client) initiateMultipart(store ObjectStorage, objectName string) (string, error) {
+	c.stats.countStorageOp("initiate_multipart_upload")
+	uploadID, err := store.InitiateMultipartUpload(objectName)
+	if err != nil {
+		c.stats.addFailure()
+	}
+	return uploadID, err
+}
+
+func (c *Client) completeMultipart(store ObjectStorage, objectName, uploadID string, parts []UploadedPart) error {
+	c.stats.countStorageOp("complete_multipart_upload")
+	err := store.CompleteMultipartUpload(objectName, uploadID, parts)
+	if err != nil {
+		c.stats.addFailure()
+	}
+	return err
+}
+
+// abortMultipart is best effort, like every abort in the upload paths: the
+// abort failing should not mask the upload error.
+func (c *Client) abortMultipart(store ObjectStorage, objectName, uploadID string) {
+	c.stats.countStorageOp("abort_multipart_upload")
+	_ = store.AbortMultipartUpload(objectName, uploadID)
+}
+
+// countingReadCloser feeds the bytes read through it into the download
+// counter as they stream, so a partially consumed download bills what was
+// actually moved.
+type countingReadCloser struct {
+	io.ReadCloser
+	stats *statsState
+}
+
+func (r *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if n > 0 {
+		r.stats.addDownload(int64(n))
+	}
+	return n, err
+}
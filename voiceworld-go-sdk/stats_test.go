@@ -0,0 +1,140 @@
+package voiceworld
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func writeBlobFile(t *testing.T, name string, size int) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, bytes.Repeat([]byte{0x5a}, size), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestStatsCountsUploadsExactly(t *testing.T) {
+	client, _ := newTestClient(t, &ClientConfig{ChunkSize: 1024})
+
+	if _, err := client.UploadFile(writeBlobFile(t, "single.bin", 700), "req-s/single.bin"); err != nil {
+		t.Fatalf("UploadFile: %v", err)
+	}
+	if _, err := client.MultipartUploadFile(writeBlobFile(t, "multi.bin", 2500), "req-s/multi.bin"); err != nil {
+		t.Fatalf("MultipartUploadFile: %v", err)
+	}
+
+	stats := client.Stats()
+	if want := int64(700 + 2500); stats.BytesUploaded != want {
+		t.Errorf("BytesUploaded = %d, want %d", stats.BytesUploaded, want)
+	}
+	if stats.BytesDownloaded != 0 {
+		t.Errorf("BytesDownloaded = %d, want 0", stats.BytesDownloaded)
+	}
+	wantOps := map[string]int64{
+		"put_object":                1,
+		"upload_part":               3, // 1024 + 1024 + 452
+		"initiate_multipart_upload": 1,
+		"complete_multipart_upload": 1,
+		"sign_url":                  2,
+	}
+	for op, want := range wantOps {
+		if got := stats.StorageRequests[op]; got != want {
+			t.Errorf("StorageRequests[%s] = %d, want %d", op, got, want)
+		}
+	}
+	if stats.Retries != 0 || stats.Failures != 0 {
+		t.Errorf("Retries/Failures = %d/%d, want 0/0", stats.Retries, stats.Failures)
+	}
+}
+
+func TestStatsCountsDownloads(t *testing.T) {
+	client, store := newTestClient(t, nil)
+	payload := strings.Repeat("x", 1234)
+	if err := store.PutObject("req-d/a.bin", strings.NewReader(payload)); err != nil {
+		t.Fatal(err)
+	}
+	rc, err := client.DownloadObject("req-d/a.bin")
+	if err != nil {
+		t.Fatalf("DownloadObject: %v", err)
+	}
+	if _, err := io.Copy(io.Discard, rc); err != nil {
+		t.Fatal(err)
+	}
+	rc.Close()
+	if got := client.Stats().BytesDownloaded; got != int64(len(payload)) {
+		t.Errorf("BytesDownloaded = %d, want %d", got, len(payload))
+	}
+	if got := client.Stats().StorageRequests["get_object"]; got != 1 {
+		t.Errorf("StorageRequests[get_object] = %d, want 1", got)
+	}
+}
+
+func TestStatsCountsAPIRequestsAndRetries(t *testing.T) {
+	server, _ := keyRecordingServer(t, 2)
+	client, _ := newTestClient(t, &ClientConfig{
+		BaseURL: server.URL,
+		Retry:   &ExponentialBackoff{MaxRetries: 5, BaseDelay: time.Millisecond},
+	})
+	client.sleep = func(time.Duration) {}
+	if _, err := client.GetTaskResult("t-1", nil); err != nil {
+		t.Fatalf("GetTaskResult: %v", err)
+	}
+	stats := client.Stats()
+	if got := stats.APIRequests["/asr_result"]; got != 3 {
+		t.Errorf("APIRequests[/asr_result] = %d, want 3 (two failures, one success)", got)
+	}
+	if stats.Retries != 2 {
+		t.Errorf("Retries = %d, want 2", stats.Retries)
+	}
+	if stats.Failures != 2 {
+		t.Errorf("Failures = %d, want 2", stats.Failures)
+	}
+}
+
+func TestResetStats(t *testing.T) {
+	client, _ := newTestClient(t, nil)
+	if _, err := client.UploadFile(writeBlobFile(t, "a.bin", 100), "req-r/a.bin"); err != nil {
+		t.Fatalf("UploadFile: %v", err)
+	}
+	if client.Stats().BytesUploaded == 0 {
+		t.Fatal("expected non-zero counters before reset")
+	}
+	client.ResetStats()
+	stats := client.Stats()
+	if stats.BytesUploaded != 0 || len(stats.StorageRequests) != 0 || len(stats.APIRequests) != 0 {
+		t.Errorf("counters survived reset: %+v", stats)
+	}
+}
+
+func TestStatsConcurrentUploads(t *testing.T) {
+	client, _ := newTestClient(t, &ClientConfig{Parallelism: 4})
+	const workers = 8
+	const size = 333
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("c%02d.bin", i)
+			if _, err := client.UploadFile(writeBlobFile(t, name, size), "req-c/"+name); err != nil {
+				t.Errorf("UploadFile %d: %v", i, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	stats := client.Stats()
+	if want := int64(workers * size); stats.BytesUploaded != want {
+		t.Errorf("BytesUploaded = %d, want %d", stats.BytesUploaded, want)
+	}
+	if got := stats.StorageRequests["put_object"]; got != workers {
+		t.Errorf("StorageRequests[put_object] = %d, want %d", got, workers)
+	}
+}
@@ -0,0 +1,141 @@
+package voiceworld
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// runObjectStorageSuite exercises the ObjectStorage contract against one
+// implementation. Every backend that can run hermetically in CI registers
+// itself in TestObjectStorageConformance.
+func runObjectStorageSuite(t *testing.T, newStore func(t *testing.T) ObjectStorage) {
+	t.Run("PutAndExist", func(t *testing.T) {
+		store := newStore(t)
+		if err := store.PutObject("a/b.wav", strings.NewReader("payload")); err != nil {
+			t.Fatalf("PutObject: %v", err)
+		}
+		ok, err := store.IsObjectExist("a/b.wav")
+		if err != nil || !ok {
+			t.Errorf("IsObjectExist(a/b.wav) = %v, %v; want true", ok, err)
+		}
+		ok, err = store.IsObjectExist("a/missing.wav")
+		if err != nil || ok {
+			t.Errorf("IsObjectExist(a/missing.wav) = %v, %v; want false", ok, err)
+		}
+	})
+
+	t.Run("PutOverwrites", func(t *testing.T) {
+		store := newStore(t)
+		if err := store.PutObject("k", strings.NewReader("old")); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.PutObject("k", strings.NewReader("new")); err != nil {
+			t.Fatal(err)
+		}
+		ok, err := store.IsObjectExist("k")
+		if err != nil || !ok {
+			t.Fatalf("IsObjectExist after overwrite = %v, %v", ok, err)
+		}
+	})
+
+	t.Run("MultipartAssemblesInPartOrder", func(t *testing.T) {
+		store := newStore(t)
+		uploadID, err := store.InitiateMultipartUpload("mp")
+		if err != nil {
+			t.Fatalf("InitiateMultipartUpload: %v", err)
+		}
+		// Upload out of order; completion order must win.
+		p2, err := store.UploadPart("mp", uploadID, 2, bytes.NewReader([]byte("world")), 5)
+		if err != nil {
+			t.Fatalf("UploadPart 2: %v", err)
+		}
+		p1, err := store.UploadPart("mp", uploadID, 1, bytes.NewReader([]byte("hello ")), 6)
+		if err != nil {
+			t.Fatalf("UploadPart 1: %v", err)
+		}
+		if err := store.CompleteMultipartUpload("mp", uploadID, []UploadedPart{p1, p2}); err != nil {
+			t.Fatalf("CompleteMultipartUpload: %v", err)
+		}
+		ok, err := store.IsObjectExist("mp")
+		if err != nil || !ok {
+			t.Fatalf("assembled object missing: %v, %v", ok, err)
+		}
+	})
+
+	t.Run("AbortDiscardsUpload", func(t *testing.T) {
+		store := newStore(t)
+		uploadID, err := store.InitiateMultipartUpload("aborted")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := store.UploadPart("aborted", uploadID, 1, strings.NewReader("x"), 1); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.AbortMultipartUpload("aborted", uploadID); err != nil {
+			t.Fatalf("AbortMultipartUpload: %v", err)
+		}
+		ok, err := store.IsObjectExist("aborted")
+		if err != nil || ok {
+			t.Errorf("aborted object exists = %v, %v; want false", ok, err)
+		}
+	})
+
+	t.Run("ListAndDelete", func(t *testing.T) {
+		store := newStore(t)
+		for _, k := range []string{"req/1.wav", "req/2.wav", "other/3.wav"} {
+			if err := store.PutObject(k, strings.NewReader(k)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		keys, err := store.ListObjects("req/")
+		if err != nil {
+			t.Fatalf("ListObjects: %v", err)
+		}
+		if want := []string{"req/1.wav", "req/2.wav"}; !reflect.DeepEqual(keys, want) {
+			t.Errorf("ListObjects(req/) = %v, want %v", keys, want)
+		}
+		if err := store.DeleteObjects(keys); err != nil {
+			t.Fatalf("DeleteObjects: %v", err)
+		}
+		keys, err = store.ListObjects("req/")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(keys) != 0 {
+			t.Errorf("keys after delete = %v, want none", keys)
+		}
+	})
+
+	t.Run("SignURLMentionsKey", func(t *testing.T) {
+		store := newStore(t)
+		if err := store.PutObject("sign/me.wav", strings.NewReader("x")); err != nil {
+			t.Fatal(err)
+		}
+		url, err := store.SignURL("sign/me.wav", signedURLExpiry)
+		if err != nil {
+			t.Fatalf("SignURL: %v", err)
+		}
+		if !strings.Contains(url, "sign/me.wav") {
+			t.Errorf("SignURL = %q, want it to reference the key", url)
+		}
+	})
+}
+
+func TestObjectStorageConformance(t *testing.T) {
+	t.Run("memory", func(t *testing.T) {
+		runObjectStorageSuite(t, func(t *testing.T) ObjectStorage {
+			return NewMemoryStorage()
+		})
+	})
+	t.Run("fs", func(t *testing.T) {
+		runObjectStorageSuite(t, func(t *testing.T) ObjectStorage {
+			store, err := newFSStorage(t.TempDir(), "")
+			if err != nil {
+				t.Fatalf("newFSStorage: %v", err)
+			}
+			return store
+		})
+	})
+}
@@ -0,0 +1,254 @@
+package voiceworld
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fsStorage implements ObjectStorage on a local directory tree. It exists so
+// the split/upload pipeline can run offline: object keys map to file paths
+// under the root, multipart uploads stage ".part" files that are
+// concatenated on completion, and signed URLs are file:// URLs (or rooted at
+// a configurable file-server base).
+type fsStorage struct {
+	root        string
+	signURLBase string
+
+	// mu serializes multipart bookkeeping; object reads/writes rely on
+	// the filesystem.
+	mu     sync.Mutex
+	nextID int
+}
+
+// newFSStorage roots a filesystem backend at dir, creating it if needed.
+func newFSStorage(dir, signURLBase string) (ObjectStorage, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("voiceworld: fs storage requires StorageConfig.RootDir")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("voiceworld: create fs storage root: %w", err)
+	}
+	return &fsStorage{root: dir, signURLBase: signURLBase}, nil
+}
+
+// objectPath maps a key to its path under the root, rejecting keys that
+// would escape it.
+func (s *fsStorage) objectPath(key string) (string, error) {
+	clean := filepath.Clean(filepath.FromSlash(key))
+	if clean == "." || strings.HasPrefix(clean, "..") || filepath.IsAbs(clean) {
+		return "", fmt.Errorf("voiceworld: invalid object key %q", key)
+	}
+	return filepath.Join(s.root, clean), nil
+}
+
+func (s *fsStorage) PutObject(key string, r io.Reader) error {
+	path, err := s.objectPath(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, r)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func (s *fsStorage) GetObject(key string) (io.ReadCloser, error) {
+	path, err := s.objectPath(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+// GetObjectRange serves [offset, offset+length) straight from the file; see
+// rangeReadStorage.
+func (s *fsStorage) GetObjectRange(key string, offset, length int64) (io.ReadCloser, error) {
+	path, err := s.objectPath(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return &limitedReadCloser{Reader: io.NewSectionReader(f, offset, length), c: f}, nil
+}
+
+func (s *fsStorage) CopyObject(srcKey, dstKey string) error {
+	src, err := s.GetObject(srcKey)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	return s.PutObject(dstKey, src)
+}
+
+// uploadDir is where the part files of one multipart upload live.
+func (s *fsStorage) uploadDir(uploadID string) string {
+	return filepath.Join(s.root, ".uploads", uploadID)
+}
+
+func (s *fsStorage) InitiateMultipartUpload(key string) (string, error) {
+	s.mu.Lock()
+	s.nextID++
+	id := fmt.Sprintf("fs-upload-%d", s.nextID)
+	s.mu.Unlock()
+	if err := os.MkdirAll(s.uploadDir(id), 0o755); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (s *fsStorage) UploadPart(key, uploadID string, partNumber int, r io.Reader, size int64) (UploadedPart, error) {
+	dir := s.uploadDir(uploadID)
+	if _, err := os.Stat(dir); err != nil {
+		return UploadedPart{}, fmt.Errorf("voiceworld: unknown upload id %q", uploadID)
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%06d.part", partNumber))
+	f, err := os.Create(path)
+	if err != nil {
+		return UploadedPart{}, err
+	}
+	n, err := io.Copy(f, io.LimitReader(r, size))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return UploadedPart{}, err
+	}
+	if n != size {
+		return UploadedPart{}, fmt.Errorf("voiceworld: short part: got %d bytes, want %d", n, size)
+	}
+	return UploadedPart{PartNumber: partNumber, ETag: fmt.Sprintf("fs-%s-%d", uploadID, partNumber)}, nil
+}
+
+func (s *fsStorage) CompleteMultipartUpload(key, uploadID string, parts []UploadedPart) error {
+	dir := s.uploadDir(uploadID)
+	path, err := s.objectPath(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	for _, p := range parts {
+		part, err := os.Open(filepath.Join(dir, fmt.Sprintf("%06d.part", p.PartNumber)))
+		if err != nil {
+			out.Close()
+			return fmt.Errorf("voiceworld: upload %q missing part %d: %w", uploadID, p.PartNumber, err)
+		}
+		_, err = io.Copy(out, part)
+		part.Close()
+		if err != nil {
+			out.Close()
+			return err
+		}
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.RemoveAll(dir)
+}
+
+func (s *fsStorage) AbortMultipartUpload(key, uploadID string) error {
+	return os.RemoveAll(s.uploadDir(uploadID))
+}
+
+func (s *fsStorage) SignURL(key string, expires time.Duration) (string, error) {
+	if s.signURLBase != "" {
+		return strings.TrimRight(s.signURLBase, "/") + "/" + key, nil
+	}
+	path, err := s.objectPath(key)
+	if err != nil {
+		return "", err
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	return "file://" + filepath.ToSlash(abs), nil
+}
+
+// ObjectKeyFromURL recognizes URLs signed under the configured base; without
+// one the file:// URLs carry no stable base to match against. See
+// keyedURLStorage.
+func (s *fsStorage) ObjectKeyFromURL(rawURL string) (string, bool) {
+	if s.signURLBase == "" {
+		return "", false
+	}
+	return objectKeyFromSignedURL(rawURL, "", "", s.signURLBase)
+}
+
+func (s *fsStorage) IsObjectExist(key string) (bool, error) {
+	path, err := s.objectPath(key)
+	if err != nil {
+		return false, err
+	}
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *fsStorage) ListObjects(prefix string) ([]string, error) {
+	var keys []string
+	err := filepath.Walk(s.root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if path == filepath.Join(s.root, ".uploads") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(s.root, path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (s *fsStorage) DeleteObjects(keys []string) error {
+	for _, key := range keys {
+		path, err := s.objectPath(key)
+		if err != nil {
+			return err
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package voiceworld
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFSStorageRejectsEscapingKeys(t *testing.T) {
+	store, err := newFSStorage(t.TempDir(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"../outside", "a/../../b", "/absolute", "."} {
+		if err := store.PutObject(key, strings.NewReader("x")); err == nil {
+			t.Errorf("PutObject(%q) succeeded, want rejection", key)
+		}
+	}
+}
+
+func TestFSStorageSignURLSchemes(t *testing.T) {
+	dir := t.TempDir()
+	store, err := newFSStorage(dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	url, err := store.SignURL("a/b.wav", signedURLExpiry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(url, "file://") || !strings.HasSuffix(url, "a/b.wav") {
+		t.Errorf("SignURL = %q, want file:// URL under the root", url)
+	}
+
+	served, err := newFSStorage(dir, "http://localhost:8080/audio/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	url, err = served.SignURL("a/b.wav", signedURLExpiry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if url != "http://localhost:8080/audio/a/b.wav" {
+		t.Errorf("SignURL with base = %q", url)
+	}
+}
+
+func TestFSStorageRequiresRoot(t *testing.T) {
+	if _, err := newFSStorage("", ""); err == nil {
+		t.Error("expected error for empty root dir")
+	}
+}
+
+// TestSplitAudioFileOnFSProvider runs the whole split pipeline against the
+// filesystem backend, the offline development configuration.
+func TestSplitAudioFileOnFSProvider(t *testing.T) {
+	root := t.TempDir()
+	client, err := NewClient("ak", "sk", &ClientConfig{
+		TokenProvider: StaticTokenProvider(OSSCredentials{}),
+		StorageConfig: &StorageConfig{Provider: "fs", RootDir: root},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := makeWAVFile(t, 16000, 1, 3)
+	result, err := client.SplitAudioFile(path, 1)
+	if err != nil {
+		t.Fatalf("SplitAudioFile: %v", err)
+	}
+	if len(result.Parts) != 3 {
+		t.Fatalf("got %d parts, want 3", len(result.Parts))
+	}
+	for _, part := range result.Parts {
+		onDisk := filepath.Join(root, filepath.FromSlash(part.ObjectKey))
+		if _, err := os.Stat(onDisk); err != nil {
+			t.Errorf("chunk %s not on disk: %v", part.ObjectKey, err)
+		}
+	}
+
+	if err := client.Cleanup(result.RequestID); err != nil {
+		t.Fatalf("Cleanup: %v", err)
+	}
+	entries, err := os.ReadDir(filepath.Join(root, result.RequestID))
+	if err == nil && len(entries) > 0 {
+		t.Errorf("chunks remain after cleanup: %v", entries)
+	}
+}
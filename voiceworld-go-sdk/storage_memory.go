@@ -0,0 +1,315 @@
+package voiceworld
+
+import (
+	"fmt"
+	"hash/crc64"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryStorage is an in-memory ObjectStorage for tests. Inject it through
+// ClientConfig.Storage to exercise the upload flows without credentials or
+// network access; inspect what was stored with Object and Keys.
+//
+// The zero value is ready to use. All methods are safe for concurrent use.
+type MemoryStorage struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	meta    map[string]map[string]string
+	uploads map[string]map[int][]byte // uploadID -> partNumber -> data
+	nextID  int
+
+	// SignURLBase is the prefix used by SignURL; defaults to
+	// "memory://bucket/".
+	SignURLBase string
+
+	// CredentialExpiry, when set, simulates STS-backed signing: URLs
+	// signed against this storage are clamped to it the way OSS URLs are
+	// clamped to their token's lifetime.
+	CredentialExpiry time.Time
+}
+
+// NewMemoryStorage returns an empty MemoryStorage.
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{}
+}
+
+// credentialExpiry implements credentialExpiryStorage.
+func (m *MemoryStorage) credentialExpiry() time.Time {
+	return m.CredentialExpiry
+}
+
+// Object returns a copy of the stored bytes for key, and whether it exists.
+func (m *MemoryStorage) Object(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.objects[key]
+	if !ok {
+		return nil, false
+	}
+	return append([]byte(nil), data...), true
+}
+
+// ObjectMeta returns a copy of the user metadata attached to key, and
+// whether the object exists.
+func (m *MemoryStorage) ObjectMeta(key string) (map[string]string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.objects[key]; !ok {
+		return nil, false
+	}
+	meta := make(map[string]string, len(m.meta[key]))
+	for k, v := range m.meta[key] {
+		meta[k] = v
+	}
+	return meta, true
+}
+
+// GetObjectMeta reads the user metadata attached to key.
+func (m *MemoryStorage) GetObjectMeta(key string) (map[string]string, error) {
+	meta, ok := m.ObjectMeta(key)
+	if !ok {
+		return nil, fmt.Errorf("memory storage: no object %q", key)
+	}
+	return meta, nil
+}
+
+// SetObjectMeta attaches user metadata to an already-stored object.
+func (m *MemoryStorage) SetObjectMeta(key string, meta map[string]string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.objects[key]; !ok {
+		return fmt.Errorf("memory storage: no object %q", key)
+	}
+	if m.meta == nil {
+		m.meta = make(map[string]map[string]string)
+	}
+	if m.meta[key] == nil {
+		m.meta[key] = make(map[string]string)
+	}
+	for k, v := range meta {
+		m.meta[key][k] = v
+	}
+	return nil
+}
+
+// GetObject streams the stored bytes for key.
+func (m *MemoryStorage) GetObject(key string) (io.ReadCloser, error) {
+	data, ok := m.Object(key)
+	if !ok {
+		return nil, fmt.Errorf("memory storage: no object %q", key)
+	}
+	return io.NopCloser(strings.NewReader(string(data))), nil
+}
+
+// GetObjectRange streams [offset, offset+length) of the stored bytes;
+// ranges running past the end return the bytes that exist. See
+// rangeReadStorage.
+func (m *MemoryStorage) GetObjectRange(key string, offset, length int64) (io.ReadCloser, error) {
+	data, ok := m.Object(key)
+	if !ok {
+		return nil, fmt.Errorf("memory storage: no object %q", key)
+	}
+	if offset < 0 || offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	end := offset + length
+	if end < offset || end > int64(len(data)) {
+		end = int64(len(data))
+	}
+	return io.NopCloser(strings.NewReader(string(data[offset:end]))), nil
+}
+
+// Keys returns every stored key in lexical order.
+func (m *MemoryStorage) Keys() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	keys := make([]string, 0, len(m.objects))
+	for k := range m.objects {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (m *MemoryStorage) PutObject(key string, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.objects == nil {
+		m.objects = make(map[string][]byte)
+	}
+	m.objects[key] = data
+	return nil
+}
+
+func (m *MemoryStorage) PutObjectIfAbsent(key string, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, taken := m.objects[key]; taken {
+		return fmt.Errorf("memory storage: %q: %w", key, ErrObjectAlreadyExists)
+	}
+	if m.objects == nil {
+		m.objects = make(map[string][]byte)
+	}
+	m.objects[key] = data
+	return nil
+}
+
+func (m *MemoryStorage) InitiateMultipartUpload(key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.uploads == nil {
+		m.uploads = make(map[string]map[int][]byte)
+	}
+	m.nextID++
+	id := fmt.Sprintf("upload-%d", m.nextID)
+	m.uploads[id] = make(map[int][]byte)
+	return id, nil
+}
+
+func (m *MemoryStorage) UploadPart(key, uploadID string, partNumber int, r io.Reader, size int64) (UploadedPart, error) {
+	data, err := io.ReadAll(io.LimitReader(r, size))
+	if err != nil {
+		return UploadedPart{}, err
+	}
+	if int64(len(data)) != size {
+		return UploadedPart{}, fmt.Errorf("short part: got %d bytes, want %d", len(data), size)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	parts, ok := m.uploads[uploadID]
+	if !ok {
+		return UploadedPart{}, fmt.Errorf("unknown upload id %q", uploadID)
+	}
+	parts[partNumber] = data
+	return UploadedPart{PartNumber: partNumber, ETag: fmt.Sprintf("etag-%s-%d", uploadID, partNumber)}, nil
+}
+
+func (m *MemoryStorage) CompleteMultipartUpload(key, uploadID string, parts []UploadedPart) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, ok := m.uploads[uploadID]
+	if !ok {
+		return fmt.Errorf("unknown upload id %q", uploadID)
+	}
+	var assembled []byte
+	for _, p := range parts {
+		data, ok := stored[p.PartNumber]
+		if !ok {
+			return fmt.Errorf("upload %q has no part %d", uploadID, p.PartNumber)
+		}
+		assembled = append(assembled, data...)
+	}
+	if m.objects == nil {
+		m.objects = make(map[string][]byte)
+	}
+	m.objects[key] = assembled
+	delete(m.uploads, uploadID)
+	return nil
+}
+
+func (m *MemoryStorage) AbortMultipartUpload(key, uploadID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.uploads, uploadID)
+	return nil
+}
+
+func (m *MemoryStorage) SignURL(key string, expires time.Duration) (string, error) {
+	base := m.SignURLBase
+	if base == "" {
+		base = "memory://bucket/"
+	}
+	return base + key, nil
+}
+
+// ObjectKeyFromURL recognizes URLs this storage signed; see keyedURLStorage.
+func (m *MemoryStorage) ObjectKeyFromURL(rawURL string) (string, bool) {
+	base := m.SignURLBase
+	if base == "" {
+		base = "memory://bucket/"
+	}
+	return objectKeyFromSignedURL(rawURL, "", "", base)
+}
+
+func (m *MemoryStorage) IsObjectExist(key string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.objects[key]
+	return ok, nil
+}
+
+func (m *MemoryStorage) ListObjects(prefix string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var keys []string
+	for k := range m.objects {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// HeadObject returns the object's size and ECMA CRC-64, mirroring the
+// metadata the production backends serve.
+func (m *MemoryStorage) HeadObject(key string) (int64, uint64, error) {
+	data, ok := m.Object(key)
+	if !ok {
+		return 0, 0, fmt.Errorf("memory storage: no object %q", key)
+	}
+	return int64(len(data)), crc64.Checksum(data, crc64Table), nil
+}
+
+func (m *MemoryStorage) UploadPartCopy(srcKey, dstKey, uploadID string, partNumber int, offset, size int64) (UploadedPart, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.objects[srcKey]
+	if !ok {
+		return UploadedPart{}, fmt.Errorf("memory storage: no object %q", srcKey)
+	}
+	if offset < 0 || offset+size > int64(len(data)) {
+		return UploadedPart{}, fmt.Errorf("memory storage: copy range [%d,%d) outside %q", offset, offset+size, srcKey)
+	}
+	parts, ok := m.uploads[uploadID]
+	if !ok {
+		return UploadedPart{}, fmt.Errorf("unknown upload id %q", uploadID)
+	}
+	parts[partNumber] = append([]byte(nil), data[offset:offset+size]...)
+	return UploadedPart{PartNumber: partNumber, ETag: fmt.Sprintf("etag-%s-%d", uploadID, partNumber)}, nil
+}
+
+func (m *MemoryStorage) CopyObject(srcKey, dstKey string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.objects[srcKey]
+	if !ok {
+		return fmt.Errorf("memory storage: no object %q", srcKey)
+	}
+	if m.objects == nil {
+		m.objects = make(map[string][]byte)
+	}
+	m.objects[dstKey] = append([]byte(nil), data...)
+	return nil
+}
+
+func (m *MemoryStorage) DeleteObjects(keys []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, k := range keys {
+		delete(m.objects, k)
+	}
+	return nil
+}
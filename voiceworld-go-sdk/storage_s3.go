@@ -0,0 +1,225 @@
+package voiceworld
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// StorageConfig selects and parameterizes the object storage backend.
+// The zero value (or a nil pointer) means Aliyun OSS with the endpoint and
+// bucket carried inside the STS credentials, which is the hosted platform's
+// layout.
+type StorageConfig struct {
+	// Provider chooses the backend: "oss" (Aliyun, the default), "s3"
+	// (any S3-compatible store such as MinIO), or "fs" (a local
+	// directory, for development and CI).
+	Provider string
+
+	// Endpoint overrides the storage endpoint. Empty means the endpoint
+	// carried in the credentials.
+	Endpoint string
+
+	// Bucket overrides the bucket. Empty means the bucket carried in the
+	// credentials.
+	Bucket string
+
+	// Region is the S3 signing region; ignored by the OSS backend.
+	Region string
+
+	// ForcePathStyle addresses the bucket in the URL path instead of the
+	// host, which MinIO and most on-prem S3 stores require.
+	ForcePathStyle bool
+
+	// Insecure uses plain HTTP to the storage endpoint. Intended for
+	// local MinIO instances.
+	Insecure bool
+
+	// RootDir is the directory backing the "fs" provider. Required for
+	// and only used by that provider.
+	RootDir string
+
+	// SignURLBase, when set, makes the "fs" provider return URLs under
+	// this base (e.g. a local file server) instead of file:// URLs.
+	SignURLBase string
+
+	// Transport, when set, is the http.RoundTripper the storage backend
+	// dials through, so storage traffic can share the proxy and
+	// connection-pool settings of the API transport. Pass the same
+	// transport here and inside ClientConfig.HTTPClient to pool both
+	// paths together. The "fs" provider makes no network calls and
+	// ignores it. Nil keeps each backend's own default.
+	Transport http.RoundTripper
+}
+
+// newStorageFromConfig builds the configured backend around one set of
+// credentials.
+func newStorageFromConfig(cfg *StorageConfig, creds *OSSCredentials) (ObjectStorage, error) {
+	if cfg == nil {
+		cfg = &StorageConfig{}
+	}
+	merged := *creds
+	if cfg.Endpoint != "" {
+		merged.Endpoint = cfg.Endpoint
+	}
+	if cfg.Bucket != "" {
+		merged.Bucket = cfg.Bucket
+	}
+	switch cfg.Provider {
+	case "", "oss":
+		return newAliyunStorage(&merged, cfg.Transport)
+	case "s3":
+		return newS3Storage(cfg, &merged)
+	case "fs":
+		return newFSStorage(cfg.RootDir, cfg.SignURLBase)
+	default:
+		return nil, fmt.Errorf("voiceworld: unknown storage provider %q", cfg.Provider)
+	}
+}
+
+// s3Storage implements ObjectStorage on any S3-compatible store via
+// minio-go. Multipart operations use the low-level Core API so part handling
+// matches the storage-neutral interface.
+type s3Storage struct {
+	core   *minio.Core
+	bucket string
+}
+
+// newS3Storage dials an S3-compatible endpoint with the given credentials.
+func newS3Storage(cfg *StorageConfig, creds *OSSCredentials) (ObjectStorage, error) {
+	if creds.Endpoint == "" {
+		return nil, fmt.Errorf("voiceworld: s3 storage requires an endpoint")
+	}
+	if creds.Bucket == "" {
+		return nil, fmt.Errorf("voiceworld: s3 storage requires a bucket")
+	}
+	lookup := minio.BucketLookupDNS
+	if cfg.ForcePathStyle {
+		lookup = minio.BucketLookupPath
+	}
+	core, err := minio.NewCore(creds.Endpoint, &minio.Options{
+		Creds:        credentials.NewStaticV4(creds.AccessKeyID, creds.AccessKeySecret, creds.SecurityToken),
+		Secure:       !cfg.Insecure,
+		Region:       cfg.Region,
+		BucketLookup: lookup,
+		Transport:    cfg.Transport,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("voiceworld: dial s3 endpoint %s: %w", creds.Endpoint, err)
+	}
+	return &s3Storage{core: core, bucket: creds.Bucket}, nil
+}
+
+func (s *s3Storage) PutObject(key string, r io.Reader) error {
+	// Size -1 streams with chunked signing; fine for the staging workloads.
+	_, err := s.core.Client.PutObject(context.Background(), s.bucket, key, r, -1, minio.PutObjectOptions{})
+	return err
+}
+
+func (s *s3Storage) GetObject(key string) (io.ReadCloser, error) {
+	rc, _, _, err := s.core.GetObject(context.Background(), s.bucket, key, minio.GetObjectOptions{})
+	return rc, err
+}
+
+// GetObjectRange fetches [offset, offset+length) with an HTTP Range request;
+// see rangeReadStorage.
+func (s *s3Storage) GetObjectRange(key string, offset, length int64) (io.ReadCloser, error) {
+	opts := minio.GetObjectOptions{}
+	if err := opts.SetRange(offset, offset+length-1); err != nil {
+		return nil, err
+	}
+	rc, _, _, err := s.core.GetObject(context.Background(), s.bucket, key, opts)
+	return rc, err
+}
+
+func (s *s3Storage) CopyObject(srcKey, dstKey string) error {
+	return s.CopyObjectTo(s.bucket, dstKey, srcKey)
+}
+
+func (s *s3Storage) CopyObjectTo(dstBucket, dstKey, srcKey string) error {
+	_, err := s.core.Client.CopyObject(context.Background(),
+		minio.CopyDestOptions{Bucket: dstBucket, Object: dstKey},
+		minio.CopySrcOptions{Bucket: s.bucket, Object: srcKey})
+	return err
+}
+
+func (s *s3Storage) UploadPartCopy(srcKey, dstKey, uploadID string, partNumber int, offset, size int64) (UploadedPart, error) {
+	part, err := s.core.CopyObjectPart(context.Background(), s.bucket, srcKey, s.bucket, dstKey,
+		uploadID, partNumber, offset, size, nil)
+	if err != nil {
+		return UploadedPart{}, err
+	}
+	return UploadedPart{PartNumber: part.PartNumber, ETag: part.ETag}, nil
+}
+
+func (s *s3Storage) InitiateMultipartUpload(key string) (string, error) {
+	return s.core.NewMultipartUpload(context.Background(), s.bucket, key, minio.PutObjectOptions{})
+}
+
+func (s *s3Storage) UploadPart(key, uploadID string, partNumber int, r io.Reader, size int64) (UploadedPart, error) {
+	part, err := s.core.PutObjectPart(context.Background(), s.bucket, key, uploadID, partNumber, r, size, minio.PutObjectPartOptions{})
+	if err != nil {
+		return UploadedPart{}, err
+	}
+	return UploadedPart{PartNumber: part.PartNumber, ETag: part.ETag}, nil
+}
+
+func (s *s3Storage) CompleteMultipartUpload(key, uploadID string, parts []UploadedPart) error {
+	completed := make([]minio.CompletePart, len(parts))
+	for i, p := range parts {
+		completed[i] = minio.CompletePart{PartNumber: p.PartNumber, ETag: p.ETag}
+	}
+	_, err := s.core.CompleteMultipartUpload(context.Background(), s.bucket, key, uploadID, completed, minio.PutObjectOptions{})
+	return err
+}
+
+func (s *s3Storage) AbortMultipartUpload(key, uploadID string) error {
+	return s.core.AbortMultipartUpload(context.Background(), s.bucket, key, uploadID)
+}
+
+func (s *s3Storage) SignURL(key string, expires time.Duration) (string, error) {
+	u, err := s.core.Client.PresignedGetObject(context.Background(), s.bucket, key, expires, url.Values{})
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+func (s *s3Storage) IsObjectExist(key string) (bool, error) {
+	_, err := s.core.Client.StatObject(context.Background(), s.bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		if resp := minio.ToErrorResponse(err); resp.Code == "NoSuchKey" {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *s3Storage) ListObjects(prefix string) ([]string, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var keys []string
+	for obj := range s.core.Client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if obj.Err != nil {
+			return nil, obj.Err
+		}
+		keys = append(keys, obj.Key)
+	}
+	return keys, nil
+}
+
+func (s *s3Storage) DeleteObjects(keys []string) error {
+	for _, key := range keys {
+		if err := s.core.Client.RemoveObject(context.Background(), s.bucket, key, minio.RemoveObjectOptions{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
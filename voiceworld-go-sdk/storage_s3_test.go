@@ -0,0 +1,97 @@
+package voiceworld
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+var testCreds = &OSSCredentials{
+	AccessKeyID:     "id",
+	AccessKeySecret: "secret",
+	SecurityToken:   "token",
+	Endpoint:        "oss-cn-hangzhou.aliyuncs.com",
+	Bucket:          "creds-bucket",
+}
+
+func TestNewStorageFromConfigDefaultsToOSS(t *testing.T) {
+	for _, cfg := range []*StorageConfig{nil, {}, {Provider: "oss"}} {
+		store, err := newStorageFromConfig(cfg, testCreds)
+		if err != nil {
+			t.Fatalf("config %+v: %v", cfg, err)
+		}
+		if _, ok := store.(*aliyunStorage); !ok {
+			t.Errorf("config %+v: got %T, want *aliyunStorage", cfg, store)
+		}
+	}
+}
+
+func TestNewStorageFromConfigS3(t *testing.T) {
+	store, err := newStorageFromConfig(&StorageConfig{
+		Provider:       "s3",
+		Endpoint:       "minio.internal:9000",
+		Bucket:         "audio",
+		Region:         "us-east-1",
+		ForcePathStyle: true,
+		Insecure:       true,
+	}, testCreds)
+	if err != nil {
+		t.Fatalf("newStorageFromConfig: %v", err)
+	}
+	s3, ok := store.(*s3Storage)
+	if !ok {
+		t.Fatalf("got %T, want *s3Storage", store)
+	}
+	if s3.bucket != "audio" {
+		t.Errorf("bucket = %q, want the StorageConfig override", s3.bucket)
+	}
+}
+
+func TestNewStorageFromConfigS3FallsBackToCredentialAddressing(t *testing.T) {
+	store, err := newStorageFromConfig(&StorageConfig{Provider: "s3"}, testCreds)
+	if err != nil {
+		t.Fatalf("newStorageFromConfig: %v", err)
+	}
+	if s3 := store.(*s3Storage); s3.bucket != "creds-bucket" {
+		t.Errorf("bucket = %q, want the bucket from the credentials", s3.bucket)
+	}
+}
+
+func TestNewStorageFromConfigS3RequiresAddressing(t *testing.T) {
+	bare := &OSSCredentials{AccessKeyID: "id", AccessKeySecret: "secret"}
+	if _, err := newStorageFromConfig(&StorageConfig{Provider: "s3"}, bare); err == nil {
+		t.Error("expected error for s3 without endpoint")
+	}
+	withEndpoint := &OSSCredentials{AccessKeyID: "id", AccessKeySecret: "secret", Endpoint: "minio:9000"}
+	if _, err := newStorageFromConfig(&StorageConfig{Provider: "s3"}, withEndpoint); err == nil {
+		t.Error("expected error for s3 without bucket")
+	}
+}
+
+func TestNewStorageFromConfigTransportReachesOSS(t *testing.T) {
+	rt := &http.Transport{MaxIdleConnsPerHost: 32}
+	store, err := newStorageFromConfig(&StorageConfig{Transport: rt}, testCreds)
+	if err != nil {
+		t.Fatalf("newStorageFromConfig: %v", err)
+	}
+	hc := store.(*aliyunStorage).bucket.Client.HTTPClient
+	if hc == nil || hc.Transport != http.RoundTripper(rt) {
+		t.Error("the OSS backend does not dial through the configured transport")
+	}
+
+	// Without one the SDK keeps making its own client.
+	store, err = newStorageFromConfig(nil, testCreds)
+	if err != nil {
+		t.Fatalf("newStorageFromConfig: %v", err)
+	}
+	if store.(*aliyunStorage).bucket.Client.HTTPClient != nil {
+		t.Error("a transport was injected although none was configured")
+	}
+}
+
+func TestNewStorageFromConfigUnknownProvider(t *testing.T) {
+	_, err := newStorageFromConfig(&StorageConfig{Provider: "carrier-pigeon"}, testCreds)
+	if err == nil || !strings.Contains(err.Error(), "carrier-pigeon") {
+		t.Errorf("err = %v, want unknown-provider diagnostic", err)
+	}
+}
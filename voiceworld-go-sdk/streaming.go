@@ -0,0 +1,557 @@
+package voiceworld
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// DefaultFrameDuration is the audio frame length streamed per WebSocket
+// message when StreamingOptions does not override it.
+const DefaultFrameDuration = 40 * time.Millisecond
+
+// StreamingOptions configures one live recognition session.
+type StreamingOptions struct {
+	// Format is the audio codec of the frames, e.g. "pcm".
+	Format string
+	// SampleRate is in Hz; the platform accepts 8000 and 16000.
+	SampleRate int
+	// FrameDuration is the negotiated frame length; audio handed to
+	// SendPCM is re-chunked to it. Zero means DefaultFrameDuration.
+	FrameDuration time.Duration
+	// NoPacing disables real-time pacing in SendPCM, sending frames as
+	// fast as possible. Useful for replaying files.
+	NoPacing bool
+
+	// MaxWindowBytes and MaxWindowFrames bound the send window: audio
+	// that has been sent but not yet acknowledged by a server ack
+	// message. When another frame would exceed either bound, Send blocks
+	// until acknowledgements arrive (SendContext bounds the wait), or
+	// fails fast with ErrWindowFull when NonBlockingWindow is set. Zero
+	// means unbounded. Only enable a window against servers that emit
+	// acks; without them an exhausted window never reopens.
+	MaxWindowBytes  int
+	MaxWindowFrames int
+	// NonBlockingWindow makes Send return ErrWindowFull instead of
+	// blocking when the send window is exhausted, for callers that would
+	// rather drop audio than stall the capture path.
+	NonBlockingWindow bool
+
+	// EnableVoiceDetection asks the server to run voice activity
+	// detection and emit SpeechStart/SpeechEnd events on the Results
+	// channel.
+	EnableVoiceDetection bool
+	// MaxStartSilenceMs ends the session if no speech is detected within
+	// this many milliseconds of its start. Zero means the platform
+	// default.
+	MaxStartSilenceMs int
+	// MaxEndSilenceMs closes the utterance after this much trailing
+	// silence; the server then emits SessionClosed. Zero means the
+	// platform default.
+	MaxEndSilenceMs int
+
+	// EnableLanguageDetection asks the server to identify the spoken
+	// language; results then carry DetectedLanguage.
+	EnableLanguageDetection bool
+}
+
+// streamStartMessage opens a session on the wire.
+type streamStartMessage struct {
+	Action                  string `json:"action"` // "start"
+	Format                  string `json:"format,omitempty"`
+	SampleRate              int    `json:"sample_rate,omitempty"`
+	EnableVoiceDetection    bool   `json:"enable_voice_detection,omitempty"`
+	MaxStartSilenceMs       int    `json:"max_start_silence_ms,omitempty"`
+	MaxEndSilenceMs         int    `json:"max_end_silence_ms,omitempty"`
+	EnableLanguageDetection bool   `json:"enable_language_detection,omitempty"`
+}
+
+// streamAckMessage is the server's flow-control acknowledgement: a data
+// payload of {"ack_frames":N} confirms receipt of the oldest N
+// unacknowledged audio frames and opens the send window back up.
+type streamAckMessage struct {
+	AckFrames int `json:"ack_frames"`
+}
+
+// SessionEventType names a lifecycle event of a streaming session.
+type SessionEventType string
+
+// Events the server emits when voice detection is enabled. SessionClosed
+// also arrives when a silence limit is hit; no further results follow it.
+const (
+	EventSpeechStart   SessionEventType = "speech_start"
+	EventSpeechEnd     SessionEventType = "speech_end"
+	EventSessionClosed SessionEventType = "session_closed"
+)
+
+// SessionEvent is one lifecycle event of a streaming session.
+type SessionEvent struct {
+	Type SessionEventType `json:"event"`
+	// Timestamp is measured from the start of the audio stream.
+	Timestamp Timestamp `json:"timestamp"`
+}
+
+// StreamingMessage is one item from the Results channel: either a
+// transcription result or a session event, never both.
+type StreamingMessage struct {
+	Result *RecognitionResult
+	Event  *SessionEvent
+}
+
+// ErrSessionClosed is returned by Send once the session has ended, either
+// because the caller closed it or because the server emitted SessionClosed.
+var ErrSessionClosed = errors.New("voiceworld: streaming session closed")
+
+// ErrWindowFull is returned by Send and SendContext when
+// StreamingOptions.NonBlockingWindow is set and the send window has no room
+// for another frame.
+var ErrWindowFull = errors.New("voiceworld: streaming send window full")
+
+// StreamingSession is one live recognition session over the WebSocket API.
+// Audio goes in through Send or SendPCM; results arrive on the Results
+// channel, which closes when the session ends (check Err afterwards).
+type StreamingSession struct {
+	conn *websocket.Conn
+	opts StreamingOptions
+
+	results chan StreamingMessage
+
+	writeMu sync.Mutex // websocket writers must not interleave
+
+	mu     sync.Mutex
+	err    error
+	closed bool
+	// Send-window state; all zero when no window is configured. winPending
+	// holds the in-flight frame sizes oldest first, so acks can credit the
+	// exact bytes back. winChanged is closed and replaced on every window
+	// change, broadcasting to blocked senders.
+	winBytes   int64
+	winFrames  int
+	winPending []int
+	winChanged chan struct{}
+}
+
+// StartStreaming opens a live recognition session against the /stream
+// endpoint. opts may be nil for platform defaults.
+func (c *Client) StartStreaming(opts *StreamingOptions) (*StreamingSession, error) {
+	if opts == nil {
+		opts = &StreamingOptions{}
+	}
+	if opts.FrameDuration <= 0 {
+		opts.FrameDuration = DefaultFrameDuration
+	}
+	if opts.SampleRate == 0 {
+		opts.SampleRate = targetSampleRate
+	}
+
+	wsURL := strings.Replace(c.endpointURL("/stream"), "http", "ws", 1)
+	header := http.Header{}
+	req, err := http.NewRequest(http.MethodGet, c.endpointURL("/stream"), nil)
+	if err != nil {
+		return nil, fmt.Errorf("voiceworld: build stream request: %w", err)
+	}
+	c.sign(req)
+	for _, k := range []string{headerAppKey, headerTimestamp, headerNonce, headerSignature} {
+		header.Set(k, req.Header.Get(k))
+	}
+
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, header)
+	if err != nil {
+		return nil, fmt.Errorf("voiceworld: open streaming session: %w", err)
+	}
+	s := &StreamingSession{
+		conn:       conn,
+		opts:       *opts,
+		results:    make(chan StreamingMessage, 16),
+		winChanged: make(chan struct{}),
+	}
+	if err := s.writeJSON(streamStartMessage{
+		Action:                  "start",
+		Format:                  opts.Format,
+		SampleRate:              opts.SampleRate,
+		EnableVoiceDetection:    opts.EnableVoiceDetection,
+		MaxStartSilenceMs:       opts.MaxStartSilenceMs,
+		MaxEndSilenceMs:         opts.MaxEndSilenceMs,
+		EnableLanguageDetection: opts.EnableLanguageDetection,
+	}); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("voiceworld: start streaming session: %w", err)
+	}
+	go s.receive()
+	return s, nil
+}
+
+// Send transmits one binary frame of audio as-is, entering it into the send
+// window when one is configured (see StreamingOptions.MaxWindowBytes). After
+// the session ends it returns ErrSessionClosed.
+func (s *StreamingSession) Send(frame []byte) error {
+	return s.SendContext(context.Background(), frame)
+}
+
+// SendContext is Send with a context bounding the wait for send-window
+// space; ctx expiring while blocked returns its error without sending. The
+// context does not bound the write itself.
+func (s *StreamingSession) SendContext(ctx context.Context, frame []byte) error {
+	if err := s.reserveWindow(ctx, len(frame)); err != nil {
+		return err
+	}
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	if err := s.conn.WriteMessage(websocket.BinaryMessage, frame); err != nil {
+		s.releaseFrame(len(frame))
+		// A write that lost the race with the session ending (the
+		// receive loop closes the connection, unblocking us) is not a
+		// transport failure.
+		s.mu.Lock()
+		closed := s.closed
+		s.mu.Unlock()
+		if closed {
+			return ErrSessionClosed
+		}
+		return fmt.Errorf("voiceworld: send audio frame: %w", err)
+	}
+	return nil
+}
+
+// windowed reports whether the session has a bounded send window at all.
+func (s *StreamingSession) windowed() bool {
+	return s.opts.MaxWindowBytes > 0 || s.opts.MaxWindowFrames > 0
+}
+
+// reserveWindow waits for room in the send window (when one is configured)
+// and reserves size bytes of it, re-checking the session state on every wake
+// so a send never races past a close.
+func (s *StreamingSession) reserveWindow(ctx context.Context, size int) error {
+	for {
+		s.mu.Lock()
+		if err := s.err; err != nil {
+			s.mu.Unlock()
+			return err
+		}
+		if s.closed {
+			s.mu.Unlock()
+			return ErrSessionClosed
+		}
+		if s.windowFits(size) {
+			if s.windowed() {
+				s.winBytes += int64(size)
+				s.winFrames++
+				s.winPending = append(s.winPending, size)
+			}
+			s.mu.Unlock()
+			return nil
+		}
+		changed := s.winChanged
+		s.mu.Unlock()
+		if s.opts.NonBlockingWindow {
+			return ErrWindowFull
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// windowFits reports whether one more frame of size bytes fits the window.
+// A frame larger than MaxWindowBytes is admitted when the window is empty,
+// so a single oversized frame cannot wedge the session. Callers hold s.mu.
+func (s *StreamingSession) windowFits(size int) bool {
+	if !s.windowed() {
+		return true
+	}
+	if s.opts.MaxWindowFrames > 0 && s.winFrames >= s.opts.MaxWindowFrames {
+		return false
+	}
+	if s.opts.MaxWindowBytes > 0 && s.winBytes+int64(size) > int64(s.opts.MaxWindowBytes) {
+		return s.winFrames == 0
+	}
+	return true
+}
+
+// ackWindow credits n acknowledged frames back to the send window, oldest
+// first, and wakes blocked senders. Acks beyond what is in flight are
+// ignored rather than driving the window negative.
+func (s *StreamingSession) ackWindow(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n > len(s.winPending) {
+		n = len(s.winPending)
+	}
+	for _, size := range s.winPending[:n] {
+		s.winBytes -= int64(size)
+	}
+	s.winFrames -= n
+	s.winPending = append(s.winPending[:0], s.winPending[n:]...)
+	s.wakeSendersLocked()
+}
+
+// releaseFrame returns a reservation whose frame never made it onto the
+// wire, after a write failure.
+func (s *StreamingSession) releaseFrame(size int) {
+	if !s.windowed() {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := len(s.winPending) - 1; i >= 0; i-- {
+		if s.winPending[i] == size {
+			s.winPending = append(s.winPending[:i], s.winPending[i+1:]...)
+			s.winBytes -= int64(size)
+			s.winFrames--
+			break
+		}
+	}
+	s.wakeSendersLocked()
+}
+
+// wakeSendersLocked broadcasts a window change to every blocked sender.
+// Callers hold s.mu.
+func (s *StreamingSession) wakeSendersLocked() {
+	close(s.winChanged)
+	s.winChanged = make(chan struct{})
+}
+
+// WindowOccupancy reports the audio currently in flight - sent but not yet
+// acknowledged - for backpressure monitoring. Always zero when no send
+// window is configured.
+func (s *StreamingSession) WindowOccupancy() (bytes int64, frames int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.winBytes, s.winFrames
+}
+
+// SendPCM consumes raw little-endian mono PCM frames from the capture
+// layer, re-chunks them to the session's frame duration, and sends them.
+// Unless NoPacing is set, sends are paced in real time so the server sees a
+// live-microphone cadence. SendPCM returns when the channel is closed (after
+// flushing the final partial frame) or on the first send error.
+func (s *StreamingSession) SendPCM(frames <-chan []int16) error {
+	return s.sendPCM(frames, s.opts.NoPacing)
+}
+
+func (s *StreamingSession) sendPCM(frames <-chan []int16, noPacing bool) error {
+	frameBytes := s.frameBytes()
+	buf := make([]byte, 0, frameBytes)
+	var ticker *time.Ticker
+	if !noPacing {
+		ticker = time.NewTicker(s.opts.FrameDuration)
+		defer ticker.Stop()
+	}
+
+	for samples := range frames {
+		for _, sample := range samples {
+			buf = append(buf, byte(sample), byte(uint16(sample)>>8))
+		}
+		for len(buf) >= frameBytes {
+			if ticker != nil {
+				<-ticker.C
+			}
+			if err := s.Send(buf[:frameBytes]); err != nil {
+				return err
+			}
+			buf = append(buf[:0], buf[frameBytes:]...)
+		}
+	}
+	if len(buf) > 0 {
+		if ticker != nil {
+			<-ticker.C
+		}
+		return s.Send(buf)
+	}
+	return nil
+}
+
+// FeedWAVFile streams the PCM payload of a WAV file through the session,
+// simulating live audio when realtime is true or replaying as fast as
+// possible otherwise. The file must match the session's sample rate.
+func (s *StreamingSession) FeedWAVFile(path string, realtime bool) error {
+	info, err := ParseWAVFile(path)
+	if err != nil {
+		return err
+	}
+	if info.SampleRate != s.opts.SampleRate {
+		return fmt.Errorf("voiceworld: wav is %d Hz but session expects %d Hz",
+			info.SampleRate, s.opts.SampleRate)
+	}
+
+	ch := make(chan []int16, 4)
+	errc := make(chan error, 1)
+	go func() {
+		errc <- readWAVFrames(path, info, ch)
+		close(ch)
+	}()
+	if err := s.sendPCM(ch, !realtime); err != nil {
+		return err
+	}
+	return <-errc
+}
+
+// Close ends the audio stream and tears the session down. Remaining results
+// drain from the Results channel.
+func (s *StreamingSession) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.wakeSendersLocked()
+	s.mu.Unlock()
+
+	if err := s.writeJSON(struct {
+		Action string `json:"action"`
+	}{"stop"}); err != nil {
+		s.conn.Close()
+		return err
+	}
+	return nil
+}
+
+// Results delivers transcription results and session events as the server
+// produces them. The channel closes when the session ends; check Err
+// afterwards.
+func (s *StreamingSession) Results() <-chan StreamingMessage {
+	return s.results
+}
+
+// Err reports why the session ended; nil while it is live or after a clean
+// close.
+func (s *StreamingSession) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// frameBytes is the byte size of one full frame at the session's format.
+func (s *StreamingSession) frameBytes() int {
+	n := int(int64(s.opts.SampleRate) * 2 * int64(s.opts.FrameDuration) / int64(time.Second))
+	if n <= 0 {
+		n = 2
+	}
+	return n
+}
+
+// writeJSON sends one JSON control message.
+func (s *StreamingSession) writeJSON(v interface{}) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return s.conn.WriteJSON(v)
+}
+
+// receive pumps server messages into the results channel until the
+// connection ends.
+func (s *StreamingSession) receive() {
+	defer close(s.results)
+	defer s.conn.Close()
+	defer func() {
+		// No acks can arrive once the read loop is done; mark the session
+		// over and fail blocked senders instead of leaving them waiting on
+		// a window that can never reopen.
+		s.mu.Lock()
+		s.closed = true
+		s.wakeSendersLocked()
+		s.mu.Unlock()
+	}()
+	for {
+		_, data, err := s.conn.ReadMessage()
+		if err != nil {
+			s.setErr(err)
+			return
+		}
+		var env apiEnvelope
+		if err := json.Unmarshal(data, &env); err != nil {
+			s.setErr(fmt.Errorf("voiceworld: decode streaming message: %w", err))
+			return
+		}
+		if env.Code != 0 {
+			s.setErr(&APIError{Code: env.Code, Message: env.Message})
+			return
+		}
+		var ack streamAckMessage
+		if err := json.Unmarshal(env.Data, &ack); err == nil && ack.AckFrames > 0 {
+			s.ackWindow(ack.AckFrames)
+			continue
+		}
+		var event SessionEvent
+		if err := json.Unmarshal(env.Data, &event); err == nil && event.Type != "" {
+			s.results <- StreamingMessage{Event: &event}
+			if event.Type == EventSessionClosed {
+				// No further results follow; mark the session
+				// closed so pending Sends fail with
+				// ErrSessionClosed once the deferred
+				// conn.Close unblocks them.
+				s.mu.Lock()
+				s.closed = true
+				s.mu.Unlock()
+				return
+			}
+			continue
+		}
+		var result RecognitionResult
+		if err := json.Unmarshal(env.Data, &result); err != nil {
+			s.setErr(fmt.Errorf("voiceworld: decode streaming result: %w", err))
+			return
+		}
+		s.results <- StreamingMessage{Result: &result}
+	}
+}
+
+// setErr records the first failure; a normal close, or any error after the
+// caller already closed the session, is not one.
+func (s *StreamingSession) setErr(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil || s.closed {
+		return
+	}
+	if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+		return
+	}
+	s.err = err
+}
+
+// readWAVFrames pushes the PCM payload of a file through ch in ~100 ms
+// slices of mono samples.
+func readWAVFrames(path string, info *WAVInfo, ch chan<- []int16) error {
+	f, err := os.Open(longPath(path))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := f.Seek(info.DataOffset, io.SeekStart); err != nil {
+		return err
+	}
+	sliceBytes := info.BytesPerSecond() / 10
+	if sliceBytes < 2 {
+		sliceBytes = 2
+	}
+	remaining := info.DataSize
+	buf := make([]byte, sliceBytes)
+	for remaining > 0 {
+		n := int64(len(buf))
+		if remaining < n {
+			n = remaining
+		}
+		if _, err := io.ReadFull(f, buf[:n]); err != nil {
+			return err
+		}
+		samples := make([]int16, n/2)
+		for i := range samples {
+			samples[i] = int16(uint16(buf[2*i]) | uint16(buf[2*i+1])<<8)
+		}
+		ch <- samples
+		remaining -= n
+	}
+	return nil
+}
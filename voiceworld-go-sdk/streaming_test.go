@@ -0,0 +1,339 @@
+package voiceworld
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// wsFrame is one binary frame as seen by the fake streaming server.
+type wsFrame struct {
+	size int
+	at   time.Time
+}
+
+// fakeStreamServer upgrades /stream connections, records incoming binary
+// frames, and answers every frame with a partial result envelope.
+type fakeStreamServer struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	start  map[string]interface{}
+	frames []wsFrame
+	done   chan struct{}
+}
+
+func newFakeStreamServer(t *testing.T) *fakeStreamServer {
+	t.Helper()
+	fs := &fakeStreamServer{done: make(chan struct{})}
+	upgrader := websocket.Upgrader{}
+	fs.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/stream" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get(headerSignature) == "" {
+			http.Error(w, "unsigned", http.StatusUnauthorized)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade: %v", err)
+			return
+		}
+		defer conn.Close()
+		defer close(fs.done)
+		for {
+			kind, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			switch kind {
+			case websocket.TextMessage:
+				msg := map[string]interface{}{}
+				if err := json.Unmarshal(data, &msg); err != nil {
+					t.Errorf("decode control message: %v", err)
+					return
+				}
+				switch msg["action"] {
+				case "start":
+					fs.mu.Lock()
+					fs.start = msg
+					fs.mu.Unlock()
+				case "stop":
+					conn.WriteMessage(websocket.TextMessage,
+						[]byte(`{"code":0,"data":{"task_id":"ws-task","result":"final"}}`))
+					conn.WriteControl(websocket.CloseMessage,
+						websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+						time.Now().Add(time.Second))
+					return
+				}
+			case websocket.BinaryMessage:
+				fs.mu.Lock()
+				fs.frames = append(fs.frames, wsFrame{size: len(data), at: time.Now()})
+				n := len(fs.frames)
+				fs.mu.Unlock()
+				if n == 1 {
+					conn.WriteMessage(websocket.TextMessage,
+						[]byte(`{"code":0,"data":{"result":"partial"}}`))
+				}
+			}
+		}
+	}))
+	t.Cleanup(fs.Server.Close)
+	return fs
+}
+
+func (fs *fakeStreamServer) recorded() []wsFrame {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return append([]wsFrame(nil), fs.frames...)
+}
+
+func newStreamingClient(t *testing.T, server *fakeStreamServer) *Client {
+	t.Helper()
+	client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return client
+}
+
+func TestSendPCMChunksAndFlushes(t *testing.T) {
+	server := newFakeStreamServer(t)
+	client := newStreamingClient(t, server)
+
+	session, err := client.StartStreaming(&StreamingOptions{
+		Format:     "pcm",
+		SampleRate: 16000,
+		NoPacing:   true,
+	})
+	if err != nil {
+		t.Fatalf("StartStreaming: %v", err)
+	}
+
+	// 40 ms at 16 kHz mono 16-bit is 640 samples / 1280 bytes per frame.
+	ch := make(chan []int16)
+	go func() {
+		// Deliberately misaligned slices: 1000+1000+20 samples = 3 full
+		// frames plus a 100-sample tail.
+		for _, n := range []int{1000, 1000, 20} {
+			ch <- make([]int16, n)
+		}
+		close(ch)
+	}()
+	if err := session.SendPCM(ch); err != nil {
+		t.Fatalf("SendPCM: %v", err)
+	}
+	if err := session.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	for range session.Results() {
+	}
+	if err := session.Err(); err != nil {
+		t.Fatalf("session error: %v", err)
+	}
+
+	frames := server.recorded()
+	want := []int{1280, 1280, 1280, 200} // 3 full frames + flushed tail
+	if len(frames) != len(want) {
+		t.Fatalf("server saw %d frames, want %d", len(frames), len(want))
+	}
+	for i, f := range frames {
+		if f.size != want[i] {
+			t.Errorf("frame %d: %d bytes, want %d", i, f.size, want[i])
+		}
+	}
+}
+
+func TestSendPCMPacesRealtime(t *testing.T) {
+	server := newFakeStreamServer(t)
+	client := newStreamingClient(t, server)
+
+	session, err := client.StartStreaming(&StreamingOptions{
+		SampleRate:    16000,
+		FrameDuration: 20 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("StartStreaming: %v", err)
+	}
+	defer session.Close()
+
+	// 5 frames of 20 ms: paced delivery should take at least ~4 ticks.
+	ch := make(chan []int16, 1)
+	go func() {
+		ch <- make([]int16, 5*320)
+		close(ch)
+	}()
+	begin := time.Now()
+	if err := session.SendPCM(ch); err != nil {
+		t.Fatalf("SendPCM: %v", err)
+	}
+	if elapsed := time.Since(begin); elapsed < 80*time.Millisecond {
+		t.Errorf("5 paced frames sent in %v, want >= 80ms", elapsed)
+	}
+	// The final write may still be in flight server-side when SendPCM
+	// returns, so give the server a moment to record it.
+	deadline := time.Now().Add(time.Second)
+	for len(server.recorded()) < 5 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if frames := server.recorded(); len(frames) != 5 {
+		t.Errorf("server saw %d frames, want 5", len(frames))
+	}
+}
+
+func TestFeedWAVFile(t *testing.T) {
+	server := newFakeStreamServer(t)
+	client := newStreamingClient(t, server)
+
+	session, err := client.StartStreaming(&StreamingOptions{SampleRate: 16000})
+	if err != nil {
+		t.Fatalf("StartStreaming: %v", err)
+	}
+
+	// Half a second of 16 kHz mono: 8000 samples = 16000 bytes = 12 full
+	// 40 ms frames (1280 bytes) plus a 640-byte tail.
+	path := makeWAVFile(t, 16000, 1, 0.5)
+	if err := session.FeedWAVFile(path, false); err != nil {
+		t.Fatalf("FeedWAVFile: %v", err)
+	}
+	if err := session.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	var results []*RecognitionResult
+	for msg := range session.Results() {
+		if msg.Result != nil {
+			results = append(results, msg.Result)
+		}
+	}
+	if err := session.Err(); err != nil {
+		t.Fatalf("session error: %v", err)
+	}
+	if len(results) == 0 || results[len(results)-1].TaskID != "ws-task" {
+		t.Fatalf("results = %+v, want final ws-task result", results)
+	}
+
+	frames := server.recorded()
+	if len(frames) != 13 {
+		t.Fatalf("server saw %d frames, want 13", len(frames))
+	}
+	for i, f := range frames[:12] {
+		if f.size != 1280 {
+			t.Errorf("frame %d: %d bytes, want 1280", i, f.size)
+		}
+	}
+	if frames[12].size != 640 {
+		t.Errorf("tail frame: %d bytes, want 640", frames[12].size)
+	}
+}
+
+func TestFeedWAVFileRejectsSampleRateMismatch(t *testing.T) {
+	server := newFakeStreamServer(t)
+	client := newStreamingClient(t, server)
+
+	session, err := client.StartStreaming(&StreamingOptions{SampleRate: 16000})
+	if err != nil {
+		t.Fatalf("StartStreaming: %v", err)
+	}
+	defer session.Close()
+
+	path := makeWAVFile(t, 8000, 1, 0.1)
+	if err := session.FeedWAVFile(path, false); err == nil ||
+		!strings.Contains(err.Error(), "8000 Hz") {
+		t.Fatalf("FeedWAVFile mismatch error = %v", err)
+	}
+}
+
+// TestSessionEvents drives a voice-detection session: the server announces
+// speech boundaries and then closes the utterance after trailing silence.
+func TestSessionEvents(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	var startMsg struct {
+		EnableVoiceDetection bool `json:"enable_voice_detection"`
+		MaxEndSilenceMs      int  `json:"max_end_silence_ms"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade: %v", err)
+			return
+		}
+		defer conn.Close()
+		if _, data, err := conn.ReadMessage(); err != nil {
+			t.Errorf("read start: %v", err)
+			return
+		} else if err := json.Unmarshal(data, &startMsg); err != nil {
+			t.Errorf("decode start: %v", err)
+			return
+		}
+		conn.WriteMessage(websocket.TextMessage,
+			[]byte(`{"code":0,"data":{"event":"speech_start","timestamp":120}}`))
+		if _, _, err := conn.ReadMessage(); err != nil { // one audio frame
+			t.Errorf("read audio: %v", err)
+			return
+		}
+		for _, msg := range []string{
+			`{"code":0,"data":{"result":"turn it off"}}`,
+			`{"code":0,"data":{"event":"speech_end","timestamp":900}}`,
+			`{"code":0,"data":{"event":"session_closed","timestamp":1700}}`,
+		} {
+			conn.WriteMessage(websocket.TextMessage, []byte(msg))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	session, err := client.StartStreaming(&StreamingOptions{
+		SampleRate:           16000,
+		EnableVoiceDetection: true,
+		MaxEndSilenceMs:      800,
+	})
+	if err != nil {
+		t.Fatalf("StartStreaming: %v", err)
+	}
+	if err := session.Send(make([]byte, 1280)); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	var got []string
+	for msg := range session.Results() {
+		switch {
+		case msg.Event != nil:
+			if msg.Event.Timestamp.Milliseconds() <= 0 {
+				t.Errorf("event %s has no timestamp", msg.Event.Type)
+			}
+			got = append(got, string(msg.Event.Type))
+		case msg.Result != nil:
+			got = append(got, "result:"+msg.Result.Result)
+		}
+	}
+	want := []string{"speech_start", "result:turn it off", "speech_end", "session_closed"}
+	if len(got) != len(want) {
+		t.Fatalf("messages = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("messages = %v, want %v", got, want)
+		}
+	}
+	if err := session.Err(); err != nil {
+		t.Errorf("Err after SessionClosed = %v, want nil", err)
+	}
+	if err := session.Send(make([]byte, 1280)); err != ErrSessionClosed {
+		t.Errorf("Send after SessionClosed = %v, want ErrSessionClosed", err)
+	}
+	if !startMsg.EnableVoiceDetection || startMsg.MaxEndSilenceMs != 800 {
+		t.Errorf("start message = %+v, want voice detection with 800ms end silence", startMsg)
+	}
+}
@@ -0,0 +1,234 @@
+package voiceworld
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// ackStreamServer is a fake streaming server whose acknowledgements the test
+// controls: acks sent on the channel become {"ack_frames":N} messages, and a
+// positive autoAck additionally acknowledges every received frame after that
+// delay, simulating a slow reader.
+type ackStreamServer struct {
+	*httptest.Server
+	acks   chan int
+	frames int32
+}
+
+func newAckStreamServer(t *testing.T, autoAck time.Duration) *ackStreamServer {
+	t.Helper()
+	as := &ackStreamServer{acks: make(chan int)}
+	upgrader := websocket.Upgrader{}
+	as.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade: %v", err)
+			return
+		}
+		defer conn.Close()
+		done := make(chan struct{})
+		defer close(done)
+		go func() { // sole writer; the read loop below only reads
+			for {
+				select {
+				case n := <-as.acks:
+					conn.WriteJSON(map[string]interface{}{
+						"code": 0, "data": map[string]int{"ack_frames": n},
+					})
+				case <-done:
+					return
+				}
+			}
+		}()
+		for {
+			kind, _, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if kind != websocket.BinaryMessage {
+				continue
+			}
+			atomic.AddInt32(&as.frames, 1)
+			if autoAck > 0 {
+				time.AfterFunc(autoAck, func() {
+					select {
+					case as.acks <- 1:
+					case <-done:
+					}
+				})
+			}
+		}
+	}))
+	t.Cleanup(as.Server.Close)
+	return as
+}
+
+func newAckStreamClient(t *testing.T, server *ackStreamServer) *Client {
+	t.Helper()
+	client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return client
+}
+
+func waitForWindow(t *testing.T, session *StreamingSession, bytes int64, frames int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if b, f := session.WindowOccupancy(); b == bytes && f == frames {
+			return
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+	b, f := session.WindowOccupancy()
+	t.Fatalf("window occupancy = %d bytes / %d frames, want %d / %d", b, f, bytes, frames)
+}
+
+func TestSendWindowBlocksUntilAck(t *testing.T) {
+	server := newAckStreamServer(t, 0)
+	client := newAckStreamClient(t, server)
+
+	session, err := client.StartStreaming(&StreamingOptions{
+		SampleRate:      16000,
+		NoPacing:        true,
+		MaxWindowFrames: 2,
+	})
+	if err != nil {
+		t.Fatalf("StartStreaming: %v", err)
+	}
+	defer session.Close()
+
+	frame := make([]byte, 1280)
+	for i := 0; i < 2; i++ {
+		if err := session.Send(frame); err != nil {
+			t.Fatalf("Send %d: %v", i, err)
+		}
+	}
+	if bytes, frames := session.WindowOccupancy(); bytes != 2560 || frames != 2 {
+		t.Errorf("occupancy = %d bytes / %d frames, want 2560 / 2", bytes, frames)
+	}
+
+	blocked := make(chan error, 1)
+	go func() { blocked <- session.Send(frame) }()
+	select {
+	case err := <-blocked:
+		t.Fatalf("Send returned %v with the window full, want it to block", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	server.acks <- 1
+	select {
+	case err := <-blocked:
+		if err != nil {
+			t.Fatalf("Send after ack: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Send still blocked after an ack opened the window")
+	}
+	waitForWindow(t, session, 2560, 2)
+
+	server.acks <- 2
+	waitForWindow(t, session, 0, 0)
+}
+
+func TestSendWindowBoundsInFlightAudio(t *testing.T) {
+	server := newAckStreamServer(t, 5*time.Millisecond) // slow reader
+	client := newAckStreamClient(t, server)
+
+	session, err := client.StartStreaming(&StreamingOptions{
+		SampleRate:      16000,
+		NoPacing:        true,
+		MaxWindowBytes:  2 * 1280,
+		MaxWindowFrames: 2,
+	})
+	if err != nil {
+		t.Fatalf("StartStreaming: %v", err)
+	}
+	defer session.Close()
+
+	frame := make([]byte, 1280)
+	for i := 0; i < 20; i++ {
+		if err := session.Send(frame); err != nil {
+			t.Fatalf("Send %d: %v", i, err)
+		}
+		if bytes, frames := session.WindowOccupancy(); bytes > 2*1280 || frames > 2 {
+			t.Fatalf("send %d: occupancy %d bytes / %d frames exceeds the window", i, bytes, frames)
+		}
+	}
+	// The final writes may still be in flight server-side; give the server
+	// a moment to record them.
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&server.frames) < 20 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := atomic.LoadInt32(&server.frames); got != 20 {
+		t.Errorf("server saw %d frames, want all 20", got)
+	}
+}
+
+func TestSendWindowNonBlocking(t *testing.T) {
+	server := newAckStreamServer(t, 0)
+	client := newAckStreamClient(t, server)
+
+	session, err := client.StartStreaming(&StreamingOptions{
+		SampleRate:        16000,
+		NoPacing:          true,
+		MaxWindowBytes:    1500,
+		NonBlockingWindow: true,
+	})
+	if err != nil {
+		t.Fatalf("StartStreaming: %v", err)
+	}
+	defer session.Close()
+
+	if err := session.Send(make([]byte, 1280)); err != nil {
+		t.Fatalf("first Send: %v", err)
+	}
+	if err := session.Send(make([]byte, 1280)); !errors.Is(err, ErrWindowFull) {
+		t.Fatalf("Send with full window = %v, want ErrWindowFull", err)
+	}
+	server.acks <- 1
+	waitForWindow(t, session, 0, 0)
+	// An oversized frame is admitted when the window is empty rather than
+	// wedging the session.
+	if err := session.Send(make([]byte, 3000)); err != nil {
+		t.Fatalf("oversized Send into an empty window: %v", err)
+	}
+}
+
+func TestSendContextRespectsCancellation(t *testing.T) {
+	server := newAckStreamServer(t, 0)
+	client := newAckStreamClient(t, server)
+
+	session, err := client.StartStreaming(&StreamingOptions{
+		SampleRate:      16000,
+		NoPacing:        true,
+		MaxWindowFrames: 1,
+	})
+	if err != nil {
+		t.Fatalf("StartStreaming: %v", err)
+	}
+	defer session.Close()
+
+	frame := make([]byte, 1280)
+	if err := session.Send(frame); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := session.SendContext(ctx, frame); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("SendContext with full window = %v, want DeadlineExceeded", err)
+	}
+	// The timed-out frame must not have leaked into the window.
+	if bytes, frames := session.WindowOccupancy(); bytes != 1280 || frames != 1 {
+		t.Errorf("occupancy = %d bytes / %d frames after timeout, want 1280 / 1", bytes, frames)
+	}
+}
@@ -0,0 +1,24 @@
+package voiceworld
+
+import "errors"
+
+// The sentinels behind ClientConfig.Strict. Each marks one place where the
+// SDK would otherwise guess and carry on; strict-mode failures wrap the
+// matching sentinel, so errors.Is picks the checkpoint out of the chain.
+var (
+	// ErrAmbiguousFormat reports audio whose container could not be
+	// identified when RecognitionConfig.Format was left empty. Lenient
+	// clients submit the bytes and let the server decide what they are.
+	ErrAmbiguousFormat = errors.New("voiceworld: audio format is ambiguous")
+
+	// ErrTrackNotInspected reports an ObjectKey track in a multi-track
+	// batch that asked for MultiTrackOptions.ResampleLocally: a staged
+	// object's layout cannot be verified, so lenient clients submit it
+	// as-is and trust that it already matches.
+	ErrTrackNotInspected = errors.New("voiceworld: staged track layout cannot be verified")
+
+	// ErrRequestIDRequired reports a call that would have generated a
+	// request ID — and with it the object keys derived from one — because
+	// the caller passed none. Lenient clients generate via NewRequestID.
+	ErrRequestIDRequired = errors.New("voiceworld: request id required")
+)
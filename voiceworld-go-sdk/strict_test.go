@@ -0,0 +1,113 @@
+package voiceworld
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// ambiguousAudioFile writes a blob that matches none of the known container
+// magics, the shape a raw PCM capture arrives in.
+func ambiguousAudioFile(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "capture.bin")
+	if err := os.WriteFile(path, []byte(strings.Repeat("\x7f\x01", 64)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestStrictAmbiguousFormat(t *testing.T) {
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte(`{"code":0,"data":{"text":"ok"}}`))
+	}))
+	t.Cleanup(server.Close)
+	path := ambiguousAudioFile(t)
+
+	lenient, _ := newTestClient(t, &ClientConfig{BaseURL: server.URL})
+	if _, err := lenient.ASR(path, nil); err != nil {
+		t.Fatalf("lenient ASR: %v", err)
+	}
+	if requests.Load() != 1 {
+		t.Fatalf("lenient client made %d requests, want 1", requests.Load())
+	}
+
+	strict, _ := newTestClient(t, &ClientConfig{BaseURL: server.URL, Strict: true})
+	_, err := strict.ASR(path, nil)
+	if !errors.Is(err, ErrAmbiguousFormat) {
+		t.Fatalf("strict ASR err = %v, want ErrAmbiguousFormat", err)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("strict client reached the server (%d requests total)", requests.Load())
+	}
+
+	// Naming the format keeps strict clients working: the guess is gone.
+	if _, err := strict.ASR(path, &RecognitionConfig{Format: "pcm", SampleRate: 16000}); err != nil {
+		t.Fatalf("strict ASR with explicit format: %v", err)
+	}
+}
+
+func TestStrictTrackNotInspected(t *testing.T) {
+	server, _ := multiTrackServer(t)
+	tracks := []TrackInput{
+		{Path: makeWAVFile(t, 16000, 1, 0.1), SpeakerLabel: "alice"},
+		{ObjectKey: "staged/bob.wav", SpeakerLabel: "bob"},
+	}
+	opts := &MultiTrackOptions{RequestID: "req-1", ResampleLocally: true}
+
+	lenient, store := newTestClient(t, &ClientConfig{BaseURL: server.URL})
+	if err := store.PutObject("staged/bob.wav", strings.NewReader("data")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := lenient.SubmitMultiTrackTranscription(context.Background(), tracks, opts); err != nil {
+		t.Fatalf("lenient submit: %v", err)
+	}
+
+	strict, _ := newTestClient(t, &ClientConfig{BaseURL: server.URL, Strict: true})
+	_, err := strict.SubmitMultiTrackTranscription(context.Background(), tracks, opts)
+	if !errors.Is(err, ErrTrackNotInspected) {
+		t.Fatalf("strict submit err = %v, want ErrTrackNotInspected", err)
+	}
+
+	// Without the resampling request there is nothing to verify the track
+	// against, so strict mode accepts staged objects as before.
+	strictStore := strict.config.Storage.(*MemoryStorage)
+	if err := strictStore.PutObject("staged/bob.wav", strings.NewReader("data")); err != nil {
+		t.Fatal(err)
+	}
+	plain := &MultiTrackOptions{RequestID: "req-2"}
+	if _, err := strict.SubmitMultiTrackTranscription(context.Background(), tracks, plain); err != nil {
+		t.Fatalf("strict submit without resampling: %v", err)
+	}
+}
+
+func TestStrictRequestIDRequired(t *testing.T) {
+	path := makeWAVFile(t, 16000, 1, 0.1)
+
+	lenient, _ := newTestClient(t, nil)
+	result, err := lenient.ProcessAndUploadFile(path, "")
+	if err != nil {
+		t.Fatalf("lenient ProcessAndUploadFile: %v", err)
+	}
+	if result.RequestID == "" {
+		t.Error("lenient call did not generate a request id")
+	}
+
+	strict, _ := newTestClient(t, &ClientConfig{Strict: true})
+	if _, err := strict.ProcessAndUploadFile(path, ""); !errors.Is(err, ErrRequestIDRequired) {
+		t.Fatalf("strict ProcessAndUploadFile err = %v, want ErrRequestIDRequired", err)
+	}
+	if result, err := strict.ProcessAndUploadFile(path, "req-strict"); err != nil {
+		t.Fatalf("strict ProcessAndUploadFile with id: %v", err)
+	} else if result.RequestID != "req-strict" {
+		t.Errorf("RequestID = %q, want req-strict", result.RequestID)
+	}
+}
@@ -0,0 +1,106 @@
+package voiceworld
+
+import "fmt"
+
+// UploadAndSubmitOptions carries the optional knobs for UploadAndSubmit. Nil
+// and the zero value split at DefaultSplitDuration and submit with the
+// platform default recognition parameters.
+type UploadAndSubmitOptions struct {
+	// ChunkDuration is the target chunk length in seconds, as in
+	// SplitAudioFile. <= 0 means DefaultSplitDuration.
+	ChunkDuration int
+
+	// Split is forwarded to the SplitAudioFile stage. Nil keeps the
+	// defaults.
+	Split *SplitOptions
+
+	// Recognition is forwarded to the per-part submission. Its RequestID
+	// is overridden with the split's generated request id so staged
+	// objects and transcripts correlate. Nil means platform defaults.
+	Recognition *RecognitionConfig
+
+	// CleanupOnSubmitFailure deletes the staged chunks when submission
+	// fails, so a dead request does not keep paying for storage. Either
+	// way the returned PartialUploadError lists the staged keys, so
+	// callers can clean up or resume the submission themselves.
+	CleanupOnSubmitFailure bool
+}
+
+// UploadAndSubmitResult is the outcome of the combined split, upload and
+// submit sequence.
+type UploadAndSubmitResult struct {
+	// Split describes the staged chunks; see SplitAudioFileResult.
+	Split *SplitAudioFileResult `json:"split"`
+	// TaskID identifies the recognition for the result endpoints. It is
+	// the task id of the first submitted part, the same one MergeResults
+	// would keep.
+	TaskID string `json:"task_id"`
+}
+
+// UploadAndSubmit runs the whole long-audio sequence in one call: split the
+// file into chunks, stage them, and submit each staged chunk for
+// recognition. It exists because callers kept writing the same orchestration
+// by hand and diverging on the failure handling. When submission fails after
+// chunks were staged, the error is a PartialUploadError listing the staged
+// keys — with CleanupOnSubmitFailure they are deleted first — so nothing is
+// silently left behind in the bucket. Passing more than one options value is
+// an error.
+func (c *Client) UploadAndSubmit(filePath string, options ...*UploadAndSubmitOptions) (*UploadAndSubmitResult, error) {
+	if len(options) > 1 {
+		return nil, fmt.Errorf("voiceworld: at most one UploadAndSubmitOptions may be given, got %d", len(options))
+	}
+	opts := &UploadAndSubmitOptions{}
+	if len(options) == 1 && options[0] != nil {
+		opts = options[0]
+	}
+	split, err := c.SplitAudioFile(filePath, opts.ChunkDuration, opts.Split)
+	if err != nil {
+		return nil, err
+	}
+
+	config := RecognitionConfig{}
+	if opts.Recognition != nil {
+		config = *opts.Recognition
+	}
+	config.RequestID = split.RequestID
+
+	taskID := ""
+	for _, part := range split.Parts {
+		if part.Error != "" {
+			// ContinueOnError slot without a staged object; the part
+			// failure is already recorded on the split result.
+			continue
+		}
+		result, rerr := c.RecognizeURL(part.URL, &config)
+		if rerr != nil {
+			return nil, c.failSubmit(split, fmt.Errorf("submit part %d: %w", part.Index, rerr), opts)
+		}
+		if taskID == "" {
+			taskID = result.TaskID
+		}
+	}
+	return &UploadAndSubmitResult{Split: split, TaskID: taskID}, nil
+}
+
+// failSubmit turns a submission failure into a PartialUploadError listing
+// the chunks that are sitting in the bucket, deleting them first when the
+// caller asked for CleanupOnSubmitFailure.
+func (c *Client) failSubmit(split *SplitAudioFileResult, cause error, opts *UploadAndSubmitOptions) error {
+	var uploaded []string
+	for _, part := range split.Parts {
+		if part.ObjectKey != "" {
+			uploaded = append(uploaded, part.ObjectKey)
+		}
+	}
+	perr := &PartialUploadError{
+		RequestID:    split.RequestID,
+		UploadedKeys: uploaded,
+		Err:          cause,
+	}
+	if opts.CleanupOnSubmitFailure {
+		if cerr := c.Cleanup(split.RequestID); cerr != nil {
+			perr.CleanupErr = cerr
+		}
+	}
+	return perr
+}
@@ -0,0 +1,108 @@
+package voiceworld
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// newSubmitServer fakes the /asr endpoint, recording the request_id of each
+// submission and failing once configured to.
+func newSubmitServer(t *testing.T) (*httptest.Server, *submitLog) {
+	t.Helper()
+	log := &submitLog{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			RequestID string `json:"request_id"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		log.mu.Lock()
+		log.requestIDs = append(log.requestIDs, req.RequestID)
+		fail := log.fail
+		log.mu.Unlock()
+		if fail {
+			fmt.Fprint(w, `{"code":40000,"message":"submission rejected"}`)
+			return
+		}
+		fmt.Fprint(w, `{"code":0,"message":"success","data":{"task_id":"task-1","result":"ok"}}`)
+	}))
+	t.Cleanup(server.Close)
+	return server, log
+}
+
+type submitLog struct {
+	mu         sync.Mutex
+	requestIDs []string
+	fail       bool
+}
+
+func TestUploadAndSubmit(t *testing.T) {
+	server, log := newSubmitServer(t)
+	client, store := newTestClient(t, &ClientConfig{BaseURL: server.URL})
+	result, err := client.UploadAndSubmit(makeWAVFile(t, 16000, 1, 2.5), &UploadAndSubmitOptions{ChunkDuration: 1})
+	if err != nil {
+		t.Fatalf("UploadAndSubmit: %v", err)
+	}
+	if result.TaskID != "task-1" {
+		t.Errorf("TaskID = %q, want task-1", result.TaskID)
+	}
+	if len(result.Split.Parts) != 3 {
+		t.Fatalf("got %d parts, want 3", len(result.Split.Parts))
+	}
+	if len(log.requestIDs) != 3 {
+		t.Fatalf("server saw %d submissions, want 3", len(log.requestIDs))
+	}
+	for _, id := range log.requestIDs {
+		if id != result.Split.RequestID {
+			t.Errorf("submission carried request id %q, want %q", id, result.Split.RequestID)
+		}
+	}
+	if keys, _ := store.ListObjects(result.Split.RequestID + "/"); len(keys) != 3 {
+		t.Errorf("%d objects staged, want 3", len(keys))
+	}
+}
+
+func TestUploadAndSubmitCleanupOnFailure(t *testing.T) {
+	server, log := newSubmitServer(t)
+	log.fail = true
+	client, store := newTestClient(t, &ClientConfig{BaseURL: server.URL})
+	_, err := client.UploadAndSubmit(makeWAVFile(t, 16000, 1, 2.5), &UploadAndSubmitOptions{
+		ChunkDuration:          1,
+		CleanupOnSubmitFailure: true,
+	})
+	var perr *PartialUploadError
+	if !errors.As(err, &perr) {
+		t.Fatalf("err = %v, want *PartialUploadError", err)
+	}
+	if len(perr.UploadedKeys) != 3 {
+		t.Errorf("error lists %d staged keys, want 3", len(perr.UploadedKeys))
+	}
+	if perr.CleanupErr != nil {
+		t.Errorf("CleanupErr = %v", perr.CleanupErr)
+	}
+	if keys, _ := store.ListObjects(perr.RequestID + "/"); len(keys) != 0 {
+		t.Errorf("%d objects left after cleanup, want 0", len(keys))
+	}
+}
+
+func TestUploadAndSubmitKeepsChunksWithoutCleanup(t *testing.T) {
+	server, log := newSubmitServer(t)
+	log.fail = true
+	client, store := newTestClient(t, &ClientConfig{BaseURL: server.URL})
+	_, err := client.UploadAndSubmit(makeWAVFile(t, 16000, 1, 2.5), &UploadAndSubmitOptions{ChunkDuration: 1})
+	var perr *PartialUploadError
+	if !errors.As(err, &perr) {
+		t.Fatalf("err = %v, want *PartialUploadError", err)
+	}
+	if keys, _ := store.ListObjects(perr.RequestID + "/"); len(keys) != 3 {
+		t.Errorf("%d objects staged, want all 3 kept for manual resume", len(keys))
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != 40000 {
+		t.Errorf("cause %v does not unwrap to the submission APIError", err)
+	}
+}
@@ -0,0 +1,166 @@
+package voiceworld
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// DedupeStore remembers which submissions already ran, so re-running a batch
+// does not pay for the same transcriptions twice. Keys are submission
+// fingerprints (audio content plus parameters, see recognitionFingerprint);
+// values are whatever the client stored — in practice the serialized result.
+// Implementations must be safe for concurrent use.
+type DedupeStore interface {
+	// Get returns the value stored under fingerprint; ok is false on a
+	// miss.
+	Get(fingerprint string) (value string, ok bool)
+	// Set records fingerprint → value, replacing any previous entry.
+	Set(fingerprint, value string) error
+}
+
+// recognitionFingerprint identifies one submission: the SHA-256 of the audio
+// bytes plus the canonicalized request parameters, so the same file under
+// different settings counts as a different submission.
+func recognitionFingerprint(filePath string, config *RecognitionConfig) (string, error) {
+	f, err := os.Open(longPath(filePath))
+	if err != nil {
+		return "", fmt.Errorf("voiceworld: open audio: %w", err)
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("voiceworld: fingerprint audio: %w", err)
+	}
+	return fmt.Sprintf("%x|%s", h.Sum(nil), config.params().Encode()), nil
+}
+
+// cachedRecognition looks the submission up in the configured DedupeStore.
+// Entries that no longer decode are treated as misses.
+func (c *Client) cachedRecognition(fingerprint string) *RecognitionResult {
+	if c.config.DedupeStore == nil || fingerprint == "" {
+		return nil
+	}
+	cached, ok := c.config.DedupeStore.Get(fingerprint)
+	if !ok {
+		return nil
+	}
+	var result RecognitionResult
+	if err := json.Unmarshal([]byte(cached), &result); err != nil {
+		return nil
+	}
+	result.FromCache = true
+	return &result
+}
+
+// storeRecognition records a fresh result under its fingerprint. Failures
+// are swallowed: a broken dedupe store must not fail the recognition that
+// already succeeded.
+func (c *Client) storeRecognition(fingerprint string, result *RecognitionResult) {
+	if c.config.DedupeStore == nil || fingerprint == "" {
+		return
+	}
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return
+	}
+	_ = c.config.DedupeStore.Set(fingerprint, string(encoded))
+}
+
+// MemoryDedupeStore is a process-local DedupeStore, good for guarding one
+// batch run.
+type MemoryDedupeStore struct {
+	mu      sync.Mutex
+	entries map[string]string
+}
+
+// NewMemoryDedupeStore returns an empty MemoryDedupeStore.
+func NewMemoryDedupeStore() *MemoryDedupeStore {
+	return &MemoryDedupeStore{entries: map[string]string{}}
+}
+
+// Get implements DedupeStore.
+func (s *MemoryDedupeStore) Get(fingerprint string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, ok := s.entries[fingerprint]
+	return value, ok
+}
+
+// Set implements DedupeStore.
+func (s *MemoryDedupeStore) Set(fingerprint, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[fingerprint] = value
+	return nil
+}
+
+// FileDedupeStore is a DedupeStore backed by one JSON file, so the guard
+// survives process restarts and re-run batches. Every Set rewrites the file
+// atomically (write-then-rename); access from the goroutines of one process
+// is serialized, but the file is not locked against other processes.
+type FileDedupeStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileDedupeStore returns a store persisting to the JSON file at path.
+// The file is created on the first Set.
+func NewFileDedupeStore(path string) *FileDedupeStore {
+	return &FileDedupeStore{path: path}
+}
+
+// load reads the current entries; a missing file is an empty store.
+func (s *FileDedupeStore) load() (map[string]string, error) {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	entries := map[string]string{}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("voiceworld: dedupe store %s is corrupt: %w", s.path, err)
+	}
+	return entries, nil
+}
+
+// Get implements DedupeStore.
+func (s *FileDedupeStore) Get(fingerprint string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, err := s.load()
+	if err != nil {
+		return "", false
+	}
+	value, ok := entries[fingerprint]
+	return value, ok
+}
+
+// Set implements DedupeStore.
+func (s *FileDedupeStore) Set(fingerprint, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, err := s.load()
+	if err != nil {
+		return err
+	}
+	entries[fingerprint] = value
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
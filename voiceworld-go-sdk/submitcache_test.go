@@ -0,0 +1,127 @@
+package voiceworld
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// dedupeServer answers /asr with a distinct transcript per call so tests can
+// tell a replay from a fresh submission.
+func dedupeServer(t *testing.T, calls *int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		fmt.Fprintf(w, `{"code":0,"data":{"task_id":"task-%d","result":"transcript %d"}}`, *calls, *calls)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDedupeStoreReplaysIdenticalSubmission(t *testing.T) {
+	var calls int
+	server := dedupeServer(t, &calls)
+	client, _ := newTestClient(t, &ClientConfig{
+		BaseURL:     server.URL,
+		DedupeStore: NewMemoryDedupeStore(),
+	})
+	audio := makeWAVFile(t, 16000, 1, 0.2)
+
+	first, err := client.ASR(audio, nil)
+	if err != nil {
+		t.Fatalf("first ASR: %v", err)
+	}
+	if first.FromCache {
+		t.Error("first result claims FromCache")
+	}
+	second, err := client.ASR(audio, nil)
+	if err != nil {
+		t.Fatalf("second ASR: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("server saw %d calls, want 1", calls)
+	}
+	if !second.FromCache {
+		t.Error("replayed result not marked FromCache")
+	}
+	if second.Result != first.Result || second.TaskID != first.TaskID {
+		t.Errorf("replayed result = (%q, %q), want (%q, %q)",
+			second.Result, second.TaskID, first.Result, first.TaskID)
+	}
+}
+
+func TestDedupeStoreDistinguishesParametersAndContent(t *testing.T) {
+	var calls int
+	server := dedupeServer(t, &calls)
+	client, _ := newTestClient(t, &ClientConfig{
+		BaseURL:     server.URL,
+		DedupeStore: NewMemoryDedupeStore(),
+	})
+	audio := makeWAVFile(t, 16000, 1, 0.2)
+
+	if _, err := client.ASR(audio, nil); err != nil {
+		t.Fatalf("ASR: %v", err)
+	}
+	// Same bytes, different parameters: a distinct submission.
+	if _, err := client.ASR(audio, &RecognitionConfig{EnablePunctuation: true}); err != nil {
+		t.Fatalf("ASR with punctuation: %v", err)
+	}
+	// Same parameters, different bytes: also distinct.
+	if _, err := client.ASR(makeWAVFile(t, 16000, 1, 0.3), nil); err != nil {
+		t.Fatalf("ASR other file: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("server saw %d calls, want 3", calls)
+	}
+}
+
+func TestFileDedupeStorePersists(t *testing.T) {
+	var calls int
+	server := dedupeServer(t, &calls)
+	path := filepath.Join(t.TempDir(), "dedupe.json")
+	audio := makeWAVFile(t, 16000, 1, 0.2)
+
+	client, _ := newTestClient(t, &ClientConfig{
+		BaseURL:     server.URL,
+		DedupeStore: NewFileDedupeStore(path),
+	})
+	if _, err := client.ASR(audio, nil); err != nil {
+		t.Fatalf("ASR: %v", err)
+	}
+
+	// A fresh store over the same file — as after a process restart —
+	// still recognizes the submission.
+	client2, _ := newTestClient(t, &ClientConfig{
+		BaseURL:     server.URL,
+		DedupeStore: NewFileDedupeStore(path),
+	})
+	result, err := client2.ASR(audio, nil)
+	if err != nil {
+		t.Fatalf("ASR after restart: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("server saw %d calls, want 1", calls)
+	}
+	if !result.FromCache {
+		t.Error("persisted result not marked FromCache")
+	}
+}
+
+func TestFileDedupeStoreCorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dedupe.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	store := NewFileDedupeStore(path)
+	// A corrupt file degrades to misses on read, and Set refuses to
+	// clobber it rather than silently discarding whatever it held.
+	if _, ok := store.Get("fp"); ok {
+		t.Error("Get on corrupt store reported a hit")
+	}
+	if err := store.Set("fp", "value"); err == nil {
+		t.Error("Set on corrupt store succeeded")
+	}
+}
@@ -0,0 +1,154 @@
+package voiceworld
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+// Defaults for TailOptions.
+const (
+	defaultTailPollInterval = 500 * time.Millisecond
+	defaultTailQuiescence   = 3 * time.Second
+)
+
+// TailOptions tunes RecognizeGrowingFile. The zero value polls every 500 ms
+// and finalizes after 3 s without growth.
+type TailOptions struct {
+	// PollInterval is how often the file is checked for appended data.
+	PollInterval time.Duration
+	// Quiescence is how long the file must stop growing before the
+	// transcript is finalized.
+	Quiescence time.Duration
+	// Config carries the recognition parameters for every submitted
+	// chunk; Format and SampleRate are overridden from the file's own
+	// header. Nil means platform defaults.
+	Config *RecognitionConfig
+}
+
+// RecognizeGrowingFile transcribes a WAV file that is still being written,
+// the capture-process pattern: newly appended frame-aligned PCM is read on
+// every poll and submitted as chunked sync recognition, and the accumulated
+// transcript — with word and segment offsets shifted onto the recording's
+// timeline — is returned once the file stops growing for the quiescence
+// period or ctx is cancelled. In-progress recordings typically carry stale
+// header size fields, so growth is judged by the file's actual size; chunk
+// offsets likewise come from the bytes consumed, not from server-reported
+// durations.
+func (c *Client) RecognizeGrowingFile(ctx context.Context, path string, opts *TailOptions) (*MergedResult, error) {
+	if opts == nil {
+		opts = &TailOptions{}
+	}
+	poll := opts.PollInterval
+	if poll <= 0 {
+		poll = defaultTailPollInterval
+	}
+	quiescence := opts.Quiescence
+	if quiescence <= 0 {
+		quiescence = defaultTailQuiescence
+	}
+
+	status, err := c.life.beginOp("recognize")
+	if err != nil {
+		return nil, err
+	}
+	defer c.life.endOp(status)
+
+	// The header may land a moment after the file appears; wait for a
+	// parseable one before consuming PCM.
+	info, err := waitForWAVHeader(ctx, path, poll)
+	if err != nil {
+		return nil, err
+	}
+	config := &RecognitionConfig{}
+	if opts.Config != nil {
+		chunkConfig := *opts.Config
+		config = &chunkConfig
+	}
+	config.Format = "pcm"
+	config.SampleRate = info.SampleRate
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	status.setRequestID(config.RequestID)
+
+	f, err := os.Open(longPath(path))
+	if err != nil {
+		return nil, fmt.Errorf("voiceworld: open audio: %w", err)
+	}
+	defer f.Close()
+
+	frameBytes := int64(info.NumChannels) * 2
+	bytesPerSecond := float64(info.SampleRate) * float64(frameBytes)
+	consumed := info.DataOffset
+	lastGrowth := c.now()
+	var results []*RecognitionResult
+
+	for {
+		fi, err := f.Stat()
+		if err != nil {
+			return nil, fmt.Errorf("voiceworld: stat growing file: %w", err)
+		}
+		chunk := fi.Size() - consumed
+		chunk -= chunk % frameBytes
+		if chunk > 0 {
+			audio := make([]byte, chunk)
+			if _, err := f.ReadAt(audio, consumed); err != nil {
+				return nil, fmt.Errorf("voiceworld: read appended pcm: %w", err)
+			}
+			result, err := c.recognizeChunk(config, audio)
+			if err != nil {
+				return nil, err
+			}
+			// The local byte count is exact; it keeps the stitched
+			// timeline right even when the server reports no duration.
+			result.Duration = NewTimestamp(time.Duration(float64(chunk) / bytesPerSecond * float64(time.Second)))
+			results = append(results, result)
+			consumed += chunk
+			lastGrowth = c.now()
+			continue
+		}
+		if c.now().Sub(lastGrowth) >= quiescence {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			// Cancellation finalizes: whatever was transcribed so far
+			// is the answer.
+			return MergeResults(results), nil
+		case <-time.After(poll):
+		}
+	}
+	return MergeResults(results), nil
+}
+
+// waitForWAVHeader polls until path parses as a WAV, for recordings whose
+// header is written a beat after the file is created.
+func waitForWAVHeader(ctx context.Context, path string, poll time.Duration) (*WAVInfo, error) {
+	for {
+		info, err := ParseWAVFile(path)
+		if err == nil {
+			return info, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("voiceworld: waiting for wav header: %w", ctx.Err())
+		case <-time.After(poll):
+		}
+	}
+}
+
+// recognizeChunk submits one appended PCM slice as a sync recognition call.
+func (c *Client) recognizeChunk(config *RecognitionConfig, audio []byte) (*RecognitionResult, error) {
+	var result RecognitionResult
+	var body io.Reader = bytes.NewReader(audio)
+	if err := c.do(http.MethodPost, "/asr", config.params(), "application/octet-stream",
+		body, &result); err != nil {
+		return nil, modelSelectionError(err, config.ModelID)
+	}
+	return &result, nil
+}
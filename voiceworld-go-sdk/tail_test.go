@@ -0,0 +1,120 @@
+package voiceworld
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// appendSamples writes frames to the end of a growing recording.
+func appendSamples(t *testing.T, path string, frames int) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	b := make([]byte, 2)
+	for i := 0; i < frames; i++ {
+		binary.LittleEndian.PutUint16(b, uint16(i%4096))
+		if _, err := f.Write(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// startGrowingWAV creates a mono 16 kHz recording whose header claims a
+// placeholder data size, the way capture processes leave it until close.
+func startGrowingWAV(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "live.wav")
+	data := makeWAVData(t, 16000, 1, 0)
+	// Stamp the streaming placeholder over the data chunk size.
+	binary.LittleEndian.PutUint32(data[len(data)-4:], 0xFFFFFFFF)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRecognizeGrowingFile(t *testing.T) {
+	var received int64
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		body, _ := io.ReadAll(r.Body)
+		atomic.AddInt64(&received, int64(len(body)))
+		if got := r.URL.Query().Get("format"); got != "pcm" {
+			t.Errorf("format = %q, want pcm", got)
+		}
+		fmt.Fprintf(w, `{"code":0,"data":{"task_id":"tail","result":"chunk %d"}}`, n)
+	}))
+	defer server.Close()
+	client, _ := newTestClient(t, &ClientConfig{BaseURL: server.URL})
+
+	path := startGrowingWAV(t)
+	appendSamples(t, path, 8000) // 0.5 s before recognition starts
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		// Two more appends while recognition is already tailing.
+		for i := 0; i < 2; i++ {
+			time.Sleep(30 * time.Millisecond)
+			appendSamples(t, path, 4000) // 0.25 s each
+		}
+	}()
+
+	result, err := client.RecognizeGrowingFile(context.Background(), path, &TailOptions{
+		PollInterval: 5 * time.Millisecond,
+		Quiescence:   300 * time.Millisecond,
+	})
+	<-done
+	if err != nil {
+		t.Fatalf("RecognizeGrowingFile: %v", err)
+	}
+	if got := atomic.LoadInt64(&received); got != 32000 {
+		t.Errorf("server received %d pcm bytes, want 32000", got)
+	}
+	if result.Duration.Duration != time.Second {
+		t.Errorf("merged duration = %v, want 1s (from consumed bytes, not the stale header)", result.Duration.Duration)
+	}
+	if atomic.LoadInt32(&calls) < 2 {
+		t.Errorf("appended audio submitted in %d calls, want several", calls)
+	}
+	if result.Result == "" {
+		t.Error("merged transcript is empty")
+	}
+}
+
+func TestRecognizeGrowingFileCancelFinalizes(t *testing.T) {
+	server := newEnvelopeServer(t, `{"code":0,"data":{"task_id":"tail","result":"partial"}}`)
+	client, _ := newTestClient(t, &ClientConfig{BaseURL: server.URL})
+
+	path := startGrowingWAV(t)
+	appendSamples(t, path, 1600)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+	result, err := client.RecognizeGrowingFile(ctx, path, &TailOptions{
+		PollInterval: 5 * time.Millisecond,
+		Quiescence:   time.Hour, // only cancellation can finalize
+	})
+	if err != nil {
+		t.Fatalf("RecognizeGrowingFile: %v", err)
+	}
+	if result.Result != "partial" {
+		t.Errorf("accumulated transcript = %q, want %q", result.Result, "partial")
+	}
+}
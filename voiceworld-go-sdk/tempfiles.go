@@ -0,0 +1,114 @@
+package voiceworld
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// processedTempPrefix marks every temp file ProcessAudio creates, so cleanup
+// can recognize SDK leftovers without ever touching arbitrary files.
+const processedTempPrefix = "voiceworld-processed-"
+
+// tempFiles registers the temp files this process created and has not yet
+// removed itself, so CleanupTempFiles can reclaim them regardless of age.
+var tempFiles = struct {
+	mu    sync.Mutex
+	paths map[string]struct{}
+}{paths: make(map[string]struct{})}
+
+// newProcessedTempFile creates a registered temp file for converted audio.
+func newProcessedTempFile() (*os.File, error) {
+	f, err := os.CreateTemp("", processedTempPrefix+"*.wav")
+	if err != nil {
+		return nil, err
+	}
+	tempFiles.mu.Lock()
+	tempFiles.paths[f.Name()] = struct{}{}
+	tempFiles.mu.Unlock()
+	return f, nil
+}
+
+// forgetTempFile drops a path from the registry once it has been removed.
+func forgetTempFile(path string) {
+	tempFiles.mu.Lock()
+	delete(tempFiles.paths, path)
+	tempFiles.mu.Unlock()
+}
+
+// removeTempFile deletes a registered temp file and forgets it.
+func removeTempFile(path string) {
+	os.Remove(path)
+	forgetTempFile(path)
+}
+
+// registeredTempFiles snapshots the registry.
+func registeredTempFiles() []string {
+	tempFiles.mu.Lock()
+	defer tempFiles.mu.Unlock()
+	paths := make([]string, 0, len(tempFiles.paths))
+	for p := range tempFiles.paths {
+		paths = append(paths, p)
+	}
+	return paths
+}
+
+// CleanupTempFiles removes converted-audio temp files the SDK left behind:
+// everything this process registered and, from the system temp directory,
+// any "voiceworld-processed-*.wav" older than olderThan — typically debris
+// from a crashed or killed earlier run. Files without the SDK's name prefix
+// are never touched. It returns how many files were removed; removal
+// problems are collected rather than stopping the sweep.
+func (c *Client) CleanupTempFiles(olderThan time.Duration) (int, error) {
+	cutoff := c.now().Add(-olderThan)
+	removed := 0
+	var problems []string
+
+	for _, path := range registeredTempFiles() {
+		fi, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			forgetTempFile(path) // the caller already removed it
+			continue
+		}
+		if err == nil && fi.ModTime().After(cutoff) {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		forgetTempFile(path)
+		removed++
+	}
+
+	entries, err := os.ReadDir(os.TempDir())
+	if err != nil {
+		return removed, fmt.Errorf("voiceworld: scan temp dir: %w", err)
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, processedTempPrefix) || !strings.HasSuffix(name, ".wav") {
+			continue
+		}
+		path := filepath.Join(os.TempDir(), name)
+		fi, err := entry.Info()
+		if err != nil || fi.ModTime().After(cutoff) {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			if !os.IsNotExist(err) {
+				problems = append(problems, err.Error())
+			}
+			continue
+		}
+		forgetTempFile(path)
+		removed++
+	}
+	if len(problems) > 0 {
+		return removed, fmt.Errorf("voiceworld: cleanup temp files: %s", strings.Join(problems, "; "))
+	}
+	return removed, nil
+}
@@ -0,0 +1,138 @@
+package voiceworld
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// stepCancelContext reports itself cancelled after a fixed number of Err
+// checks, so cancellation lands deterministically mid-conversion.
+type stepCancelContext struct {
+	context.Context
+	checksLeft int32
+}
+
+func (c *stepCancelContext) Err() error {
+	if atomic.AddInt32(&c.checksLeft, -1) < 0 {
+		return context.Canceled
+	}
+	return nil
+}
+
+// processedTempPaths lists the SDK's temp files currently on disk.
+func processedTempPaths(t *testing.T) map[string]bool {
+	t.Helper()
+	entries, err := os.ReadDir(os.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths := make(map[string]bool)
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), processedTempPrefix) {
+			paths[e.Name()] = true
+		}
+	}
+	return paths
+}
+
+func TestProcessAudioContextCancelledMidCopy(t *testing.T) {
+	path := makeWAVFile(t, 44100, 2, 3) // several copy blocks of input
+	before := processedTempPaths(t)
+
+	// Let the header parse and the first blocks through, then cancel.
+	ctx := &stepCancelContext{Context: context.Background(), checksLeft: 2}
+	out, err := ProcessAudioContext(ctx, path)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if out != "" {
+		t.Errorf("cancelled conversion returned a path: %q", out)
+	}
+	after := processedTempPaths(t)
+	for name := range after {
+		if !before[name] {
+			t.Errorf("partial output %s left behind", name)
+		}
+	}
+}
+
+func TestProcessAudioContextCancelledDuringWrite(t *testing.T) {
+	path := makeWAVFile(t, 16000, 1, 10) // multi-block output payload
+	before := processedTempPaths(t)
+
+	// Enough checks to finish reading, cancelling inside the write loop.
+	ctx := &stepCancelContext{Context: context.Background(), checksLeft: 3}
+	if _, err := ProcessAudioContext(ctx, path); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	after := processedTempPaths(t)
+	for name := range after {
+		if !before[name] {
+			t.Errorf("partial output %s left behind", name)
+		}
+	}
+}
+
+func TestCleanupTempFiles(t *testing.T) {
+	client, _ := newTestClient(t, nil)
+	path := makeWAVFile(t, 16000, 1, 0.1)
+	out, err := ProcessAudio(path)
+	if err != nil {
+		t.Fatalf("ProcessAudio: %v", err)
+	}
+
+	// A foreign file in the temp dir must survive the sweep.
+	foreign := filepath.Join(os.TempDir(), "voiceworld-unrelated.wav")
+	if err := os.WriteFile(foreign, []byte("keep"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(foreign)
+
+	removed, err := client.CleanupTempFiles(0)
+	if err != nil {
+		t.Fatalf("CleanupTempFiles: %v", err)
+	}
+	if removed == 0 {
+		t.Error("nothing removed, want at least the registered conversion output")
+	}
+	if _, err := os.Stat(out); !os.IsNotExist(err) {
+		t.Errorf("registered temp file %s still present", out)
+	}
+	if _, err := os.Stat(foreign); err != nil {
+		t.Errorf("foreign file was touched: %v", err)
+	}
+}
+
+func TestCleanupTempFilesRespectsAge(t *testing.T) {
+	client, _ := newTestClient(t, nil)
+	path := makeWAVFile(t, 16000, 1, 0.1)
+	out, err := ProcessAudio(path)
+	if err != nil {
+		t.Fatalf("ProcessAudio: %v", err)
+	}
+	defer removeTempFile(out)
+
+	if _, err := client.CleanupTempFiles(time.Hour); err != nil {
+		t.Fatalf("CleanupTempFiles: %v", err)
+	}
+	if _, err := os.Stat(out); err != nil {
+		t.Errorf("fresh temp file was removed: %v", err)
+	}
+
+	old := client.now().Add(-2 * time.Hour)
+	if err := os.Chtimes(out, old, old); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.CleanupTempFiles(time.Hour); err != nil {
+		t.Fatalf("CleanupTempFiles: %v", err)
+	}
+	if _, err := os.Stat(out); !os.IsNotExist(err) {
+		t.Error("stale temp file survived the sweep")
+	}
+}
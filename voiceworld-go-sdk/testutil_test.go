@@ -0,0 +1,72 @@
+package voiceworld
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// ms is shorthand for building Timestamp fixtures.
+func ms(n int64) Timestamp {
+	return NewTimestamp(time.Duration(n) * time.Millisecond)
+}
+
+// newEnvelopeServer serves a fixed JSON envelope to every request.
+func newEnvelopeServer(t *testing.T, body string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// makeWAVData builds an in-memory 16-bit PCM WAV with a deterministic sample
+// pattern so tests can verify payload integrity after round trips.
+func makeWAVData(t *testing.T, sampleRate, channels int, seconds float64) []byte {
+	t.Helper()
+	numFrames := int(float64(sampleRate) * seconds)
+	info := &WAVInfo{NumChannels: channels, SampleRate: sampleRate, BitsPerSample: 16}
+	var buf bytes.Buffer
+	dataSize := int64(numFrames * channels * 2)
+	if err := writeWAVHeader(&buf, info, dataSize); err != nil {
+		t.Fatalf("writeWAVHeader: %v", err)
+	}
+	sample := make([]byte, 2)
+	for i := 0; i < numFrames*channels; i++ {
+		binary.LittleEndian.PutUint16(sample, uint16(i%4096))
+		buf.Write(sample)
+	}
+	return buf.Bytes()
+}
+
+// makeWAVFile writes a deterministic WAV into a temp dir and returns its path.
+func makeWAVFile(t *testing.T, sampleRate, channels int, seconds float64) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "audio.wav")
+	if err := os.WriteFile(path, makeWAVData(t, sampleRate, channels, seconds), 0o644); err != nil {
+		t.Fatalf("write wav fixture: %v", err)
+	}
+	return path
+}
+
+// newTestClient builds a Client wired to a MemoryStorage; API calls that do
+// not touch storage will fail, which is fine for upload-path tests.
+func newTestClient(t *testing.T, config *ClientConfig) (*Client, *MemoryStorage) {
+	t.Helper()
+	store := NewMemoryStorage()
+	if config == nil {
+		config = &ClientConfig{}
+	}
+	config.Storage = store
+	client, err := NewClient("test-app-key", "test-secret", config)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return client, store
+}
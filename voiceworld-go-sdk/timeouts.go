@@ -0,0 +1,110 @@
+package voiceworld
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// Phases of a timed-out request, for telling "the server is unreachable"
+// apart from "the server took the request and went quiet".
+const (
+	// TimeoutPhaseConnect means the connection was never established.
+	TimeoutPhaseConnect = "connect"
+	// TimeoutPhaseResponse means the server was reached but the response
+	// did not complete within the bound.
+	TimeoutPhaseResponse = "response"
+)
+
+// TimeoutError reports an API call that exceeded its configured time bound.
+// Phase distinguishes connect timeouts from response timeouts, and Limit is
+// the bound that applied — the per-operation one when configured, otherwise
+// ClientConfig.Timeout.
+type TimeoutError struct {
+	// Endpoint is the API path that timed out, e.g. "/asr".
+	Endpoint string
+	// Limit is the time bound that was exceeded.
+	Limit time.Duration
+	// Phase is TimeoutPhaseConnect or TimeoutPhaseResponse.
+	Phase string
+	// Err is the underlying transport error.
+	Err error
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("voiceworld: %s did not complete within %s (%s timeout): %v",
+		e.Endpoint, e.Limit, e.Phase, e.Err)
+}
+
+func (e *TimeoutError) Unwrap() error { return e.Err }
+
+// Timeout marks the error for os.IsTimeout and the net.Error convention.
+func (e *TimeoutError) Timeout() bool { return true }
+
+// operationTimeout is the per-operation bound for one endpoint: control-plane
+// calls (token issuing, clock sync) answer in milliseconds and get
+// TokenTimeout, recognition calls may chew on long audio and get ASRTimeout,
+// and the preprocess endpoint receives streamed audio under UploadTimeout.
+// Zero means no per-operation bound — unclassified endpoints and classes
+// left unset stay under the client-wide Timeout alone.
+func (c *Client) operationTimeout(path string) time.Duration {
+	switch {
+	case path == "/get_oss_token" || path == "/time":
+		return c.config.TokenTimeout
+	case strings.HasPrefix(path, "/asr") || path == "/language_id":
+		return c.config.ASRTimeout
+	case path == "/preprocess_audio":
+		return c.config.UploadTimeout
+	}
+	return 0
+}
+
+// operationContext derives the context one request runs under, bounded by
+// the endpoint's operation timeout when one is configured.
+func (c *Client) operationContext(path string) (context.Context, context.CancelFunc) {
+	timeout := c.operationTimeout(path)
+	if timeout <= 0 {
+		return context.Background(), func() {}
+	}
+	return context.WithTimeout(context.Background(), timeout)
+}
+
+// wrapTimeout converts a timed-out request's error into a *TimeoutError for
+// path; anything that is not a timeout passes through unchanged.
+func (c *Client) wrapTimeout(path string, err error) error {
+	if err == nil || !isTimeout(err) {
+		return err
+	}
+	limit := c.operationTimeout(path)
+	if limit <= 0 {
+		limit = c.config.Timeout
+	}
+	return &TimeoutError{
+		Endpoint: path,
+		Limit:    limit,
+		Phase:    timeoutPhase(err),
+		Err:      err,
+	}
+}
+
+// isTimeout reports whether err's chain contains a deadline failure.
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	return os.IsTimeout(err)
+}
+
+// timeoutPhase classifies where a timed-out request died: a dial that never
+// completed is a connect timeout, everything past it a response timeout.
+func timeoutPhase(err error) string {
+	var op *net.OpError
+	if errors.As(err, &op) && op.Op == "dial" {
+		return TimeoutPhaseConnect
+	}
+	return TimeoutPhaseResponse
+}
@@ -0,0 +1,91 @@
+package voiceworld
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestOperationTimeoutClassification(t *testing.T) {
+	client, _ := newTestClient(t, &ClientConfig{
+		TokenTimeout:  time.Second,
+		ASRTimeout:    10 * time.Minute,
+		UploadTimeout: 2 * time.Minute,
+	})
+	tests := []struct {
+		path string
+		want time.Duration
+	}{
+		{"/get_oss_token", time.Second},
+		{"/time", time.Second},
+		{"/asr", 10 * time.Minute},
+		{"/asr_result", 10 * time.Minute},
+		{"/asr_multitrack", 10 * time.Minute},
+		{"/language_id", 10 * time.Minute},
+		{"/preprocess_audio", 2 * time.Minute},
+		{"/models", 0},
+		{"/quota", 0},
+	}
+	for _, tt := range tests {
+		if got := client.operationTimeout(tt.path); got != tt.want {
+			t.Errorf("operationTimeout(%s) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestASRTimeoutReturnsTypedError(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Write([]byte(`{"code":0,"data":{}}`))
+	}))
+	t.Cleanup(func() { close(release); server.Close() })
+
+	client, _ := newTestClient(t, &ClientConfig{
+		BaseURL:    server.URL,
+		ASRTimeout: 50 * time.Millisecond,
+	})
+	_, err := client.GetTaskResult("t-1", nil)
+	var terr *TimeoutError
+	if !errors.As(err, &terr) {
+		t.Fatalf("err = %v, want a *TimeoutError", err)
+	}
+	if terr.Endpoint != "/asr_result" {
+		t.Errorf("Endpoint = %q, want /asr_result", terr.Endpoint)
+	}
+	if terr.Limit != 50*time.Millisecond {
+		t.Errorf("Limit = %v, want 50ms", terr.Limit)
+	}
+	if terr.Phase != TimeoutPhaseResponse {
+		t.Errorf("Phase = %q, want %q", terr.Phase, TimeoutPhaseResponse)
+	}
+	if !os.IsTimeout(err) {
+		t.Error("a *TimeoutError must satisfy os.IsTimeout")
+	}
+}
+
+func TestTimeoutPhaseClassification(t *testing.T) {
+	dial := &net.OpError{Op: "dial", Net: "tcp", Err: context.DeadlineExceeded}
+	if got := timeoutPhase(dial); got != TimeoutPhaseConnect {
+		t.Errorf("dial timeout phase = %q, want %q", got, TimeoutPhaseConnect)
+	}
+	if got := timeoutPhase(context.DeadlineExceeded); got != TimeoutPhaseResponse {
+		t.Errorf("body timeout phase = %q, want %q", got, TimeoutPhaseResponse)
+	}
+}
+
+func TestWrapTimeoutPassesThroughOtherErrors(t *testing.T) {
+	client, _ := newTestClient(t, &ClientConfig{ASRTimeout: time.Second})
+	apiErr := &APIError{Code: 42, Message: "nope"}
+	if got := client.wrapTimeout("/asr", apiErr); got != apiErr {
+		t.Errorf("wrapTimeout rewrote a non-timeout error: %v", got)
+	}
+	if got := client.wrapTimeout("/asr", nil); got != nil {
+		t.Errorf("wrapTimeout(nil) = %v", got)
+	}
+}
@@ -0,0 +1,111 @@
+package voiceworld
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Timestamp is a moment or span on the audio timeline. Server versions
+// disagree on the wire shape - integer milliseconds, float seconds, or
+// "HH:MM:SS.mmm" clock strings - so it decodes all three and normalizes to
+// the embedded time.Duration. Marshaling always emits integer milliseconds.
+type Timestamp struct {
+	time.Duration
+	// Raw preserves the wire value verbatim when it matched none of the
+	// known shapes. Duration is zero then and the response as a whole
+	// still decodes; callers that care can inspect Raw.
+	Raw string
+}
+
+// NewTimestamp wraps a duration in a Timestamp.
+func NewTimestamp(d time.Duration) Timestamp {
+	return Timestamp{Duration: d}
+}
+
+// MarshalJSON emits integer milliseconds, or the preserved raw value (as a
+// string) for timestamps that never parsed.
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if t.Raw != "" {
+		return json.Marshal(t.Raw)
+	}
+	return []byte(strconv.FormatInt(t.Milliseconds(), 10)), nil
+}
+
+// UnmarshalJSON accepts every timestamp shape observed in the wild:
+// integer milliseconds (1500), float seconds (1.5), and clock strings
+// ("00:00:01.500", also as the content of a JSON string holding either
+// numeric form). Unparseable values land in Raw instead of failing the
+// whole response.
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	*t = Timestamp{}
+	s := strings.TrimSpace(string(data))
+	if s == "" || s == "null" {
+		return nil
+	}
+	if strings.HasPrefix(s, `"`) {
+		var str string
+		if err := json.Unmarshal(data, &str); err != nil {
+			t.Raw = s
+			return nil
+		}
+		if d, ok := parseTimestampString(str); ok {
+			t.Duration = d
+			return nil
+		}
+		t.Raw = str
+		return nil
+	}
+	if d, ok := parseTimestampNumber(s); ok {
+		t.Duration = d
+		return nil
+	}
+	t.Raw = s
+	return nil
+}
+
+// parseTimestampNumber interprets a bare JSON number: integers are
+// milliseconds, anything with a fraction or exponent is seconds.
+func parseTimestampNumber(s string) (time.Duration, bool) {
+	if !strings.ContainsAny(s, ".eE") {
+		ms, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return 0, false
+		}
+		return time.Duration(ms) * time.Millisecond, true
+	}
+	sec, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false
+	}
+	return time.Duration(sec * float64(time.Second)), true
+}
+
+// parseTimestampString interprets the content of a JSON string: a clock
+// value like "01:02:03.456", or either numeric form quoted.
+func parseTimestampString(s string) (time.Duration, bool) {
+	parts := strings.Split(s, ":")
+	if len(parts) == 3 {
+		h, err1 := strconv.Atoi(parts[0])
+		m, err2 := strconv.Atoi(parts[1])
+		sec, err3 := strconv.ParseFloat(parts[2], 64)
+		if err1 == nil && err2 == nil && err3 == nil &&
+			h >= 0 && m >= 0 && m < 60 && sec >= 0 && sec < 60 {
+			return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute +
+				time.Duration(sec*float64(time.Second)), true
+		}
+		return 0, false
+	}
+	return parseTimestampNumber(strings.TrimSpace(s))
+}
+
+// String renders the normalized value, or the raw wire value when parsing
+// failed.
+func (t Timestamp) String() string {
+	if t.Raw != "" {
+		return t.Raw
+	}
+	return fmt.Sprintf("%dms", t.Milliseconds())
+}
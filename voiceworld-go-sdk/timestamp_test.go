@@ -0,0 +1,75 @@
+package voiceworld
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// TestTimestampUnmarshal covers every wire shape observed across server
+// versions.
+func TestTimestampUnmarshal(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		json string
+		want time.Duration
+		raw  string
+	}{
+		{name: "integer milliseconds", json: `1500`, want: 1500 * time.Millisecond},
+		{name: "zero", json: `0`, want: 0},
+		{name: "float seconds", json: `1.5`, want: 1500 * time.Millisecond},
+		{name: "float seconds sub-ms", json: `0.0015`, want: 1500 * time.Microsecond},
+		{name: "exponent seconds", json: `1.5e1`, want: 15 * time.Second},
+		{name: "clock string", json: `"00:00:01.500"`, want: 1500 * time.Millisecond},
+		{name: "clock string with hours", json: `"01:02:03.456"`,
+			want: time.Hour + 2*time.Minute + 3456*time.Millisecond},
+		{name: "clock string no millis", json: `"00:01:02"`, want: 62 * time.Second},
+		{name: "quoted milliseconds", json: `"1500"`, want: 1500 * time.Millisecond},
+		{name: "quoted float seconds", json: `"1.5"`, want: 1500 * time.Millisecond},
+		{name: "null", json: `null`, want: 0},
+		{name: "garbage string", json: `"half past three"`, raw: "half past three"},
+		{name: "out of range clock", json: `"00:99:00"`, raw: "00:99:00"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var ts Timestamp
+			if err := json.Unmarshal([]byte(tc.json), &ts); err != nil {
+				t.Fatalf("Unmarshal(%s): %v", tc.json, err)
+			}
+			if ts.Duration != tc.want || ts.Raw != tc.raw {
+				t.Errorf("Unmarshal(%s) = {%v %q}, want {%v %q}",
+					tc.json, ts.Duration, ts.Raw, tc.want, tc.raw)
+			}
+		})
+	}
+}
+
+// TestTimestampBadValueDoesNotFailResponse: an unparseable time must not
+// error the surrounding decode.
+func TestTimestampBadValueDoesNotFailResponse(t *testing.T) {
+	var result RecognitionResult
+	err := json.Unmarshal([]byte(`{"result":"ok","duration":"soon","segments":[`+
+		`{"text":"a","begin_time":1.0,"end_time":"00:00:02.000"}]}`), &result)
+	if err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if result.Duration.Raw != "soon" || result.Duration.Duration != 0 {
+		t.Errorf("Duration = %+v", result.Duration)
+	}
+	seg := result.Segments[0]
+	if seg.BeginTime != ms(1000) || seg.EndTime != ms(2000) {
+		t.Errorf("segment times = %v, %v", seg.BeginTime, seg.EndTime)
+	}
+}
+
+func TestTimestampMarshal(t *testing.T) {
+	data, err := json.Marshal(struct {
+		A Timestamp `json:"a"`
+		B Timestamp `json:"b"`
+	}{ms(62345), Timestamp{Raw: "half past three"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data); got != `{"a":62345,"b":"half past three"}` {
+		t.Errorf("Marshal = %s", got)
+	}
+}
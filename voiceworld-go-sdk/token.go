@@ -0,0 +1,204 @@
+package voiceworld
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OSSCredentials is a set of temporary STS credentials scoped to the bucket
+// that stages audio for recognition.
+type OSSCredentials struct {
+	AccessKeyID     string `json:"access_key_id"`
+	AccessKeySecret string `json:"access_key_secret"`
+	SecurityToken   string `json:"security_token"`
+	// Expiration is RFC 3339 in UTC, as issued by STS.
+	Expiration string `json:"expiration"`
+	Endpoint   string `json:"endpoint"`
+	Bucket     string `json:"bucket"`
+}
+
+// OSSToken is the historical name for OSSCredentials, kept for existing
+// callers of GetOSSToken.
+type OSSToken = OSSCredentials
+
+// Expired reports whether the credentials' expiration lies in the past (with
+// a small safety margin so almost-dead credentials are not handed to a long
+// upload).
+func (t *OSSCredentials) Expired() bool {
+	exp, err := time.Parse(time.RFC3339, t.Expiration)
+	if err != nil {
+		// An unparseable expiration is treated as expired so callers
+		// refresh rather than fail mid-upload.
+		return true
+	}
+	return time.Until(exp) < 30*time.Second
+}
+
+// TokenProvider supplies object storage credentials to the upload paths.
+// Implementations must be safe for concurrent use. Deployments whose STS
+// tokens come from elsewhere (e.g. an internal vault) plug in their own
+// implementation via ClientConfig.TokenProvider.
+type TokenProvider interface {
+	OSSCredentials(ctx context.Context) (*OSSCredentials, error)
+}
+
+// TokenRequest narrows the credentials a provider is asked for, so the
+// token service can mint STS grants scoped to exactly what one operation
+// needs instead of the whole bucket. The zero value requests the historical
+// bucket-wide grant. Servers that ignore the parameters keep working — the
+// response shape is unchanged.
+type TokenRequest struct {
+	// Prefix limits the grant to object keys under this prefix, e.g.
+	// "req-1/". Empty means no key restriction.
+	Prefix string
+	// TTL asks for credentials valid this long. Zero leaves the lifetime
+	// to the server.
+	TTL time.Duration
+	// Actions lists the storage operations the grant needs, e.g.
+	// "PutObject". Empty leaves the action set to the server.
+	Actions []string
+}
+
+// zero reports whether the request asks for nothing beyond the default
+// bucket-wide grant.
+func (r TokenRequest) zero() bool {
+	return r.Prefix == "" && r.TTL == 0 && len(r.Actions) == 0
+}
+
+// ScopedTokenProvider is the optional TokenProvider capability behind
+// prefix-scoped uploads. Providers without it are handed no scope and keep
+// returning their usual bucket-wide credentials.
+type ScopedTokenProvider interface {
+	ScopedOSSCredentials(ctx context.Context, req TokenRequest) (*OSSCredentials, error)
+}
+
+// httpTokenProvider is the platform's own token service: a signed POST to
+// /get_oss_token. It is the default when no TokenProvider is configured.
+type httpTokenProvider struct {
+	client *Client
+}
+
+func (p *httpTokenProvider) OSSCredentials(ctx context.Context) (*OSSCredentials, error) {
+	return p.ScopedOSSCredentials(ctx, TokenRequest{})
+}
+
+func (p *httpTokenProvider) ScopedOSSCredentials(ctx context.Context, req TokenRequest) (*OSSCredentials, error) {
+	body := struct {
+		Prefix     string   `json:"prefix,omitempty"`
+		TTLSeconds int64    `json:"ttl_seconds,omitempty"`
+		Actions    []string `json:"actions,omitempty"`
+	}{req.Prefix, int64(req.TTL / time.Second), req.Actions}
+	var creds OSSCredentials
+	if err := p.client.postJSON("/get_oss_token", &body, &creds); err != nil {
+		return nil, err
+	}
+	return &creds, nil
+}
+
+// StaticTokenProvider returns a TokenProvider that always hands out the same
+// credentials, typically long-lived keys in on-prem deployments or fixtures
+// in tests.
+func StaticTokenProvider(creds OSSCredentials) TokenProvider {
+	return &staticTokenProvider{creds: creds}
+}
+
+type staticTokenProvider struct {
+	creds OSSCredentials
+}
+
+func (p *staticTokenProvider) OSSCredentials(ctx context.Context) (*OSSCredentials, error) {
+	creds := p.creds
+	return &creds, nil
+}
+
+// CredentialInvalidator is implemented by TokenProviders that memoize
+// credentials and can drop them, so the client can force a refresh once the
+// storage backend rejects a set as expired. NewCachingTokenProvider's
+// provider implements it.
+type CredentialInvalidator interface {
+	InvalidateCredentials()
+}
+
+// NewCachingTokenProvider wraps another provider and reuses its credentials
+// until they expire, so bursts of uploads do not hammer the token service.
+// Scoped credentials are cached per scope: a grant narrowed to one request's
+// prefix is never handed to an upload targeting another.
+func NewCachingTokenProvider(inner TokenProvider) TokenProvider {
+	return &cachingTokenProvider{inner: inner, cached: make(map[string]*OSSCredentials)}
+}
+
+type cachingTokenProvider struct {
+	inner TokenProvider
+
+	mu     sync.Mutex
+	cached map[string]*OSSCredentials // scope key -> credentials
+}
+
+// scopeKey is the cache key for one TokenRequest; the zero request maps to
+// "" so unscoped callers share one entry.
+func scopeKey(req TokenRequest) string {
+	if req.zero() {
+		return ""
+	}
+	return req.Prefix + "\x00" + req.TTL.String() + "\x00" + strings.Join(req.Actions, ",")
+}
+
+func (p *cachingTokenProvider) OSSCredentials(ctx context.Context) (*OSSCredentials, error) {
+	return p.ScopedOSSCredentials(ctx, TokenRequest{})
+}
+
+func (p *cachingTokenProvider) ScopedOSSCredentials(ctx context.Context, req TokenRequest) (*OSSCredentials, error) {
+	key := scopeKey(req)
+	if _, ok := p.inner.(ScopedTokenProvider); !ok {
+		// The inner provider hands out the same bucket-wide credentials
+		// regardless of scope; share one cache entry.
+		key = ""
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if cached, ok := p.cached[key]; ok && !cached.Expired() {
+		creds := *cached
+		return &creds, nil
+	}
+	creds, err := fetchCredentials(ctx, p.inner, req)
+	if err != nil {
+		return nil, err
+	}
+	p.cached[key] = creds
+	out := *creds
+	return &out, nil
+}
+
+// InvalidateCredentials drops every cached credential set so the next call
+// hits the inner provider, even if a cached set had not nominally expired —
+// the storage service has the final word on validity.
+func (p *cachingTokenProvider) InvalidateCredentials() {
+	p.mu.Lock()
+	p.cached = make(map[string]*OSSCredentials)
+	p.mu.Unlock()
+}
+
+// fetchCredentials asks provider for credentials, passing the scope along
+// when it can accept one and falling back to the bucket-wide grant when it
+// cannot.
+func fetchCredentials(ctx context.Context, provider TokenProvider, req TokenRequest) (*OSSCredentials, error) {
+	if sp, ok := provider.(ScopedTokenProvider); ok && !req.zero() {
+		return sp.ScopedOSSCredentials(ctx, req)
+	}
+	return provider.OSSCredentials(ctx)
+}
+
+// GetOSSToken fetches temporary object storage credentials from the client's
+// TokenProvider (by default, the /get_oss_token endpoint).
+func (c *Client) GetOSSToken() (*OSSToken, error) {
+	return c.tokenProvider.OSSCredentials(context.Background())
+}
+
+// GetScopedOSSToken fetches credentials narrowed per req, when the
+// configured TokenProvider can mint them; otherwise it returns the usual
+// bucket-wide credentials.
+func (c *Client) GetScopedOSSToken(req TokenRequest) (*OSSToken, error) {
+	return fetchCredentials(context.Background(), c.tokenProvider, req)
+}
@@ -0,0 +1,211 @@
+package voiceworld
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestScopedTokenRequestBody(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode token request: %v", err)
+		}
+		mu.Lock()
+		bodies = append(bodies, body)
+		mu.Unlock()
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"code": 0,
+			"data": map[string]string{
+				"access_key_id":     "id",
+				"access_key_secret": "secret",
+				"security_token":    "token",
+				"expiration":        "2099-01-01T00:00:00Z",
+				"endpoint":          "oss.example.com",
+				"bucket":            "bucket",
+			},
+		})
+	}))
+	defer server.Close()
+	client, err := NewClient("app-key", "secret", &ClientConfig{BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	if _, err := client.GetScopedOSSToken(TokenRequest{
+		Prefix:  "req-1/",
+		TTL:     15 * time.Minute,
+		Actions: []string{"PutObject"},
+	}); err != nil {
+		t.Fatalf("GetScopedOSSToken: %v", err)
+	}
+	if _, err := client.GetOSSToken(); err != nil {
+		t.Fatalf("GetOSSToken: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(bodies) != 2 {
+		t.Fatalf("got %d token requests, want 2", len(bodies))
+	}
+	scoped := bodies[0]
+	if scoped["prefix"] != "req-1/" {
+		t.Errorf("prefix = %v, want req-1/", scoped["prefix"])
+	}
+	if scoped["ttl_seconds"] != float64(900) {
+		t.Errorf("ttl_seconds = %v, want 900", scoped["ttl_seconds"])
+	}
+	if actions, _ := scoped["actions"].([]interface{}); len(actions) != 1 || actions[0] != "PutObject" {
+		t.Errorf("actions = %v, want [PutObject]", scoped["actions"])
+	}
+	// The unscoped request keeps the historical empty body, so old servers
+	// see no change.
+	if len(bodies[1]) != 0 {
+		t.Errorf("unscoped token request body = %v, want empty", bodies[1])
+	}
+}
+
+// recordingScopedProvider hands out fixed credentials and records every
+// scope it was asked for.
+type recordingScopedProvider struct {
+	mu   sync.Mutex
+	reqs []TokenRequest
+}
+
+func (p *recordingScopedProvider) OSSCredentials(ctx context.Context) (*OSSCredentials, error) {
+	return p.ScopedOSSCredentials(ctx, TokenRequest{})
+}
+
+func (p *recordingScopedProvider) ScopedOSSCredentials(ctx context.Context, req TokenRequest) (*OSSCredentials, error) {
+	p.mu.Lock()
+	p.reqs = append(p.reqs, req)
+	p.mu.Unlock()
+	return &OSSCredentials{
+		AccessKeyID:     "id",
+		AccessKeySecret: "secret",
+		Expiration:      "2099-01-01T00:00:00Z",
+		Endpoint:        "oss.example.com",
+		Bucket:          "bucket",
+	}, nil
+}
+
+func (p *recordingScopedProvider) requests() []TokenRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]TokenRequest(nil), p.reqs...)
+}
+
+func TestUploadRequestsPrefixScopedToken(t *testing.T) {
+	provider := &recordingScopedProvider{}
+	client, err := NewClient("app-key", "secret", &ClientConfig{
+		TokenProvider: provider,
+		StorageConfig: &StorageConfig{Provider: "fs", RootDir: t.TempDir()},
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	path := makeWAVFile(t, 16000, 1, 0.1)
+	if _, err := client.UploadFile(path, "req-9/a.wav"); err != nil {
+		t.Fatalf("UploadFile: %v", err)
+	}
+	reqs := provider.requests()
+	if len(reqs) == 0 {
+		t.Fatal("no token request recorded")
+	}
+	if reqs[0].Prefix != "req-9/" {
+		t.Errorf("token request prefix = %q, want req-9/", reqs[0].Prefix)
+	}
+}
+
+// countingScopedProvider counts fetches per prefix.
+type countingScopedProvider struct {
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (p *countingScopedProvider) OSSCredentials(ctx context.Context) (*OSSCredentials, error) {
+	return p.ScopedOSSCredentials(ctx, TokenRequest{})
+}
+
+func (p *countingScopedProvider) ScopedOSSCredentials(ctx context.Context, req TokenRequest) (*OSSCredentials, error) {
+	p.mu.Lock()
+	if p.calls == nil {
+		p.calls = make(map[string]int)
+	}
+	p.calls[req.Prefix]++
+	p.mu.Unlock()
+	return &OSSCredentials{AccessKeyID: "id-" + req.Prefix, Expiration: "2099-01-01T00:00:00Z"}, nil
+}
+
+func TestCachingProviderCachesPerScope(t *testing.T) {
+	inner := &countingScopedProvider{}
+	provider := NewCachingTokenProvider(inner).(ScopedTokenProvider)
+	ctx := context.Background()
+
+	a := TokenRequest{Prefix: "req-a/"}
+	b := TokenRequest{Prefix: "req-b/"}
+	for i := 0; i < 3; i++ {
+		creds, err := provider.ScopedOSSCredentials(ctx, a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if creds.AccessKeyID != "id-req-a/" {
+			t.Fatalf("scope a got credentials %q", creds.AccessKeyID)
+		}
+	}
+	if _, err := provider.ScopedOSSCredentials(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+	inner.mu.Lock()
+	if inner.calls["req-a/"] != 1 || inner.calls["req-b/"] != 1 {
+		t.Errorf("inner calls = %v, want one per prefix", inner.calls)
+	}
+	inner.mu.Unlock()
+
+	provider.(CredentialInvalidator).InvalidateCredentials()
+	if _, err := provider.ScopedOSSCredentials(ctx, a); err != nil {
+		t.Fatal(err)
+	}
+	inner.mu.Lock()
+	if inner.calls["req-a/"] != 2 {
+		t.Errorf("invalidation did not drop the scoped entry: %v", inner.calls)
+	}
+	inner.mu.Unlock()
+}
+
+// countingPlainProvider cannot mint scoped tokens.
+type countingPlainProvider struct {
+	mu    sync.Mutex
+	calls int
+}
+
+func (p *countingPlainProvider) OSSCredentials(ctx context.Context) (*OSSCredentials, error) {
+	p.mu.Lock()
+	p.calls++
+	p.mu.Unlock()
+	return &OSSCredentials{AccessKeyID: "id", Expiration: "2099-01-01T00:00:00Z"}, nil
+}
+
+func TestCachingProviderSharesEntryForUnscopedInner(t *testing.T) {
+	inner := &countingPlainProvider{}
+	provider := NewCachingTokenProvider(inner).(ScopedTokenProvider)
+	ctx := context.Background()
+	if _, err := provider.ScopedOSSCredentials(ctx, TokenRequest{Prefix: "req-a/"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := provider.ScopedOSSCredentials(ctx, TokenRequest{Prefix: "req-b/"}); err != nil {
+		t.Fatal(err)
+	}
+	inner.mu.Lock()
+	defer inner.mu.Unlock()
+	if inner.calls != 1 {
+		t.Errorf("inner provider called %d times, want 1 shared bucket-wide fetch", inner.calls)
+	}
+}
@@ -0,0 +1,133 @@
+package voiceworld
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// scriptedTokenProvider returns its credentials in sequence, recording how
+// often it is asked.
+type scriptedTokenProvider struct {
+	creds []OSSCredentials
+	calls int
+	err   error
+}
+
+func (p *scriptedTokenProvider) OSSCredentials(ctx context.Context) (*OSSCredentials, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	i := p.calls
+	if i >= len(p.creds) {
+		i = len(p.creds) - 1
+	}
+	p.calls++
+	creds := p.creds[i]
+	return &creds, nil
+}
+
+func expiringAt(d time.Duration) string {
+	return time.Now().Add(d).UTC().Format(time.RFC3339)
+}
+
+func TestStaticTokenProvider(t *testing.T) {
+	want := OSSCredentials{AccessKeyID: "id", Bucket: "b"}
+	p := StaticTokenProvider(want)
+	got, err := p.OSSCredentials(context.Background())
+	if err != nil {
+		t.Fatalf("OSSCredentials: %v", err)
+	}
+	if got.AccessKeyID != "id" || got.Bucket != "b" {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestCachingTokenProviderReusesFreshCredentials(t *testing.T) {
+	inner := &scriptedTokenProvider{creds: []OSSCredentials{
+		{AccessKeyID: "first", Expiration: expiringAt(time.Hour)},
+	}}
+	p := NewCachingTokenProvider(inner)
+
+	for i := 0; i < 3; i++ {
+		creds, err := p.OSSCredentials(context.Background())
+		if err != nil {
+			t.Fatalf("call %d: %v", i, err)
+		}
+		if creds.AccessKeyID != "first" {
+			t.Errorf("call %d: AccessKeyID = %q", i, creds.AccessKeyID)
+		}
+	}
+	if inner.calls != 1 {
+		t.Errorf("inner provider called %d times, want 1", inner.calls)
+	}
+}
+
+func TestCachingTokenProviderRefreshesExpired(t *testing.T) {
+	inner := &scriptedTokenProvider{creds: []OSSCredentials{
+		{AccessKeyID: "stale", Expiration: expiringAt(5 * time.Second)}, // inside the safety margin
+		{AccessKeyID: "fresh", Expiration: expiringAt(time.Hour)},
+	}}
+	p := NewCachingTokenProvider(inner)
+
+	if _, err := p.OSSCredentials(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	creds, err := p.OSSCredentials(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if creds.AccessKeyID != "fresh" {
+		t.Errorf("AccessKeyID = %q, want fresh credentials after expiry", creds.AccessKeyID)
+	}
+	if inner.calls != 2 {
+		t.Errorf("inner provider called %d times, want 2", inner.calls)
+	}
+}
+
+func TestCachingTokenProviderPropagatesErrors(t *testing.T) {
+	wantErr := errors.New("vault unavailable")
+	p := NewCachingTokenProvider(&scriptedTokenProvider{err: wantErr})
+	if _, err := p.OSSCredentials(context.Background()); !errors.Is(err, wantErr) {
+		t.Errorf("err = %v, want %v", err, wantErr)
+	}
+}
+
+func TestExpired(t *testing.T) {
+	tests := []struct {
+		name       string
+		expiration string
+		want       bool
+	}{
+		{"far future", expiringAt(time.Hour), false},
+		{"past", expiringAt(-time.Minute), true},
+		{"inside safety margin", expiringAt(10 * time.Second), true},
+		{"unparseable", "not-a-time", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			creds := &OSSCredentials{Expiration: tt.expiration}
+			if got := creds.Expired(); got != tt.want {
+				t.Errorf("Expired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientUsesConfiguredTokenProvider(t *testing.T) {
+	inner := &scriptedTokenProvider{creds: []OSSCredentials{
+		{AccessKeyID: "vault-id", Expiration: expiringAt(time.Hour)},
+	}}
+	client, err := NewClient("ak", "sk", &ClientConfig{TokenProvider: inner})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := client.GetOSSToken()
+	if err != nil {
+		t.Fatalf("GetOSSToken: %v", err)
+	}
+	if token.AccessKeyID != "vault-id" {
+		t.Errorf("AccessKeyID = %q, want the provider's credentials", token.AccessKeyID)
+	}
+}
@@ -0,0 +1,395 @@
+package voiceworld
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUploadFile(t *testing.T) {
+	client, store := newTestClient(t, nil)
+	path := filepath.Join(t.TempDir(), "a.bin")
+	content := []byte("some audio bytes")
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	url, err := client.UploadFile(path, "req-1/a.bin")
+	if err != nil {
+		t.Fatalf("UploadFile: %v", err)
+	}
+	if !strings.HasSuffix(url, "req-1/a.bin") {
+		t.Errorf("signed url = %q, want suffix req-1/a.bin", url)
+	}
+	got, ok := store.Object("req-1/a.bin")
+	if !ok {
+		t.Fatalf("object not stored; keys = %v", store.Keys())
+	}
+	if !bytes.Equal(got, content) {
+		t.Errorf("stored bytes = %q, want %q", got, content)
+	}
+}
+
+func TestUploadFileMissingSource(t *testing.T) {
+	client, _ := newTestClient(t, nil)
+	if _, err := client.UploadFile("/does/not/exist", "k"); err == nil {
+		t.Fatal("expected error for missing source file")
+	}
+}
+
+func TestMultipartUploadFile(t *testing.T) {
+	// 3 parts: two full 1 KiB chunks and a 512 B tail, uploaded two at a time.
+	client, store := newTestClient(t, &ClientConfig{ChunkSize: 1024, Parallelism: 2})
+	path := filepath.Join(t.TempDir(), "big.bin")
+	content := bytes.Repeat([]byte{0xAB}, 2*1024+512)
+	for i := range content {
+		content[i] = byte(i) // make reordering detectable
+	}
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	url, err := client.MultipartUploadFile(path, "req-2/big.bin")
+	if err != nil {
+		t.Fatalf("MultipartUploadFile: %v", err)
+	}
+	if !strings.HasSuffix(url, "req-2/big.bin") {
+		t.Errorf("signed url = %q, want suffix req-2/big.bin", url)
+	}
+	got, ok := store.Object("req-2/big.bin")
+	if !ok {
+		t.Fatal("object not stored")
+	}
+	if !bytes.Equal(got, content) {
+		t.Errorf("assembled object differs from source (%d vs %d bytes)", len(got), len(content))
+	}
+}
+
+func TestUploadPreprocessedAudio(t *testing.T) {
+	client, store := newTestClient(t, nil)
+	path := filepath.Join(t.TempDir(), "p.wav")
+	if err := os.WriteFile(path, []byte("processed"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.UploadPreprocessedAudio(path, "req-3"); err != nil {
+		t.Fatalf("UploadPreprocessedAudio: %v", err)
+	}
+	if _, ok := store.Object("preprocessed/req-3.wav"); !ok {
+		t.Errorf("expected key preprocessed/req-3.wav, have %v", store.Keys())
+	}
+}
+
+func TestSplitAudioFile(t *testing.T) {
+	client, store := newTestClient(t, &ClientConfig{Parallelism: 3})
+	// 5 seconds of 16 kHz mono audio split into 2 s chunks -> 3 parts.
+	path := makeWAVFile(t, 16000, 1, 5)
+
+	result, err := client.SplitAudioFile(path, 2)
+	if err != nil {
+		t.Fatalf("SplitAudioFile: %v", err)
+	}
+	if len(result.Parts) != 3 {
+		t.Fatalf("got %d parts, want 3", len(result.Parts))
+	}
+	if result.TotalDuration < 4.99 || result.TotalDuration > 5.01 {
+		t.Errorf("TotalDuration = %v, want ~5s", result.TotalDuration)
+	}
+	for i, part := range result.Parts {
+		if part.Index != i+1 {
+			t.Errorf("part %d has Index %d", i, part.Index)
+		}
+		data, ok := store.Object(part.ObjectKey)
+		if !ok {
+			t.Fatalf("chunk %s not stored", part.ObjectKey)
+		}
+		info, err := ParseWAV(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("chunk %d is not valid WAV: %v", part.Index, err)
+		}
+		if info.SampleRate != 16000 || info.NumChannels != 1 {
+			t.Errorf("chunk %d format = %d Hz/%d ch, want 16000/1", part.Index, info.SampleRate, info.NumChannels)
+		}
+	}
+	// Last chunk carries the 1 s remainder.
+	last := result.Parts[2]
+	if last.Duration < 0.99 || last.Duration > 1.01 {
+		t.Errorf("last chunk duration = %v, want ~1s", last.Duration)
+	}
+}
+
+func TestCleanup(t *testing.T) {
+	client, store := newTestClient(t, nil)
+	seed := map[string]string{
+		"req-9/0001.wav": "a",
+		"req-9/0002.wav": "b",
+		"other/keep.wav": "c",
+	}
+	for k, v := range seed {
+		if err := store.PutObject(k, strings.NewReader(v)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := client.Cleanup("req-9"); err != nil {
+		t.Fatalf("Cleanup: %v", err)
+	}
+	if got := store.Keys(); len(got) != 1 || got[0] != "other/keep.wav" {
+		t.Errorf("keys after cleanup = %v, want [other/keep.wav]", got)
+	}
+}
+
+func TestCleanupEmptyRequestID(t *testing.T) {
+	client, _ := newTestClient(t, nil)
+	if err := client.Cleanup(""); err == nil {
+		t.Fatal("expected error for empty request id")
+	}
+}
+
+// failingKeyStorage rejects puts for keys with a given suffix, and can
+// optionally break deletes too, to exercise the partial-failure paths of
+// SplitAudioFile.
+type failingKeyStorage struct {
+	*MemoryStorage
+	failSuffix   string
+	deleteBroken bool
+}
+
+func (s *failingKeyStorage) PutObject(key string, r io.Reader) error {
+	if strings.HasSuffix(key, s.failSuffix) {
+		return errors.New("simulated put failure")
+	}
+	return s.MemoryStorage.PutObject(key, r)
+}
+
+func (s *failingKeyStorage) DeleteObjects(keys []string) error {
+	if s.deleteBroken {
+		return errors.New("simulated delete failure")
+	}
+	return s.MemoryStorage.DeleteObjects(keys)
+}
+
+func TestSplitAudioFilePartialUploadError(t *testing.T) {
+	store := &failingKeyStorage{MemoryStorage: NewMemoryStorage(), failSuffix: "0002.wav"}
+	client, err := NewClient("ak", "sk", &ClientConfig{Storage: store, Parallelism: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.SplitAudioFile(makeWAVFile(t, 16000, 1, 5), 2)
+	var perr *PartialUploadError
+	if !errors.As(err, &perr) {
+		t.Fatalf("SplitAudioFile error = %v, want *PartialUploadError", err)
+	}
+	if perr.RequestID == "" {
+		t.Error("PartialUploadError has no request id")
+	}
+	if len(perr.UploadedKeys) != 2 {
+		t.Fatalf("UploadedKeys = %v, want the 2 chunks that made it", perr.UploadedKeys)
+	}
+	for i, key := range perr.UploadedKeys {
+		if strings.HasSuffix(key, "0002.wav") {
+			t.Errorf("failed chunk listed as uploaded: %v", perr.UploadedKeys)
+		}
+		if i > 0 && key < perr.UploadedKeys[i-1] {
+			t.Errorf("UploadedKeys not in index order: %v", perr.UploadedKeys)
+		}
+		if _, ok := store.Object(key); !ok {
+			t.Errorf("key %s listed but not in the bucket", key)
+		}
+	}
+	if perr.CleanupErr != nil {
+		t.Errorf("CleanupErr = %v without CleanupOnFailure", perr.CleanupErr)
+	}
+	if !strings.Contains(err.Error(), "simulated put failure") {
+		t.Errorf("error message hides the cause: %v", err)
+	}
+}
+
+func TestSplitAudioFileCleanupOnFailure(t *testing.T) {
+	store := &failingKeyStorage{MemoryStorage: NewMemoryStorage(), failSuffix: "0002.wav"}
+	client, err := NewClient("ak", "sk", &ClientConfig{Storage: store, Parallelism: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.SplitAudioFile(makeWAVFile(t, 16000, 1, 5), 2, &SplitOptions{CleanupOnFailure: true})
+	var perr *PartialUploadError
+	if !errors.As(err, &perr) {
+		t.Fatalf("SplitAudioFile error = %v, want *PartialUploadError", err)
+	}
+	if len(perr.UploadedKeys) != 2 {
+		t.Fatalf("UploadedKeys = %v, want the 2 chunks that made it", perr.UploadedKeys)
+	}
+	if perr.CleanupErr != nil {
+		t.Fatalf("CleanupErr = %v, want successful cleanup", perr.CleanupErr)
+	}
+	if keys := store.Keys(); len(keys) != 0 {
+		t.Errorf("bucket still holds %v after cleanup", keys)
+	}
+}
+
+func TestSplitAudioFileCleanupFailureDoesNotMaskCause(t *testing.T) {
+	store := &failingKeyStorage{
+		MemoryStorage: NewMemoryStorage(),
+		failSuffix:    "0002.wav",
+		deleteBroken:  true,
+	}
+	client, err := NewClient("ak", "sk", &ClientConfig{Storage: store, Parallelism: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.SplitAudioFile(makeWAVFile(t, 16000, 1, 5), 2, &SplitOptions{CleanupOnFailure: true})
+	var perr *PartialUploadError
+	if !errors.As(err, &perr) {
+		t.Fatalf("SplitAudioFile error = %v, want *PartialUploadError", err)
+	}
+	if perr.CleanupErr == nil {
+		t.Fatal("CleanupErr not recorded")
+	}
+	if !strings.Contains(err.Error(), "simulated put failure") ||
+		!strings.Contains(err.Error(), "simulated delete failure") {
+		t.Errorf("error message should carry both failures: %v", err)
+	}
+	if !strings.Contains(errors.Unwrap(err).Error(), "simulated put failure") {
+		t.Errorf("Unwrap = %v, want the original cause", errors.Unwrap(err))
+	}
+}
+
+func TestSplitAudioFileContinueOnError(t *testing.T) {
+	store := &failingKeyStorage{MemoryStorage: NewMemoryStorage(), failSuffix: "0002.wav"}
+	client, err := NewClient("ak", "sk", &ClientConfig{Storage: store, Parallelism: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := client.SplitAudioFile(makeWAVFile(t, 16000, 1, 5), 2, &SplitOptions{ContinueOnError: true})
+	if err != nil {
+		t.Fatalf("SplitAudioFile: %v", err)
+	}
+	if result.FailedParts != 1 {
+		t.Errorf("FailedParts = %d, want 1", result.FailedParts)
+	}
+	if len(result.Parts) != 3 {
+		t.Fatalf("got %d parts, want 3 (alignment preserved)", len(result.Parts))
+	}
+	bad := result.Parts[1]
+	if bad.Index != 2 || bad.Error == "" || bad.ObjectKey != "" || bad.URL != "" {
+		t.Errorf("failed part = %+v, want Index 2 with Error and no object", bad)
+	}
+	if bad.Size == 0 || bad.Duration < 1.99 || bad.Duration > 2.01 {
+		t.Errorf("failed part should keep its shape, got Size %d Duration %v", bad.Size, bad.Duration)
+	}
+	for _, i := range []int{0, 2} {
+		part := result.Parts[i]
+		if part.Error != "" || part.URL == "" {
+			t.Errorf("part %d = %+v, want a successful upload", i+1, part)
+		}
+		if _, ok := store.Object(part.ObjectKey); !ok {
+			t.Errorf("part %d not in the bucket", i+1)
+		}
+	}
+}
+
+func TestSplitAudioFileContinueOnErrorAllFailed(t *testing.T) {
+	store := &failingKeyStorage{MemoryStorage: NewMemoryStorage(), failSuffix: ".wav"}
+	client, err := NewClient("ak", "sk", &ClientConfig{Storage: store, Parallelism: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.SplitAudioFile(makeWAVFile(t, 16000, 1, 5), 2, &SplitOptions{ContinueOnError: true})
+	if err == nil {
+		t.Fatal("expected failure when every chunk fails")
+	}
+	if !strings.Contains(err.Error(), "all 3 chunks") {
+		t.Errorf("error should report the failure count: %v", err)
+	}
+}
+
+func TestSplitAudioFilePartNameFunc(t *testing.T) {
+	client, store := newTestClient(t, &ClientConfig{Parallelism: 3})
+
+	result, err := client.SplitAudioFile(makeWAVFile(t, 16000, 1, 5), 2, &SplitOptions{
+		PartNameFunc: OffsetPartName,
+	})
+	if err != nil {
+		t.Fatalf("SplitAudioFile: %v", err)
+	}
+	want := []string{
+		result.RequestID + "/part_0001_0000000000ms.wav",
+		result.RequestID + "/part_0002_0000002000ms.wav",
+		result.RequestID + "/part_0003_0000004000ms.wav",
+	}
+	for i, part := range result.Parts {
+		if part.ObjectKey != want[i] {
+			t.Errorf("part %d key = %q, want %q", i+1, part.ObjectKey, want[i])
+		}
+		if _, ok := store.Object(part.ObjectKey); !ok {
+			t.Errorf("part %d not stored under its generated name", i+1)
+		}
+	}
+	// Lexical order and part order must still agree for downstream sorters.
+	keys := store.Keys()
+	for i, key := range keys {
+		if key != want[i] {
+			t.Errorf("lexical position %d = %q, want %q", i, key, want[i])
+		}
+	}
+}
+
+func TestSplitAudioFileCustomPartName(t *testing.T) {
+	client, store := newTestClient(t, nil)
+
+	result, err := client.SplitAudioFile(makeWAVFile(t, 16000, 1, 3), 2, &SplitOptions{
+		PartNameFunc: func(requestID string, index, total int, startMs int64) string {
+			return fmt.Sprintf("%s/chunk-%d-of-%d.wav", requestID, index, total)
+		},
+	})
+	if err != nil {
+		t.Fatalf("SplitAudioFile: %v", err)
+	}
+	if got := result.Parts[1].ObjectKey; got != result.RequestID+"/chunk-2-of-2.wav" {
+		t.Errorf("part 2 key = %q", got)
+	}
+	if len(store.Keys()) != 2 {
+		t.Errorf("keys = %v, want 2 chunks", store.Keys())
+	}
+}
+
+func TestListRequestParts(t *testing.T) {
+	client, store := newTestClient(t, nil)
+	seed := []string{
+		"req-7/0002.wav",
+		"req-7/part_1.wav",
+		"req-7/part_0003_0000120000ms.wav",
+		"req-7/notes.txt",
+		"other/0001.wav",
+	}
+	for _, key := range seed {
+		if err := store.PutObject(key, strings.NewReader("x")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	parts, err := client.ListRequestParts("req-7")
+	if err != nil {
+		t.Fatalf("ListRequestParts: %v", err)
+	}
+	if len(parts) != 3 {
+		t.Fatalf("got %d parts, want 3: %+v", len(parts), parts)
+	}
+	wantKeys := []string{"req-7/part_1.wav", "req-7/0002.wav", "req-7/part_0003_0000120000ms.wav"}
+	for i, part := range parts {
+		if part.Index != i+1 || part.ObjectKey != wantKeys[i] {
+			t.Errorf("parts[%d] = %+v, want index %d key %q", i, part, i+1, wantKeys[i])
+		}
+	}
+	if _, err := client.ListRequestParts(""); err == nil {
+		t.Error("expected error for empty request id")
+	}
+}
@@ -0,0 +1,112 @@
+package voiceworld
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignedURLClampedToCredentialLifetime(t *testing.T) {
+	instant := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	var warnings []string
+	client, store := newTestClient(t, &ClientConfig{
+		OnWarning: func(msg string) { warnings = append(warnings, msg) },
+	})
+	client.now = fixedClock(instant).Now
+	// The fake token dies in 90 seconds, far short of the default
+	// one-hour URL expiry.
+	store.CredentialExpiry = instant.Add(90 * time.Second)
+
+	path := makeWAVFile(t, 16000, 1, 2)
+	result, err := client.SplitAudioFile(path, 1)
+	if err != nil {
+		t.Fatalf("SplitAudioFile: %v", err)
+	}
+	for _, part := range result.Parts {
+		if !part.ExpiresAt.Equal(store.CredentialExpiry) {
+			t.Errorf("part %d ExpiresAt = %v, want the token expiry %v",
+				part.Index, part.ExpiresAt, store.CredentialExpiry)
+		}
+	}
+	if len(warnings) != len(result.Parts) {
+		t.Fatalf("got %d clamp warnings for %d signed parts", len(warnings), len(result.Parts))
+	}
+	// Parts upload in parallel, so match warnings to keys as a set.
+	all := strings.Join(warnings, "\n")
+	if !strings.Contains(all, "clamped") {
+		t.Errorf("warnings %q do not name the clamp", all)
+	}
+	for _, part := range result.Parts {
+		if !strings.Contains(all, part.ObjectKey) {
+			t.Errorf("no warning names the clamped key %s", part.ObjectKey)
+		}
+	}
+}
+
+func TestSignedURLFullTTLWithoutCredentialBound(t *testing.T) {
+	instant := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	warned := false
+	client, _ := newTestClient(t, &ClientConfig{
+		OnWarning: func(string) { warned = true },
+	})
+	client.now = fixedClock(instant).Now
+
+	result, err := client.SplitAudioFile(makeWAVFile(t, 16000, 1, 2), 1)
+	if err != nil {
+		t.Fatalf("SplitAudioFile: %v", err)
+	}
+	for _, part := range result.Parts {
+		if want := instant.Add(signedURLExpiry); !part.ExpiresAt.Equal(want) {
+			t.Errorf("part %d ExpiresAt = %v, want the full default ttl %v", part.Index, part.ExpiresAt, want)
+		}
+	}
+	if warned {
+		t.Error("warning fired although no credential bound was set")
+	}
+}
+
+func TestProcessAndUploadFileReportsURLExpiry(t *testing.T) {
+	instant := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	client, store := newTestClient(t, nil)
+	client.now = fixedClock(instant).Now
+
+	result, err := client.ProcessAndUploadFile(makeWAVFile(t, 16000, 1, 1), "req-exp")
+	if err != nil {
+		t.Fatalf("ProcessAndUploadFile: %v", err)
+	}
+	if want := instant.Add(signedURLExpiry); !result.URLExpiresAt.Equal(want) {
+		t.Errorf("URLExpiresAt = %v, want %v", result.URLExpiresAt, want)
+	}
+
+	// A dying token shortens the advertised expiry the same way it
+	// shortens the URL.
+	store.CredentialExpiry = instant.Add(2 * time.Minute)
+	result, err = client.ProcessAndUploadFile(makeWAVFile(t, 16000, 1, 1), "req-exp2")
+	if err != nil {
+		t.Fatalf("ProcessAndUploadFile: %v", err)
+	}
+	if !result.URLExpiresAt.Equal(store.CredentialExpiry) {
+		t.Errorf("URLExpiresAt = %v, want the token expiry %v", result.URLExpiresAt, store.CredentialExpiry)
+	}
+}
+
+func TestRefreshURLsStampsNewExpiry(t *testing.T) {
+	instant := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	client, _ := newTestClient(t, nil)
+	client.now = fixedClock(instant).Now
+
+	result, err := client.SplitAudioFile(makeWAVFile(t, 16000, 1, 2), 1)
+	if err != nil {
+		t.Fatalf("SplitAudioFile: %v", err)
+	}
+	later := instant.Add(3 * time.Hour)
+	client.now = fixedClock(later).Now
+	if err := result.RefreshURLs(client); err != nil {
+		t.Fatalf("RefreshURLs: %v", err)
+	}
+	for _, part := range result.Parts {
+		if want := later.Add(signedURLExpiry); !part.ExpiresAt.Equal(want) {
+			t.Errorf("part %d ExpiresAt = %v, want %v after refresh", part.Index, part.ExpiresAt, want)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package voiceworld
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ErrUnsupportedEndpoint reports that the server predates an API the SDK
+// asked for (usage and quota reporting shipped later than recognition).
+// Callers can degrade gracefully by testing with errors.Is.
+var ErrUnsupportedEndpoint = errors.New("voiceworld: endpoint not available on this server version")
+
+// UsagePeriod bounds a usage query; both ends are inclusive.
+type UsagePeriod struct {
+	From time.Time
+	To   time.Time
+}
+
+// UsageDay is one day of the breakdown inside a UsageReport.
+type UsageDay struct {
+	// Date is the day in "2006-01-02" form, server-local.
+	Date string `json:"date"`
+	// AudioSeconds is the audio processed that day.
+	AudioSeconds int64 `json:"audio_seconds"`
+	// Requests counts API calls by endpoint path, e.g. "/asr".
+	Requests map[string]int64 `json:"requests,omitempty"`
+}
+
+// UsageReport aggregates consumption over a period.
+type UsageReport struct {
+	// AudioSeconds is the audio processed over the whole period.
+	AudioSeconds int64 `json:"audio_seconds"`
+	// Requests counts API calls by endpoint path.
+	Requests map[string]int64 `json:"requests,omitempty"`
+	// StoredBytes is the staging storage held at the end of the period.
+	StoredBytes int64 `json:"stored_bytes"`
+	// Days is the daily breakdown, collected across result pages.
+	Days []UsageDay `json:"days,omitempty"`
+}
+
+// Quota reports the plan limits and what is left of them.
+type Quota struct {
+	// PlanAudioSeconds is the audio allowance per billing period; zero
+	// means unmetered.
+	PlanAudioSeconds int64 `json:"plan_audio_seconds"`
+	// RemainingAudioSeconds is the unused part of the allowance.
+	RemainingAudioSeconds int64 `json:"remaining_audio_seconds"`
+	// PlanStoredBytes is the staging storage allowance.
+	PlanStoredBytes int64 `json:"plan_stored_bytes"`
+	// RemainingStoredBytes is the unused part of the storage allowance.
+	RemainingStoredBytes int64 `json:"remaining_stored_bytes"`
+	// ResetsAt is when the audio allowance renews.
+	ResetsAt time.Time `json:"resets_at"`
+}
+
+// GetUsage reports consumption over the period via the /usage endpoint,
+// following pagination until the daily breakdown is complete. Servers
+// without the endpoint surface ErrUnsupportedEndpoint.
+func (c *Client) GetUsage(period UsagePeriod) (*UsageReport, error) {
+	if period.From.IsZero() || period.To.IsZero() {
+		return nil, fmt.Errorf("voiceworld: usage period needs both From and To")
+	}
+	if period.To.Before(period.From) {
+		return nil, fmt.Errorf("voiceworld: usage period ends before it starts")
+	}
+
+	report := &UsageReport{}
+	pageToken := ""
+	for {
+		query := url.Values{
+			"from": {period.From.UTC().Format(time.RFC3339)},
+			"to":   {period.To.UTC().Format(time.RFC3339)},
+		}
+		if pageToken != "" {
+			query.Set("page_token", pageToken)
+		}
+		var page struct {
+			UsageReport
+			NextPageToken string `json:"next_page_token"`
+		}
+		if err := c.do(http.MethodGet, "/usage", query, "", nil, &page); err != nil {
+			return nil, unsupportedEndpointError(err)
+		}
+		if pageToken == "" {
+			report.AudioSeconds = page.AudioSeconds
+			report.Requests = page.Requests
+			report.StoredBytes = page.StoredBytes
+		}
+		report.Days = append(report.Days, page.Days...)
+		if page.NextPageToken == "" {
+			return report, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+// GetQuota reports the plan limits and remaining allowance via the /quota
+// endpoint. Servers without the endpoint surface ErrUnsupportedEndpoint.
+func (c *Client) GetQuota() (*Quota, error) {
+	var quota Quota
+	if err := c.do(http.MethodGet, "/quota", nil, "", nil, &quota); err != nil {
+		return nil, unsupportedEndpointError(err)
+	}
+	return &quota, nil
+}
+
+// unsupportedEndpointError maps "this server does not have the endpoint"
+// responses to ErrUnsupportedEndpoint, leaving real failures untouched.
+func unsupportedEndpointError(err error) error {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) &&
+		(apiErr.HTTPStatus == http.StatusNotFound || apiErr.HTTPStatus == http.StatusNotImplemented) {
+		return fmt.Errorf("%w (%v)", ErrUnsupportedEndpoint, err)
+	}
+	return err
+}
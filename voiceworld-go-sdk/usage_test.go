@@ -0,0 +1,98 @@
+package voiceworld
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetUsageFollowsPagination(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/usage" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		queries = append(queries, r.URL.RawQuery)
+		if r.URL.Query().Get("page_token") == "" {
+			w.Write([]byte(`{"code":0,"data":{"audio_seconds":7200,` +
+				`"requests":{"/asr":120,"/preprocess_audio":8},"stored_bytes":1048576,` +
+				`"days":[{"date":"2026-08-01","audio_seconds":3600,"requests":{"/asr":60}}],` +
+				`"next_page_token":"p2"}}`))
+			return
+		}
+		w.Write([]byte(`{"code":0,"data":{` +
+			`"days":[{"date":"2026-08-02","audio_seconds":3600}]}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC)
+	report, err := client.GetUsage(UsagePeriod{From: from, To: from.AddDate(0, 1, 0)})
+	if err != nil {
+		t.Fatalf("GetUsage: %v", err)
+	}
+	if len(queries) != 2 {
+		t.Fatalf("server saw %d requests: %v", len(queries), queries)
+	}
+	if report.AudioSeconds != 7200 || report.Requests["/asr"] != 120 || report.StoredBytes != 1048576 {
+		t.Errorf("report = %+v", report)
+	}
+	if len(report.Days) != 2 || report.Days[1].Date != "2026-08-02" {
+		t.Errorf("Days = %+v", report.Days)
+	}
+}
+
+func TestGetUsageValidatesPeriod(t *testing.T) {
+	client, _ := newTestClient(t, nil)
+	if _, err := client.GetUsage(UsagePeriod{}); err == nil {
+		t.Error("empty period accepted")
+	}
+	now := time.Now()
+	if _, err := client.GetUsage(UsagePeriod{From: now, To: now.Add(-time.Hour)}); err == nil {
+		t.Error("inverted period accepted")
+	}
+}
+
+func TestGetQuota(t *testing.T) {
+	server := newEnvelopeServer(t, `{"code":0,"data":{"plan_audio_seconds":360000,`+
+		`"remaining_audio_seconds":120000,"plan_stored_bytes":5368709120,`+
+		`"remaining_stored_bytes":4294967296,"resets_at":"2026-09-01T00:00:00Z"}}`)
+	client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	quota, err := client.GetQuota()
+	if err != nil {
+		t.Fatalf("GetQuota: %v", err)
+	}
+	if quota.RemainingAudioSeconds != 120000 || quota.PlanStoredBytes != 5368709120 {
+		t.Errorf("quota = %+v", quota)
+	}
+	if quota.ResetsAt.Month() != time.September {
+		t.Errorf("ResetsAt = %v", quota.ResetsAt)
+	}
+}
+
+// TestUsageUnsupportedServer maps a plain 404 to ErrUnsupportedEndpoint so
+// callers can degrade gracefully on older deployments.
+func TestUsageUnsupportedServer(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(server.Close)
+
+	client, err := NewClient("ak", "sk", &ClientConfig{BaseURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetQuota(); !errors.Is(err, ErrUnsupportedEndpoint) {
+		t.Errorf("GetQuota err = %v, want ErrUnsupportedEndpoint", err)
+	}
+	now := time.Now()
+	if _, err := client.GetUsage(UsagePeriod{From: now.Add(-time.Hour), To: now}); !errors.Is(err, ErrUnsupportedEndpoint) {
+		t.Errorf("GetUsage err = %v, want ErrUnsupportedEndpoint", err)
+	}
+}
@@ -0,0 +1,43 @@
+package voiceworld
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// NewRequestID returns a fresh identifier used to group the staged objects
+// and recognition results of one logical request. IDs are URL- and
+// object-key-safe, unique across concurrent processes, and sort in creation
+// order: a fixed-width millisecond timestamp followed by random bits, so two
+// processes started in the same second never collide the way bare Unix
+// timestamps do.
+func NewRequestID() string {
+	var b [8]byte
+	if _, err := randRead(b[:]); err != nil {
+		return fmt.Sprintf("req-%d", timeNow().UnixNano())
+	}
+	return fmt.Sprintf("req-%012x-%s", timeNow().UnixMilli(), hex.EncodeToString(b[:]))
+}
+
+// validateRequestID checks a caller-supplied request id against the
+// character set safe for object key prefixes: letters, digits, '-', '_' and
+// '.'. Path separators and ".." are rejected so an id cannot escape its
+// slot in the key space.
+func validateRequestID(id string) error {
+	if id == "" {
+		return fmt.Errorf("voiceworld: request id must not be empty")
+	}
+	if strings.Contains(id, "..") {
+		return fmt.Errorf("voiceworld: invalid request id %q: must not contain %q", id, "..")
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9',
+			r == '-', r == '_', r == '.':
+		default:
+			return fmt.Errorf("voiceworld: invalid request id %q: only letters, digits, '-', '_' and '.' are allowed", id)
+		}
+	}
+	return nil
+}
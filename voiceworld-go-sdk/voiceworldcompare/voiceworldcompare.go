@@ -0,0 +1,210 @@
+// Package voiceworldcompare measures how two transcripts differ, for
+// re-recognition comparisons after model upgrades: word error rate over
+// CJK-aware tokens, character error rate, and the aligned edit operations
+// behind either, suitable for rendering a diff.
+package voiceworldcompare
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Options controls transcript normalization before comparison. The zero
+// value (and nil) lower-cases Latin text and ignores punctuation, since
+// recognition casing and EnablePunctuation output are not what benchmark
+// comparisons are after.
+type Options struct {
+	// CaseSensitive keeps letter case significant.
+	CaseSensitive bool
+	// KeepPunctuation compares punctuation marks as tokens of their own
+	// instead of discarding them.
+	KeepPunctuation bool
+}
+
+func (o *Options) orDefault() *Options {
+	if o == nil {
+		return &Options{}
+	}
+	return o
+}
+
+// Tokenize splits a transcript into comparison tokens: every Han character
+// is a token of its own — Chinese recognition output carries no usable word
+// boundaries — while runs of other letters and digits form word tokens.
+// Punctuation and spacing are dropped unless opts.KeepPunctuation.
+func Tokenize(s string, opts *Options) []string {
+	opts = opts.orDefault()
+	var tokens []string
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			tokens = append(tokens, word.String())
+			word.Reset()
+		}
+	}
+	for _, r := range s {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			flush()
+			tokens = append(tokens, string(r))
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if !opts.CaseSensitive {
+				r = unicode.ToLower(r)
+			}
+			word.WriteRune(r)
+		case unicode.IsSpace(r):
+			flush()
+		default:
+			flush()
+			if opts.KeepPunctuation {
+				tokens = append(tokens, string(r))
+			}
+		}
+	}
+	flush()
+	return tokens
+}
+
+// WER is the word error rate of hyp against the reference ref: the minimum
+// number of token substitutions, insertions and deletions turning ref into
+// hyp, divided by the reference token count. Tokenization is CJK-aware; see
+// Tokenize. An empty reference yields 0 against an empty hypothesis and
+// +Inf against anything else.
+func WER(ref, hyp string, opts *Options) float64 {
+	return errorRate(Tokenize(ref, opts), Tokenize(hyp, opts))
+}
+
+// CER is the character error rate: the same edit-distance measure over
+// individual characters with spacing removed, the figure Chinese benchmarks
+// usually report.
+func CER(ref, hyp string, opts *Options) float64 {
+	return errorRate(runeTokens(ref, opts), runeTokens(hyp, opts))
+}
+
+// runeTokens is character-level tokenization under the same normalization
+// rules as Tokenize.
+func runeTokens(s string, opts *Options) []string {
+	var chars []string
+	for _, token := range Tokenize(s, opts) {
+		for _, r := range token {
+			chars = append(chars, string(r))
+		}
+	}
+	return chars
+}
+
+func errorRate(ref, hyp []string) float64 {
+	if len(ref) == 0 {
+		if len(hyp) == 0 {
+			return 0
+		}
+		return math.Inf(1)
+	}
+	edits := 0
+	for _, op := range align(ref, hyp) {
+		if op.Kind != Match {
+			edits++
+		}
+	}
+	return float64(edits) / float64(len(ref))
+}
+
+// OpKind classifies one aligned edit operation.
+type OpKind int
+
+const (
+	// Match is a token present in both transcripts.
+	Match OpKind = iota
+	// Substitute replaces a reference token with a different one.
+	Substitute
+	// Insert is a hypothesis token with no reference counterpart.
+	Insert
+	// Delete is a reference token the hypothesis dropped.
+	Delete
+)
+
+func (k OpKind) String() string {
+	switch k {
+	case Match:
+		return "match"
+	case Substitute:
+		return "sub"
+	case Insert:
+		return "ins"
+	case Delete:
+		return "del"
+	}
+	return "unknown"
+}
+
+// Op is one step of the minimal alignment between two transcripts. Ref is
+// empty for insertions and Hyp for deletions.
+type Op struct {
+	Kind OpKind
+	Ref  string
+	Hyp  string
+}
+
+// Diff aligns hyp against ref and returns the edit operations in reference
+// order, the alignment WER and CER count their edits from. Tokenization is
+// CJK-aware; see Tokenize.
+func Diff(ref, hyp string, opts *Options) []Op {
+	return align(Tokenize(ref, opts), Tokenize(hyp, opts))
+}
+
+// align runs the standard Levenshtein dynamic program over token slices and
+// backtraces one minimal path. Ties prefer diagonal moves (match or
+// substitution), then deletion, so equal-cost alignments come out
+// deterministic.
+func align(ref, hyp []string) []Op {
+	rows, cols := len(ref)+1, len(hyp)+1
+	dist := make([][]int, rows)
+	for i := range dist {
+		dist[i] = make([]int, cols)
+		dist[i][0] = i
+	}
+	for j := 0; j < cols; j++ {
+		dist[0][j] = j
+	}
+	for i := 1; i < rows; i++ {
+		for j := 1; j < cols; j++ {
+			diag := dist[i-1][j-1]
+			if ref[i-1] != hyp[j-1] {
+				diag++
+			}
+			best := diag
+			if d := dist[i-1][j] + 1; d < best {
+				best = d
+			}
+			if d := dist[i][j-1] + 1; d < best {
+				best = d
+			}
+			dist[i][j] = best
+		}
+	}
+
+	var ops []Op
+	i, j := len(ref), len(hyp)
+	for i > 0 || j > 0 {
+		switch {
+		case i > 0 && j > 0 && ref[i-1] == hyp[j-1] && dist[i][j] == dist[i-1][j-1]:
+			ops = append(ops, Op{Kind: Match, Ref: ref[i-1], Hyp: hyp[j-1]})
+			i, j = i-1, j-1
+		case i > 0 && j > 0 && dist[i][j] == dist[i-1][j-1]+1:
+			ops = append(ops, Op{Kind: Substitute, Ref: ref[i-1], Hyp: hyp[j-1]})
+			i, j = i-1, j-1
+		case i > 0 && dist[i][j] == dist[i-1][j]+1:
+			ops = append(ops, Op{Kind: Delete, Ref: ref[i-1]})
+			i--
+		default:
+			ops = append(ops, Op{Kind: Insert, Hyp: hyp[j-1]})
+			j--
+		}
+	}
+	// The backtrace built the alignment tail first.
+	for a, b := 0, len(ops)-1; a < b; a, b = a+1, b-1 {
+		ops[a], ops[b] = ops[b], ops[a]
+	}
+	return ops
+}
@@ -0,0 +1,95 @@
+package voiceworldcompare
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestTokenizeMixedScripts(t *testing.T) {
+	got := Tokenize("今天OK了, yes", nil)
+	want := []string{"今", "天", "ok", "了", "yes"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Tokenize = %v, want %v", got, want)
+	}
+	kept := Tokenize("好, yes", &Options{KeepPunctuation: true})
+	if want := []string{"好", ",", "yes"}; !reflect.DeepEqual(kept, want) {
+		t.Errorf("Tokenize with punctuation = %v, want %v", kept, want)
+	}
+}
+
+func TestWERPinnedValues(t *testing.T) {
+	cases := []struct {
+		name     string
+		ref, hyp string
+		opts     *Options
+		want     float64
+	}{
+		{"identical", "the quick brown fox", "the quick brown fox", nil, 0},
+		{"one substitution", "the quick brown fox", "the quick brown dog", nil, 0.25},
+		{"one deletion", "a b c", "a c", nil, 1.0 / 3},
+		{"one insertion", "a b c", "a b x c", nil, 1.0 / 3},
+		{"han characters", "今天天气很好", "今天天气不错", nil, 2.0 / 6},
+		{"mixed chinese english", "今天 we test 模型", "今天 we tested 模型", nil, 1.0 / 6},
+		{"case and punctuation ignored", "Hello, world!", "hello world", nil, 0},
+		{"case sensitive", "Hello world", "hello world", &Options{CaseSensitive: true}, 0.5},
+		{"empty both", "", "", nil, 0},
+	}
+	for _, tc := range cases {
+		if got := WER(tc.ref, tc.hyp, tc.opts); math.Abs(got-tc.want) > 1e-12 {
+			t.Errorf("%s: WER = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+	if got := WER("", "hello", nil); !math.IsInf(got, 1) {
+		t.Errorf("WER against empty reference = %v, want +Inf", got)
+	}
+}
+
+func TestCERPinnedValues(t *testing.T) {
+	cases := []struct {
+		name     string
+		ref, hyp string
+		want     float64
+	}{
+		{"one substitution", "abcd", "abxd", 0.25},
+		{"han insertion", "今天好", "今天很好", 1.0 / 3},
+		{"mixed chinese english", "voice 世界", "voices 世界", 1.0 / 7},
+		{"identical", "一样 same", "一样 same", 0},
+	}
+	for _, tc := range cases {
+		if got := CER(tc.ref, tc.hyp, nil); math.Abs(got-tc.want) > 1e-12 {
+			t.Errorf("%s: CER = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestDiffAlignsOperations(t *testing.T) {
+	got := Diff("the quick fox", "the fast fox jumps", nil)
+	want := []Op{
+		{Kind: Match, Ref: "the", Hyp: "the"},
+		{Kind: Substitute, Ref: "quick", Hyp: "fast"},
+		{Kind: Match, Ref: "fox", Hyp: "fox"},
+		{Kind: Insert, Hyp: "jumps"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff = %v, want %v", got, want)
+	}
+
+	del := Diff("他 说 好", "他 好", nil)
+	wantDel := []Op{
+		{Kind: Match, Ref: "他", Hyp: "他"},
+		{Kind: Delete, Ref: "说"},
+		{Kind: Match, Ref: "好", Hyp: "好"},
+	}
+	if !reflect.DeepEqual(del, wantDel) {
+		t.Errorf("Diff = %v, want %v", del, wantDel)
+	}
+}
+
+func TestOpKindString(t *testing.T) {
+	for kind, want := range map[OpKind]string{Match: "match", Substitute: "sub", Insert: "ins", Delete: "del"} {
+		if got := kind.String(); got != want {
+			t.Errorf("OpKind(%d).String() = %q, want %q", kind, got, want)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+// Package voiceworldexport renders recognition results into the artifacts
+// downstream consumers want: plain text with speaker labels, machine-readable
+// JSON, CSV for spreadsheets, and SRT/VTT subtitles. All writers share one
+// ExportOptions struct so formatting behaves the same across formats.
+package voiceworldexport
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	voiceworld "github.com/voiceworld/voiceworld-go-sdk"
+)
+
+// Timestamp formats for ExportOptions.TimestampFormat.
+const (
+	// TimestampClock renders "00:01:02.345" (the default).
+	TimestampClock = "clock"
+	// TimestampSeconds renders fractional seconds, "62.345".
+	TimestampSeconds = "seconds"
+	// TimestampMillis renders integer milliseconds, "62345".
+	TimestampMillis = "ms"
+)
+
+// ExportOptions controls formatting across all writers. The zero value
+// omits timestamps and speakers and uses TimestampClock where timestamps
+// are structural to the format (CSV, SRT, VTT).
+type ExportOptions struct {
+	// IncludeTimestamps prefixes TXT lines with the segment time span and
+	// keeps the timing fields in JSON output.
+	IncludeTimestamps bool
+	// IncludeSpeakers labels lines/rows/cues with the segment speaker.
+	IncludeSpeakers bool
+	// TimestampFormat is one of TimestampClock, TimestampSeconds or
+	// TimestampMillis; empty means TimestampClock. SRT and VTT ignore it,
+	// their formats prescribe the timestamp shape.
+	TimestampFormat string
+}
+
+// segments returns the result's segments, or the whole transcript as one
+// unstamped segment when the server returned none.
+func segments(result *voiceworld.RecognitionResult) []voiceworld.Segment {
+	if len(result.Segments) > 0 {
+		return result.Segments
+	}
+	if result.Result == "" {
+		return nil
+	}
+	return []voiceworld.Segment{{Text: result.Result, EndTime: result.Duration}}
+}
+
+// formatTimestamp renders milliseconds in the requested format.
+func formatTimestamp(ms int64, format string) (string, error) {
+	switch format {
+	case "", TimestampClock:
+		d := time.Duration(ms) * time.Millisecond
+		return fmt.Sprintf("%02d:%02d:%02d.%03d",
+			int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60, ms%1000), nil
+	case TimestampSeconds:
+		return fmt.Sprintf("%.3f", float64(ms)/1000), nil
+	case TimestampMillis:
+		return fmt.Sprintf("%d", ms), nil
+	default:
+		return "", fmt.Errorf("voiceworldexport: unknown timestamp format %q", format)
+	}
+}
+
+// WriteTXT writes one line per segment, optionally prefixed with the time
+// span and speaker label.
+func WriteTXT(w io.Writer, result *voiceworld.RecognitionResult, opts ExportOptions) error {
+	for _, seg := range segments(result) {
+		var parts []string
+		if opts.IncludeTimestamps {
+			begin, err := formatTimestamp(seg.BeginTime.Milliseconds(), opts.TimestampFormat)
+			if err != nil {
+				return err
+			}
+			end, err := formatTimestamp(seg.EndTime.Milliseconds(), opts.TimestampFormat)
+			if err != nil {
+				return err
+			}
+			parts = append(parts, fmt.Sprintf("[%s - %s]", begin, end))
+		}
+		if opts.IncludeSpeakers && seg.SpeakerID != "" {
+			parts = append(parts, seg.SpeakerID+":")
+		}
+		parts = append(parts, seg.Text)
+		if _, err := fmt.Fprintln(w, strings.Join(parts, " ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// jsonSegment is the stable wire shape of one segment in WriteJSON output.
+type jsonSegment struct {
+	Text      string `json:"text"`
+	BeginTime *int64 `json:"begin_time,omitempty"`
+	EndTime   *int64 `json:"end_time,omitempty"`
+	Speaker   string `json:"speaker,omitempty"`
+}
+
+// WriteJSON writes an indented JSON document with the transcript and its
+// segments. Timing fields are included only with IncludeTimestamps, speaker
+// labels only with IncludeSpeakers.
+func WriteJSON(w io.Writer, result *voiceworld.RecognitionResult, opts ExportOptions) error {
+	doc := struct {
+		TaskID   string        `json:"task_id,omitempty"`
+		Result   string        `json:"result"`
+		Duration int64         `json:"duration,omitempty"`
+		Segments []jsonSegment `json:"segments,omitempty"`
+	}{TaskID: result.TaskID, Result: result.Result, Duration: result.Duration.Milliseconds()}
+	for _, seg := range segments(result) {
+		js := jsonSegment{Text: seg.Text}
+		if opts.IncludeTimestamps {
+			begin, end := seg.BeginTime.Milliseconds(), seg.EndTime.Milliseconds()
+			js.BeginTime, js.EndTime = &begin, &end
+		}
+		if opts.IncludeSpeakers {
+			js.Speaker = seg.SpeakerID
+		}
+		doc.Segments = append(doc.Segments, js)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(&doc)
+}
+
+// WriteCSV writes one row per segment. Columns are start, end and text, with
+// a speaker column between end and text when IncludeSpeakers is set.
+func WriteCSV(w io.Writer, result *voiceworld.RecognitionResult, opts ExportOptions) error {
+	cw := csv.NewWriter(w)
+	header := []string{"start", "end"}
+	if opts.IncludeSpeakers {
+		header = append(header, "speaker")
+	}
+	header = append(header, "text")
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, seg := range segments(result) {
+		begin, err := formatTimestamp(seg.BeginTime.Milliseconds(), opts.TimestampFormat)
+		if err != nil {
+			return err
+		}
+		end, err := formatTimestamp(seg.EndTime.Milliseconds(), opts.TimestampFormat)
+		if err != nil {
+			return err
+		}
+		row := []string{begin, end}
+		if opts.IncludeSpeakers {
+			row = append(row, seg.SpeakerID)
+		}
+		row = append(row, seg.Text)
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// subtitleTimestamp renders milliseconds in subtitle form; SRT separates the
+// millisecond part with a comma, VTT with a dot.
+func subtitleTimestamp(ms int64, sep string) string {
+	d := time.Duration(ms) * time.Millisecond
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d",
+		int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60, sep, ms%1000)
+}
+
+// cueText is the segment text, with the speaker label prepended when asked.
+func cueText(seg voiceworld.Segment, opts ExportOptions) string {
+	if opts.IncludeSpeakers && seg.SpeakerID != "" {
+		return seg.SpeakerID + ": " + seg.Text
+	}
+	return seg.Text
+}
+
+// WriteSRT writes SubRip subtitles, one numbered cue per segment.
+func WriteSRT(w io.Writer, result *voiceworld.RecognitionResult, opts ExportOptions) error {
+	for i, seg := range segments(result) {
+		_, err := fmt.Fprintf(w, "%d\n%s --> %s\n%s\n\n", i+1,
+			subtitleTimestamp(seg.BeginTime.Milliseconds(), ","),
+			subtitleTimestamp(seg.EndTime.Milliseconds(), ","),
+			cueText(seg, opts))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteVTT writes WebVTT subtitles, one cue per segment.
+func WriteVTT(w io.Writer, result *voiceworld.RecognitionResult, opts ExportOptions) error {
+	if _, err := fmt.Fprint(w, "WEBVTT\n\n"); err != nil {
+		return err
+	}
+	for _, seg := range segments(result) {
+		_, err := fmt.Fprintf(w, "%s --> %s\n%s\n\n",
+			subtitleTimestamp(seg.BeginTime.Milliseconds(), "."),
+			subtitleTimestamp(seg.EndTime.Milliseconds(), "."),
+			cueText(seg, opts))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
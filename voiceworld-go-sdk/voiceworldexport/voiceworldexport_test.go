@@ -0,0 +1,107 @@
+package voiceworldexport
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	voiceworld "github.com/voiceworld/voiceworld-go-sdk"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+// exportFixture is a small diarized transcript exercising ASCII and CJK
+// text, an embedded comma (CSV quoting) and a span past the minute mark.
+func exportFixture() *voiceworld.RecognitionResult {
+	return &voiceworld.RecognitionResult{
+		TaskID:   "task-export",
+		Result:   "大家好，欢迎参加会议。 Thanks, happy to be here. 我们开始吧。",
+		Duration: ms(62345),
+		Segments: []voiceworld.Segment{
+			{Text: "大家好，欢迎参加会议。", BeginTime: ms(0), EndTime: ms(4200), SpeakerID: "speaker_1"},
+			{Text: "Thanks, happy to be here.", BeginTime: ms(4200), EndTime: ms(7800), SpeakerID: "speaker_2"},
+			{Text: "我们开始吧。", BeginTime: ms(7800), EndTime: ms(62345), SpeakerID: "speaker_1"},
+		},
+	}
+}
+
+func TestGoldenOutputs(t *testing.T) {
+	full := ExportOptions{IncludeTimestamps: true, IncludeSpeakers: true}
+	for _, tc := range []struct {
+		golden string
+		opts   ExportOptions
+		write  func(io.Writer, *voiceworld.RecognitionResult, ExportOptions) error
+	}{
+		{"plain.txt", ExportOptions{}, WriteTXT},
+		{"full.txt", full, WriteTXT},
+		{"full.json", full, WriteJSON},
+		{"bare.json", ExportOptions{}, WriteJSON},
+		{"full.csv", ExportOptions{IncludeSpeakers: true, TimestampFormat: TimestampSeconds}, WriteCSV},
+		{"full.srt", full, WriteSRT},
+		{"bare.vtt", ExportOptions{}, WriteVTT},
+	} {
+		t.Run(tc.golden, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tc.write(&buf, exportFixture(), tc.opts); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			path := filepath.Join("testdata", tc.golden)
+			if *update {
+				if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+					t.Fatalf("update golden: %v", err)
+				}
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("read golden: %v", err)
+			}
+			if got := buf.String(); got != string(want) {
+				t.Errorf("output differs from %s:\ngot:\n%s\nwant:\n%s", path, got, want)
+			}
+		})
+	}
+}
+
+func TestTimestampFormats(t *testing.T) {
+	for _, tc := range []struct {
+		format string
+		want   string
+	}{
+		{"", "01:02:03.456"},
+		{TimestampClock, "01:02:03.456"},
+		{TimestampSeconds, "3723.456"},
+		{TimestampMillis, "3723456"},
+	} {
+		got, err := formatTimestamp(3723456, tc.format)
+		if err != nil {
+			t.Fatalf("formatTimestamp(%q): %v", tc.format, err)
+		}
+		if got != tc.want {
+			t.Errorf("formatTimestamp(%q) = %q, want %q", tc.format, got, tc.want)
+		}
+	}
+	if _, err := formatTimestamp(0, "sundial"); err == nil {
+		t.Error("unknown format accepted")
+	}
+}
+
+func TestSegmentlessResultFallsBackToTranscript(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteTXT(&buf, &voiceworld.RecognitionResult{Result: "只有整段文本"}, ExportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(buf.String()) != "只有整段文本" {
+		t.Errorf("output = %q", buf.String())
+	}
+}
+
+// ms is shorthand for building Timestamp fixtures.
+func ms(n int64) voiceworld.Timestamp {
+	return voiceworld.NewTimestamp(time.Duration(n) * time.Millisecond)
+}
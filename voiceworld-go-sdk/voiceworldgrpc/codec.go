@@ -0,0 +1,68 @@
+package voiceworldgrpc
+
+import (
+	"fmt"
+
+	"github.com/voiceworld/voiceworld-go-sdk/internal/asrpb"
+)
+
+// The service's messages are encoded with the hand-maintained wire code in
+// internal/asrpb (see that package for why there is no generated code).
+// wireCodec bridges them into grpc-go, which only needs Marshal/Unmarshal
+// pairs per message type.
+
+// recognizeRequestMessage adapts asrpb.RecognizeRequest to the codec.
+type recognizeRequestMessage asrpb.RecognizeRequest
+
+// envelopeMessage adapts asrpb.Envelope to the codec.
+type envelopeMessage asrpb.Envelope
+
+// audioChunkMessage is one streamed audio frame: a RecognizeRequest whose
+// Audio field carries the frame (other fields are set on the first frame
+// only).
+type audioChunkMessage asrpb.RecognizeRequest
+
+type wireCodec struct{}
+
+func (wireCodec) Name() string { return "voiceworld-wire" }
+
+func (wireCodec) Marshal(v interface{}) ([]byte, error) {
+	switch m := v.(type) {
+	case *recognizeRequestMessage:
+		return asrpb.AppendRecognizeRequest(nil, (*asrpb.RecognizeRequest)(m)), nil
+	case *audioChunkMessage:
+		return asrpb.AppendRecognizeRequest(nil, (*asrpb.RecognizeRequest)(m)), nil
+	case *envelopeMessage:
+		return asrpb.AppendEnvelope(nil, (*asrpb.Envelope)(m)), nil
+	default:
+		return nil, fmt.Errorf("voiceworldgrpc: codec cannot marshal %T", v)
+	}
+}
+
+func (wireCodec) Unmarshal(data []byte, v interface{}) error {
+	switch m := v.(type) {
+	case *recognizeRequestMessage:
+		decoded, err := asrpb.UnmarshalRecognizeRequest(data)
+		if err != nil {
+			return err
+		}
+		*m = recognizeRequestMessage(*decoded)
+		return nil
+	case *audioChunkMessage:
+		decoded, err := asrpb.UnmarshalRecognizeRequest(data)
+		if err != nil {
+			return err
+		}
+		*m = audioChunkMessage(*decoded)
+		return nil
+	case *envelopeMessage:
+		decoded, err := asrpb.UnmarshalEnvelope(data)
+		if err != nil {
+			return err
+		}
+		*m = envelopeMessage(*decoded)
+		return nil
+	default:
+		return fmt.Errorf("voiceworldgrpc: codec cannot unmarshal into %T", v)
+	}
+}
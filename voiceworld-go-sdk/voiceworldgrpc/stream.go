@@ -0,0 +1,131 @@
+package voiceworldgrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"google.golang.org/grpc"
+
+	voiceworld "github.com/voiceworld/voiceworld-go-sdk"
+	"github.com/voiceworld/voiceworld-go-sdk/internal/asrpb"
+)
+
+// streamingDesc describes the bidirectional RPC.
+var streamingDesc = &grpc.StreamDesc{
+	StreamName:    "StreamingRecognize",
+	ClientStreams: true,
+	ServerStreams: true,
+}
+
+// Stream is one live recognition session. Audio goes in through Send,
+// results come out of the Results channel, mirroring the Send/Results
+// surface of the WebSocket API. After the Results channel closes, Err
+// reports why.
+type Stream struct {
+	grpcStream grpc.ClientStream
+	params     *voiceworld.RecognitionConfig
+
+	results chan *voiceworld.RecognitionResult
+
+	mu        sync.Mutex
+	sentFirst bool
+	err       error
+}
+
+// StreamingRecognize opens a bidirectional recognition session. The first
+// Send carries the session parameters; the context's deadline and
+// cancellation govern the whole session.
+func (c *Client) StreamingRecognize(ctx context.Context, params *voiceworld.RecognitionConfig) (*Stream, error) {
+	if params == nil {
+		params = &voiceworld.RecognitionConfig{}
+	}
+	gs, err := c.conn.NewStream(c.authContext(ctx, methodStreamingRecognize),
+		streamingDesc, methodStreamingRecognize, grpc.ForceCodec(wireCodec{}))
+	if err != nil {
+		return nil, fmt.Errorf("voiceworldgrpc: open stream: %w", err)
+	}
+	s := &Stream{
+		grpcStream: gs,
+		params:     params,
+		results:    make(chan *voiceworld.RecognitionResult, 16),
+	}
+	go s.receive()
+	return s, nil
+}
+
+// Send transmits one frame of audio. The session parameters ride along on
+// the first frame.
+func (s *Stream) Send(audio []byte) error {
+	s.mu.Lock()
+	if s.err != nil {
+		err := s.err
+		s.mu.Unlock()
+		return err
+	}
+	msg := &audioChunkMessage{Audio: audio}
+	if !s.sentFirst {
+		msg.Format = s.params.Format
+		msg.SampleRate = int32(s.params.SampleRate)
+		msg.RequestID = s.params.RequestID
+		s.sentFirst = true
+	}
+	s.mu.Unlock()
+
+	if err := s.grpcStream.SendMsg(msg); err != nil {
+		return fmt.Errorf("voiceworldgrpc: send frame: %w", err)
+	}
+	return nil
+}
+
+// CloseSend signals end of audio; results keep arriving until the server
+// closes its side.
+func (s *Stream) CloseSend() error {
+	return s.grpcStream.CloseSend()
+}
+
+// Results delivers transcription results as the server produces them. The
+// channel closes when the session ends; check Err afterwards.
+func (s *Stream) Results() <-chan *voiceworld.RecognitionResult {
+	return s.results
+}
+
+// Err reports why the session ended. It is nil after a clean server-side
+// close.
+func (s *Stream) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if errors.Is(s.err, io.EOF) {
+		return nil
+	}
+	return s.err
+}
+
+// receive pumps server messages into the results channel until the stream
+// ends.
+func (s *Stream) receive() {
+	defer close(s.results)
+	for {
+		var env envelopeMessage
+		if err := s.grpcStream.RecvMsg(&env); err != nil {
+			s.setErr(err)
+			return
+		}
+		result, err := envelopeToResult((*asrpb.Envelope)(&env))
+		if err != nil {
+			s.setErr(err)
+			return
+		}
+		s.results <- result
+	}
+}
+
+func (s *Stream) setErr(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err == nil {
+		s.err = err
+	}
+}
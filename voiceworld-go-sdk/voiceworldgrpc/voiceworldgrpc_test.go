@@ -0,0 +1,242 @@
+package voiceworldgrpc
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+
+	voiceworld "github.com/voiceworld/voiceworld-go-sdk"
+	"github.com/voiceworld/voiceworld-go-sdk/internal/asrpb"
+)
+
+const (
+	testAppKey    = "grpc-app-key"
+	testSecretKey = "grpc-secret"
+)
+
+// fakeRecognizer is the in-process service backing the tests.
+type fakeRecognizer struct {
+	lastAudio []byte
+	lastMeta  metadata.MD
+}
+
+// verifyAuth checks the signed metadata the client is expected to attach.
+func verifyAuth(ctx context.Context, fullMethod string) error {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return errors.New("no metadata")
+	}
+	get := func(k string) string {
+		if v := md.Get(k); len(v) == 1 {
+			return v[0]
+		}
+		return ""
+	}
+	canonical := fmt.Sprintf("%s\nPOST\n%s\n%s\n%s",
+		get("x-vw-appkey"), fullMethod, get("x-vw-timestamp"), get("x-vw-nonce"))
+	mac := hmac.New(sha256.New, []byte(testSecretKey))
+	mac.Write([]byte(canonical))
+	if get("x-vw-signature") != hex.EncodeToString(mac.Sum(nil)) {
+		return errors.New("bad signature")
+	}
+	return nil
+}
+
+func (f *fakeRecognizer) recognize(ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	if err := verifyAuth(ctx, methodRecognize); err != nil {
+		return nil, err
+	}
+	var req recognizeRequestMessage
+	if err := dec(&req); err != nil {
+		return nil, err
+	}
+	f.lastAudio = req.Audio
+	f.lastMeta, _ = metadata.FromIncomingContext(ctx)
+	return &envelopeMessage{
+		Message: "ok",
+		Result: &asrpb.Result{
+			TaskID:   "grpc-task",
+			Text:     fmt.Sprintf("heard %d bytes of %s", len(req.Audio), req.Format),
+			Duration: 1500,
+		},
+	}, nil
+}
+
+func (f *fakeRecognizer) streamingRecognize(stream grpc.ServerStream) error {
+	if err := verifyAuth(stream.Context(), methodStreamingRecognize); err != nil {
+		return err
+	}
+	total := 0
+	frames := 0
+	for {
+		var msg audioChunkMessage
+		if err := stream.RecvMsg(&msg); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return err
+		}
+		frames++
+		total += len(msg.Audio)
+		// A partial result per frame, like the real service.
+		if err := stream.SendMsg(&envelopeMessage{Result: &asrpb.Result{
+			Text: fmt.Sprintf("partial after %d frames", frames),
+		}}); err != nil {
+			return err
+		}
+	}
+	return stream.SendMsg(&envelopeMessage{Result: &asrpb.Result{
+		TaskID: "stream-task",
+		Text:   fmt.Sprintf("final: %d bytes", total),
+	}})
+}
+
+// startFakeService runs the recognizer on a bufconn and returns a connected
+// Client.
+func startFakeService(t *testing.T) (*Client, *fakeRecognizer) {
+	t.Helper()
+	fake := &fakeRecognizer{}
+	desc := &grpc.ServiceDesc{
+		ServiceName: "voiceworld.asr.v1.Recognizer",
+		HandlerType: (*interface{})(nil),
+		Methods: []grpc.MethodDesc{{
+			MethodName: "Recognize",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+				return fake.recognize(ctx, dec)
+			},
+		}},
+		Streams: []grpc.StreamDesc{{
+			StreamName:    "StreamingRecognize",
+			ClientStreams: true,
+			ServerStreams: true,
+			Handler: func(srv interface{}, stream grpc.ServerStream) error {
+				return fake.streamingRecognize(stream)
+			},
+		}},
+	}
+	server := grpc.NewServer(grpc.ForceServerCodec(wireCodec{}))
+	server.RegisterService(desc, fake)
+
+	lis := bufconn.Listen(1 << 20)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	client, err := Dial(context.Background(), Config{
+		Address:   "bufconn",
+		AppKey:    testAppKey,
+		SecretKey: testSecretKey,
+		Insecure:  true,
+		DialOptions: []grpc.DialOption{
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return lis.DialContext(ctx)
+			}),
+		},
+	})
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client, fake
+}
+
+func TestRecognize(t *testing.T) {
+	client, fake := startFakeService(t)
+	audio := []byte{1, 2, 3, 4, 5}
+
+	result, err := client.Recognize(context.Background(), audio,
+		&voiceworld.RecognitionConfig{Format: "pcm", SampleRate: 16000})
+	if err != nil {
+		t.Fatalf("Recognize: %v", err)
+	}
+	if result.TaskID != "grpc-task" {
+		t.Errorf("TaskID = %q", result.TaskID)
+	}
+	if result.Result != "heard 5 bytes of pcm" {
+		t.Errorf("Result = %q", result.Result)
+	}
+	if string(fake.lastAudio) != string(audio) {
+		t.Errorf("server saw audio % x", fake.lastAudio)
+	}
+}
+
+func TestRecognizeDeadline(t *testing.T) {
+	client, _ := startFakeService(t)
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	if _, err := client.Recognize(ctx, []byte{1}, nil); err == nil {
+		t.Fatal("expected deadline error")
+	}
+}
+
+func TestStreamingRecognize(t *testing.T) {
+	client, _ := startFakeService(t)
+	stream, err := client.StreamingRecognize(context.Background(),
+		&voiceworld.RecognitionConfig{Format: "pcm", SampleRate: 16000})
+	if err != nil {
+		t.Fatalf("StreamingRecognize: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := stream.Send(make([]byte, 640)); err != nil {
+			t.Fatalf("Send %d: %v", i, err)
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatalf("CloseSend: %v", err)
+	}
+
+	var texts []string
+	for result := range stream.Results() {
+		texts = append(texts, result.Result)
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatalf("stream ended with error: %v", err)
+	}
+	if len(texts) != 4 { // 3 partials + final
+		t.Fatalf("got %d results: %v", len(texts), texts)
+	}
+	if texts[3] != "final: 1920 bytes" {
+		t.Errorf("final result = %q", texts[3])
+	}
+}
+
+// TestHighLevelClientRoutesThroughGRPC wires the gRPC client into the REST
+// Client and checks recognition of a local file goes over gRPC.
+func TestHighLevelClientRoutesThroughGRPC(t *testing.T) {
+	grpcClient, fake := startFakeService(t)
+	client, err := voiceworld.NewClient("ak", "sk", &voiceworld.ClientConfig{
+		BaseURL:    "http://unused.invalid",
+		Recognizer: grpcClient,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "a.pcm")
+	if err := os.WriteFile(path, []byte{9, 9, 9}, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	result, err := client.ASR(path, nil)
+	if err != nil {
+		t.Fatalf("ASR via gRPC: %v", err)
+	}
+	if result.TaskID != "grpc-task" {
+		t.Errorf("TaskID = %q", result.TaskID)
+	}
+	if len(fake.lastAudio) != 3 {
+		t.Errorf("gRPC backend saw %d bytes", len(fake.lastAudio))
+	}
+}
@@ -0,0 +1,208 @@
+// Package voiceworldreplay records VoiceWorld API traffic to cassette files
+// and replays it later, so integration-shaped tests can run without the real
+// backend.
+//
+// In record mode the transport forwards requests to the network and appends
+// each exchange (with credentials scrubbed) to the cassette; call Save when
+// done. In replay mode it serves responses by matching the request method,
+// path and query against the cassette and fails loudly on anything
+// unmatched:
+//
+//	rt, _ := voiceworldreplay.NewTransport(voiceworldreplay.ModeReplay, "testdata/flow.cassette")
+//	client, _ := voiceworld.NewClient(key, secret, &voiceworld.ClientConfig{
+//		HTTPClient: &http.Client{Transport: rt},
+//	})
+package voiceworldreplay
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sync"
+)
+
+// Mode selects whether a Transport talks to the network or to a cassette.
+type Mode int
+
+const (
+	// ModeRecord forwards requests and captures the exchanges.
+	ModeRecord Mode = iota
+	// ModeReplay serves responses from the cassette and never dials out.
+	ModeReplay
+)
+
+// Interaction is one recorded request/response exchange.
+type Interaction struct {
+	Method string              `json:"method"`
+	Path   string              `json:"path"`
+	Query  url.Values          `json:"query,omitempty"`
+	Status int                 `json:"status"`
+	Header map[string][]string `json:"header,omitempty"`
+	Body   string              `json:"body"`
+}
+
+// Transport is an http.RoundTripper in either record or replay mode. It is
+// safe for concurrent use.
+type Transport struct {
+	mode     Mode
+	cassette string
+	// Inner is the transport used to reach the network in record mode.
+	// Nil means http.DefaultTransport.
+	Inner http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool // replay mode: which interactions are consumed
+}
+
+// NewTransport opens a transport on the given cassette file. In replay mode
+// the cassette must already exist.
+func NewTransport(mode Mode, cassette string) (*Transport, error) {
+	t := &Transport{mode: mode, cassette: cassette}
+	if mode == ModeReplay {
+		raw, err := os.ReadFile(cassette)
+		if err != nil {
+			return nil, fmt.Errorf("voiceworldreplay: open cassette: %w", err)
+		}
+		if err := json.Unmarshal(raw, &t.interactions); err != nil {
+			return nil, fmt.Errorf("voiceworldreplay: parse cassette %s: %w", cassette, err)
+		}
+		t.used = make([]bool, len(t.interactions))
+	}
+	return t, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.mode == ModeReplay {
+		return t.replay(req)
+	}
+	return t.record(req)
+}
+
+// record forwards the request and captures the scrubbed exchange.
+func (t *Transport) record(req *http.Request) (*http.Response, error) {
+	inner := t.Inner
+	if inner == nil {
+		inner = http.DefaultTransport
+	}
+	resp, err := inner.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("voiceworldreplay: read response for recording: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	interaction := Interaction{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  req.URL.Query(),
+		Status: resp.StatusCode,
+		Header: scrubHeader(resp.Header),
+		Body:   scrubBody(string(body)),
+	}
+	t.mu.Lock()
+	t.interactions = append(t.interactions, interaction)
+	t.mu.Unlock()
+	return resp, nil
+}
+
+// replay serves the first unused interaction matching the request.
+func (t *Transport) replay(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i, in := range t.interactions {
+		if t.used[i] || in.Method != req.Method || in.Path != req.URL.Path {
+			continue
+		}
+		if !queryMatches(in.Query, req.URL.Query()) {
+			continue
+		}
+		t.used[i] = true
+		return &http.Response{
+			StatusCode: in.Status,
+			Status:     http.StatusText(in.Status),
+			Header:     http.Header(in.Header),
+			Body:       io.NopCloser(bytes.NewReader([]byte(in.Body))),
+			Request:    req,
+		}, nil
+	}
+	return nil, fmt.Errorf("voiceworldreplay: no recorded interaction matches %s %s?%s (cassette %s has %d interactions)",
+		req.Method, req.URL.Path, req.URL.RawQuery, t.cassette, len(t.interactions))
+}
+
+// Save writes the recorded interactions to the cassette file. It is a no-op
+// in replay mode.
+func (t *Transport) Save() error {
+	if t.mode != ModeRecord {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	raw, err := json.MarshalIndent(t.interactions, "", "  ")
+	if err != nil {
+		return fmt.Errorf("voiceworldreplay: encode cassette: %w", err)
+	}
+	if err := os.WriteFile(t.cassette, raw, 0o644); err != nil {
+		return fmt.Errorf("voiceworldreplay: write cassette: %w", err)
+	}
+	return nil
+}
+
+// queryMatches compares the significant query parameters; signature-bearing
+// parameters are ignored so replay is stable across signing keys.
+func queryMatches(recorded, got url.Values) bool {
+	ignored := map[string]bool{"signature": true, "Signature": true}
+	for _, side := range []struct{ a, b url.Values }{{recorded, got}, {got, recorded}} {
+		for k, vs := range side.a {
+			if ignored[k] {
+				continue
+			}
+			other, ok := side.b[k]
+			if !ok || len(other) != len(vs) {
+				return false
+			}
+			for i := range vs {
+				if vs[i] != other[i] {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// scrubbedHeaders are never written to cassettes.
+var scrubbedHeaders = []string{"X-VW-Signature", "X-Signature", "Authorization", "X-Vw-Signature"}
+
+// scrubHeader copies h with credential-bearing headers redacted.
+func scrubHeader(h http.Header) map[string][]string {
+	out := make(map[string][]string, len(h))
+	for k, vs := range h {
+		out[k] = vs
+	}
+	for _, k := range scrubbedHeaders {
+		if _, ok := out[k]; ok {
+			out[k] = []string{"[SCRUBBED]"}
+		}
+	}
+	return out
+}
+
+// secretFields matches JSON fields whose values must not land on disk: the
+// app secret and STS credential material.
+var secretFields = regexp.MustCompile(`"(access_key_secret|security_token|secret_key|app_secret)"\s*:\s*"[^"]*"`)
+
+// scrubBody redacts credential values inside a JSON body.
+func scrubBody(body string) string {
+	return secretFields.ReplaceAllString(body, `"$1":"[SCRUBBED]"`)
+}
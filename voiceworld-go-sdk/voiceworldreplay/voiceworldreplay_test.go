@@ -0,0 +1,155 @@
+package voiceworldreplay
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	voiceworld "github.com/voiceworld/voiceworld-go-sdk"
+)
+
+// deadTransport proves replay never touches the network.
+type deadTransport struct{}
+
+func (deadTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("network disabled")
+}
+
+func startBackend(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/get_oss_token", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"code":0,"message":"ok","data":{
+			"access_key_id":"AKID","access_key_secret":"VERY-SECRET","security_token":"STS-SECRET",
+			"expiration":"2099-01-01T00:00:00Z","endpoint":"oss.example.com","bucket":"b"}}`)
+	})
+	mux.HandleFunc("/asr", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"code":0,"message":"ok","data":{"task_id":"t1","result":"recorded text"}}`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newReplayClient(t *testing.T, cassette string) *voiceworld.Client {
+	t.Helper()
+	rt, err := NewTransport(ModeReplay, cassette)
+	if err != nil {
+		t.Fatalf("NewTransport(replay): %v", err)
+	}
+	rt.Inner = deadTransport{}
+	client, err := voiceworld.NewClient("ak", "sk", &voiceworld.ClientConfig{
+		BaseURL:    "http://recorded.invalid",
+		HTTPClient: &http.Client{Transport: rt},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestRecordThenReplay(t *testing.T) {
+	backend := startBackend(t)
+	cassette := filepath.Join(t.TempDir(), "flow.cassette")
+
+	// Record a token fetch and a recognition.
+	rec, err := NewTransport(ModeRecord, cassette)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := voiceworld.NewClient("ak", "sk", &voiceworld.ClientConfig{
+		BaseURL:    backend.URL,
+		HTTPClient: &http.Client{Transport: rec},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetOSSToken(); err != nil {
+		t.Fatalf("GetOSSToken (record): %v", err)
+	}
+	if _, err := client.RecognizeURL("https://example.com/a.wav", nil); err != nil {
+		t.Fatalf("RecognizeURL (record): %v", err)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	// Replay with the network disabled.
+	replayed := newReplayClient(t, cassette)
+	token, err := replayed.GetOSSToken()
+	if err != nil {
+		t.Fatalf("GetOSSToken (replay): %v", err)
+	}
+	if token.Endpoint != "oss.example.com" {
+		t.Errorf("replayed endpoint = %q", token.Endpoint)
+	}
+	result, err := replayed.RecognizeURL("https://example.com/a.wav", nil)
+	if err != nil {
+		t.Fatalf("RecognizeURL (replay): %v", err)
+	}
+	if result.Result != "recorded text" {
+		t.Errorf("replayed result = %q", result.Result)
+	}
+}
+
+func TestCassetteIsScrubbed(t *testing.T) {
+	backend := startBackend(t)
+	cassette := filepath.Join(t.TempDir(), "scrub.cassette")
+
+	rec, err := NewTransport(ModeRecord, cassette)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := voiceworld.NewClient("ak", "app-secret-value", &voiceworld.ClientConfig{
+		BaseURL:    backend.URL,
+		HTTPClient: &http.Client{Transport: rec},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetOSSToken(); err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := os.ReadFile(cassette)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"VERY-SECRET", "STS-SECRET", "app-secret-value"} {
+		if strings.Contains(string(raw), secret) {
+			t.Errorf("cassette contains secret %q", secret)
+		}
+	}
+	if !strings.Contains(string(raw), "[SCRUBBED]") {
+		t.Error("cassette has no scrubbed markers; scrubbing not applied?")
+	}
+}
+
+func TestReplayFailsLoudlyOnUnmatchedRequest(t *testing.T) {
+	cassette := filepath.Join(t.TempDir(), "empty.cassette")
+	if err := os.WriteFile(cassette, []byte("[]"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	client := newReplayClient(t, cassette)
+	_, err := client.GetOSSToken()
+	if err == nil {
+		t.Fatal("expected unmatched-request error")
+	}
+	if !strings.Contains(err.Error(), "no recorded interaction") {
+		t.Errorf("err = %v, want the unmatched diagnostic", err)
+	}
+}
+
+func TestReplayRequiresExistingCassette(t *testing.T) {
+	if _, err := NewTransport(ModeReplay, filepath.Join(t.TempDir(), "missing.cassette")); err == nil {
+		t.Error("expected error for missing cassette")
+	}
+}
@@ -0,0 +1,193 @@
+// Package voiceworldtest provides an in-process fake of the VoiceWorld
+// platform for testing code that uses the SDK, without network access or
+// credentials.
+//
+// A typical test wires a client against the fake, runs the code under test,
+// and then asserts on what reached the backend:
+//
+//	h := voiceworldtest.NewTestClient(t)
+//	url, err := h.Client.UploadFile("testdata/a.wav", "req-1/a.wav")
+//	...
+//	got, ok := h.Storage.Object("req-1/a.wav")
+//	if !ok || !bytes.Equal(got, wantBytes) {
+//	    t.Errorf("uploaded object mismatch")
+//	}
+package voiceworldtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	voiceworld "github.com/voiceworld/voiceworld-go-sdk"
+)
+
+// Request is one API call the fake server received.
+type Request struct {
+	Method string
+	Path   string
+	Query  map[string][]string
+	Header http.Header
+	Body   []byte
+}
+
+// Server is a programmable fake of the VoiceWorld HTTP API. It implements
+// /asr, /get_oss_token and /preprocess_audio and records every request it
+// receives. The zero value is not usable; create one with NewServer.
+type Server struct {
+	httpServer *httptest.Server
+
+	mu            sync.Mutex
+	requests      []Request
+	asrResult     voiceworld.RecognitionResult
+	preprocessURL string
+	errors        map[string]apiError // path -> forced failure
+}
+
+type apiError struct {
+	code    int
+	message string
+}
+
+// NewServer starts a fake API server with sensible default responses. It is
+// shut down automatically when the test finishes.
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+	s := &Server{
+		asrResult:     voiceworld.RecognitionResult{TaskID: "task-fake", Result: "fake transcript"},
+		preprocessURL: "https://fake.example.com/preprocessed.wav",
+		errors:        make(map[string]apiError),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/asr", s.handle(func() interface{} { return s.asrResult }))
+	mux.HandleFunc("/get_oss_token", s.handle(func() interface{} {
+		return map[string]string{
+			"access_key_id":     "fake-id",
+			"access_key_secret": "fake-secret",
+			"security_token":    "fake-token",
+			"expiration":        "2099-01-01T00:00:00Z",
+			"endpoint":          "oss.fake.example.com",
+			"bucket":            "fake-bucket",
+		}
+	}))
+	mux.HandleFunc("/preprocess_audio", s.handle(func() interface{} {
+		return map[string]string{"url": s.preprocessURL}
+	}))
+	s.httpServer = httptest.NewServer(mux)
+	t.Cleanup(s.httpServer.Close)
+	return s
+}
+
+// URL is the base URL of the fake server, suitable for ClientConfig.BaseURL.
+func (s *Server) URL() string { return s.httpServer.URL }
+
+// Requests returns a copy of every request received so far, in order.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// RequestsTo returns the received requests whose path matches exactly.
+func (s *Server) RequestsTo(path string) []Request {
+	var out []Request
+	for _, r := range s.Requests() {
+		if r.Path == path {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// SetASRResult programs the response of the /asr endpoint.
+func (s *Server) SetASRResult(result voiceworld.RecognitionResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.asrResult = result
+}
+
+// SetPreprocessURL programs the processed-audio URL returned by
+// /preprocess_audio.
+func (s *Server) SetPreprocessURL(url string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.preprocessURL = url
+}
+
+// FailWith makes the given path (e.g. "/asr") return an error envelope with
+// the platform code and message until cleared with a zero code.
+func (s *Server) FailWith(path string, code int, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if code == 0 {
+		delete(s.errors, path)
+		return
+	}
+	s.errors[path] = apiError{code: code, message: message}
+}
+
+// handle wraps one endpoint: it records the request and emits either the
+// programmed error or the envelope around data().
+func (s *Server) handle(data func() interface{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		s.mu.Lock()
+		s.requests = append(s.requests, Request{
+			Method: r.Method,
+			Path:   r.URL.Path,
+			Query:  r.URL.Query(),
+			Header: r.Header.Clone(),
+			Body:   body,
+		})
+		forced, failed := s.errors[r.URL.Path]
+		var payload interface{}
+		if !failed {
+			payload = data()
+		}
+		s.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if failed {
+			fmt.Fprintf(w, `{"code":%d,"message":%q}`, forced.code, forced.message)
+			return
+		}
+		raw, err := json.Marshal(payload)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprintf(w, `{"code":0,"message":"success","data":%s}`, raw)
+	}
+}
+
+// Harness bundles a ready-to-use Client with handles to the fakes behind it.
+type Harness struct {
+	// Client talks to Server and stores objects in Storage.
+	Client *voiceworld.Client
+	// Server is the fake HTTP API; use it to program responses and
+	// inspect received requests.
+	Server *Server
+	// Storage holds every uploaded object; use Object/Keys to assert on
+	// uploads.
+	Storage *voiceworld.MemoryStorage
+}
+
+// NewTestClient builds a Client wired against a fresh fake server and
+// in-memory object storage. Everything is cleaned up when the test finishes.
+func NewTestClient(t testing.TB) *Harness {
+	t.Helper()
+	server := NewServer(t)
+	storage := voiceworld.NewMemoryStorage()
+	client, err := voiceworld.NewClient("test-app-key", "test-secret-key", &voiceworld.ClientConfig{
+		BaseURL: server.URL(),
+		Storage: storage,
+	})
+	if err != nil {
+		t.Fatalf("voiceworldtest: NewClient: %v", err)
+	}
+	return &Harness{Client: client, Server: server, Storage: storage}
+}
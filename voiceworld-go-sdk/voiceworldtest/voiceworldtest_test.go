@@ -0,0 +1,101 @@
+package voiceworldtest
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	voiceworld "github.com/voiceworld/voiceworld-go-sdk"
+)
+
+func TestUploadIsObservable(t *testing.T) {
+	h := NewTestClient(t)
+	path := filepath.Join(t.TempDir(), "a.wav")
+	content := []byte("fake audio")
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := h.Client.UploadFile(path, "req-1/a.wav"); err != nil {
+		t.Fatalf("UploadFile: %v", err)
+	}
+	got, ok := h.Storage.Object("req-1/a.wav")
+	if !ok {
+		t.Fatalf("object not stored; keys = %v", h.Storage.Keys())
+	}
+	if !bytes.Equal(got, content) {
+		t.Errorf("stored bytes = %q, want %q", got, content)
+	}
+}
+
+func TestProgrammedASRResult(t *testing.T) {
+	h := NewTestClient(t)
+	h.Server.SetASRResult(voiceworld.RecognitionResult{TaskID: "t42", Result: "programmed text"})
+
+	result, err := h.Client.RecognizeURL("https://example.com/a.wav", nil)
+	if err != nil {
+		t.Fatalf("RecognizeURL: %v", err)
+	}
+	if result.TaskID != "t42" || result.Result != "programmed text" {
+		t.Errorf("result = %+v, want programmed values", result)
+	}
+}
+
+func TestRequestsAreRecorded(t *testing.T) {
+	h := NewTestClient(t)
+	if _, err := h.Client.GetOSSToken(); err != nil {
+		t.Fatalf("GetOSSToken: %v", err)
+	}
+	if _, err := h.Client.RecognizeURL("https://example.com/a.wav", nil); err != nil {
+		t.Fatalf("RecognizeURL: %v", err)
+	}
+
+	if got := len(h.Server.Requests()); got != 2 {
+		t.Fatalf("recorded %d requests, want 2", got)
+	}
+	asr := h.Server.RequestsTo("/asr")
+	if len(asr) != 1 {
+		t.Fatalf("recorded %d /asr requests, want 1", len(asr))
+	}
+	if !bytes.Contains(asr[0].Body, []byte("example.com/a.wav")) {
+		t.Errorf("/asr body = %q, want the audio url", asr[0].Body)
+	}
+	if asr[0].Header.Get("X-VW-AppKey") != "test-app-key" {
+		t.Errorf("request not signed with the test app key")
+	}
+}
+
+func TestFailWith(t *testing.T) {
+	h := NewTestClient(t)
+	h.Server.FailWith("/asr", 40001, "invalid audio")
+
+	_, err := h.Client.RecognizeURL("https://example.com/a.wav", nil)
+	var apiErr *voiceworld.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("err = %v, want *voiceworld.APIError", err)
+	}
+	if apiErr.Code != 40001 || apiErr.Message != "invalid audio" {
+		t.Errorf("apiErr = %+v, want code 40001", apiErr)
+	}
+
+	// Clearing restores the programmed success response.
+	h.Server.FailWith("/asr", 0, "")
+	if _, err := h.Client.RecognizeURL("https://example.com/a.wav", nil); err != nil {
+		t.Fatalf("RecognizeURL after clearing failure: %v", err)
+	}
+}
+
+func TestPreprocessEndpoint(t *testing.T) {
+	h := NewTestClient(t)
+	h.Server.SetPreprocessURL("https://processed.example.com/x.wav")
+
+	url, err := h.Client.PreprocessAudio("https://raw.example.com/x.wav", "req-7")
+	if err != nil {
+		t.Fatalf("PreprocessAudio: %v", err)
+	}
+	if url != "https://processed.example.com/x.wav" {
+		t.Errorf("url = %q, want programmed value", url)
+	}
+}
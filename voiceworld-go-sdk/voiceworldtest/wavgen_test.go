@@ -0,0 +1,84 @@
+package voiceworldtest
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+
+	voiceworld "github.com/voiceworld/voiceworld-go-sdk"
+)
+
+func TestGenerateWAVDefaults(t *testing.T) {
+	var buf bytes.Buffer
+	if err := GenerateWAV(&buf, WAVSpec{}); err != nil {
+		t.Fatalf("GenerateWAV: %v", err)
+	}
+	info, err := voiceworld.ParseWAV(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("ParseWAV: %v", err)
+	}
+	if info.SampleRate != 16000 || info.NumChannels != 1 || info.BitsPerSample != 16 {
+		t.Errorf("layout = %d Hz %d ch %d bit, want 16000 Hz 1 ch 16 bit", info.SampleRate, info.NumChannels, info.BitsPerSample)
+	}
+	if got := info.Duration(); math.Abs(got-1.0) > 0.001 {
+		t.Errorf("duration = %v s, want 1 s", got)
+	}
+}
+
+func TestGenerateWAVTonePattern(t *testing.T) {
+	var buf bytes.Buffer
+	spec := WAVSpec{
+		SampleRate: 8000,
+		Duration:   time.Second,
+		Tone:       100 * time.Millisecond,
+		Silence:    100 * time.Millisecond,
+	}
+	if err := GenerateWAV(&buf, spec); err != nil {
+		t.Fatalf("GenerateWAV: %v", err)
+	}
+	info, err := voiceworld.ParseWAV(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("ParseWAV: %v", err)
+	}
+	pcm := buf.Bytes()[info.DataOffset:]
+	sample := func(i int) int16 {
+		return int16(binary.LittleEndian.Uint16(pcm[2*i:]))
+	}
+	// 50 ms into the file is mid-burst; 150 ms is mid-silence.
+	var peak int16
+	for i := 350; i < 450; i++ { // around 50 ms at 8 kHz
+		if s := sample(i); s > peak {
+			peak = s
+		}
+	}
+	if peak == 0 {
+		t.Error("tone burst region is silent")
+	}
+	for i := 1100; i < 1500; i++ { // 137..187 ms
+		if sample(i) != 0 {
+			t.Fatalf("silence region has sample %d at frame %d", sample(i), i)
+		}
+	}
+}
+
+func TestGenerateWAVStereo(t *testing.T) {
+	var buf bytes.Buffer
+	if err := GenerateWAV(&buf, WAVSpec{Channels: 2, Duration: 100 * time.Millisecond}); err != nil {
+		t.Fatalf("GenerateWAV: %v", err)
+	}
+	info, err := voiceworld.ParseWAV(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("ParseWAV: %v", err)
+	}
+	if info.NumChannels != 2 {
+		t.Fatalf("channels = %d, want 2", info.NumChannels)
+	}
+	pcm := buf.Bytes()[info.DataOffset:]
+	for i := 0; i < len(pcm); i += 4 {
+		if !bytes.Equal(pcm[i:i+2], pcm[i+2:i+4]) {
+			t.Fatalf("channels diverge at frame %d", i/4)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package voiceworld
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// WarmupStep records one component of a Warmup call.
+type WarmupStep struct {
+	// Attempted says whether the step ran at all; the streaming step
+	// only runs when asked for.
+	Attempted bool `json:"attempted"`
+	// Duration is how long the step took, successful or not.
+	Duration time.Duration `json:"duration"`
+	// Error is the step's failure, empty on success. Warmup failures are
+	// advisory: the first real call simply pays the cost itself.
+	Error string `json:"error,omitempty"`
+}
+
+// WarmupOptions selects what Warmup establishes beyond the basics.
+type WarmupOptions struct {
+	// Streaming also opens, and immediately closes, a websocket to the
+	// /stream endpoint, paying its handshake before the first utterance.
+	Streaming bool
+}
+
+// WarmupReport says what Warmup managed to establish and how long each
+// piece took.
+type WarmupReport struct {
+	// Connection covers DNS, TCP and TLS to the API endpoint; the
+	// connection stays pooled for the calls that follow.
+	Connection WarmupStep `json:"connection"`
+	// Credentials covers prefetching storage credentials from the
+	// TokenProvider, so a caching provider starts out hot.
+	Credentials WarmupStep `json:"credentials"`
+	// Streaming covers the optional websocket handshake.
+	Streaming WarmupStep `json:"streaming"`
+}
+
+// Warmup pays the process-start costs — DNS, TCP, TLS, credentials and
+// optionally the streaming handshake — before the first user-facing
+// request, which otherwise eats them inside its latency budget. The steps
+// run concurrently; failures land on the report instead of an error, since
+// a cold start is a latency problem, not a correctness one. Safe to call
+// repeatedly. Passing more than one options value is an error.
+func (c *Client) Warmup(options ...*WarmupOptions) (*WarmupReport, error) {
+	if len(options) > 1 {
+		return nil, fmt.Errorf("voiceworld: at most one WarmupOptions may be given, got %d", len(options))
+	}
+	opts := &WarmupOptions{}
+	if len(options) == 1 && options[0] != nil {
+		opts = options[0]
+	}
+
+	report := &WarmupReport{}
+	var wg sync.WaitGroup
+	run := func(step *WarmupStep, fn func() error) {
+		step.Attempted = true
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			begin := c.now()
+			err := fn()
+			step.Duration = c.now().Sub(begin)
+			if err != nil {
+				step.Error = err.Error()
+			}
+		}()
+	}
+	run(&report.Connection, c.warmConnection)
+	run(&report.Credentials, c.warmCredentials)
+	if opts.Streaming {
+		run(&report.Streaming, c.warmStreaming)
+	}
+	wg.Wait()
+	return report, nil
+}
+
+// warmConnection establishes and pools a connection to the API endpoint.
+// Any HTTP status will do: reaching the server is the point.
+func (c *Client) warmConnection() error {
+	req, err := http.NewRequest(http.MethodHead, c.endpointURL("/time"), nil)
+	if err != nil {
+		return fmt.Errorf("voiceworld: build warmup request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("voiceworld: warm connection: %w", err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// warmCredentials prefetches storage credentials so a caching
+// TokenProvider starts out hot.
+func (c *Client) warmCredentials() error {
+	if _, err := c.tokenProvider.OSSCredentials(context.Background()); err != nil {
+		return fmt.Errorf("voiceworld: warm credentials: %w", err)
+	}
+	return nil
+}
+
+// warmStreaming performs one websocket handshake against /stream and hangs
+// up, mirroring how StartStreaming dials.
+func (c *Client) warmStreaming() error {
+	req, err := http.NewRequest(http.MethodGet, c.endpointURL("/stream"), nil)
+	if err != nil {
+		return fmt.Errorf("voiceworld: build warmup request: %w", err)
+	}
+	c.sign(req)
+	wsURL := strings.Replace(c.endpointURL("/stream"), "http", "ws", 1)
+	conn, resp, err := websocket.DefaultDialer.Dial(wsURL, req.Header)
+	if err != nil {
+		return fmt.Errorf("voiceworld: warm streaming endpoint: %w", err)
+	}
+	if resp != nil {
+		resp.Body.Close()
+	}
+	return conn.Close()
+}
@@ -0,0 +1,96 @@
+package voiceworld
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+)
+
+func TestWarmupPoolsConnection(t *testing.T) {
+	server := newEnvelopeServer(t, `{"code":0,"data":{"models":[]}}`)
+	var dials int32
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			atomic.AddInt32(&dials, 1)
+			return (&net.Dialer{}).DialContext(ctx, network, addr)
+		},
+	}
+	client, err := NewClient("ak", "sk", &ClientConfig{
+		BaseURL:       server.URL,
+		HTTPClient:    &http.Client{Transport: transport},
+		TokenProvider: StaticTokenProvider(OSSCredentials{Bucket: "b"}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := client.Warmup()
+	if err != nil {
+		t.Fatalf("Warmup: %v", err)
+	}
+	if !report.Connection.Attempted || report.Connection.Error != "" {
+		t.Fatalf("connection step = %+v", report.Connection)
+	}
+	if !report.Credentials.Attempted || report.Credentials.Error != "" {
+		t.Fatalf("credentials step = %+v", report.Credentials)
+	}
+	if report.Streaming.Attempted {
+		t.Error("streaming warmed without being asked for")
+	}
+	if got := atomic.LoadInt32(&dials); got != 1 {
+		t.Fatalf("warmup dialed %d times, want 1", got)
+	}
+
+	// The real call right after rides the pooled connection.
+	if _, err := client.ListModels(); err != nil {
+		t.Fatalf("ListModels: %v", err)
+	}
+	if got := atomic.LoadInt32(&dials); got != 1 {
+		t.Errorf("call after warmup dialed again (%d dials total)", got)
+	}
+}
+
+func TestWarmupStreamingHandshake(t *testing.T) {
+	fs := newFakeStreamServer(t)
+	client, err := NewClient("ak", "sk", &ClientConfig{
+		BaseURL:       fs.URL,
+		TokenProvider: StaticTokenProvider(OSSCredentials{Bucket: "b"}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, werr := client.Warmup(&WarmupOptions{Streaming: true})
+	if werr != nil {
+		t.Fatalf("Warmup: %v", werr)
+	}
+	if !report.Streaming.Attempted || report.Streaming.Error != "" {
+		t.Errorf("streaming step = %+v", report.Streaming)
+	}
+}
+
+func TestWarmupReportsFailuresWithoutFailing(t *testing.T) {
+	server := newEnvelopeServer(t, `{"code":0,"data":{}}`)
+	base := server.URL
+	server.Close() // nothing listens anymore
+
+	client, err := NewClient("ak", "sk", &ClientConfig{
+		BaseURL:       base,
+		TokenProvider: StaticTokenProvider(OSSCredentials{Bucket: "b"}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := client.Warmup(&WarmupOptions{Streaming: true})
+	if err != nil {
+		t.Fatalf("Warmup should not fail outright: %v", err)
+	}
+	if report.Connection.Error == "" || report.Streaming.Error == "" {
+		t.Errorf("report = %+v, want connection and streaming failures recorded", report)
+	}
+	if report.Credentials.Error != "" {
+		t.Errorf("static credentials step failed: %v", report.Credentials.Error)
+	}
+}
@@ -0,0 +1,236 @@
+package voiceworld
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+)
+
+// AudioValidationError reports malformed or unsupported audio input. All
+// header and chunk parsing returns it instead of panicking, because split
+// and upload paths regularly see user-supplied files.
+type AudioValidationError struct {
+	// Reason describes what was wrong with the input.
+	Reason string
+}
+
+// Error implements the error interface.
+func (e *AudioValidationError) Error() string {
+	return "voiceworld: invalid audio: " + e.Reason
+}
+
+// invalidAudio builds an AudioValidationError from a format string.
+func invalidAudio(format string, args ...interface{}) error {
+	return &AudioValidationError{Reason: fmt.Sprintf(format, args...)}
+}
+
+// Limits accepted by the validator. Generous for speech workloads while
+// keeping arithmetic far from overflow.
+const (
+	maxWAVChannels   = 16
+	maxWAVSampleRate = 384000
+)
+
+// WAVInfo describes the PCM layout of a RIFF/WAVE file, extracted from its
+// fmt and data chunks.
+type WAVInfo struct {
+	NumChannels   int
+	SampleRate    int
+	BitsPerSample int
+	// DataOffset is the byte offset of the first PCM sample in the file.
+	DataOffset int64
+	// DataSize is the actual size of the data chunk payload in bytes:
+	// the header's claim reconciled with the bytes really present, so
+	// every consumer of this struct plans against the same number. See
+	// DeclaredDataBytes and Truncated for the raw claim.
+	DataSize int64
+	// DeclaredDataBytes is the data chunk size the header claimed,
+	// before reconciliation. 0 and 0xFFFFFFFF are streaming-recorder
+	// placeholders meaning "unknown; read to end of file".
+	DeclaredDataBytes int64
+	// Truncated reports that the header claimed more data than the file
+	// contains — typically a recorder that crashed mid-write. DataSize
+	// already holds the usable byte count; callers that care can warn
+	// with both values.
+	Truncated bool
+}
+
+// BytesPerSecond is the PCM data rate implied by the format.
+func (w *WAVInfo) BytesPerSecond() int {
+	return w.SampleRate * w.NumChannels * w.BitsPerSample / 8
+}
+
+// Duration is the audio length in seconds.
+func (w *WAVInfo) Duration() float64 {
+	bps := w.BytesPerSecond()
+	if bps == 0 {
+		return 0
+	}
+	return float64(w.DataSize) / float64(bps)
+}
+
+// blockAlign is the byte size of one frame (one sample across all channels).
+func (w *WAVInfo) blockAlign() int {
+	return w.NumChannels * w.BitsPerSample / 8
+}
+
+// validate rejects layouts that would break downstream arithmetic.
+func (w *WAVInfo) validate() error {
+	if w.NumChannels < 1 || w.NumChannels > maxWAVChannels {
+		return invalidAudio("channel count %d out of range [1, %d]", w.NumChannels, maxWAVChannels)
+	}
+	if w.SampleRate < 1 || w.SampleRate > maxWAVSampleRate {
+		return invalidAudio("sample rate %d out of range [1, %d]", w.SampleRate, maxWAVSampleRate)
+	}
+	switch w.BitsPerSample {
+	case 8, 16, 24, 32:
+	default:
+		return invalidAudio("unsupported bit depth %d", w.BitsPerSample)
+	}
+	if w.DataSize < 0 {
+		return invalidAudio("negative data size %d", w.DataSize)
+	}
+	return nil
+}
+
+// ParseWAVFile opens path and reads its WAV header.
+func ParseWAVFile(path string) (*WAVInfo, error) {
+	f, err := os.Open(longPath(path))
+	if err != nil {
+		return nil, fmt.Errorf("voiceworld: open wav: %w", err)
+	}
+	defer f.Close()
+	return ParseWAV(f)
+}
+
+// ParseWAV reads a RIFF/WAVE header from r and returns the PCM layout. The
+// reader is left positioned at an unspecified offset; callers that need the
+// PCM payload should seek to DataOffset. Malformed input yields an
+// *AudioValidationError, never a panic: chunk sizes are bounds-checked
+// against the reader's real size and the format fields are range-checked.
+func ParseWAV(r io.ReadSeeker) (*WAVInfo, error) {
+	fileSize, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, fmt.Errorf("voiceworld: measure wav size: %w", err)
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	if fileSize < 44 {
+		return nil, invalidAudio("file is %d bytes, smaller than the minimal wav header", fileSize)
+	}
+
+	var riff [12]byte
+	if _, err := io.ReadFull(r, riff[:]); err != nil {
+		return nil, invalidAudio("short riff header: %v", err)
+	}
+	if string(riff[0:4]) != "RIFF" || string(riff[8:12]) != "WAVE" {
+		return nil, invalidAudio("not a RIFF/WAVE file")
+	}
+
+	info := &WAVInfo{}
+	haveFmt := false
+	haveData := false
+	offset := int64(12)
+	for offset+8 <= fileSize {
+		if _, err := r.Seek(offset, io.SeekStart); err != nil {
+			return nil, err
+		}
+		var hdr [8]byte
+		if _, err := io.ReadFull(r, hdr[:]); err != nil {
+			break
+		}
+		id := string(hdr[0:4])
+		size := int64(binary.LittleEndian.Uint32(hdr[4:8]))
+		body := offset + 8
+
+		switch id {
+		case "fmt ":
+			if size < 16 {
+				return nil, invalidAudio("fmt chunk is %d bytes, need at least 16", size)
+			}
+			if body+16 > fileSize {
+				return nil, invalidAudio("fmt chunk extends past end of file")
+			}
+			var fmtChunk [16]byte
+			if _, err := io.ReadFull(r, fmtChunk[:]); err != nil {
+				return nil, invalidAudio("short fmt chunk: %v", err)
+			}
+			format := binary.LittleEndian.Uint16(fmtChunk[0:2])
+			if format != 1 {
+				return nil, invalidAudio("unsupported wav format code %d (only PCM is supported)", format)
+			}
+			info.NumChannels = int(binary.LittleEndian.Uint16(fmtChunk[2:4]))
+			info.SampleRate = int(binary.LittleEndian.Uint32(fmtChunk[4:8]))
+			info.BitsPerSample = int(binary.LittleEndian.Uint16(fmtChunk[14:16]))
+			haveFmt = true
+		case "data":
+			info.DataOffset = body
+			avail := fileSize - body
+			info.DeclaredDataBytes = size
+			switch {
+			case size == 0 || size == 0xFFFFFFFF:
+				// A streaming placeholder: the recorder never went back
+				// to fill the size in, so the payload is whatever
+				// follows the header.
+				info.DataSize = avail
+			case size > avail:
+				// A truncated or lying file claims more than is
+				// present; trust the bytes on disk so chunk planning
+				// and reads stay inside the file.
+				info.DataSize = avail
+				info.Truncated = true
+			default:
+				info.DataSize = size
+			}
+			// The scan itself still advances by the in-file bound.
+			if size > avail {
+				size = avail
+			}
+			haveData = true
+		}
+		if haveFmt && haveData {
+			break
+		}
+		// Chunks are word-aligned; a huge bogus size just ends the scan
+		// at the fileSize bound above.
+		offset = body + size + (size & 1)
+	}
+
+	if !haveFmt {
+		return nil, invalidAudio("missing fmt chunk")
+	}
+	if !haveData {
+		return nil, invalidAudio("missing data chunk")
+	}
+	if err := info.validate(); err != nil {
+		return nil, err
+	}
+	// Trim a trailing partial frame so consumers never read half a sample.
+	if align := int64(info.blockAlign()); align > 0 {
+		info.DataSize -= info.DataSize % align
+	}
+	return info, nil
+}
+
+// writeWAVHeader emits a canonical 44-byte PCM WAV header for dataSize bytes
+// of payload in the given format.
+func writeWAVHeader(w io.Writer, info *WAVInfo, dataSize int64) error {
+	var hdr [44]byte
+	copy(hdr[0:4], "RIFF")
+	binary.LittleEndian.PutUint32(hdr[4:8], uint32(36+dataSize))
+	copy(hdr[8:12], "WAVE")
+	copy(hdr[12:16], "fmt ")
+	binary.LittleEndian.PutUint32(hdr[16:20], 16)
+	binary.LittleEndian.PutUint16(hdr[20:22], 1)
+	binary.LittleEndian.PutUint16(hdr[22:24], uint16(info.NumChannels))
+	binary.LittleEndian.PutUint32(hdr[24:28], uint32(info.SampleRate))
+	binary.LittleEndian.PutUint32(hdr[28:32], uint32(info.BytesPerSecond()))
+	binary.LittleEndian.PutUint16(hdr[32:34], uint16(info.blockAlign()))
+	binary.LittleEndian.PutUint16(hdr[34:36], uint16(info.BitsPerSample))
+	copy(hdr[36:40], "data")
+	binary.LittleEndian.PutUint32(hdr[40:44], uint32(dataSize))
+	_, err := w.Write(hdr[:])
+	return err
+}
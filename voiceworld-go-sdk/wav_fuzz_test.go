@@ -0,0 +1,84 @@
+package voiceworld
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// FuzzParseWAV asserts the parser never panics and that accepted files have
+// internally consistent layouts, whatever bytes arrive.
+func FuzzParseWAV(f *testing.F) {
+	valid := makeFuzzWAV()
+	f.Add(valid)
+	f.Add(valid[:40])             // sub-header-size
+	f.Add(valid[:len(valid)/2])   // truncated mid-data
+	f.Add([]byte("RIFFxxxxWAVE")) // header only
+	f.Add(bytes.Repeat(valid, 2)) // trailing garbage
+	corrupted := append([]byte(nil), valid...)
+	binary.LittleEndian.PutUint32(corrupted[40:44], 0xFFFFFFFF) // lying data size
+	f.Add(corrupted)
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		info, err := ParseWAV(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+		if info.DataOffset < 0 || info.DataSize < 0 || info.DataOffset+info.DataSize > int64(len(data)) {
+			t.Fatalf("accepted layout escapes the input: %+v for %d bytes", info, len(data))
+		}
+		if info.BytesPerSecond() <= 0 {
+			t.Fatalf("accepted layout with non-positive data rate: %+v", info)
+		}
+	})
+}
+
+// FuzzPlanChunks asserts planning never panics and that every accepted plan
+// tiles the data chunk exactly.
+func FuzzPlanChunks(f *testing.F) {
+	f.Add(uint16(1), uint32(16000), uint16(16), int64(32000), 60)
+	f.Add(uint16(2), uint32(8000), uint16(16), int64(12345), 1)
+	f.Add(uint16(0), uint32(0), uint16(0), int64(-1), -7)
+	f.Add(uint16(0xFFFF), uint32(0xFFFFFFFF), uint16(0xFFFF), int64(1<<62), 1<<30)
+
+	f.Fuzz(func(t *testing.T, channels uint16, rate uint32, bits uint16, dataSize int64, chunkDuration int) {
+		info := &WAVInfo{
+			NumChannels:   int(channels),
+			SampleRate:    int(rate),
+			BitsPerSample: int(bits),
+			DataOffset:    44,
+			DataSize:      dataSize,
+		}
+		spans, err := planChunks(info, chunkDuration)
+		if err != nil {
+			return
+		}
+		var total int64
+		prevEnd := int64(0)
+		for _, s := range spans {
+			if s.size <= 0 || s.offset != prevEnd {
+				t.Fatalf("bad span %+v in plan for %+v", s, info)
+			}
+			prevEnd = s.offset + s.size
+			total += s.size
+		}
+		if total != info.DataSize {
+			t.Fatalf("plan covers %d of %d bytes", total, info.DataSize)
+		}
+	})
+}
+
+// makeFuzzWAV builds a small valid WAV without a *testing.T.
+func makeFuzzWAV() []byte {
+	info := &WAVInfo{NumChannels: 1, SampleRate: 8000, BitsPerSample: 16}
+	var buf bytes.Buffer
+	payload := make([]byte, 1600)
+	for i := range payload {
+		payload[i] = byte(i)
+	}
+	if err := writeWAVHeader(&buf, info, int64(len(payload))); err != nil {
+		panic(err)
+	}
+	buf.Write(payload)
+	return buf.Bytes()
+}
@@ -0,0 +1,219 @@
+package voiceworld
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestParseWAVValid(t *testing.T) {
+	data := makeWAVData(t, 16000, 2, 1)
+	info, err := ParseWAV(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("ParseWAV: %v", err)
+	}
+	if info.SampleRate != 16000 || info.NumChannels != 2 || info.BitsPerSample != 16 {
+		t.Errorf("info = %+v", info)
+	}
+	if info.Duration() < 0.99 || info.Duration() > 1.01 {
+		t.Errorf("Duration = %v, want ~1s", info.Duration())
+	}
+}
+
+func TestParseWAVMalformed(t *testing.T) {
+	valid := makeWAVData(t, 16000, 1, 1)
+
+	corrupt := func(mutate func([]byte) []byte) []byte {
+		c := append([]byte(nil), valid...)
+		return mutate(c)
+	}
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"sub header size", valid[:40]},
+		{"not riff", corrupt(func(b []byte) []byte { copy(b, "JUNK"); return b })},
+		{"not wave", corrupt(func(b []byte) []byte { copy(b[8:], "JUNK"); return b })},
+		{"zero channels", corrupt(func(b []byte) []byte {
+			binary.LittleEndian.PutUint16(b[22:24], 0)
+			return b
+		})},
+		{"zero sample rate", corrupt(func(b []byte) []byte {
+			binary.LittleEndian.PutUint32(b[24:28], 0)
+			return b
+		})},
+		{"absurd channels", corrupt(func(b []byte) []byte {
+			binary.LittleEndian.PutUint16(b[22:24], 0xFFFF)
+			return b
+		})},
+		{"bogus bit depth", corrupt(func(b []byte) []byte {
+			binary.LittleEndian.PutUint16(b[34:36], 7)
+			return b
+		})},
+		{"non-pcm format", corrupt(func(b []byte) []byte {
+			binary.LittleEndian.PutUint16(b[20:22], 3)
+			return b
+		})},
+		{"no data chunk", corrupt(func(b []byte) []byte { copy(b[36:40], "junk"); return b[:44] })},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseWAV(bytes.NewReader(tt.data))
+			if err == nil {
+				t.Fatal("ParseWAV accepted malformed input")
+			}
+			var verr *AudioValidationError
+			if !errors.As(err, &verr) {
+				t.Errorf("err = %v (%T), want *AudioValidationError", err, err)
+			}
+		})
+	}
+}
+
+func TestParseWAVCapsLyingDataSize(t *testing.T) {
+	data := makeWAVData(t, 16000, 1, 1)
+	// Claim 4 GiB of data in a 32 KiB file.
+	binary.LittleEndian.PutUint32(data[40:44], 0xFFFFFFF0)
+	info, err := ParseWAV(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("ParseWAV: %v", err)
+	}
+	want := int64(len(data) - 44)
+	if info.DataSize != want {
+		t.Errorf("DataSize = %d, want capped to %d", info.DataSize, want)
+	}
+	if info.DeclaredDataBytes != 0xFFFFFFF0 {
+		t.Errorf("DeclaredDataBytes = %d, want the header's claim", info.DeclaredDataBytes)
+	}
+	if !info.Truncated {
+		t.Error("Truncated = false, want true for a header claiming more than exists")
+	}
+}
+
+func TestParseWAVTruncatedFile(t *testing.T) {
+	data := makeWAVData(t, 16000, 1, 1)
+	declared := int64(binary.LittleEndian.Uint32(data[40:44]))
+	// A recorder that crashed mid-write: header intact, payload cut short.
+	cut := data[:len(data)-1000]
+	info, err := ParseWAV(bytes.NewReader(cut))
+	if err != nil {
+		t.Fatalf("ParseWAV: %v", err)
+	}
+	want := int64(len(cut) - 44)
+	want -= want % int64(info.blockAlign())
+	if info.DataSize != want {
+		t.Errorf("DataSize = %d, want %d bytes actually present", info.DataSize, want)
+	}
+	if info.DeclaredDataBytes != declared {
+		t.Errorf("DeclaredDataBytes = %d, want %d", info.DeclaredDataBytes, declared)
+	}
+	if !info.Truncated {
+		t.Error("Truncated = false, want true")
+	}
+}
+
+func TestParseWAVPlaceholderDataSize(t *testing.T) {
+	// Streaming recorders write 0 or 0xFFFFFFFF and never patch the real
+	// size in; both must mean "the payload runs to end of file".
+	for _, placeholder := range []uint32{0, 0xFFFFFFFF} {
+		data := makeWAVData(t, 16000, 1, 1)
+		binary.LittleEndian.PutUint32(data[40:44], placeholder)
+		info, err := ParseWAV(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("placeholder %#x: ParseWAV: %v", placeholder, err)
+		}
+		if want := int64(len(data) - 44); info.DataSize != want {
+			t.Errorf("placeholder %#x: DataSize = %d, want %d", placeholder, info.DataSize, want)
+		}
+		if info.DeclaredDataBytes != int64(placeholder) {
+			t.Errorf("placeholder %#x: DeclaredDataBytes = %d", placeholder, info.DeclaredDataBytes)
+		}
+		if info.Truncated {
+			t.Errorf("placeholder %#x: Truncated = true, want false", placeholder)
+		}
+	}
+}
+
+func TestTruncatedFileProcessesAndSplits(t *testing.T) {
+	client, _ := newTestClient(t, nil)
+	path := makeWAVFile(t, 44100, 2, 2)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cut := path + ".cut.wav"
+	if err := os.WriteFile(cut, data[:len(data)-30000], 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := ProcessAudio(cut)
+	if err != nil {
+		t.Fatalf("ProcessAudio on truncated file: %v", err)
+	}
+	defer os.Remove(out)
+	if _, err := ParseWAVFile(out); err != nil {
+		t.Fatalf("processed output unparsable: %v", err)
+	}
+
+	// Splitting plans against the reconciled size, so every chunk stays
+	// inside the file.
+	result, err := client.SplitAudioFile(cut, 1)
+	if err != nil {
+		t.Fatalf("SplitAudioFile on truncated file: %v", err)
+	}
+	if len(result.Parts) == 0 {
+		t.Fatal("no parts produced")
+	}
+}
+
+func TestParseWAVTrimsPartialFrame(t *testing.T) {
+	data := makeWAVData(t, 16000, 2, 1)
+	truncated := data[:len(data)-3] // rip through the final frame
+	info, err := ParseWAV(bytes.NewReader(truncated))
+	if err != nil {
+		t.Fatalf("ParseWAV: %v", err)
+	}
+	if info.DataSize%int64(info.blockAlign()) != 0 {
+		t.Errorf("DataSize %d is not frame aligned", info.DataSize)
+	}
+}
+
+func TestPlanChunks(t *testing.T) {
+	info := &WAVInfo{NumChannels: 1, SampleRate: 16000, BitsPerSample: 16, DataOffset: 44, DataSize: 5 * 32000}
+	spans, err := planChunks(info, 2)
+	if err != nil {
+		t.Fatalf("planChunks: %v", err)
+	}
+	if len(spans) != 3 {
+		t.Fatalf("got %d spans, want 3", len(spans))
+	}
+	var total int64
+	for i, s := range spans {
+		if s.size <= 0 || s.offset < 0 || s.offset+s.size > info.DataSize {
+			t.Errorf("span %d out of range: %+v", i, s)
+		}
+		if s.offset%int64(info.blockAlign()) != 0 {
+			t.Errorf("span %d not frame aligned", i)
+		}
+		total += s.size
+	}
+	if total != info.DataSize {
+		t.Errorf("spans cover %d bytes, want %d", total, info.DataSize)
+	}
+}
+
+func TestPlanChunksRejectsBadInput(t *testing.T) {
+	bad := []*WAVInfo{
+		{NumChannels: 0, SampleRate: 16000, BitsPerSample: 16, DataSize: 100},
+		{NumChannels: 1, SampleRate: 16000, BitsPerSample: 16, DataSize: 0},
+		{NumChannels: 1, SampleRate: 16000, BitsPerSample: 16, DataSize: -5},
+	}
+	for i, info := range bad {
+		if _, err := planChunks(info, 60); err == nil {
+			t.Errorf("case %d: planChunks accepted %+v", i, info)
+		}
+	}
+}